@@ -13,14 +13,22 @@ import (
 	"time"
 
 	"databasus-backend/internal/config"
+	"databasus-backend/internal/features/api_keys"
 	"databasus-backend/internal/features/audit_logs"
 	"databasus-backend/internal/features/backups/backups"
+	backups_binlog "databasus-backend/internal/features/backups/binlog"
 	backups_config "databasus-backend/internal/features/backups/config"
+	backups_oplog "databasus-backend/internal/features/backups/oplog"
+	backups_pauses "databasus-backend/internal/features/backups/pauses"
+	backups_reconciliation "databasus-backend/internal/features/backups/reconciliation"
+	backups_verification "databasus-backend/internal/features/backups/verification"
+	backups_wal "databasus-backend/internal/features/backups/wal"
 	"databasus-backend/internal/features/databases"
 	"databasus-backend/internal/features/disk"
 	"databasus-backend/internal/features/encryption/secrets"
 	healthcheck_attempt "databasus-backend/internal/features/healthcheck/attempt"
 	healthcheck_config "databasus-backend/internal/features/healthcheck/config"
+	"databasus-backend/internal/features/manifests"
 	"databasus-backend/internal/features/notifiers"
 	"databasus-backend/internal/features/restores"
 	"databasus-backend/internal/features/storages"
@@ -160,7 +168,35 @@ func startServerWithGracefulShutdown(log *slog.Logger, app *gin.Engine) {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	<-quit
-	log.Info("Shutdown signal received")
+	log.Info("Shutdown signal received, waiting for running backups and restores")
+
+	// the server keeps working while draining, so the healthcheck reports
+	// the drain state. The second signal cancels the drain
+	config.StartDraining()
+
+	drainTimeout := time.Duration(config.GetEnv().ShutdownDrainTimeoutSeconds) * time.Second
+	if waitForRunningJobs(time.Now().UTC().Add(drainTimeout), quit) {
+		log.Info("All running backups and restores finished")
+	} else {
+		log.Warn(
+			"Drain timeout reached, cancelling running backups and restores",
+			"runningBackupsCount",
+			backups.GetBackupService().GetRunningBackupsCount(),
+			"runningRestoresCount",
+			restores.GetRestoreService().GetRunningRestoresCount(),
+		)
+	}
+
+	// stops background services and WAL, binlog and oplog archiving. Backups
+	// and restores left after the drain are cancelled and queued again
+	config.Shutdown()
+
+	stopArchivingServices(log, 30*time.Second)
+
+	// cancelled backups and restores need a moment to be queued again
+	if !waitForRunningJobs(time.Now().UTC().Add(30*time.Second), quit) {
+		log.Warn("Some backups and restores are not stopped, they are requeued by lease")
+	}
 
 	// The context is used to inform the server it has 10 seconds to finish
 	// the request it is currently handling
@@ -173,6 +209,49 @@ func startServerWithGracefulShutdown(log *slog.Logger, app *gin.Engine) {
 	log.Info("Server gracefully stopped")
 }
 
+// stopArchivingServices stops WAL and binlog receivers and the running oplog
+// slice and waits for them to exit before the server is stopped
+func stopArchivingServices(log *slog.Logger, timeout time.Duration) {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		backups_wal.GetWalArchivingBackgroundService().Stop()
+		backups_binlog.GetBinlogArchivingBackgroundService().Stop()
+		backups_oplog.GetOplogArchivingBackgroundService().Stop()
+	}()
+
+	select {
+	case <-done:
+		log.Info("WAL, binlog and oplog archiving stopped")
+	case <-time.After(timeout):
+		log.Warn("WAL, binlog and oplog archiving is not stopped in time")
+	}
+}
+
+// waitForRunningJobs waits until no backups and restores run in this
+// instance. Returns false if the deadline is reached or a signal is received
+func waitForRunningJobs(deadline time.Time, quit <-chan os.Signal) bool {
+	for {
+		runningJobsCount := backups.GetBackupService().GetRunningBackupsCount() +
+			restores.GetRestoreService().GetRunningRestoresCount()
+		if runningJobsCount == 0 {
+			return true
+		}
+
+		if time.Now().UTC().After(deadline) {
+			return false
+		}
+
+		select {
+		case <-quit:
+			return false
+		case <-time.After(1 * time.Second):
+		}
+	}
+}
+
 func setUpRoutes(r *gin.Engine) {
 	v1 := r.Group("/api/v1")
 
@@ -204,6 +283,14 @@ func setUpRoutes(r *gin.Engine) {
 	healthcheck_config.GetHealthcheckConfigController().RegisterRoutes(protected)
 	healthcheck_attempt.GetHealthcheckAttemptController().RegisterRoutes(protected)
 	backups_config.GetBackupConfigController().RegisterRoutes(protected)
+	backups_pauses.GetBackupPauseController().RegisterRoutes(protected)
+	backups_wal.GetWalController().RegisterRoutes(protected)
+	backups_binlog.GetBinlogController().RegisterRoutes(protected)
+	backups_oplog.GetOplogController().RegisterRoutes(protected)
+	backups_verification.GetVerificationController().RegisterRoutes(protected)
+	backups_reconciliation.GetReconciliationController().RegisterRoutes(protected)
+	manifests.GetManifestController().RegisterRoutes(protected)
+	api_keys.GetAPIKeyController().RegisterRoutes(protected)
 	audit_logs.GetAuditLogController().RegisterRoutes(protected)
 	users_controllers.GetManagementController().RegisterRoutes(protected)
 	users_controllers.GetSettingsController().RegisterRoutes(protected)
@@ -218,6 +305,11 @@ func setUpDependencies() {
 	notifiers.SetupDependencies()
 	storages.SetupDependencies()
 	backups_config.SetupDependencies()
+	backups_wal.SetupDependencies()
+	backups_binlog.SetupDependencies()
+	backups_oplog.SetupDependencies()
+	backups_verification.SetupDependencies()
+	api_keys.SetupDependencies()
 }
 
 func runBackgroundTasks(log *slog.Logger) {
@@ -232,6 +324,26 @@ func runBackgroundTasks(log *slog.Logger) {
 		backups.GetBackupBackgroundService().Run()
 	})
 
+	go runWithPanicLogging(log, "WAL archiving background service", func() {
+		backups_wal.GetWalArchivingBackgroundService().Run()
+	})
+
+	go runWithPanicLogging(log, "binlog archiving background service", func() {
+		backups_binlog.GetBinlogArchivingBackgroundService().Run()
+	})
+
+	go runWithPanicLogging(log, "oplog archiving background service", func() {
+		backups_oplog.GetOplogArchivingBackgroundService().Run()
+	})
+
+	go runWithPanicLogging(log, "backup verification background service", func() {
+		backups_verification.GetVerificationBackgroundService().Run()
+	})
+
+	go runWithPanicLogging(log, "storage reconciliation background service", func() {
+		backups_reconciliation.GetReconciliationBackgroundService().Run()
+	})
+
 	go runWithPanicLogging(log, "restore background service", func() {
 		restores.GetRestoreBackgroundService().Run()
 	})
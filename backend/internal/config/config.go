@@ -33,6 +33,22 @@ type EnvVariables struct {
 	TempFolder    string
 	SecretKeyPath string
 
+	// limits of concurrently running backups: in total, against the same
+	// database host and into the same storage. Other backups wait in queue
+	BackupMaxConcurrent           int `env:"BACKUP_MAX_CONCURRENT"             env-default:"4"`
+	BackupMaxConcurrentPerHost    int `env:"BACKUP_MAX_CONCURRENT_PER_HOST"    env-default:"1"`
+	BackupMaxConcurrentPerStorage int `env:"BACKUP_MAX_CONCURRENT_PER_STORAGE" env-default:"2"`
+
+	// how long running backups and restores may finish on shutdown before
+	// they are cancelled and queued again. Keep it below the stop timeout of
+	// the container, otherwise they are killed instead
+	ShutdownDrainTimeoutSeconds int `env:"SHUTDOWN_DRAIN_TIMEOUT_SECONDS" env-default:"300"`
+
+	// absolute directories physical restores and point-in-time recoveries may
+	// write data directories into. Restores into data directories are
+	// disabled while the list is empty
+	RestoreDataDirectoryBaseDirs []string `env:"RESTORE_DATA_DIRECTORY_BASE_DIRS" env-separator:","`
+
 	TestGoogleDriveClientID     string `env:"TEST_GOOGLE_DRIVE_CLIENT_ID"`
 	TestGoogleDriveClientSecret string `env:"TEST_GOOGLE_DRIVE_CLIENT_SECRET"`
 	TestGoogleDriveTokenJSON    string `env:"TEST_GOOGLE_DRIVE_TOKEN_JSON"`
@@ -177,6 +193,27 @@ func loadEnvVariables() {
 	}
 	log.Info("ENV_MODE loaded", "mode", env.EnvMode)
 
+	if env.BackupMaxConcurrent < 1 || env.BackupMaxConcurrentPerHost < 1 ||
+		env.BackupMaxConcurrentPerStorage < 1 {
+		log.Error("Backup concurrency limits must be at least 1")
+		os.Exit(1)
+	}
+
+	if env.ShutdownDrainTimeoutSeconds < 0 {
+		log.Error("SHUTDOWN_DRAIN_TIMEOUT_SECONDS must not be negative")
+		os.Exit(1)
+	}
+
+	for i, baseDir := range env.RestoreDataDirectoryBaseDirs {
+		baseDir = strings.TrimSpace(baseDir)
+		if !filepath.IsAbs(baseDir) {
+			log.Error("RESTORE_DATA_DIRECTORY_BASE_DIRS must contain absolute paths", "path", baseDir)
+			os.Exit(1)
+		}
+
+		env.RestoreDataDirectoryBaseDirs[i] = filepath.Clean(baseDir)
+	}
+
 	env.PostgresesInstallDir = filepath.Join(backendRoot, "tools", "postgresql")
 	tools.VerifyPostgresesInstallation(log, env.EnvMode, env.PostgresesInstallDir)
 
@@ -1,23 +1,35 @@
 package config
 
-import (
-	"os"
-	"os/signal"
-	"syscall"
-)
-
-var isShutDownSignalReceived = false
-
-func StartListeningForShutdownSignal() {
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
-
-	go func() {
-		<-quit
-		isShutDownSignalReceived = true
-	}()
+import "sync/atomic"
+
+var isDraining atomic.Bool
+
+var isShutDownSignalReceived atomic.Bool
+
+// StartDraining is called on shutdown signal. New backups and restores are
+// not started anymore, while running ones are allowed to finish
+func StartDraining() {
+	isDraining.Store(true)
+}
+
+func IsDraining() bool {
+	return isDraining.Load()
+}
+
+// Shutdown asks running backups and restores to stop. They are queued again
+// and started after restart
+func Shutdown() {
+	isDraining.Store(true)
+	isShutDownSignalReceived.Store(true)
 }
 
 func IsShouldShutdown() bool {
-	return isShutDownSignalReceived
+	return isShutDownSignalReceived.Load()
+}
+
+// ResetShutdownForTesting reverts StartDraining and Shutdown, so tests of
+// the drain state do not affect other tests of the package
+func ResetShutdownForTesting() {
+	isDraining.Store(false)
+	isShutDownSignalReceived.Store(false)
 }
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StartDraining_WhenDraining_RunningJobsNotCancelled(t *testing.T) {
+	defer ResetShutdownForTesting()
+
+	assert.False(t, IsDraining())
+	assert.False(t, IsShouldShutdown())
+
+	StartDraining()
+
+	assert.True(t, IsDraining())
+	assert.False(t, IsShouldShutdown())
+}
+
+func Test_Shutdown_WhenShutdown_DrainingAndRunningJobsCancelled(t *testing.T) {
+	defer ResetShutdownForTesting()
+
+	Shutdown()
+
+	assert.True(t, IsDraining())
+	assert.True(t, IsShouldShutdown())
+}
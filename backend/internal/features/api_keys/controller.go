@@ -0,0 +1,132 @@
+package api_keys
+
+import (
+	"net/http"
+
+	users_middleware "databasus-backend/internal/features/users/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type APIKeyController struct {
+	apiKeyService *APIKeyService
+}
+
+func (c *APIKeyController) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/workspaces/:id/api-keys", c.GetAPIKeys)
+	router.POST("/workspaces/:id/api-keys", c.CreateAPIKey)
+	router.POST("/workspaces/:id/api-keys/:keyId/revoke", c.RevokeAPIKey)
+}
+
+// GetAPIKeys
+// @Summary Get workspace API keys
+// @Description Get all API keys of the workspace, including revoked and expired ones
+// @Tags api-keys
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Workspace ID"
+// @Success 200 {array} APIKey
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /workspaces/{id}/api-keys [get]
+func (c *APIKeyController) GetAPIKeys(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	workspaceID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid workspace ID"})
+		return
+	}
+
+	apiKeys, err := c.apiKeyService.GetAPIKeysWithAuth(user, workspaceID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, apiKeys)
+}
+
+// CreateAPIKey
+// @Summary Create workspace API key
+// @Description Create an API key for automation. The key is returned only once and should be passed in Authorization header as "Bearer <key>"
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Workspace ID"
+// @Param request body CreateAPIKeyRequest true "API key data"
+// @Success 200 {object} CreateAPIKeyResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /workspaces/{id}/api-keys [post]
+func (c *APIKeyController) CreateAPIKey(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	workspaceID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid workspace ID"})
+		return
+	}
+
+	var request CreateAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := c.apiKeyService.CreateAPIKeyWithAuth(user, workspaceID, &request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// RevokeAPIKey
+// @Summary Revoke workspace API key
+// @Description Revoke the API key. Revoked keys are kept to show them in audit logs
+// @Tags api-keys
+// @Security BearerAuth
+// @Param id path string true "Workspace ID"
+// @Param keyId path string true "API key ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /workspaces/{id}/api-keys/{keyId}/revoke [post]
+func (c *APIKeyController) RevokeAPIKey(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	workspaceID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid workspace ID"})
+		return
+	}
+
+	apiKeyID, err := uuid.Parse(ctx.Param("keyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid API key ID"})
+		return
+	}
+
+	if err := c.apiKeyService.RevokeAPIKeyWithAuth(user, workspaceID, apiKeyID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,186 @@
+package api_keys
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	users_enums "databasus-backend/internal/features/users/enums"
+	users_testing "databasus-backend/internal/features/users/testing"
+	workspaces_controllers "databasus-backend/internal/features/workspaces/controllers"
+	workspaces_testing "databasus-backend/internal/features/workspaces/testing"
+	test_utils "databasus-backend/internal/util/testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestRouter() *gin.Engine {
+	router := workspaces_testing.CreateTestRouter(
+		workspaces_controllers.GetWorkspaceController(),
+		workspaces_controllers.GetMembershipController(),
+		GetAPIKeyController(),
+	)
+
+	SetupDependencies()
+
+	return router
+}
+
+func Test_CreateAPIKey_WhenKeyUsed_AccessLimitedToKeyWorkspace(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+	otherWorkspace := workspaces_testing.CreateTestWorkspace("Other Workspace", owner, router)
+
+	defer func() {
+		workspaces_testing.RemoveTestWorkspace(workspace, router)
+		workspaces_testing.RemoveTestWorkspace(otherWorkspace, router)
+	}()
+
+	apiKey := createTestAPIKey(t, router, owner.Token, workspace.ID.String())
+
+	test_utils.MakeGetRequest(
+		t,
+		router,
+		"/api/v1/workspaces/"+workspace.ID.String(),
+		"Bearer "+apiKey.Key,
+		http.StatusOK,
+	)
+
+	test_utils.MakeGetRequest(
+		t,
+		router,
+		"/api/v1/workspaces/"+otherWorkspace.ID.String(),
+		"Bearer "+apiKey.Key,
+		http.StatusForbidden,
+	)
+
+	var apiKeys []*APIKey
+	test_utils.MakeGetRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/workspaces/"+workspace.ID.String()+"/api-keys",
+		"Bearer "+owner.Token,
+		http.StatusOK,
+		&apiKeys,
+	)
+
+	assert.Len(t, apiKeys, 1)
+	assert.NotNil(t, apiKeys[0].LastUsedAt)
+	assert.True(t, strings.HasPrefix(apiKey.Key, apiKeys[0].KeyPrefix))
+}
+
+func Test_RevokeAPIKey_WhenKeyUsed_ReturnsUnauthorized(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	defer func() {
+		workspaces_testing.RemoveTestWorkspace(workspace, router)
+	}()
+
+	apiKey := createTestAPIKey(t, router, owner.Token, workspace.ID.String())
+
+	revokeURL := "/api/v1/workspaces/" + workspace.ID.String() +
+		"/api-keys/" + apiKey.APIKey.ID.String() + "/revoke"
+
+	test_utils.MakeRequest(t, router, test_utils.RequestOptions{
+		Method:         "POST",
+		URL:            revokeURL,
+		AuthToken:      "Bearer " + owner.Token,
+		ExpectedStatus: http.StatusNoContent,
+	})
+
+	test_utils.MakeGetRequest(
+		t,
+		router,
+		"/api/v1/workspaces/"+workspace.ID.String(),
+		"Bearer "+apiKey.Key,
+		http.StatusUnauthorized,
+	)
+}
+
+func Test_CreateAPIKey_WithAPIKeyAuth_ReturnsBadRequest(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	defer func() {
+		workspaces_testing.RemoveTestWorkspace(workspace, router)
+	}()
+
+	apiKey := createTestAPIKey(t, router, owner.Token, workspace.ID.String())
+
+	test_utils.MakePostRequest(
+		t,
+		router,
+		"/api/v1/workspaces/"+workspace.ID.String()+"/api-keys",
+		"Bearer "+apiKey.Key,
+		CreateAPIKeyRequest{Name: "escalated", Role: users_enums.WorkspaceRoleAdmin},
+		http.StatusBadRequest,
+	)
+}
+
+func Test_CreateAPIKey_WithOwnerRole_ReturnsBadRequest(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	defer func() {
+		workspaces_testing.RemoveTestWorkspace(workspace, router)
+	}()
+
+	test_utils.MakePostRequest(
+		t,
+		router,
+		"/api/v1/workspaces/"+workspace.ID.String()+"/api-keys",
+		"Bearer "+owner.Token,
+		CreateAPIKeyRequest{Name: "ci", Role: users_enums.WorkspaceRoleOwner},
+		http.StatusBadRequest,
+	)
+}
+
+func Test_APIKey_WithExpirationTime_ExpiredAfterIt(t *testing.T) {
+	expiresAt := time.Now().UTC().Add(time.Hour)
+	apiKey := &APIKey{ExpiresAt: &expiresAt}
+
+	assert.False(t, apiKey.IsExpired(time.Now().UTC()))
+	assert.True(t, apiKey.IsExpired(expiresAt))
+	assert.False(t, (&APIKey{}).IsExpired(time.Now().UTC()))
+}
+
+func Test_GenerateAPIKey_KeysAreUniqueAndHashedDeterministically(t *testing.T) {
+	firstKey, err := generateAPIKey()
+	assert.NoError(t, err)
+
+	secondKey, err := generateAPIKey()
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, firstKey, secondKey)
+	assert.True(t, strings.HasPrefix(firstKey, "dbs_"))
+	assert.Equal(t, hashAPIKey(firstKey), hashAPIKey(firstKey))
+	assert.NotEqual(t, hashAPIKey(firstKey), hashAPIKey(secondKey))
+	assert.NotContains(t, hashAPIKey(firstKey), firstKey)
+}
+
+func createTestAPIKey(
+	t *testing.T,
+	router *gin.Engine,
+	token string,
+	workspaceID string,
+) *CreateAPIKeyResponse {
+	var response CreateAPIKeyResponse
+	test_utils.MakePostRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/workspaces/"+workspaceID+"/api-keys",
+		"Bearer "+token,
+		CreateAPIKeyRequest{Name: "ci", Role: users_enums.WorkspaceRoleMember},
+		http.StatusOK,
+		&response,
+	)
+
+	return &response
+}
@@ -0,0 +1,34 @@
+package api_keys
+
+import (
+	audit_logs "databasus-backend/internal/features/audit_logs"
+	users_services "databasus-backend/internal/features/users/services"
+	workspaces_services "databasus-backend/internal/features/workspaces/services"
+	"databasus-backend/internal/util/logger"
+)
+
+var apiKeyRepository = &APIKeyRepository{}
+
+var apiKeyService = &APIKeyService{
+	apiKeyRepository,
+	users_services.GetUserService(),
+	workspaces_services.GetWorkspaceService(),
+	audit_logs.GetAuditLogService(),
+	logger.GetLogger(),
+}
+
+var apiKeyController = &APIKeyController{
+	apiKeyService,
+}
+
+func GetAPIKeyService() *APIKeyService {
+	return apiKeyService
+}
+
+func GetAPIKeyController() *APIKeyController {
+	return apiKeyController
+}
+
+func SetupDependencies() {
+	users_services.GetUserService().SetAPIKeyAuthenticator(apiKeyService)
+}
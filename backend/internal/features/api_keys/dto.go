@@ -0,0 +1,20 @@
+package api_keys
+
+import (
+	"time"
+
+	users_enums "databasus-backend/internal/features/users/enums"
+)
+
+type CreateAPIKeyRequest struct {
+	Name      string                    `json:"name"      binding:"required,min=1,max=255"`
+	Role      users_enums.WorkspaceRole `json:"role"      binding:"required"`
+	ExpiresAt *time.Time                `json:"expiresAt"`
+}
+
+type CreateAPIKeyResponse struct {
+	APIKey *APIKey `json:"apiKey"`
+
+	// Key is returned only once, it cannot be restored later
+	Key string `json:"key"`
+}
@@ -0,0 +1,38 @@
+package api_keys
+
+import (
+	"time"
+
+	users_enums "databasus-backend/internal/features/users/enums"
+
+	"github.com/google/uuid"
+)
+
+// APIKey allows automation (e.g. CI pipelines) to call the API on behalf of
+// the workspace. Only SHA-256 hash of the key is stored, the key itself is
+// shown once on creation
+type APIKey struct {
+	ID              uuid.UUID                 `json:"id"              gorm:"column:id"`
+	WorkspaceID     uuid.UUID                 `json:"workspaceId"     gorm:"column:workspace_id"`
+	Name            string                    `json:"name"            gorm:"column:name"`
+	KeyPrefix       string                    `json:"keyPrefix"       gorm:"column:key_prefix"`
+	HashedKey       string                    `json:"-"               gorm:"column:hashed_key"`
+	Role            users_enums.WorkspaceRole `json:"role"            gorm:"column:role"`
+	CreatedByUserID uuid.UUID                 `json:"createdByUserId" gorm:"column:created_by_user_id"`
+	ExpiresAt       *time.Time                `json:"expiresAt"       gorm:"column:expires_at"`
+	LastUsedAt      *time.Time                `json:"lastUsedAt"      gorm:"column:last_used_at"`
+	RevokedAt       *time.Time                `json:"revokedAt"       gorm:"column:revoked_at"`
+	CreatedAt       time.Time                 `json:"createdAt"       gorm:"column:created_at"`
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
@@ -0,0 +1,288 @@
+package api_keys
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	audit_logs "databasus-backend/internal/features/audit_logs"
+	users_enums "databasus-backend/internal/features/users/enums"
+	users_models "databasus-backend/internal/features/users/models"
+	users_services "databasus-backend/internal/features/users/services"
+	workspaces_services "databasus-backend/internal/features/workspaces/services"
+
+	"github.com/google/uuid"
+)
+
+const (
+	apiKeyRandomBytes = 32
+	// displayed prefix is long enough to tell keys apart, but too short to
+	// help guessing the key
+	apiKeyDisplayedPrefixLength = 12
+	// last usage is not saved on each request to avoid a write per request
+	lastUsedUpdateInterval = time.Minute
+)
+
+type APIKeyService struct {
+	apiKeyRepository *APIKeyRepository
+	userService      *users_services.UserService
+	workspaceService *workspaces_services.WorkspaceService
+	auditLogService  *audit_logs.AuditLogService
+	logger           *slog.Logger
+}
+
+func (s *APIKeyService) CreateAPIKeyWithAuth(
+	user *users_models.User,
+	workspaceID uuid.UUID,
+	request *CreateAPIKeyRequest,
+) (*CreateAPIKeyResponse, error) {
+	if err := s.checkCanManageAPIKeys(user, workspaceID); err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return nil, errors.New("API key name is required")
+	}
+
+	// owner role allows to delete the workspace and transfer ownership,
+	// which should not be available for automation
+	if !request.Role.IsValid() || request.Role == users_enums.WorkspaceRoleOwner {
+		return nil, errors.New("API key role must be admin, member or viewer")
+	}
+
+	now := time.Now().UTC()
+
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(now) {
+		return nil, errors.New("API key expiration time must be in the future")
+	}
+
+	key, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey := &APIKey{
+		ID:              uuid.New(),
+		WorkspaceID:     workspaceID,
+		Name:            name,
+		KeyPrefix:       key[:apiKeyDisplayedPrefixLength],
+		HashedKey:       hashAPIKey(key),
+		Role:            request.Role,
+		CreatedByUserID: user.ID,
+		ExpiresAt:       request.ExpiresAt,
+		CreatedAt:       now,
+	}
+
+	if apiKey.ExpiresAt != nil {
+		expiresAt := apiKey.ExpiresAt.UTC()
+		apiKey.ExpiresAt = &expiresAt
+	}
+
+	if err := s.apiKeyRepository.Create(apiKey); err != nil {
+		return nil, err
+	}
+
+	s.auditLogService.WriteAuditLog(
+		fmt.Sprintf("API key created: %s (role: %s)", apiKey.Name, apiKey.Role),
+		&user.ID,
+		&workspaceID,
+	)
+
+	return &CreateAPIKeyResponse{
+		APIKey: apiKey,
+		Key:    key,
+	}, nil
+}
+
+func (s *APIKeyService) GetAPIKeysWithAuth(
+	user *users_models.User,
+	workspaceID uuid.UUID,
+) ([]*APIKey, error) {
+	if err := s.checkCanManageAPIKeys(user, workspaceID); err != nil {
+		return nil, err
+	}
+
+	return s.apiKeyRepository.FindByWorkspaceID(workspaceID)
+}
+
+func (s *APIKeyService) RevokeAPIKeyWithAuth(
+	user *users_models.User,
+	workspaceID uuid.UUID,
+	apiKeyID uuid.UUID,
+) error {
+	if err := s.checkCanManageAPIKeys(user, workspaceID); err != nil {
+		return err
+	}
+
+	apiKey, err := s.apiKeyRepository.FindByID(apiKeyID)
+	if err != nil {
+		return err
+	}
+
+	if apiKey.WorkspaceID != workspaceID {
+		return errors.New("API key does not belong to this workspace")
+	}
+
+	if apiKey.IsRevoked() {
+		return errors.New("API key is already revoked")
+	}
+
+	if err := s.apiKeyRepository.UpdateRevokedAt(apiKey.ID, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	s.auditLogService.WriteAuditLog(
+		fmt.Sprintf("API key revoked: %s", apiKey.Name),
+		&user.ID,
+		&workspaceID,
+	)
+
+	return nil
+}
+
+// GetUserFromAPIKey returns the user the API key acts as. The user is the
+// key creator limited to the key's workspace and role. The key stops
+// working as soon as the creator is deactivated or cannot manage the
+// workspace anymore
+func (s *APIKeyService) GetUserFromAPIKey(key string) (*users_models.User, error) {
+	apiKey, err := s.apiKeyRepository.FindByHashedKey(hashAPIKey(key))
+	if err != nil {
+		return nil, err
+	}
+
+	if apiKey == nil {
+		return nil, errors.New("API key not found")
+	}
+
+	now := time.Now().UTC()
+
+	if apiKey.IsRevoked() {
+		return nil, errors.New("API key is revoked")
+	}
+
+	if apiKey.IsExpired(now) {
+		return nil, errors.New("API key is expired")
+	}
+
+	creator, err := s.userService.GetUserByID(apiKey.CreatedByUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !creator.IsActiveUser() {
+		return nil, errors.New("API key creator is not active")
+	}
+
+	canManage, err := s.workspaceService.CanUserManageWorkspace(apiKey.WorkspaceID, creator)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage {
+		return nil, errors.New("API key creator cannot manage the workspace anymore")
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= lastUsedUpdateInterval {
+		if err := s.apiKeyRepository.UpdateLastUsedAt(apiKey.ID, now); err != nil {
+			s.logger.Error(
+				"Failed to update API key last usage",
+				"apiKeyId",
+				apiKey.ID,
+				"error",
+				err,
+			)
+		}
+	}
+
+	keyUser := *creator
+	// global admin permissions are never granted to API keys
+	keyUser.Role = users_enums.UserRoleMember
+	keyUser.APIKey = &users_models.APIKeyScope{
+		ID:          apiKey.ID,
+		Name:        apiKey.Name,
+		WorkspaceID: apiKey.WorkspaceID,
+		Role:        apiKey.Role,
+	}
+
+	return &keyUser, nil
+}
+
+// WriteAPIKeyRequestAuditLog records which API key performed the request.
+// Read-only requests are not recorded
+func (s *APIKeyService) WriteAPIKeyRequestAuditLog(
+	user *users_models.User,
+	method string,
+	path string,
+	status int,
+) {
+	if user.APIKey == nil {
+		return
+	}
+
+	if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+		return
+	}
+
+	if err := s.auditLogService.CreateAuditLog(&audit_logs.AuditLog{
+		UserID:      &user.ID,
+		WorkspaceID: &user.APIKey.WorkspaceID,
+		APIKeyID:    &user.APIKey.ID,
+		Message: fmt.Sprintf(
+			"API key %s performed request: %s %s (status: %d)",
+			user.APIKey.Name,
+			method,
+			path,
+			status,
+		),
+		CreatedAt: time.Now().UTC(),
+	}); err != nil {
+		s.logger.Error(
+			"Failed to write API key audit log",
+			"apiKeyId",
+			user.APIKey.ID,
+			"error",
+			err,
+		)
+	}
+}
+
+func (s *APIKeyService) checkCanManageAPIKeys(
+	user *users_models.User,
+	workspaceID uuid.UUID,
+) error {
+	if user.IsAPIKey() {
+		return errors.New("API keys cannot be managed with API key")
+	}
+
+	canManage, err := s.workspaceService.CanUserManageWorkspace(workspaceID, user)
+	if err != nil {
+		return err
+	}
+	if !canManage {
+		return errors.New("insufficient permissions to manage API keys of this workspace")
+	}
+
+	return nil
+}
+
+func generateAPIKey() (string, error) {
+	randomBytes := make([]byte, apiKeyRandomBytes)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	return users_models.APIKeyPrefix + hex.EncodeToString(randomBytes), nil
+}
+
+// hashAPIKey uses SHA-256 rather than bcrypt: keys are random and long, so
+// a fast hash is enough and allows to find the key by its hash
+func hashAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
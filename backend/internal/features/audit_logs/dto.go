@@ -28,4 +28,6 @@ type AuditLogDTO struct {
 	UserEmail     *string    `json:"userEmail"     gorm:"column:user_email"`
 	UserName      *string    `json:"userName"      gorm:"column:user_name"`
 	WorkspaceName *string    `json:"workspaceName" gorm:"column:workspace_name"`
+	APIKeyID      *uuid.UUID `json:"apiKeyId"      gorm:"column:api_key_id"`
+	APIKeyName    *string    `json:"apiKeyName"    gorm:"column:api_key_name"`
 }
@@ -10,6 +10,7 @@ type AuditLog struct {
 	ID          uuid.UUID  `json:"id"          gorm:"column:id"`
 	UserID      *uuid.UUID `json:"userId"      gorm:"column:user_id"`
 	WorkspaceID *uuid.UUID `json:"workspaceId" gorm:"column:workspace_id"`
+	APIKeyID    *uuid.UUID `json:"apiKeyId"    gorm:"column:api_key_id"`
 	Message     string     `json:"message"     gorm:"column:message"`
 	CreatedAt   time.Time  `json:"createdAt"   gorm:"column:created_at"`
 }
@@ -32,10 +32,13 @@ func (r *AuditLogRepository) GetGlobal(
 			al.created_at,
 			u.email as user_email,
 			u.name as user_name,
-			w.name as workspace_name
+			w.name as workspace_name,
+			al.api_key_id,
+			k.name as api_key_name
 		FROM audit_logs al
 		LEFT JOIN users u ON al.user_id = u.id
-		LEFT JOIN workspaces w ON al.workspace_id = w.id`
+		LEFT JOIN workspaces w ON al.workspace_id = w.id
+		LEFT JOIN api_keys k ON al.api_key_id = k.id`
 
 	args := []interface{}{}
 
@@ -68,10 +71,13 @@ func (r *AuditLogRepository) GetByUser(
 			al.created_at,
 			u.email as user_email,
 			u.name as user_name,
-			w.name as workspace_name
+			w.name as workspace_name,
+			al.api_key_id,
+			k.name as api_key_name
 		FROM audit_logs al
 		LEFT JOIN users u ON al.user_id = u.id
 		LEFT JOIN workspaces w ON al.workspace_id = w.id
+		LEFT JOIN api_keys k ON al.api_key_id = k.id
 		WHERE al.user_id = ?`
 
 	args := []interface{}{userID}
@@ -105,10 +111,13 @@ func (r *AuditLogRepository) GetByWorkspace(
 			al.created_at,
 			u.email as user_email,
 			u.name as user_name,
-			w.name as workspace_name
+			w.name as workspace_name,
+			al.api_key_id,
+			k.name as api_key_name
 		FROM audit_logs al
 		LEFT JOIN users u ON al.user_id = u.id
 		LEFT JOIN workspaces w ON al.workspace_id = w.id
+		LEFT JOIN api_keys k ON al.api_key_id = k.id
 		WHERE al.workspace_id = ?`
 
 	args := []interface{}{workspaceID}
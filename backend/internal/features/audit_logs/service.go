@@ -76,6 +76,11 @@ func (s *AuditLogService) GetUserAuditLogs(
 	user *user_models.User,
 	request *GetAuditLogsRequest,
 ) (*GetAuditLogsResponse, error) {
+	// API keys are scoped to a single workspace, so user logs are not available for them
+	if user.IsAPIKey() {
+		return nil, ErrInsufficientPermissionsToViewLogs
+	}
+
 	// Users can view their own logs, ADMIN can view any user's logs
 	if user.Role != user_enums.UserRoleAdmin && user.ID != targetUserID {
 		return nil, ErrInsufficientPermissionsToViewLogs
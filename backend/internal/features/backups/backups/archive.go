@@ -0,0 +1,182 @@
+package backups
+
+import (
+	"fmt"
+	"time"
+
+	"databasus-backend/internal/config"
+	backups_config "databasus-backend/internal/features/backups/config"
+	"databasus-backend/internal/features/storages"
+)
+
+// archiveOldBackups moves completed backups older than ArchiveAfterDays from
+// the primary storage to the archive storage of their backup config. The
+// backup keeps its ID and encryption metadata, only the storage is changed,
+// so restores read archived backups from the archive storage transparently
+func (s *BackupService) archiveOldBackups() error {
+	backupConfigs, err := s.backupConfigService.GetBackupConfigsWithArchiving()
+	if err != nil {
+		return err
+	}
+
+	for _, backupConfig := range backupConfigs {
+		if backupConfig.StorageID == nil {
+			continue
+		}
+
+		createdBefore := time.Now().UTC().AddDate(0, 0, -backupConfig.ArchiveAfterDays)
+
+		backups, err := s.backupRepository.FindToArchive(
+			backupConfig.DatabaseID,
+			*backupConfig.StorageID,
+			createdBefore,
+		)
+		if err != nil {
+			s.logger.Error(
+				"Failed to find backups to archive",
+				"databaseId",
+				backupConfig.DatabaseID,
+				"error",
+				err,
+			)
+			continue
+		}
+
+		for _, backup := range backups {
+			if config.IsShouldShutdown() || config.IsDraining() {
+				return nil
+			}
+
+			if err := s.archiveBackup(backupConfig, backup); err != nil {
+				s.logger.Error(
+					"Failed to archive backup",
+					"backupId",
+					backup.ID,
+					"storageId",
+					*backupConfig.ArchiveStorageID,
+					"error",
+					err,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// archiveBackup copies the backup file into the archive storage, verifies
+// the size of the archived file, switches the backup to the archive storage
+// and only then deletes the source file
+func (s *BackupService) archiveBackup(
+	backupConfig *backups_config.BackupConfig,
+	backup *Backup,
+) error {
+	sourceStorage, err := s.storageService.GetStorageByID(backup.StorageID)
+	if err != nil {
+		return fmt.Errorf("failed to get source storage: %w", err)
+	}
+
+	archiveStorage, err := s.storageService.GetStorageByID(*backupConfig.ArchiveStorageID)
+	if err != nil {
+		return fmt.Errorf("failed to get archive storage: %w", err)
+	}
+
+	// the source file cannot be deleted while it is locked, so the backup
+	// is archived after the lock expires
+	lockedUntil, err := sourceStorage.GetFileLockedUntil(s.fieldEncryptor, backup.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check backup file lock: %w", err)
+	}
+
+	if lockedUntil != nil {
+		s.logger.Debug(
+			"Backup file is locked by the storage, archiving is postponed",
+			"backupId",
+			backup.ID,
+			"lockedUntil",
+			lockedUntil,
+		)
+		return nil
+	}
+
+	transferredBytes, err := s.transferBackupFile(
+		backup,
+		backupConfig,
+		sourceStorage,
+		archiveStorage,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkArchivedFileSize(archiveStorage, backup, transferredBytes); err != nil {
+		_ = archiveStorage.DeleteFile(s.fieldEncryptor, backup.ID)
+		return err
+	}
+
+	isUpdated, err := s.backupRepository.UpdateStorage(
+		backup.ID,
+		sourceStorage.ID,
+		archiveStorage.ID,
+		time.Now().UTC(),
+	)
+	if err != nil {
+		_ = archiveStorage.DeleteFile(s.fieldEncryptor, backup.ID)
+		return err
+	}
+
+	if !isUpdated {
+		// the backup has been deleted while it was copied
+		_ = archiveStorage.DeleteFile(s.fieldEncryptor, backup.ID)
+		return nil
+	}
+
+	// the backup is already read from the archive storage, so a file left
+	// in the source storage does not break restores
+	if err := sourceStorage.DeleteFile(s.fieldEncryptor, backup.ID); err != nil {
+		s.logger.Warn(
+			"Failed to delete archived backup file from source storage",
+			"backupId",
+			backup.ID,
+			"storageId",
+			sourceStorage.ID,
+			"error",
+			err,
+		)
+	}
+
+	s.logger.Info(
+		"Backup archived",
+		"backupId",
+		backup.ID,
+		"storageId",
+		archiveStorage.ID,
+		"sizeBytes",
+		transferredBytes,
+	)
+
+	return nil
+}
+
+// checkArchivedFileSize compares the size of the archived file reported by
+// the archive storage with the number of bytes read from the source storage
+func (s *BackupService) checkArchivedFileSize(
+	archiveStorage *storages.Storage,
+	backup *Backup,
+	expectedBytes int64,
+) error {
+	archivedBytes, err := archiveStorage.GetFileSize(s.fieldEncryptor, backup.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get archived backup file size: %w", err)
+	}
+
+	if archivedBytes != expectedBytes {
+		return fmt.Errorf(
+			"archived backup file size mismatch: expected %d bytes, got %d",
+			expectedBytes,
+			archivedBytes,
+		)
+	}
+
+	return nil
+}
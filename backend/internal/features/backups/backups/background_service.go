@@ -3,48 +3,103 @@ package backups
 import (
 	"databasus-backend/internal/config"
 	backups_config "databasus-backend/internal/features/backups/config"
-	"databasus-backend/internal/features/storages"
-	"databasus-backend/internal/util/encryption"
-	"databasus-backend/internal/util/period"
+	backups_pauses "databasus-backend/internal/features/backups/pauses"
+	"databasus-backend/internal/util/jobs"
+	"fmt"
 	"log/slog"
+	"sync/atomic"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type BackupBackgroundService struct {
 	backupService       *BackupService
 	backupRepository    *BackupRepository
 	backupConfigService *backups_config.BackupConfigService
-	storageService      *storages.StorageService
+	backupPauseService  *backups_pauses.BackupPauseService
 
 	lastBackupTime time.Time
-	logger         *slog.Logger
+	// schedule key (see BackupConfig.GetScheduleKey) -> newest backup kept by
+	// KeepMinBackupsCount the last notification was sent for, to notify only
+	// when it changes
+	keptBackupNotifications map[uuid.UUID]uuid.UUID
+	logger                  *slog.Logger
+
+	// archiving and replication of large backups take long, so they run in
+	// background and are not started again until the previous run is finished
+	isArchivingRunning   atomic.Bool
+	isReplicationRunning atomic.Bool
 }
 
 func (s *BackupBackgroundService) Run() {
 	s.lastBackupTime = time.Now().UTC()
 
-	if err := s.failBackupsInProgress(); err != nil {
-		s.logger.Error("Failed to fail backups in progress", "error", err)
+	if err := s.requeueInterruptedBackups(); err != nil {
+		s.logger.Error("Failed to requeue interrupted backups", "error", err)
+		panic(err)
+	}
+
+	if err := s.backupService.requeueInterruptedBackupCopies(); err != nil {
+		s.logger.Error("Failed to requeue interrupted backup copies", "error", err)
 		panic(err)
 	}
 
-	if config.IsShouldShutdown() {
+	if err := s.backupService.resetInterruptedIntegrityChecks(); err != nil {
+		s.logger.Error("Failed to reset interrupted integrity checks", "error", err)
+		panic(err)
+	}
+
+	if config.IsDraining() {
 		return
 	}
 
+	// backups queued before restart are started again
+	s.backupService.DispatchQueuedBackups()
+
 	for {
-		if config.IsShouldShutdown() {
+		if config.IsDraining() {
 			return
 		}
 
+		// expired pins are released first, so retention removes the
+		// backups in the same run
+		if err := s.backupService.releaseExpiredPins(); err != nil {
+			s.logger.Error("Failed to release expired backup pins", "error", err)
+		}
+
 		if err := s.cleanOldBackups(); err != nil {
 			s.logger.Error("Failed to clean old backups", "error", err)
 		}
 
+		if err := s.backupService.deleteBackupsWithExpiredLock(); err != nil {
+			s.logger.Error("Failed to delete backups with expired storage lock", "error", err)
+		}
+
+		s.startArchiving()
+
+		if err := s.backupPauseService.ResumeExpiredPauses(); err != nil {
+			s.logger.Error("Failed to resume expired backup pauses", "error", err)
+		}
+
 		if err := s.runPendingBackups(); err != nil {
 			s.logger.Error("Failed to run pending backups", "error", err)
 		}
 
+		// leases of backups interrupted right before startup expire only
+		// after it, so they are checked on each run
+		if err := s.requeueInterruptedBackups(); err != nil {
+			s.logger.Error("Failed to requeue interrupted backups", "error", err)
+		}
+
+		s.backupService.DispatchQueuedBackups()
+
+		if err := s.backupService.requeueInterruptedBackupCopies(); err != nil {
+			s.logger.Error("Failed to requeue interrupted backup copies", "error", err)
+		}
+
+		s.startReplication()
+
 		s.lastBackupTime = time.Now().UTC()
 		time.Sleep(1 * time.Minute)
 	}
@@ -55,20 +110,52 @@ func (s *BackupBackgroundService) IsBackupsWorkerRunning() bool {
 	return s.lastBackupTime.After(time.Now().UTC().Add(-5 * time.Minute))
 }
 
-func (s *BackupBackgroundService) failBackupsInProgress() error {
-	backupsInProgress, err := s.backupRepository.FindByStatus(BackupStatusInProgress)
+// requeueInterruptedBackups queues again backups whose lease expired because
+// the instance running them crashed or was restarted. Backups which used all
+// attempts are failed
+func (s *BackupBackgroundService) requeueInterruptedBackups() error {
+	now := time.Now().UTC()
+
+	interruptedBackups, err := s.backupRepository.FindWithExpiredLease(now)
 	if err != nil {
 		return err
 	}
 
-	for _, backup := range backupsInProgress {
-		backupConfig, err := s.backupConfigService.GetBackupConfigByDbId(backup.DatabaseID)
+	for _, backup := range interruptedBackups {
+		// still running in this instance, only the heartbeat is late
+		if s.backupService.workerPool.IsRunning(backup.ID) {
+			continue
+		}
+
+		if backup.Attempts < jobs.MaxAttempts {
+			isRequeued, err := s.backupRepository.RequeueExpired(backup.ID, now)
+			if err != nil {
+				return err
+			}
+
+			if isRequeued {
+				s.logger.Info(
+					"Interrupted backup queued again",
+					"backupId",
+					backup.ID,
+					"attempts",
+					backup.Attempts,
+				)
+			}
+
+			continue
+		}
+
+		backupConfig, err := s.backupService.GetBackupConfigOfBackup(backup)
 		if err != nil {
 			s.logger.Error("Failed to get backup config by database ID", "error", err)
 			continue
 		}
 
-		failMessage := "Backup failed due to application restart"
+		failMessage := fmt.Sprintf(
+			"Backup failed after %d attempts interrupted by application restart",
+			backup.Attempts,
+		)
 		backup.FailMessage = &failMessage
 		backup.Status = BackupStatusFailed
 		backup.BackupSizeMb = 0
@@ -94,19 +181,17 @@ func (s *BackupBackgroundService) cleanOldBackups() error {
 		return err
 	}
 
-	for _, backupConfig := range enabledBackupConfigs {
-		backupStorePeriod := backupConfig.StorePeriod
-
-		if backupStorePeriod == period.PeriodForever {
-			continue
-		}
+	scheduleBackupConfigs, err := s.backupConfigService.GetScheduleBackupConfigsWithEnabledBackups()
+	if err != nil {
+		return err
+	}
 
-		storeDuration := backupStorePeriod.ToDuration()
-		dateBeforeBackupsShouldBeDeleted := time.Now().UTC().Add(-storeDuration)
+	enabledBackupConfigs = append(enabledBackupConfigs, scheduleBackupConfigs...)
+	now := time.Now().UTC()
 
-		oldBackups, err := s.backupRepository.FindBackupsBeforeDate(
-			backupConfig.DatabaseID,
-			dateBeforeBackupsShouldBeDeleted,
+	for _, backupConfig := range enabledBackupConfigs {
+		oldBackups, keptBackups, err := s.backupService.GetBackupsToDeleteByRetention(
+			backupConfig,
 		)
 		if err != nil {
 			s.logger.Error(
@@ -119,27 +204,22 @@ func (s *BackupBackgroundService) cleanOldBackups() error {
 			continue
 		}
 
+		s.notifyAboutKeptBackups(backupConfig, keptBackups)
+
 		for _, backup := range oldBackups {
-			storage, err := s.storageService.GetStorageByID(backup.StorageID)
-			if err != nil {
-				s.logger.Error(
-					"Failed to get storage by ID",
-					"storageId",
-					backup.StorageID,
-					"error",
-					err,
-				)
+			// the file is still locked by the storage, the deletion is retried
+			// by deleteBackupsWithExpiredLock
+			if backup.IsDeletionLocked(now) {
 				continue
 			}
 
-			encryptor := encryption.GetFieldEncryptor()
-			err = storage.DeleteFile(encryptor, backup.ID)
-			if err != nil {
-				s.logger.Error("Failed to delete backup file", "backupId", backup.ID, "error", err)
+			if err := s.backupService.deleteBackup(backup); err != nil {
+				s.logger.Error("Failed to delete old backup", "backupId", backup.ID, "error", err)
+				continue
 			}
 
-			if err := s.backupRepository.DeleteByID(backup.ID); err != nil {
-				s.logger.Error("Failed to delete old backup", "backupId", backup.ID, "error", err)
+			// the deletion is postponed, the file turned out to be locked
+			if backup.IsDeletionLocked(now) {
 				continue
 			}
 
@@ -156,99 +236,234 @@ func (s *BackupBackgroundService) cleanOldBackups() error {
 	return nil
 }
 
+func (s *BackupBackgroundService) startArchiving() {
+	if !s.isArchivingRunning.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		defer s.isArchivingRunning.Store(false)
+
+		if err := s.backupService.archiveOldBackups(); err != nil {
+			s.logger.Error("Failed to archive old backups", "error", err)
+		}
+	}()
+}
+
+func (s *BackupBackgroundService) startReplication() {
+	if !s.isReplicationRunning.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		defer s.isReplicationRunning.Store(false)
+
+		if err := s.backupService.replicatePendingBackupCopies(); err != nil {
+			s.logger.Error("Failed to replicate pending backup copies", "error", err)
+		}
+	}()
+}
+
+// notifyAboutKeptBackups notifies that cleanup skipped expired backups because
+// of KeepMinBackupsCount. Notification is sent once per newest kept backup,
+// so it is repeated only if one more backup expires while runs keep failing
+func (s *BackupBackgroundService) notifyAboutKeptBackups(
+	backupConfig *backups_config.BackupConfig,
+	keptBackups []*Backup,
+) {
+	scheduleKey := backupConfig.GetScheduleKey()
+
+	if len(keptBackups) == 0 {
+		delete(s.keptBackupNotifications, scheduleKey)
+		return
+	}
+
+	newestKeptBackup := keptBackups[0]
+	for _, backup := range keptBackups {
+		if backup.CreatedAt.After(newestKeptBackup.CreatedAt) {
+			newestKeptBackup = backup
+		}
+	}
+
+	if s.keptBackupNotifications[scheduleKey] == newestKeptBackup.ID {
+		return
+	}
+
+	s.keptBackupNotifications[scheduleKey] = newestKeptBackup.ID
+
+	s.logger.Warn(
+		"Cleanup kept expired backups to honor minimum backups count",
+		"databaseId",
+		backupConfig.DatabaseID,
+		"keptBackupsCount",
+		len(keptBackups),
+	)
+
+	message := fmt.Sprintf(
+		"Cleanup skipped %d expired backup(s), because the last %d completed backup(s) "+
+			"are always kept. No newer completed backups exist: check why recent backups fail",
+		len(keptBackups),
+		backupConfig.KeepMinBackupsCount,
+	)
+
+	s.backupService.SendBackupNotification(
+		backupConfig,
+		newestKeptBackup,
+		backups_config.NotificationBackupCleanupSkipped,
+		&message,
+	)
+}
+
+// runPendingBackups queues backups of configs and backup schedules whose next
+// scheduled run is reached and computes their following run
 func (s *BackupBackgroundService) runPendingBackups() error {
-	enabledBackupConfigs, err := s.backupConfigService.GetBackupConfigsWithEnabledBackups()
+	now := time.Now().UTC()
+
+	dueBackupConfigs, err := s.backupConfigService.GetBackupConfigsDueForBackup(now)
 	if err != nil {
 		return err
 	}
 
-	for _, backupConfig := range enabledBackupConfigs {
+	for _, backupConfig := range dueBackupConfigs {
 		if backupConfig.BackupInterval == nil {
 			continue
 		}
 
-		lastBackup, err := s.backupRepository.FindLastByDatabaseID(backupConfig.DatabaseID)
-		if err != nil {
-			s.logger.Error(
-				"Failed to get last backup for database",
-				"databaseId",
+		isBackupDue := true
+		nextRunAfter := now
+
+		// the next run is not computed yet (new config or changed interval),
+		// so it is derived from the last backup
+		if backupConfig.NextRunAt == nil {
+			lastBackup, err := s.backupRepository.FindLastByDatabaseIDAndScheduleID(
 				backupConfig.DatabaseID,
-				"error",
-				err,
+				backupConfig.ScheduleID,
 			)
-			continue
-		}
+			if err != nil {
+				s.logger.Error(
+					"Failed to get last backup for database",
+					"databaseId",
+					backupConfig.DatabaseID,
+					"error",
+					err,
+				)
+				continue
+			}
 
-		var lastBackupTime *time.Time
-		if lastBackup != nil {
-			lastBackupTime = &lastBackup.CreatedAt
+			var lastBackupTime *time.Time
+			if lastBackup != nil {
+				lastBackupTime = &lastBackup.CreatedAt
+			}
+
+			isBackupDue = backupConfig.BackupInterval.ShouldTriggerBackup(now, lastBackupTime) ||
+				s.backupService.GetRemainedBackupTryCount(lastBackup) > 0
+			if !isBackupDue {
+				nextRunAfter = lastBackup.CreatedAt
+			}
 		}
 
-		remainedBackupTryCount := s.GetRemainedBackupTryCount(lastBackup)
+		if isBackupDue {
+			database, err := s.backupService.databaseService.GetDatabaseByID(
+				backupConfig.DatabaseID,
+			)
+			if err != nil {
+				s.logger.Error(
+					"Failed to get database",
+					"databaseId",
+					backupConfig.DatabaseID,
+					"error",
+					err,
+				)
+				continue
+			}
+
+			blockReason, err := s.backupService.getScheduledBackupBlockReason(
+				backupConfig,
+				database,
+				now,
+			)
+			if err != nil {
+				s.logger.Error(
+					"Failed to check whether scheduled backup can be started",
+					"databaseId",
+					backupConfig.DatabaseID,
+					"error",
+					err,
+				)
+				continue
+			}
+
+			// the next run is not moved, so the backup is queued right after
+			// the blackout window or the pause ends
+			if blockReason != "" {
+				s.logger.Debug(
+					"Scheduled backup is postponed",
+					"databaseId",
+					backupConfig.DatabaseID,
+					"scheduleId",
+					backupConfig.ScheduleID,
+					"reason",
+					blockReason,
+				)
+				continue
+			}
 
-		if backupConfig.BackupInterval.ShouldTriggerBackup(time.Now().UTC(), lastBackupTime) ||
-			remainedBackupTryCount > 0 {
 			s.logger.Info(
-				"Triggering scheduled backup",
+				"Queueing scheduled backup",
 				"databaseId",
 				backupConfig.DatabaseID,
+				"scheduleId",
+				backupConfig.ScheduleID,
 				"intervalType",
 				backupConfig.BackupInterval.Interval,
 			)
 
-			go s.backupService.MakeBackup(backupConfig.DatabaseID, remainedBackupTryCount == 1)
+			// the next run is not moved, so the backup is queued again on the
+			// next run (e.g. after the previous backup is finished)
+			if err := s.backupService.EnqueueBackup(
+				backupConfig.DatabaseID,
+				backupConfig.ScheduleID,
+				false,
+			); err != nil {
+				s.logger.Warn(
+					"Scheduled backup is not queued",
+					"databaseId",
+					backupConfig.DatabaseID,
+					"error",
+					err,
+				)
+				continue
+			}
+
 			s.logger.Info(
-				"Successfully triggered scheduled backup",
+				"Successfully queued scheduled backup",
 				"databaseId",
 				backupConfig.DatabaseID,
 			)
 		}
-	}
-
-	return nil
-}
-
-// GetRemainedBackupTryCount returns the number of remaining backup tries for a given backup.
-// If the backup is not failed or the backup config does not allow retries, it returns 0.
-// If the backup is failed and the backup config allows retries, it returns the number of remaining tries.
-// If the backup is failed and the backup config does not allow retries, it returns 0.
-func (s *BackupBackgroundService) GetRemainedBackupTryCount(lastBackup *Backup) int {
-	if lastBackup == nil {
-		return 0
-	}
-
-	if lastBackup.Status != BackupStatusFailed {
-		return 0
-	}
-
-	backupConfig, err := s.backupConfigService.GetBackupConfigByDbId(lastBackup.DatabaseID)
-	if err != nil {
-		s.logger.Error("Failed to get backup config by database ID", "error", err)
-		return 0
-	}
-
-	if !backupConfig.IsRetryIfFailed {
-		return 0
-	}
 
-	maxFailedTriesCount := backupConfig.MaxFailedTriesCount
-
-	lastBackups, err := s.backupRepository.FindByDatabaseIDWithLimit(
-		lastBackup.DatabaseID,
-		maxFailedTriesCount,
-	)
-	if err != nil {
-		s.logger.Error("Failed to find last backups by database ID", "error", err)
-		return 0
-	}
-
-	lastFailedBackups := make([]*Backup, 0)
+		nextRunAt, err := backupConfig.BackupInterval.NextRunAfter(nextRunAfter)
+		if err != nil {
+			s.logger.Error(
+				"Failed to compute next backup run",
+				"databaseId",
+				backupConfig.DatabaseID,
+				"error",
+				err,
+			)
+			continue
+		}
 
-	for _, backup := range lastBackups {
-		if backup.Status == BackupStatusFailed {
-			lastFailedBackups = append(lastFailedBackups, backup)
+		if err := s.backupConfigService.SetNextRunAt(backupConfig, nextRunAt); err != nil {
+			s.logger.Error(
+				"Failed to save next backup run",
+				"databaseId",
+				backupConfig.DatabaseID,
+				"error",
+				err,
+			)
 		}
 	}
 
-	return maxFailedTriesCount - len(lastFailedBackups)
+	return nil
 }
@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -387,3 +388,66 @@ func Test_MakeBackgroundBackupWhenBakupsDisabled_BackupSkipped(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, backups, 1)
 }
+
+func Test_CleanOldBackupsWhenRecentBackupsFailed_LastCompletedBackupsKept(t *testing.T) {
+	// setup data
+	user := users_testing.CreateTestUser(users_enums.UserRoleAdmin)
+	router := CreateTestRouter()
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", user, router)
+	storage := storages.CreateTestStorage(workspace.ID)
+	notifier := notifiers.CreateTestNotifier(workspace.ID)
+	database := databases.CreateTestDatabase(workspace.ID, storage, notifier)
+
+	defer func() {
+		// cleanup backups first
+		backups, _ := backupRepository.FindByDatabaseID(database.ID)
+		for _, backup := range backups {
+			backupRepository.DeleteByID(backup.ID)
+		}
+
+		databases.RemoveTestDatabase(database)
+		time.Sleep(50 * time.Millisecond)
+		notifiers.RemoveTestNotifier(notifier)
+		storages.RemoveTestStorage(storage.ID)
+		workspaces_testing.RemoveTestWorkspace(workspace, router)
+	}()
+
+	backupConfig := backups_config.EnableBackupsForTestDatabase(database.ID, storage)
+	backupConfig.StorePeriod = period.PeriodDay
+	backupConfig.KeepMinBackupsCount = 2
+
+	_, err := backups_config.GetBackupConfigService().SaveBackupConfig(backupConfig)
+	assert.NoError(t, err)
+
+	// 3 expired completed backups and a recent failed one
+	for _, age := range []time.Duration{72 * time.Hour, 96 * time.Hour, 120 * time.Hour} {
+		err := backupRepository.Save(&Backup{
+			ID:         uuid.New(),
+			DatabaseID: database.ID,
+			StorageID:  storage.ID,
+			Status:     BackupStatusCompleted,
+			CreatedAt:  time.Now().UTC().Add(-age),
+		})
+		assert.NoError(t, err)
+	}
+
+	err = backupRepository.Save(&Backup{
+		ID:         uuid.New(),
+		DatabaseID: database.ID,
+		StorageID:  storage.ID,
+		Status:     BackupStatusFailed,
+		CreatedAt:  time.Now().UTC().Add(-1 * time.Hour),
+	})
+	assert.NoError(t, err)
+
+	err = GetBackupBackgroundService().cleanOldBackups()
+	assert.NoError(t, err)
+
+	backups, err := backupRepository.FindByDatabaseID(database.ID)
+	assert.NoError(t, err)
+	assert.Len(t, backups, 3)
+
+	assert.Equal(t, BackupStatusFailed, backups[0].Status)
+	assert.Equal(t, BackupStatusCompleted, backups[1].Status)
+	assert.Equal(t, BackupStatusCompleted, backups[2].Status)
+}
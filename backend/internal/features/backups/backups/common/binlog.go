@@ -0,0 +1,98 @@
+package common
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// binlog coordinates are written by mysqldump/mariadb-dump into the dump
+// header, so only the beginning of the dump is inspected
+const binlogCoordinatesHeaderLimit = 1024 * 1024
+
+var (
+	// MySQL 8.0.26+ prints CHANGE REPLICATION SOURCE, older versions and
+	// MariaDB print CHANGE MASTER
+	binlogPositionRegex = regexp.MustCompile(
+		`CHANGE (?:MASTER|REPLICATION SOURCE) TO (?:MASTER|SOURCE)_LOG_FILE='([^']+)', (?:MASTER|SOURCE)_LOG_POS=(\d+)`,
+	)
+	mysqlGtidSetRegex   = regexp.MustCompile(`GTID_PURGED=(?:/\*!\d+ '\+'\*/ )?'([^']*)'`)
+	mariadbGtidSetRegex = regexp.MustCompile(`gtid_slave_pos='([^']*)'`)
+)
+
+// BinlogCoordinates is the position in binary logs the dump is consistent
+// with. Binary logs are replayed from this position during point-in-time
+// recovery
+type BinlogCoordinates struct {
+	File     string
+	Position int64
+	GtidSet  *string
+}
+
+// BinlogCoordinatesReader passes the dump through and keeps its header
+// to find binlog coordinates after the dump is completed
+type BinlogCoordinatesReader struct {
+	reader io.Reader
+	header bytes.Buffer
+}
+
+func NewBinlogCoordinatesReader(reader io.Reader) *BinlogCoordinatesReader {
+	return &BinlogCoordinatesReader{reader: reader}
+}
+
+func (r *BinlogCoordinatesReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+
+	if n > 0 && r.header.Len() < binlogCoordinatesHeaderLimit {
+		remaining := binlogCoordinatesHeaderLimit - r.header.Len()
+		r.header.Write(p[:min(n, remaining)])
+	}
+
+	return n, err
+}
+
+// GetCoordinates returns nil if the dump header has no binlog position
+// (e.g. binary logging is disabled on the server)
+func (r *BinlogCoordinatesReader) GetCoordinates() *BinlogCoordinates {
+	return ParseBinlogCoordinates(r.header.String())
+}
+
+func ParseBinlogCoordinates(dumpHeader string) *BinlogCoordinates {
+	positionMatch := binlogPositionRegex.FindStringSubmatch(dumpHeader)
+	if positionMatch == nil {
+		return nil
+	}
+
+	position, err := strconv.ParseInt(positionMatch[2], 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	coordinates := &BinlogCoordinates{
+		File:     positionMatch[1],
+		Position: position,
+	}
+
+	gtidMatch := mysqlGtidSetRegex.FindStringSubmatch(dumpHeader)
+	if gtidMatch == nil {
+		gtidMatch = mariadbGtidSetRegex.FindStringSubmatch(dumpHeader)
+	}
+
+	if gtidMatch != nil {
+		// MySQL splits long GTID sets into several lines
+		gtidSet := strings.Join(strings.Fields(gtidMatch[1]), "")
+		if gtidSet != "" {
+			coordinates.GtidSet = &gtidSet
+		}
+	}
+
+	return coordinates
+}
+
+func (m *BackupMetadata) SetBinlogCoordinates(coordinates *BinlogCoordinates) {
+	m.BinlogFile = &coordinates.File
+	m.BinlogPosition = &coordinates.Position
+	m.GtidSet = coordinates.GtidSet
+}
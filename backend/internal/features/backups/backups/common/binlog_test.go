@@ -0,0 +1,82 @@
+package common
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseBinlogCoordinates_WhenMysqlDumpHeader_ReturnsPositionAndGtidSet(t *testing.T) {
+	dumpHeader := `-- MySQL dump 10.13  Distrib 8.0.36, for Linux (x86_64)
+SET @@GLOBAL.GTID_PURGED=/*!80000 '+'*/ '3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5,
+4a22fb58-71ca-11e1-9e33-c80aa9429562:1-12';
+--
+-- Position to start replication or point-in-time recovery from
+--
+
+-- CHANGE REPLICATION SOURCE TO SOURCE_LOG_FILE='binlog.000042', SOURCE_LOG_POS=1573;
+`
+
+	coordinates := ParseBinlogCoordinates(dumpHeader)
+
+	assert.NotNil(t, coordinates)
+	assert.Equal(t, "binlog.000042", coordinates.File)
+	assert.Equal(t, int64(1573), coordinates.Position)
+	assert.NotNil(t, coordinates.GtidSet)
+	assert.Equal(
+		t,
+		"3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5,4a22fb58-71ca-11e1-9e33-c80aa9429562:1-12",
+		*coordinates.GtidSet,
+	)
+}
+
+func Test_ParseBinlogCoordinates_WhenMariadbDumpHeader_ReturnsPositionAndGtidSet(t *testing.T) {
+	dumpHeader := `-- MariaDB dump 10.19  Distrib 10.11.6-MariaDB
+--
+-- Preferably set GTID position
+--
+
+-- SET GLOBAL gtid_slave_pos='0-1-1234';
+-- CHANGE MASTER TO MASTER_LOG_FILE='mysql-bin.000007', MASTER_LOG_POS=328;
+`
+
+	coordinates := ParseBinlogCoordinates(dumpHeader)
+
+	assert.NotNil(t, coordinates)
+	assert.Equal(t, "mysql-bin.000007", coordinates.File)
+	assert.Equal(t, int64(328), coordinates.Position)
+	assert.NotNil(t, coordinates.GtidSet)
+	assert.Equal(t, "0-1-1234", *coordinates.GtidSet)
+}
+
+func Test_ParseBinlogCoordinates_WhenBinaryLoggingDisabled_ReturnsNil(t *testing.T) {
+	dumpHeader := `-- MySQL dump 10.13  Distrib 8.0.36, for Linux (x86_64)
+/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;
+`
+
+	assert.Nil(t, ParseBinlogCoordinates(dumpHeader))
+}
+
+func Test_BinlogCoordinatesReader_WhenDumpIsRead_PassesDataThroughAndFindsCoordinates(
+	t *testing.T,
+) {
+	dump := "-- CHANGE MASTER TO MASTER_LOG_FILE='mysql-bin.000003', MASTER_LOG_POS=4;\n" +
+		strings.Repeat("INSERT INTO t VALUES (1);\n", 100000)
+
+	reader := NewBinlogCoordinatesReader(strings.NewReader(dump))
+
+	var output bytes.Buffer
+	_, err := io.Copy(&output, reader)
+
+	assert.NoError(t, err)
+	assert.Equal(t, dump, output.String())
+
+	coordinates := reader.GetCoordinates()
+	assert.NotNil(t, coordinates)
+	assert.Equal(t, "mysql-bin.000003", coordinates.File)
+	assert.Equal(t, int64(4), coordinates.Position)
+	assert.Nil(t, coordinates.GtidSet)
+}
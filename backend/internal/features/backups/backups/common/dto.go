@@ -7,11 +7,38 @@ type BackupType string
 const (
 	BackupTypeDefault   BackupType = "DEFAULT"   // For MySQL, MongoDB, PostgreSQL legacy (-Fc)
 	BackupTypeDirectory BackupType = "DIRECTORY" // PostgreSQL directory type (-Fd)
+	// PostgreSQL pg_basebackup tar (-Ft) without WAL, used as a base for
+	// point-in-time recovery together with the archived WAL segments
+	BackupTypeBaseBackup BackupType = "BASE_BACKUP"
+	// PostgreSQL pg_basebackup tar (-Ft) with the WAL required to make
+	// the data directory consistent, restorable on its own
+	BackupTypePhysical BackupType = "PHYSICAL"
+	// MongoDB mongodump archive of the whole instance taken with --oplog,
+	// used as a base for point-in-time recovery together with the archived
+	// oplog slices
+	BackupTypeOplogDump BackupType = "OPLOG_DUMP"
+	// PostgreSQL tar archive of globals.sql (pg_dumpall --globals-only) and
+	// custom format dump (-Fc) of every database of the server
+	BackupTypeServerDump BackupType = "SERVER_DUMP"
 )
 
+// IsDataDirectoryArchive returns true for tar archives of a PostgreSQL data
+// directory, which are restored by file layout rather than by restore tools
+func (t BackupType) IsDataDirectoryArchive() bool {
+	return t == BackupTypeBaseBackup || t == BackupTypePhysical
+}
+
 type BackupMetadata struct {
 	EncryptionSalt *string
 	EncryptionIV   *string
 	Encryption     backups_config.BackupEncryption
 	Type           BackupType
+	WalStartLsn    *string
+	WalStopLsn     *string
+	// binlog coordinates of MySQL and MariaDB dumps taken with binlog archiving
+	BinlogFile     *string
+	BinlogPosition *int64
+	GtidSet        *string
+	// hex encoded SHA-256 of the backup file content before encryption
+	Checksum *string
 }
@@ -0,0 +1,44 @@
+package common
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Layout of SERVER_DUMP backups: tar archive with globals (roles and
+// tablespaces) first, followed by custom format dump of each database
+const (
+	ServerDumpGlobalsEntry  = "globals.sql"
+	ServerDumpEntryFileMode = 0600
+
+	serverDumpDatabasesDir = "databases/"
+	serverDumpDatabaseExt  = ".dump"
+)
+
+// ServerDumpDatabaseEntry returns the archive entry name of the database dump.
+// Database names are escaped because they may contain any characters,
+// including slashes
+func ServerDumpDatabaseEntry(databaseName string) string {
+	return serverDumpDatabasesDir + url.PathEscape(databaseName) + serverDumpDatabaseExt
+}
+
+// ParseServerDumpDatabaseEntry returns the database name of the archive entry
+// or false if the entry is not a database dump
+func ParseServerDumpDatabaseEntry(entryName string) (string, bool) {
+	escapedName, ok := strings.CutPrefix(entryName, serverDumpDatabasesDir)
+	if !ok {
+		return "", false
+	}
+
+	escapedName, ok = strings.CutSuffix(escapedName, serverDumpDatabaseExt)
+	if !ok || escapedName == "" {
+		return "", false
+	}
+
+	databaseName, err := url.PathUnescape(escapedName)
+	if err != nil {
+		return "", false
+	}
+
+	return databaseName, true
+}
@@ -0,0 +1,29 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ServerDumpDatabaseEntry_WhenNameHasSpecialCharacters_NameRoundTripped(t *testing.T) {
+	for _, databaseName := range []string{"shop", "my db", "a/b", "100%", "Ünicode"} {
+		entryName := ServerDumpDatabaseEntry(databaseName)
+
+		parsedName, ok := ParseServerDumpDatabaseEntry(entryName)
+
+		assert.True(t, ok)
+		assert.Equal(t, databaseName, parsedName)
+		assert.NotContains(t, entryName[len("databases/"):], "/")
+	}
+}
+
+func Test_ParseServerDumpDatabaseEntry_WhenNotDatabaseDump_ReturnsFalse(t *testing.T) {
+	entryNames := []string{ServerDumpGlobalsEntry, "databases/", "databases/.dump", "shop.dump"}
+
+	for _, entryName := range entryNames {
+		_, ok := ParseServerDumpDatabaseEntry(entryName)
+
+		assert.False(t, ok, entryName)
+	}
+}
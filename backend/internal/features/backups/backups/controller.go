@@ -1,6 +1,8 @@
 package backups
 
 import (
+	common "databasus-backend/internal/features/backups/backups/common"
+	backups_config "databasus-backend/internal/features/backups/config"
 	"databasus-backend/internal/features/databases"
 	users_middleware "databasus-backend/internal/features/users/middleware"
 	"fmt"
@@ -21,6 +23,10 @@ func (c *BackupController) RegisterRoutes(router *gin.RouterGroup) {
 	router.GET("/backups/:id/file", c.GetFile)
 	router.DELETE("/backups/:id", c.DeleteBackup)
 	router.POST("/backups/:id/cancel", c.CancelBackup)
+	router.POST("/backups/:id/verify", c.VerifyBackup)
+	router.POST("/backups/:id/pin", c.PinBackup)
+	router.POST("/backups/:id/unpin", c.UnpinBackup)
+	router.POST("/backups/retention/preview", c.PreviewRetention)
 }
 
 // GetBackups
@@ -128,6 +134,39 @@ func (c *BackupController) DeleteBackup(ctx *gin.Context) {
 	ctx.Status(http.StatusNoContent)
 }
 
+// PreviewRetention
+// @Summary Preview retention policy
+// @Description Show which backups of the database would be deleted by the retention policy of the backup config before it is saved. Nothing is deleted
+// @Tags backups
+// @Accept json
+// @Produce json
+// @Param request body backups_config.BackupConfig true "Backup config with retention policy"
+// @Success 200 {object} PreviewRetentionResponse
+// @Failure 400
+// @Failure 401
+// @Router /backups/retention/preview [post]
+func (c *BackupController) PreviewRetention(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var request backups_config.BackupConfig
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := c.backupService.PreviewRetentionWithAuth(user, &request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
 // CancelBackup
 // @Summary Cancel an in-progress backup
 // @Description Cancel a backup that is currently in progress
@@ -159,6 +198,106 @@ func (c *BackupController) CancelBackup(ctx *gin.Context) {
 	ctx.Status(http.StatusNoContent)
 }
 
+// VerifyBackup
+// @Summary Verify backup integrity
+// @Description Start re-downloading the backup file and comparing its SHA-256 with the checksum computed during the backup. The result is available in integrity fields of the backup
+// @Tags backups
+// @Param id path string true "Backup ID"
+// @Success 204
+// @Failure 400
+// @Failure 401
+// @Router /backups/{id}/verify [post]
+func (c *BackupController) VerifyBackup(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid backup ID"})
+		return
+	}
+
+	if err := c.backupService.VerifyBackupWithAuth(user, id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// PinBackup
+// @Summary Pin a backup
+// @Description Keep the backup regardless of retention until pinnedUntil or until it is unpinned (legal hold). Pinned backups cannot be deleted. Changing the pin of a pinned backup requires workspace admin rights
+// @Tags backups
+// @Accept json
+// @Produce json
+// @Param id path string true "Backup ID"
+// @Param request body PinBackupRequest true "Pin reason and optional expiry"
+// @Success 200 {object} Backup
+// @Failure 400
+// @Failure 401
+// @Router /backups/{id}/pin [post]
+func (c *BackupController) PinBackup(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid backup ID"})
+		return
+	}
+
+	var request PinBackupRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	backup, err := c.backupService.PinBackupWithAuth(user, id, &request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, backup)
+}
+
+// UnpinBackup
+// @Summary Unpin a backup
+// @Description Lift the hold of a pinned backup, so retention and deletion apply again. Only workspace admins can unpin backups
+// @Tags backups
+// @Param id path string true "Backup ID"
+// @Success 204
+// @Failure 400
+// @Failure 401
+// @Router /backups/{id}/unpin [post]
+func (c *BackupController) UnpinBackup(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid backup ID"})
+		return
+	}
+
+	if err := c.backupService.UnpinBackupWithAuth(user, id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
 // GetFile
 // @Summary Download a backup file
 // @Description Download the backup file for the specified backup
@@ -224,6 +363,9 @@ func (c *BackupController) generateBackupFilename(
 
 	// Determine extension based on database type
 	extension := c.getBackupExtension(database.Type)
+	if backup.Type.IsDataDirectoryArchive() || backup.Type == common.BackupTypeServerDump {
+		extension = ".tar"
+	}
 
 	return fmt.Sprintf("%s_backup_%s%s", safeName, timestamp, extension)
 }
@@ -2,6 +2,8 @@ package backups
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -28,6 +30,7 @@ import (
 	workspaces_models "databasus-backend/internal/features/workspaces/models"
 	workspaces_testing "databasus-backend/internal/features/workspaces/testing"
 	"databasus-backend/internal/util/encryption"
+	"databasus-backend/internal/util/period"
 	test_utils "databasus-backend/internal/util/testing"
 	"databasus-backend/internal/util/tools"
 )
@@ -494,6 +497,274 @@ func Test_DownloadBackup_AuditLogWritten(t *testing.T) {
 	assert.True(t, found, "Audit log for backup download not found")
 }
 
+func Test_VerifyBackup_WhenChecksumMatches_BackupMarkedAsValid(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	_, backup := createTestDatabaseWithBackups(workspace, owner, router)
+
+	hash := sha256.Sum256([]byte("dummy backup content for testing"))
+	checksum := hex.EncodeToString(hash[:])
+	backup.Checksum = &checksum
+
+	repo := &BackupRepository{}
+	assert.NoError(t, repo.Save(backup))
+
+	status, err := GetBackupService().VerifyBackup(backup)
+	assert.NoError(t, err)
+	assert.Equal(t, BackupIntegrityStatusValid, status)
+
+	verifiedBackup, err := repo.FindByID(backup.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, BackupIntegrityStatusValid, *verifiedBackup.IntegrityStatus)
+	assert.NotNil(t, verifiedBackup.IntegrityCheckedAt)
+}
+
+func Test_VerifyBackup_WhenChecksumDiffers_BackupMarkedAsCorrupted(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	_, backup := createTestDatabaseWithBackups(workspace, owner, router)
+
+	hash := sha256.Sum256([]byte("other content"))
+	checksum := hex.EncodeToString(hash[:])
+	backup.Checksum = &checksum
+
+	repo := &BackupRepository{}
+	assert.NoError(t, repo.Save(backup))
+
+	status, err := GetBackupService().VerifyBackup(backup)
+	assert.NoError(t, err)
+	assert.Equal(t, BackupIntegrityStatusCorrupted, status)
+
+	verifiedBackup, err := repo.FindByID(backup.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, BackupIntegrityStatusCorrupted, *verifiedBackup.IntegrityStatus)
+	assert.Contains(t, *verifiedBackup.IntegrityMessage, "checksum mismatch")
+}
+
+func Test_VerifyBackup_WhenBackupFileDeleted_BackupMarkedAsMissing(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	_, backup := createTestDatabaseWithBackups(workspace, owner, router)
+
+	hash := sha256.Sum256([]byte("dummy backup content for testing"))
+	checksum := hex.EncodeToString(hash[:])
+	backup.Checksum = &checksum
+
+	repo := &BackupRepository{}
+	assert.NoError(t, repo.Save(backup))
+
+	storage, err := storages.GetStorageService().GetStorageByID(backup.StorageID)
+	assert.NoError(t, err)
+	assert.NoError(t, storage.DeleteFile(encryption.GetFieldEncryptor(), backup.ID))
+
+	status, err := GetBackupService().VerifyBackup(backup)
+	assert.NoError(t, err)
+	assert.Equal(t, BackupIntegrityStatusMissing, status)
+
+	verifiedBackup, err := repo.FindByID(backup.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, BackupIntegrityStatusMissing, *verifiedBackup.IntegrityStatus)
+}
+
+func Test_StartIntegrityVerification_WhenAlreadyVerifying_NotStartedAgain(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	_, backup := createTestDatabaseWithBackups(workspace, owner, router)
+
+	repo := &BackupRepository{}
+
+	isStarted, err := repo.StartIntegrityVerification(backup.ID)
+	assert.NoError(t, err)
+	assert.True(t, isStarted)
+
+	isStarted, err = repo.StartIntegrityVerification(backup.ID)
+	assert.NoError(t, err)
+	assert.False(t, isStarted)
+}
+
+func Test_VerifyBackup_WhenBackupHasNoChecksum_ReturnsBadRequest(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	_, backup := createTestDatabaseWithBackups(workspace, owner, router)
+
+	testResp := test_utils.MakePostRequest(
+		t,
+		router,
+		fmt.Sprintf("/api/v1/backups/%s/verify", backup.ID.String()),
+		"Bearer "+owner.Token,
+		nil,
+		http.StatusBadRequest,
+	)
+
+	assert.Contains(t, string(testResp.Body), "backup has no checksum")
+}
+
+func Test_PinBackup_PinnedBackupCannotBeDeletedUntilUnpinnedByAdmin(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	_, backup := createTestDatabaseWithBackups(workspace, owner, router)
+
+	member := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspaces_testing.AddMemberToWorkspace(
+		workspace,
+		member,
+		users_enums.WorkspaceRoleMember,
+		owner.Token,
+		router,
+	)
+
+	var pinnedBackup Backup
+	test_utils.MakePostRequestAndUnmarshal(
+		t,
+		router,
+		fmt.Sprintf("/api/v1/backups/%s/pin", backup.ID.String()),
+		"Bearer "+member.Token,
+		PinBackupRequest{Reason: "State before GDPR deletion"},
+		http.StatusOK,
+		&pinnedBackup,
+	)
+
+	assert.NotNil(t, pinnedBackup.PinnedAt)
+	assert.Equal(t, "State before GDPR deletion", *pinnedBackup.PinReason)
+	assert.Nil(t, pinnedBackup.PinnedUntil)
+
+	testResp := test_utils.MakeDeleteRequest(
+		t,
+		router,
+		fmt.Sprintf("/api/v1/backups/%s", backup.ID.String()),
+		"Bearer "+owner.Token,
+		http.StatusBadRequest,
+	)
+	assert.Contains(t, string(testResp.Body), "backup is pinned")
+
+	testResp = test_utils.MakePostRequest(
+		t,
+		router,
+		fmt.Sprintf("/api/v1/backups/%s/unpin", backup.ID.String()),
+		"Bearer "+member.Token,
+		nil,
+		http.StatusBadRequest,
+	)
+	assert.Contains(t, string(testResp.Body), "only workspace admins can unpin backups")
+
+	test_utils.MakePostRequest(
+		t,
+		router,
+		fmt.Sprintf("/api/v1/backups/%s/unpin", backup.ID.String()),
+		"Bearer "+owner.Token,
+		nil,
+		http.StatusNoContent,
+	)
+
+	test_utils.MakeDeleteRequest(
+		t,
+		router,
+		fmt.Sprintf("/api/v1/backups/%s", backup.ID.String()),
+		"Bearer "+owner.Token,
+		http.StatusNoContent,
+	)
+
+	auditLogs, err := audit_logs.GetAuditLogService().GetWorkspaceAuditLogs(
+		workspace.ID,
+		&audit_logs.GetAuditLogsRequest{
+			Limit:  100,
+			Offset: 0,
+		},
+	)
+	assert.NoError(t, err)
+
+	isPinLogged := false
+	isUnpinLogged := false
+	for _, log := range auditLogs.AuditLogs {
+		if strings.Contains(log.Message, "Backup pinned") &&
+			strings.Contains(log.Message, "State before GDPR deletion") {
+			isPinLogged = true
+		}
+
+		if strings.Contains(log.Message, "Backup unpinned") {
+			isUnpinLogged = true
+		}
+	}
+	assert.True(t, isPinLogged, "Audit log for backup pin not found")
+	assert.True(t, isUnpinLogged, "Audit log for backup unpin not found")
+}
+
+func Test_PinBackup_PinnedBackupNotDeletedByRetention(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	database, backup := createTestDatabaseWithBackups(workspace, owner, router)
+
+	repo := &BackupRepository{}
+	backup.CreatedAt = time.Now().UTC().Add(-30 * 24 * time.Hour)
+	assert.NoError(t, repo.Save(backup))
+
+	backupConfig, err := backups_config.GetBackupConfigService().GetBackupConfigByDbId(database.ID)
+	assert.NoError(t, err)
+	backupConfig.StorePeriod = period.PeriodWeek
+	backupConfig.KeepMinBackupsCount = 0
+
+	backupsToDelete, _, err := GetBackupService().GetBackupsToDeleteByRetention(backupConfig)
+	assert.NoError(t, err)
+	assert.Len(t, backupsToDelete, 1)
+
+	pinnedUntil := time.Now().UTC().Add(24 * time.Hour)
+	test_utils.MakePostRequest(
+		t,
+		router,
+		fmt.Sprintf("/api/v1/backups/%s/pin", backup.ID.String()),
+		"Bearer "+owner.Token,
+		PinBackupRequest{Reason: "Audit", PinnedUntil: &pinnedUntil},
+		http.StatusOK,
+	)
+
+	backupsToDelete, _, err = GetBackupService().GetBackupsToDeleteByRetention(backupConfig)
+	assert.NoError(t, err)
+	assert.Empty(t, backupsToDelete)
+}
+
+func Test_GetBackupsToDeleteByRetention_WhenOldBackupQueued_BackupNotDeleted(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	database, backup := createTestDatabaseWithBackups(workspace, owner, router)
+
+	repo := &BackupRepository{}
+	backup.Status = BackupStatusQueued
+	backup.CreatedAt = time.Now().UTC().Add(-30 * 24 * time.Hour)
+	assert.NoError(t, repo.Save(backup))
+
+	backupConfig, err := backups_config.GetBackupConfigService().GetBackupConfigByDbId(database.ID)
+	assert.NoError(t, err)
+	backupConfig.StorePeriod = period.PeriodWeek
+	backupConfig.KeepMinBackupsCount = 0
+
+	backupsToDelete, _, err := GetBackupService().GetBackupsToDeleteByRetention(backupConfig)
+	assert.NoError(t, err)
+	assert.Empty(t, backupsToDelete)
+
+	backup.Status = BackupStatusCompleted
+	assert.NoError(t, repo.Save(backup))
+
+	backupsToDelete, _, err = GetBackupService().GetBackupsToDeleteByRetention(backupConfig)
+	assert.NoError(t, err)
+	assert.Len(t, backupsToDelete, 1)
+}
+
 func Test_DownloadBackup_ProperFilenameForPostgreSQL(t *testing.T) {
 	tests := []struct {
 		name           string
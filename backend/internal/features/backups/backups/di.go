@@ -1,11 +1,14 @@
 package backups
 
 import (
+	"sync/atomic"
 	"time"
 
+	"databasus-backend/internal/config"
 	audit_logs "databasus-backend/internal/features/audit_logs"
 	"databasus-backend/internal/features/backups/backups/usecases"
 	backups_config "databasus-backend/internal/features/backups/config"
+	backups_pauses "databasus-backend/internal/features/backups/pauses"
 	"databasus-backend/internal/features/databases"
 	encryption_secrets "databasus-backend/internal/features/encryption/secrets"
 	"databasus-backend/internal/features/notifiers"
@@ -13,12 +16,20 @@ import (
 	workspaces_services "databasus-backend/internal/features/workspaces/services"
 	"databasus-backend/internal/util/encryption"
 	"databasus-backend/internal/util/logger"
+
+	"github.com/google/uuid"
 )
 
 var backupRepository = &BackupRepository{}
 
 var backupContextManager = NewBackupContextManager()
 
+var backupWorkerPool = NewBackupWorkerPool(
+	config.GetEnv().BackupMaxConcurrent,
+	config.GetEnv().BackupMaxConcurrentPerHost,
+	config.GetEnv().BackupMaxConcurrentPerStorage,
+)
+
 var backupService = &BackupService{
 	databases.GetDatabaseService(),
 	storages.GetStorageService(),
@@ -34,15 +45,20 @@ var backupService = &BackupService{
 	workspaces_services.GetWorkspaceService(),
 	audit_logs.GetAuditLogService(),
 	backupContextManager,
+	backupWorkerPool,
+	backups_pauses.GetBackupPauseService(),
 }
 
 var backupBackgroundService = &BackupBackgroundService{
 	backupService,
 	backupRepository,
 	backups_config.GetBackupConfigService(),
-	storages.GetStorageService(),
+	backups_pauses.GetBackupPauseService(),
 	time.Now().UTC(),
+	map[uuid.UUID]uuid.UUID{},
 	logger.GetLogger(),
+	atomic.Bool{},
+	atomic.Bool{},
 }
 
 var backupController = &BackupController{
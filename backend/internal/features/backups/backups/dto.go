@@ -3,6 +3,7 @@ package backups
 import (
 	"databasus-backend/internal/features/backups/backups/encryption"
 	"io"
+	"time"
 )
 
 type GetBackupsRequest struct {
@@ -18,6 +19,19 @@ type GetBackupsResponse struct {
 	Offset  int       `json:"offset"`
 }
 
+type PreviewRetentionResponse struct {
+	BackupsToDelete []*Backup `json:"backupsToDelete"`
+	// expired backups which are kept because of KeepMinBackupsCount
+	BackupsKeptByMinCount []*Backup `json:"backupsKeptByMinCount"`
+	TotalBackupsCount     int64     `json:"totalBackupsCount"`
+}
+
+type PinBackupRequest struct {
+	Reason string `json:"reason" binding:"required"`
+	// nil to keep the backup until it is unpinned
+	PinnedUntil *time.Time `json:"pinnedUntil"`
+}
+
 type decryptionReaderCloser struct {
 	*encryption.DecryptionReader
 	baseReader io.ReadCloser
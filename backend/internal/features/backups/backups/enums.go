@@ -3,8 +3,30 @@ package backups
 type BackupStatus string
 
 const (
+	// waits in queue for a free worker, see BackupWorkerPool
+	BackupStatusQueued     BackupStatus = "QUEUED"
 	BackupStatusInProgress BackupStatus = "IN_PROGRESS"
 	BackupStatusCompleted  BackupStatus = "COMPLETED"
 	BackupStatusFailed     BackupStatus = "FAILED"
 	BackupStatusCanceled   BackupStatus = "CANCELED"
 )
+
+type BackupCopyStatus string
+
+const (
+	BackupCopyStatusPending    BackupCopyStatus = "PENDING"
+	BackupCopyStatusInProgress BackupCopyStatus = "IN_PROGRESS"
+	BackupCopyStatusCompleted  BackupCopyStatus = "COMPLETED"
+	BackupCopyStatusFailed     BackupCopyStatus = "FAILED"
+)
+
+type BackupIntegrityStatus string
+
+const (
+	BackupIntegrityStatusVerifying BackupIntegrityStatus = "VERIFYING"
+	BackupIntegrityStatusValid     BackupIntegrityStatus = "VALID"
+	// the file is readable, but its checksum differs from the stored one
+	BackupIntegrityStatusCorrupted BackupIntegrityStatus = "CORRUPTED"
+	// the file cannot be found or opened in the storage
+	BackupIntegrityStatusMissing BackupIntegrityStatus = "MISSING"
+)
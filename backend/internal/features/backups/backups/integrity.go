@@ -0,0 +1,230 @@
+package backups
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	storages_common "databasus-backend/internal/features/storages/common"
+	users_models "databasus-backend/internal/features/users/models"
+
+	"github.com/google/uuid"
+)
+
+// VerifyBackupWithAuth starts integrity verification of the backup in
+// background. The result is saved into integrity fields of the backup
+func (s *BackupService) VerifyBackupWithAuth(
+	user *users_models.User,
+	backupID uuid.UUID,
+) error {
+	backup, err := s.backupRepository.FindByID(backupID)
+	if err != nil {
+		return err
+	}
+
+	database, err := s.databaseService.GetDatabaseByID(backup.DatabaseID)
+	if err != nil {
+		return err
+	}
+
+	if database.WorkspaceID == nil {
+		return errors.New("cannot verify backup for database without workspace")
+	}
+
+	canAccess, _, err := s.workspaceService.CanUserAccessWorkspace(
+		*database.WorkspaceID,
+		user,
+	)
+	if err != nil {
+		return err
+	}
+	if !canAccess {
+		return errors.New("insufficient permissions to verify backup for this database")
+	}
+
+	if backup.Status != BackupStatusCompleted {
+		return errors.New("only completed backups can be verified")
+	}
+
+	if backup.Checksum == nil {
+		return errors.New("backup has no checksum, it was created before checksums were introduced")
+	}
+
+	isStarted, err := s.backupRepository.StartIntegrityVerification(backup.ID)
+	if err != nil {
+		return err
+	}
+	if !isStarted {
+		return errors.New("backup is already being verified")
+	}
+
+	go func() {
+		if _, err := s.VerifyBackup(backup); err != nil {
+			s.logger.Error("Failed to verify backup", "backupId", backup.ID, "error", err)
+		}
+	}()
+
+	s.auditLogService.WriteAuditLog(
+		fmt.Sprintf(
+			"Backup integrity verification started for database: %s (ID: %s)",
+			database.Name,
+			backupID.String(),
+		),
+		&user.ID,
+		database.WorkspaceID,
+	)
+
+	return nil
+}
+
+// VerifyBackup re-downloads the backup file from the primary storage,
+// recomputes SHA-256 of the decrypted content and compares it with the
+// checksum computed while the backup was created. If the storage cannot be
+// read (e.g. it is unreachable), the previous integrity result of the backup
+// is kept and the error is returned
+func (s *BackupService) VerifyBackup(backup *Backup) (BackupIntegrityStatus, error) {
+	if backup.Checksum == nil {
+		return "", errors.New("backup has no checksum")
+	}
+
+	status, message, err := s.checkBackupIntegrity(backup)
+	if err != nil {
+		if updateErr := s.backupRepository.UpdateIntegrity(
+			backup.ID,
+			backup.IntegrityStatus,
+			backup.IntegrityMessage,
+			backup.IntegrityCheckedAt,
+		); updateErr != nil {
+			s.logger.Error(
+				"Failed to restore backup integrity status",
+				"backupId",
+				backup.ID,
+				"error",
+				updateErr,
+			)
+		}
+
+		return "", err
+	}
+
+	checkedAt := time.Now().UTC()
+	if err := s.backupRepository.UpdateIntegrity(
+		backup.ID,
+		&status,
+		message,
+		&checkedAt,
+	); err != nil {
+		return "", err
+	}
+
+	if status != BackupIntegrityStatusValid {
+		s.logger.Warn(
+			"Backup integrity check failed",
+			"backupId",
+			backup.ID,
+			"status",
+			status,
+			"message",
+			*message,
+		)
+	}
+
+	return status, nil
+}
+
+// checkBackupIntegrity returns the integrity status of the backup file. The
+// file is MISSING only when the storage reports it does not exist, other
+// storage errors are returned, so the backup is left unverified
+func (s *BackupService) checkBackupIntegrity(
+	backup *Backup,
+) (BackupIntegrityStatus, *string, error) {
+	storage, err := s.storageService.GetStorageByID(backup.StorageID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get storage: %w", err)
+	}
+
+	fileReader, err := storage.GetFile(s.fieldEncryptor, backup.ID)
+	if err == nil {
+		return s.checkBackupFileChecksum(backup, fileReader)
+	}
+
+	// storages do not report the missing file on GetFile in the same way,
+	// so the file existence is checked separately
+	if !errors.Is(err, storages_common.ErrFileNotFound) {
+		if _, sizeErr := storage.GetFileSize(s.fieldEncryptor, backup.ID); sizeErr != nil {
+			err = sizeErr
+		}
+	}
+
+	if errors.Is(err, storages_common.ErrFileNotFound) {
+		message := fmt.Sprintf("backup file not found: %v", err)
+		return BackupIntegrityStatusMissing, &message, nil
+	}
+
+	return "", nil, fmt.Errorf("failed to get backup file: %w", err)
+}
+
+func (s *BackupService) checkBackupFileChecksum(
+	backup *Backup,
+	fileReader io.ReadCloser,
+) (BackupIntegrityStatus, *string, error) {
+	reader, err := s.decryptBackupReader(backup, fileReader)
+	if err != nil {
+		message := err.Error()
+		return BackupIntegrityStatusCorrupted, &message, nil
+	}
+	defer func() {
+		if err := reader.Close(); err != nil {
+			s.logger.Error("Failed to close backup file reader", "error", err)
+		}
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		message := fmt.Sprintf("failed to read backup file: %v", err)
+		return BackupIntegrityStatusCorrupted, &message, nil
+	}
+
+	actualChecksum := hex.EncodeToString(hash.Sum(nil))
+	if actualChecksum != *backup.Checksum {
+		message := fmt.Sprintf(
+			"checksum mismatch: expected %s, got %s",
+			*backup.Checksum,
+			actualChecksum,
+		)
+		return BackupIntegrityStatusCorrupted, &message, nil
+	}
+
+	return BackupIntegrityStatusValid, nil, nil
+}
+
+// MarkBackupFileMissing saves MISSING integrity status for the backup which
+// file is not found in its storage by other checks (e.g. reconciliation)
+func (s *BackupService) MarkBackupFileMissing(backupID uuid.UUID, message string) error {
+	status := BackupIntegrityStatusMissing
+	checkedAt := time.Now().UTC()
+
+	return s.backupRepository.UpdateIntegrity(backupID, &status, &message, &checkedAt)
+}
+
+// resetInterruptedIntegrityChecks clears verifications interrupted by
+// application restart, so they can be started again
+func (s *BackupService) resetInterruptedIntegrityChecks() error {
+	verifyingBackups, err := s.backupRepository.FindByIntegrityStatus(
+		BackupIntegrityStatusVerifying,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, backup := range verifyingBackups {
+		if err := s.backupRepository.UpdateIntegrity(backup.ID, nil, nil, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package backups
 
 import (
+	common "databasus-backend/internal/features/backups/backups/common"
 	backups_config "databasus-backend/internal/features/backups/config"
 	"time"
 
@@ -20,9 +21,100 @@ type Backup struct {
 
 	BackupDurationMs int64 `json:"backupDurationMs" gorm:"column:backup_duration_ms;default:0"`
 
+	// backup schedule which created the backup, nil for backups of the
+	// backup config schedule and manual ones. Retention of the schedule is
+	// applied only to its backups
+	ScheduleID *uuid.UUID `json:"scheduleId" gorm:"column:schedule_id;type:uuid"`
+
+	// manual backups are started before scheduled ones waiting in queue
+	IsManual bool `json:"isManual" gorm:"column:is_manual;not null;default:false"`
+
+	// how many times the backup has been started, a backup interrupted by
+	// application restart is queued again until jobs.MaxAttempts is reached
+	Attempts int `json:"attempts" gorm:"column:attempts;not null;default:0"`
+	// extended by heartbeats of the running backup, see BackupRepository.Save
+	LeaseExpiresAt *time.Time `json:"-" gorm:"column:lease_expires_at"`
+
 	EncryptionSalt *string                         `json:"-"          gorm:"column:encryption_salt"`
 	EncryptionIV   *string                         `json:"-"          gorm:"column:encryption_iv"`
 	Encryption     backups_config.BackupEncryption `json:"encryption" gorm:"column:encryption;type:text;not null;default:'NONE'"`
 
+	Type common.BackupType `json:"type" gorm:"column:type;type:text;not null;default:'DEFAULT'"`
+
+	// WAL positions of pg_basebackup backups (base and physical ones)
+	WalStartLsn *string `json:"walStartLsn" gorm:"column:wal_start_lsn"`
+	WalStopLsn  *string `json:"walStopLsn"  gorm:"column:wal_stop_lsn"`
+
+	// binlog coordinates the MySQL and MariaDB dump is consistent with,
+	// filled only when binlog archiving is enabled
+	BinlogFile     *string `json:"binlogFile"     gorm:"column:binlog_file"`
+	BinlogPosition *int64  `json:"binlogPosition" gorm:"column:binlog_position"`
+	GtidSet        *string `json:"gtidSet"        gorm:"column:gtid_set"`
+
+	// hex encoded SHA-256 of the backup file content before encryption,
+	// nil for backups created before checksums were introduced
+	Checksum *string `json:"checksum" gorm:"column:checksum"`
+
+	// result of the last integrity verification, nil if never verified
+	IntegrityStatus    *BackupIntegrityStatus `json:"integrityStatus"    gorm:"column:integrity_status;type:text"`
+	IntegrityMessage   *string                `json:"integrityMessage"   gorm:"column:integrity_message"`
+	IntegrityCheckedAt *time.Time             `json:"integrityCheckedAt" gorm:"column:integrity_checked_at"`
+
+	// pinned (legal hold) backups are not deleted by retention and cannot be
+	// deleted until a workspace admin unpins them. The pin ends at
+	// PinnedUntil, nil keeps the backup forever
+	PinnedAt       *time.Time `json:"pinnedAt"       gorm:"column:pinned_at"`
+	PinReason      *string    `json:"pinReason"      gorm:"column:pin_reason"`
+	PinnedUntil    *time.Time `json:"pinnedUntil"    gorm:"column:pinned_until"`
+	PinnedByUserID *uuid.UUID `json:"pinnedByUserId" gorm:"column:pinned_by_user_id;type:uuid"`
+
+	// set when the backup is moved from the primary storage to the archive
+	// storage of the backup config, StorageID points to the archive storage
+	ArchivedAt *time.Time `json:"archivedAt" gorm:"column:archived_at"`
+
+	// set when the backup is deleted while its file is locked by the storage
+	// (e.g. S3 Object Lock). The backup is kept and its deletion is retried
+	// after the lock expires
+	DeletionLockedUntil *time.Time `json:"deletionLockedUntil" gorm:"column:deletion_locked_until"`
+
+	// Copies of the backup replicated to secondary storages
+	Copies []*BackupCopy `json:"copies" gorm:"foreignKey:BackupID"`
+
 	CreatedAt time.Time `json:"createdAt" gorm:"column:created_at"`
 }
+
+func (b *Backup) IsPinned(now time.Time) bool {
+	return b.PinnedAt != nil && (b.PinnedUntil == nil || now.Before(*b.PinnedUntil))
+}
+
+func (b *Backup) IsDeletionLocked(now time.Time) bool {
+	return b.DeletionLockedUntil != nil && now.Before(*b.DeletionLockedUntil)
+}
+
+// BackupCopy is a copy of the backup file in a secondary storage. The file
+// is copied byte by byte under the same file name, so encryption metadata
+// of the backup is valid for copies as well
+type BackupCopy struct {
+	ID uuid.UUID `json:"id" gorm:"column:id;type:uuid;primaryKey"`
+
+	BackupID  uuid.UUID `json:"backupId"  gorm:"column:backup_id;type:uuid;not null"`
+	StorageID uuid.UUID `json:"storageId" gorm:"column:storage_id;type:uuid;not null"`
+
+	Status      BackupCopyStatus `json:"status"      gorm:"column:status;type:text;not null"`
+	FailMessage *string          `json:"failMessage" gorm:"column:fail_message"`
+
+	// how many times the copy has been started, a failed copy is pending
+	// again until maxReplicationAttempts is reached
+	Attempts int `json:"attempts" gorm:"column:attempts;not null;default:0"`
+	// a pending copy is not started before this time, nil starts it right away
+	NextAttemptAt *time.Time `json:"nextAttemptAt" gorm:"column:next_attempt_at"`
+	// extended by heartbeats of the running replication
+	LeaseExpiresAt *time.Time `json:"-" gorm:"column:lease_expires_at"`
+
+	CreatedAt   time.Time  `json:"createdAt"   gorm:"column:created_at"`
+	CompletedAt *time.Time `json:"completedAt" gorm:"column:completed_at"`
+}
+
+func (c *BackupCopy) TableName() string {
+	return "backup_copies"
+}
@@ -0,0 +1,217 @@
+package backups
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"databasus-backend/internal/features/databases"
+	users_models "databasus-backend/internal/features/users/models"
+
+	"github.com/google/uuid"
+)
+
+const maxPinReasonLength = 500
+
+var errBackupPinned = errors.New(
+	"backup is pinned, a workspace admin must unpin it before deletion",
+)
+
+// PinBackupWithAuth pins the backup, so it is kept regardless of retention
+// until PinnedUntil or until it is unpinned. Changing the pin of a pinned
+// backup may shorten the hold, so it requires the same rights as unpinning
+func (s *BackupService) PinBackupWithAuth(
+	user *users_models.User,
+	backupID uuid.UUID,
+	request *PinBackupRequest,
+) (*Backup, error) {
+	backup, database, err := s.getBackupWithDatabase(backupID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	if backup.IsPinned(now) {
+		if err := s.checkCanUnpinBackup(user, database); err != nil {
+			return nil, err
+		}
+	} else {
+		canManage, err := s.workspaceService.CanUserManageDBs(*database.WorkspaceID, user)
+		if err != nil {
+			return nil, err
+		}
+		if !canManage {
+			return nil, errors.New("insufficient permissions to pin backup for this database")
+		}
+	}
+
+	if backup.Status != BackupStatusCompleted {
+		return nil, errors.New("only completed backups can be pinned")
+	}
+
+	reason := strings.TrimSpace(request.Reason)
+	if reason == "" {
+		return nil, errors.New("pin reason is required")
+	}
+
+	if len(reason) > maxPinReasonLength {
+		return nil, fmt.Errorf("pin reason must be at most %d characters", maxPinReasonLength)
+	}
+
+	var pinnedUntil *time.Time
+	if request.PinnedUntil != nil {
+		if !request.PinnedUntil.After(now) {
+			return nil, errors.New("pin expiry must be in the future")
+		}
+
+		utcPinnedUntil := request.PinnedUntil.UTC()
+		pinnedUntil = &utcPinnedUntil
+	}
+
+	if err := s.backupRepository.UpdatePin(
+		backup.ID,
+		&now,
+		&reason,
+		pinnedUntil,
+		&user.ID,
+	); err != nil {
+		return nil, err
+	}
+
+	pinnedUntilMessage := "until unpinned"
+	if pinnedUntil != nil {
+		pinnedUntilMessage = "until " + pinnedUntil.Format(time.RFC3339)
+	}
+
+	s.auditLogService.WriteAuditLog(
+		fmt.Sprintf(
+			"Backup pinned %s for database: %s (ID: %s). Reason: %s",
+			pinnedUntilMessage,
+			database.Name,
+			backupID.String(),
+			reason,
+		),
+		&user.ID,
+		database.WorkspaceID,
+	)
+
+	return s.backupRepository.FindByID(backup.ID)
+}
+
+// UnpinBackupWithAuth lifts the hold of the pinned backup, only workspace
+// admins can do it. The backup is deleted by retention again
+func (s *BackupService) UnpinBackupWithAuth(
+	user *users_models.User,
+	backupID uuid.UUID,
+) error {
+	backup, database, err := s.getBackupWithDatabase(backupID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkCanUnpinBackup(user, database); err != nil {
+		return err
+	}
+
+	if !backup.IsPinned(time.Now().UTC()) {
+		return errors.New("backup is not pinned")
+	}
+
+	if err := s.backupRepository.UpdatePin(backup.ID, nil, nil, nil, nil); err != nil {
+		return err
+	}
+
+	s.auditLogService.WriteAuditLog(
+		fmt.Sprintf(
+			"Backup unpinned for database: %s (ID: %s)",
+			database.Name,
+			backupID.String(),
+		),
+		&user.ID,
+		database.WorkspaceID,
+	)
+
+	return nil
+}
+
+// releaseExpiredPins unpins backups whose pin expired. Expired pins already
+// do not protect backups, they are removed to record it in the audit log
+func (s *BackupService) releaseExpiredPins() error {
+	backups, err := s.backupRepository.FindWithExpiredPin(time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	for _, backup := range backups {
+		if err := s.backupRepository.UpdatePin(backup.ID, nil, nil, nil, nil); err != nil {
+			return err
+		}
+
+		database, err := s.databaseService.GetDatabaseByID(backup.DatabaseID)
+		if err != nil {
+			s.logger.Error("Failed to get database of backup", "backupId", backup.ID, "error", err)
+			continue
+		}
+
+		s.auditLogService.WriteAuditLog(
+			fmt.Sprintf(
+				"Backup pin expired for database: %s (ID: %s)",
+				database.Name,
+				backup.ID.String(),
+			),
+			nil,
+			database.WorkspaceID,
+		)
+	}
+
+	return nil
+}
+
+func (s *BackupService) getBackupWithDatabase(
+	backupID uuid.UUID,
+) (*Backup, *databases.Database, error) {
+	backup, err := s.backupRepository.FindByID(backupID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	database, err := s.databaseService.GetDatabaseByID(backup.DatabaseID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if database.WorkspaceID == nil {
+		return nil, nil, errors.New("cannot pin backup for database without workspace")
+	}
+
+	return backup, database, nil
+}
+
+func (s *BackupService) checkCanUnpinBackup(
+	user *users_models.User,
+	database *databases.Database,
+) error {
+	canManage, err := s.workspaceService.CanUserManageWorkspace(*database.WorkspaceID, user)
+	if err != nil {
+		return err
+	}
+	if !canManage {
+		return errors.New("insufficient permissions, only workspace admins can unpin backups")
+	}
+
+	return nil
+}
+
+func filterUnpinnedBackups(backups []*Backup, now time.Time) []*Backup {
+	unpinnedBackups := make([]*Backup, 0, len(backups))
+
+	for _, backup := range backups {
+		if !backup.IsPinned(now) {
+			unpinnedBackups = append(unpinnedBackups, backup)
+		}
+	}
+
+	return unpinnedBackups
+}
@@ -0,0 +1,36 @@
+package backups
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FilterUnpinnedBackups_ExpiredPinsDoNotProtectBackups(t *testing.T) {
+	now := time.Now().UTC()
+	pinnedAt := now.Add(-time.Hour)
+	pinnedUntilInFuture := now.Add(time.Hour)
+	pinnedUntilInPast := now.Add(-time.Minute)
+
+	notPinnedBackup := &Backup{ID: uuid.New()}
+	pinnedForeverBackup := &Backup{ID: uuid.New(), PinnedAt: &pinnedAt}
+	pinnedBackup := &Backup{
+		ID:          uuid.New(),
+		PinnedAt:    &pinnedAt,
+		PinnedUntil: &pinnedUntilInFuture,
+	}
+	expiredPinBackup := &Backup{
+		ID:          uuid.New(),
+		PinnedAt:    &pinnedAt,
+		PinnedUntil: &pinnedUntilInPast,
+	}
+
+	unpinnedBackups := filterUnpinnedBackups(
+		[]*Backup{notPinnedBackup, pinnedForeverBackup, pinnedBackup, expiredPinBackup},
+		now,
+	)
+
+	assert.Equal(t, []*Backup{notPinnedBackup, expiredPinBackup}, unpinnedBackups)
+}
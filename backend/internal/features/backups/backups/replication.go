@@ -0,0 +1,432 @@
+package backups
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"databasus-backend/internal/config"
+	common "databasus-backend/internal/features/backups/backups/common"
+	backups_config "databasus-backend/internal/features/backups/config"
+	"databasus-backend/internal/features/storages"
+	"databasus-backend/internal/util/jobs"
+
+	"github.com/google/uuid"
+)
+
+const replicationTimeout = 23 * time.Hour
+
+// a failed copy is started again after replicationRetryDelay, doubled with
+// each attempt, until maxReplicationAttempts is reached
+const (
+	maxReplicationAttempts = 5
+	replicationRetryDelay  = 10 * time.Minute
+)
+
+// startReplication creates pending copies of the completed backup for each
+// secondary storage of the backup config. Pending copies are replicated by
+// the background service, see replicatePendingBackupCopies
+func (s *BackupService) startReplication(
+	backup *Backup,
+	backupConfig *backups_config.BackupConfig,
+) {
+	for _, secondaryStorage := range backupConfig.SecondaryStorages {
+		backupCopy := &BackupCopy{
+			BackupID:  backup.ID,
+			StorageID: secondaryStorage.ID,
+			Status:    BackupCopyStatusPending,
+			CreatedAt: time.Now().UTC(),
+		}
+
+		if err := s.backupRepository.SaveCopy(backupCopy); err != nil {
+			s.logger.Error("Failed to save backup copy", "backupId", backup.ID, "error", err)
+		}
+	}
+}
+
+// replicatePendingBackupCopies replicates pending copies whose next attempt
+// is reached, one by one
+func (s *BackupService) replicatePendingBackupCopies() error {
+	backupCopies, err := s.backupRepository.FindCopiesDueForReplication(time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	for _, backupCopy := range backupCopies {
+		if config.IsShouldShutdown() || config.IsDraining() {
+			return nil
+		}
+
+		s.replicateBackupCopy(backupCopy)
+	}
+
+	return nil
+}
+
+func (s *BackupService) replicateBackupCopy(backupCopy *BackupCopy) {
+	isClaimed, err := s.backupRepository.ClaimCopy(
+		backupCopy.ID,
+		time.Now().UTC(),
+		jobs.GetLeaseExpiresAt(),
+	)
+	if err != nil {
+		s.logger.Error("Failed to start backup copy", "backupCopyId", backupCopy.ID, "error", err)
+		return
+	}
+
+	// replicated or deleted meanwhile
+	if !isClaimed {
+		return
+	}
+
+	backupCopy.Attempts++
+
+	stopHeartbeat := jobs.StartHeartbeat(s.logger, jobs.HeartbeatInterval, func() error {
+		return s.backupRepository.ExtendCopyLease(backupCopy.ID, jobs.GetLeaseExpiresAt())
+	})
+	defer stopHeartbeat()
+
+	backup, backupConfig, err := s.getBackupWithConfig(backupCopy.BackupID)
+	if err == nil {
+		err = s.copyBackupFile(backup, backupCopy, backupConfig)
+	}
+
+	// the copy keeps its lease and is started again after restart
+	if err != nil && config.IsShouldShutdown() {
+		return
+	}
+
+	backupCopy.LeaseExpiresAt = nil
+
+	if err == nil {
+		completedAt := time.Now().UTC()
+		backupCopy.CompletedAt = &completedAt
+		backupCopy.FailMessage = nil
+		backupCopy.NextAttemptAt = nil
+		backupCopy.Status = BackupCopyStatusCompleted
+
+		s.logger.Info(
+			"Backup replicated",
+			"backupId",
+			backupCopy.BackupID,
+			"storageId",
+			backupCopy.StorageID,
+		)
+
+		if err := s.backupRepository.SaveCopy(backupCopy); err != nil {
+			s.logger.Error(
+				"Failed to save backup copy",
+				"backupCopyId",
+				backupCopy.ID,
+				"error",
+				err,
+			)
+		}
+
+		return
+	}
+
+	s.logger.Error(
+		"Failed to replicate backup",
+		"backupId",
+		backupCopy.BackupID,
+		"storageId",
+		backupCopy.StorageID,
+		"attempts",
+		backupCopy.Attempts,
+		"error",
+		err,
+	)
+
+	s.failBackupCopyAttempt(backup, backupConfig, backupCopy, err.Error())
+}
+
+// failBackupCopyAttempt schedules the next attempt of the failed copy or,
+// when all attempts are used, fails the copy and notifies about it
+func (s *BackupService) failBackupCopyAttempt(
+	backup *Backup,
+	backupConfig *backups_config.BackupConfig,
+	backupCopy *BackupCopy,
+	failMessage string,
+) {
+	backupCopy.FailMessage = &failMessage
+
+	if backupCopy.Attempts < maxReplicationAttempts {
+		nextAttemptAt := time.Now().UTC().Add(getReplicationRetryDelay(backupCopy.Attempts))
+		backupCopy.NextAttemptAt = &nextAttemptAt
+		backupCopy.Status = BackupCopyStatusPending
+	} else {
+		backupCopy.NextAttemptAt = nil
+		backupCopy.Status = BackupCopyStatusFailed
+	}
+
+	if err := s.backupRepository.SaveCopy(backupCopy); err != nil {
+		s.logger.Error("Failed to save backup copy", "backupCopyId", backupCopy.ID, "error", err)
+		return
+	}
+
+	if backupCopy.Status != BackupCopyStatusFailed || backup == nil || backupConfig == nil {
+		return
+	}
+
+	message := fmt.Sprintf(
+		"Copy of the backup to the secondary storage failed after %d attempts: %s",
+		backupCopy.Attempts,
+		failMessage,
+	)
+
+	s.SendBackupNotification(
+		backupConfig,
+		backup,
+		backups_config.NotificationBackupReplicationFailed,
+		&message,
+	)
+}
+
+// requeueInterruptedBackupCopies makes copies whose lease expired because
+// the instance replicating them crashed or was restarted pending again.
+// Copies which used all attempts are failed
+func (s *BackupService) requeueInterruptedBackupCopies() error {
+	now := time.Now().UTC()
+
+	backupCopies, err := s.backupRepository.FindCopiesWithExpiredLease(now)
+	if err != nil {
+		return err
+	}
+
+	for _, backupCopy := range backupCopies {
+		if backupCopy.Attempts < maxReplicationAttempts {
+			isRequeued, err := s.backupRepository.RequeueExpiredCopy(backupCopy.ID, now)
+			if err != nil {
+				return err
+			}
+
+			if isRequeued {
+				s.logger.Info(
+					"Interrupted backup copy is pending again",
+					"backupCopyId",
+					backupCopy.ID,
+					"attempts",
+					backupCopy.Attempts,
+				)
+			}
+
+			continue
+		}
+
+		backup, backupConfig, err := s.getBackupWithConfig(backupCopy.BackupID)
+		if err != nil {
+			s.logger.Error("Failed to get backup of backup copy", "error", err)
+		}
+
+		s.failBackupCopyAttempt(
+			backup,
+			backupConfig,
+			backupCopy,
+			"Backup replication was interrupted by application restart",
+		)
+	}
+
+	return nil
+}
+
+func (s *BackupService) getBackupWithConfig(
+	backupID uuid.UUID,
+) (*Backup, *backups_config.BackupConfig, error) {
+	backup, err := s.backupRepository.FindByID(backupID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	backupConfig, err := s.GetBackupConfigOfBackup(backup)
+	if err != nil {
+		return backup, nil, err
+	}
+
+	return backup, backupConfig, nil
+}
+
+// getReplicationRetryDelay returns the delay before the next attempt of the
+// copy which failed attempts times
+func getReplicationRetryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		return replicationRetryDelay
+	}
+
+	return replicationRetryDelay * time.Duration(1<<(attempts-1))
+}
+
+func (s *BackupService) copyBackupFile(
+	backup *Backup,
+	backupCopy *BackupCopy,
+	backupConfig *backups_config.BackupConfig,
+) error {
+	primaryStorage, err := s.storageService.GetStorageByID(backup.StorageID)
+	if err != nil {
+		return fmt.Errorf("failed to get primary storage: %w", err)
+	}
+
+	secondaryStorage, err := s.storageService.GetStorageByID(backupCopy.StorageID)
+	if err != nil {
+		return fmt.Errorf("failed to get secondary storage: %w", err)
+	}
+
+	_, err = s.transferBackupFile(backup, backupConfig, primaryStorage, secondaryStorage)
+	return err
+}
+
+// transferBackupFile streams the backup file from the source storage into
+// the target one under the same file name and returns the number of
+// transferred bytes. A partially saved file is removed on failure
+func (s *BackupService) transferBackupFile(
+	backup *Backup,
+	backupConfig *backups_config.BackupConfig,
+	sourceStorage *storages.Storage,
+	targetStorage *storages.Storage,
+) (int64, error) {
+	reader, err := sourceStorage.GetFile(s.fieldEncryptor, backup.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get backup file from source storage: %w", err)
+	}
+	defer func() {
+		if err := reader.Close(); err != nil {
+			s.logger.Error("Failed to close backup file reader", "error", err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(
+		withBackupRetainUntil(context.Background(), backupConfig, backup),
+		replicationTimeout,
+	)
+	defer cancel()
+
+	go func() {
+		ticker := time.NewTicker(1 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if config.IsShouldShutdown() {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	countingWriter := common.NewCountingWriter(io.Discard)
+
+	if err := targetStorage.SaveFile(
+		ctx,
+		s.fieldEncryptor,
+		s.logger,
+		backup.ID,
+		io.TeeReader(reader, countingWriter),
+	); err != nil {
+		// remove partially uploaded file
+		_ = targetStorage.DeleteFile(s.fieldEncryptor, backup.ID)
+		return 0, fmt.Errorf("failed to save backup file to target storage: %w", err)
+	}
+
+	return countingWriter.GetBytesWritten(), nil
+}
+
+// GetReadableBackupStorage returns the storage to read the backup file from:
+// the primary storage or, if the file cannot be read from it, the first
+// secondary storage with a completed copy
+func (s *BackupService) GetReadableBackupStorage(backup *Backup) (*storages.Storage, error) {
+	primaryStorage, primaryErr := s.storageService.GetStorageByID(backup.StorageID)
+	if primaryErr == nil {
+		primaryErr = s.checkBackupFileReadable(primaryStorage, backup)
+		if primaryErr == nil {
+			return primaryStorage, nil
+		}
+	}
+
+	backupCopies, err := s.backupRepository.FindCopiesByBackupID(backup.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, backupCopy := range backupCopies {
+		if backupCopy.Status != BackupCopyStatusCompleted {
+			continue
+		}
+
+		secondaryStorage, err := s.storageService.GetStorageByID(backupCopy.StorageID)
+		if err != nil {
+			continue
+		}
+
+		if err := s.checkBackupFileReadable(secondaryStorage, backup); err != nil {
+			s.logger.Warn(
+				"Backup copy is not readable",
+				"backupId",
+				backup.ID,
+				"storageId",
+				backupCopy.StorageID,
+				"error",
+				err,
+			)
+			continue
+		}
+
+		s.logger.Warn(
+			"Primary backup file is not readable, falling back to secondary copy",
+			"backupId",
+			backup.ID,
+			"storageId",
+			backupCopy.StorageID,
+			"primaryError",
+			primaryErr,
+		)
+
+		return secondaryStorage, nil
+	}
+
+	return nil, fmt.Errorf("failed to get backup file: %w", primaryErr)
+}
+
+// checkBackupFileReadable checks the backup file exists in the storage
+// without downloading it
+func (s *BackupService) checkBackupFileReadable(
+	storage *storages.Storage,
+	backup *Backup,
+) error {
+	_, err := storage.GetFileSize(s.fieldEncryptor, backup.ID)
+	return err
+}
+
+// deleteBackupCopiesFiles removes backup copies from secondary storages.
+// Copy rows are removed together with the backup
+func (s *BackupService) deleteBackupCopiesFiles(backup *Backup) {
+	backupCopies, err := s.backupRepository.FindCopiesByBackupID(backup.ID)
+	if err != nil {
+		s.logger.Error("Failed to find backup copies", "backupId", backup.ID, "error", err)
+		return
+	}
+
+	for _, backupCopy := range backupCopies {
+		secondaryStorage, err := s.storageService.GetStorageByID(backupCopy.StorageID)
+		if err != nil {
+			s.logger.Error("Failed to get storage by ID", "storageId", backupCopy.StorageID)
+			continue
+		}
+
+		if err := secondaryStorage.DeleteFile(s.fieldEncryptor, backup.ID); err != nil {
+			s.logger.Error(
+				"Failed to delete backup copy file",
+				"backupId",
+				backup.ID,
+				"storageId",
+				backupCopy.StorageID,
+				"error",
+				err,
+			)
+		}
+	}
+}
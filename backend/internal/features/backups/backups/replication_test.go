@@ -0,0 +1,15 @@
+package backups
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetReplicationRetryDelay_DelayDoubledWithEachAttempt(t *testing.T) {
+	assert.Equal(t, 10*time.Minute, getReplicationRetryDelay(1))
+	assert.Equal(t, 20*time.Minute, getReplicationRetryDelay(2))
+	assert.Equal(t, 40*time.Minute, getReplicationRetryDelay(3))
+	assert.Equal(t, 80*time.Minute, getReplicationRetryDelay(4))
+}
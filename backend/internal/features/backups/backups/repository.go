@@ -1,6 +1,7 @@
 package backups
 
 import (
+	common "databasus-backend/internal/features/backups/backups/common"
 	"databasus-backend/internal/storage"
 	"errors"
 
@@ -22,11 +23,15 @@ func (r *BackupRepository) Save(backup *Backup) error {
 	isNew := backup.ID == uuid.Nil
 	if isNew {
 		backup.ID = uuid.New()
-		return db.Create(backup).
+		return db.Omit("Copies").
+			Create(backup).
 			Error
 	}
 
-	return db.Save(backup).
+	// the lease is changed only by claims and heartbeats, so saving of the
+	// backup progress does not overwrite it with a stale value
+	return db.Omit("Copies", "LeaseExpiresAt").
+		Save(backup).
 		Error
 }
 
@@ -46,6 +51,7 @@ func (r *BackupRepository) FindByDatabaseID(databaseID uuid.UUID) ([]*Backup, er
 
 func (r *BackupRepository) FindByDatabaseIDWithLimit(
 	databaseID uuid.UUID,
+	scheduleID *uuid.UUID,
 	limit int,
 ) ([]*Backup, error) {
 	if limit <= 0 {
@@ -54,8 +60,7 @@ func (r *BackupRepository) FindByDatabaseIDWithLimit(
 
 	var backups []*Backup
 
-	if err := storage.
-		GetDb().
+	if err := whereScheduleID(storage.GetDb(), scheduleID).
 		Where("database_id = ?", databaseID).
 		Order("created_at DESC").
 		Limit(limit).
@@ -80,6 +85,23 @@ func (r *BackupRepository) FindByStorageID(storageID uuid.UUID) ([]*Backup, erro
 	return backups, nil
 }
 
+func (r *BackupRepository) FindByDatabaseIDAndStorageID(
+	databaseID uuid.UUID,
+	scheduleID *uuid.UUID,
+	storageID uuid.UUID,
+) ([]*Backup, error) {
+	var backups []*Backup
+
+	if err := whereScheduleID(storage.GetDb(), scheduleID).
+		Where("database_id = ? AND storage_id = ?", databaseID, storageID).
+		Order("created_at DESC").
+		Find(&backups).Error; err != nil {
+		return nil, err
+	}
+
+	return backups, nil
+}
+
 func (r *BackupRepository) FindLastByDatabaseID(databaseID uuid.UUID) (*Backup, error) {
 	var backup Backup
 
@@ -98,11 +120,32 @@ func (r *BackupRepository) FindLastByDatabaseID(databaseID uuid.UUID) (*Backup,
 	return &backup, nil
 }
 
+func (r *BackupRepository) FindLastByDatabaseIDAndScheduleID(
+	databaseID uuid.UUID,
+	scheduleID *uuid.UUID,
+) (*Backup, error) {
+	var backup Backup
+
+	if err := whereScheduleID(storage.GetDb(), scheduleID).
+		Where("database_id = ?", databaseID).
+		Order("created_at DESC").
+		First(&backup).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &backup, nil
+}
+
 func (r *BackupRepository) FindByID(id uuid.UUID) (*Backup, error) {
 	var backup Backup
 
 	if err := storage.
 		GetDb().
+		Preload("Copies").
 		Where("id = ?", id).
 		First(&backup).Error; err != nil {
 		return nil, err
@@ -125,6 +168,162 @@ func (r *BackupRepository) FindByStatus(status BackupStatus) ([]*Backup, error)
 	return backups, nil
 }
 
+// FindQueued returns queued backups in the order they should be started:
+// manual backups first, then the oldest ones
+func (r *BackupRepository) FindQueued() ([]*Backup, error) {
+	var backups []*Backup
+
+	if err := storage.
+		GetDb().
+		Where("status = ?", BackupStatusQueued).
+		Order("is_manual DESC, created_at ASC").
+		Find(&backups).Error; err != nil {
+		return nil, err
+	}
+
+	return backups, nil
+}
+
+// QueueIfNotActive saves the queued backup unless a backup of the same
+// database, schedule and kind (manual or scheduled) is already queued or in
+// progress. The backup config row of the database is locked, so concurrent
+// calls for the database are serialized. Returns the active backup if the
+// new one is not queued
+func (r *BackupRepository) QueueIfNotActive(backup *Backup) (*Backup, error) {
+	if backup.DatabaseID == uuid.Nil || backup.StorageID == uuid.Nil {
+		return nil, errors.New("database ID and storage ID are required")
+	}
+
+	var activeBackup *Backup
+
+	err := storage.GetDb().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(
+			"SELECT 1 FROM backup_configs WHERE database_id = ? FOR UPDATE",
+			backup.DatabaseID,
+		).Error; err != nil {
+			return err
+		}
+
+		var existingBackup Backup
+		err := whereScheduleID(tx, backup.ScheduleID).
+			Where(
+				"database_id = ? AND is_manual = ? AND status IN ?",
+				backup.DatabaseID,
+				backup.IsManual,
+				[]BackupStatus{BackupStatusQueued, BackupStatusInProgress},
+			).
+			Order("created_at DESC").
+			First(&existingBackup).Error
+		if err == nil {
+			activeBackup = &existingBackup
+			return nil
+		}
+
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		backup.ID = uuid.New()
+		return tx.Omit("Copies").Create(backup).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return activeBackup, nil
+}
+
+// ClaimQueued moves the backup from QUEUED to IN_PROGRESS status, counts
+// the attempt and takes the lease. Returns false if the backup is not queued
+// anymore (cancelled or already started)
+func (r *BackupRepository) ClaimQueued(
+	backupID uuid.UUID,
+	leaseExpiresAt time.Time,
+) (bool, error) {
+	result := storage.
+		GetDb().
+		Model(&Backup{}).
+		Where("id = ? AND status = ?", backupID, BackupStatusQueued).
+		Updates(map[string]any{
+			"status":           BackupStatusInProgress,
+			"attempts":         gorm.Expr("attempts + 1"),
+			"lease_expires_at": leaseExpiresAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *BackupRepository) ExtendLease(backupID uuid.UUID, leaseExpiresAt time.Time) error {
+	return storage.
+		GetDb().
+		Model(&Backup{}).
+		Where("id = ? AND status = ?", backupID, BackupStatusInProgress).
+		Update("lease_expires_at", leaseExpiresAt).
+		Error
+}
+
+// FindWithExpiredLease returns backups in progress which are not heartbeated
+// anymore. Backups started before leases were introduced have no lease
+func (r *BackupRepository) FindWithExpiredLease(now time.Time) ([]*Backup, error) {
+	var backups []*Backup
+
+	if err := storage.
+		GetDb().
+		Where(
+			"status = ? AND (lease_expires_at IS NULL OR lease_expires_at < ?)",
+			BackupStatusInProgress,
+			now,
+		).
+		Order("created_at ASC").
+		Find(&backups).Error; err != nil {
+		return nil, err
+	}
+
+	return backups, nil
+}
+
+// RequeueExpired moves the backup with expired lease back to QUEUED status.
+// Returns false if the backup has been finished or heartbeated meanwhile
+func (r *BackupRepository) RequeueExpired(backupID uuid.UUID, now time.Time) (bool, error) {
+	result := storage.
+		GetDb().
+		Model(&Backup{}).
+		Where(
+			"id = ? AND status = ? AND (lease_expires_at IS NULL OR lease_expires_at < ?)",
+			backupID,
+			BackupStatusInProgress,
+			now,
+		).
+		Updates(map[string]any{
+			"status":             BackupStatusQueued,
+			"lease_expires_at":   nil,
+			"backup_size_mb":     0,
+			"backup_duration_ms": 0,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// CancelQueued cancels the backup if it is still queued
+func (r *BackupRepository) CancelQueued(backupID uuid.UUID) (bool, error) {
+	result := storage.
+		GetDb().
+		Model(&Backup{}).
+		Where("id = ? AND status = ?", backupID, BackupStatusQueued).
+		Update("status", BackupStatusCanceled)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
 func (r *BackupRepository) FindByStorageIdAndStatus(
 	storageID uuid.UUID,
 	status BackupStatus,
@@ -159,19 +358,111 @@ func (r *BackupRepository) FindByDatabaseIdAndStatus(
 	return backups, nil
 }
 
+func (r *BackupRepository) FindLastByDatabaseIdAndStatusWithLimit(
+	databaseID uuid.UUID,
+	scheduleID *uuid.UUID,
+	status BackupStatus,
+	limit int,
+) ([]*Backup, error) {
+	var backups []*Backup
+
+	if err := whereScheduleID(storage.GetDb(), scheduleID).
+		Where("database_id = ? AND status = ?", databaseID, status).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&backups).Error; err != nil {
+		return nil, err
+	}
+
+	return backups, nil
+}
+
+func (r *BackupRepository) FindLastByDatabaseIdAndStatusExcludingTypes(
+	databaseID uuid.UUID,
+	status BackupStatus,
+	excludedTypes []common.BackupType,
+) (*Backup, error) {
+	var backup Backup
+
+	if err := storage.
+		GetDb().
+		Where("database_id = ? AND status = ? AND type NOT IN ?", databaseID, status, excludedTypes).
+		Order("created_at DESC").
+		First(&backup).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &backup, nil
+}
+
+func (r *BackupRepository) FindOldestByDatabaseIdStatusAndType(
+	databaseID uuid.UUID,
+	status BackupStatus,
+	backupType common.BackupType,
+) (*Backup, error) {
+	var backup Backup
+
+	if err := storage.
+		GetDb().
+		Where("database_id = ? AND status = ? AND type = ?", databaseID, status, backupType).
+		Order("created_at ASC").
+		First(&backup).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &backup, nil
+}
+
+// FindWithBinlogCoordinatesByDatabaseIdAndStatus returns backups which
+// recorded binlog coordinates, oldest first
+func (r *BackupRepository) FindWithBinlogCoordinatesByDatabaseIdAndStatus(
+	databaseID uuid.UUID,
+	status BackupStatus,
+) ([]*Backup, error) {
+	var backups []*Backup
+
+	if err := storage.
+		GetDb().
+		Where(
+			"database_id = ? AND status = ? AND binlog_file IS NOT NULL",
+			databaseID,
+			status,
+		).
+		Order("created_at ASC").
+		Find(&backups).Error; err != nil {
+		return nil, err
+	}
+
+	return backups, nil
+}
+
 func (r *BackupRepository) DeleteByID(id uuid.UUID) error {
 	return storage.GetDb().Delete(&Backup{}, "id = ?", id).Error
 }
 
 func (r *BackupRepository) FindBackupsBeforeDate(
 	databaseID uuid.UUID,
+	scheduleID *uuid.UUID,
 	date time.Time,
 ) ([]*Backup, error) {
 	var backups []*Backup
 
-	if err := storage.
-		GetDb().
+	// queued and running backups are not finished yet, created_at of them
+	// is the queue time
+	if err := whereScheduleID(storage.GetDb(), scheduleID).
 		Where("database_id = ? AND created_at < ?", databaseID, date).
+		Where(
+			"status IN ?",
+			[]BackupStatus{BackupStatusCompleted, BackupStatusFailed, BackupStatusCanceled},
+		).
 		Order("created_at DESC").
 		Find(&backups).Error; err != nil {
 		return nil, err
@@ -188,6 +479,7 @@ func (r *BackupRepository) FindByDatabaseIDWithPagination(
 
 	if err := storage.
 		GetDb().
+		Preload("Copies").
 		Where("database_id = ?", databaseID).
 		Order("created_at DESC").
 		Limit(limit).
@@ -212,3 +504,346 @@ func (r *BackupRepository) CountByDatabaseID(databaseID uuid.UUID) (int64, error
 
 	return count, nil
 }
+
+func (r *BackupRepository) SaveCopy(backupCopy *BackupCopy) error {
+	if backupCopy.BackupID == uuid.Nil || backupCopy.StorageID == uuid.Nil {
+		return errors.New("backup ID and storage ID are required")
+	}
+
+	if backupCopy.ID == uuid.Nil {
+		backupCopy.ID = uuid.New()
+		return storage.GetDb().Create(backupCopy).Error
+	}
+
+	return storage.GetDb().Save(backupCopy).Error
+}
+
+func (r *BackupRepository) FindCopiesByBackupID(backupID uuid.UUID) ([]*BackupCopy, error) {
+	var backupCopies []*BackupCopy
+
+	if err := storage.
+		GetDb().
+		Where("backup_id = ?", backupID).
+		Order("created_at ASC").
+		Find(&backupCopies).Error; err != nil {
+		return nil, err
+	}
+
+	return backupCopies, nil
+}
+
+// FindCopiesDueForReplication returns pending copies whose next attempt is
+// reached, the oldest first
+func (r *BackupRepository) FindCopiesDueForReplication(now time.Time) ([]*BackupCopy, error) {
+	var backupCopies []*BackupCopy
+
+	if err := storage.
+		GetDb().
+		Where(
+			"status = ? AND (next_attempt_at IS NULL OR next_attempt_at <= ?)",
+			BackupCopyStatusPending,
+			now,
+		).
+		Order("created_at ASC").
+		Find(&backupCopies).Error; err != nil {
+		return nil, err
+	}
+
+	return backupCopies, nil
+}
+
+// ClaimCopy moves the pending copy to IN_PROGRESS status, counts the attempt
+// and takes the lease. Returns false if the copy is not due anymore (started
+// by another instance or deleted)
+func (r *BackupRepository) ClaimCopy(
+	backupCopyID uuid.UUID,
+	now time.Time,
+	leaseExpiresAt time.Time,
+) (bool, error) {
+	result := storage.
+		GetDb().
+		Model(&BackupCopy{}).
+		Where(
+			"id = ? AND status = ? AND (next_attempt_at IS NULL OR next_attempt_at <= ?)",
+			backupCopyID,
+			BackupCopyStatusPending,
+			now,
+		).
+		Updates(map[string]any{
+			"status":           BackupCopyStatusInProgress,
+			"attempts":         gorm.Expr("attempts + 1"),
+			"lease_expires_at": leaseExpiresAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *BackupRepository) ExtendCopyLease(
+	backupCopyID uuid.UUID,
+	leaseExpiresAt time.Time,
+) error {
+	return storage.
+		GetDb().
+		Model(&BackupCopy{}).
+		Where("id = ? AND status = ?", backupCopyID, BackupCopyStatusInProgress).
+		Update("lease_expires_at", leaseExpiresAt).
+		Error
+}
+
+// FindCopiesWithExpiredLease returns copies in progress which are not
+// heartbeated anymore. Copies started before leases were introduced have no
+// lease
+func (r *BackupRepository) FindCopiesWithExpiredLease(now time.Time) ([]*BackupCopy, error) {
+	var backupCopies []*BackupCopy
+
+	if err := storage.
+		GetDb().
+		Where(
+			"status = ? AND (lease_expires_at IS NULL OR lease_expires_at < ?)",
+			BackupCopyStatusInProgress,
+			now,
+		).
+		Order("created_at ASC").
+		Find(&backupCopies).Error; err != nil {
+		return nil, err
+	}
+
+	return backupCopies, nil
+}
+
+// RequeueExpiredCopy moves the copy with expired lease back to PENDING
+// status. Returns false if the copy has been finished or heartbeated meanwhile
+func (r *BackupRepository) RequeueExpiredCopy(backupCopyID uuid.UUID, now time.Time) (bool, error) {
+	result := storage.
+		GetDb().
+		Model(&BackupCopy{}).
+		Where(
+			"id = ? AND status = ? AND (lease_expires_at IS NULL OR lease_expires_at < ?)",
+			backupCopyID,
+			BackupCopyStatusInProgress,
+			now,
+		).
+		Updates(map[string]any{
+			"status":           BackupCopyStatusPending,
+			"lease_expires_at": nil,
+			"next_attempt_at":  nil,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *BackupRepository) FindCopiesByStorageIdAndStatus(
+	storageID uuid.UUID,
+	status BackupCopyStatus,
+) ([]*BackupCopy, error) {
+	var backupCopies []*BackupCopy
+
+	if err := storage.
+		GetDb().
+		Where("storage_id = ? AND status = ?", storageID, status).
+		Order("created_at DESC").
+		Find(&backupCopies).Error; err != nil {
+		return nil, err
+	}
+
+	return backupCopies, nil
+}
+
+// FindExistingIDs returns IDs of the given list which have a backup row
+func (r *BackupRepository) FindExistingIDs(ids []uuid.UUID) ([]uuid.UUID, error) {
+	existingIDs := make([]uuid.UUID, 0)
+	if len(ids) == 0 {
+		return existingIDs, nil
+	}
+
+	if err := storage.
+		GetDb().
+		Model(&Backup{}).
+		Where("id IN ?", ids).
+		Pluck("id", &existingIDs).Error; err != nil {
+		return nil, err
+	}
+
+	return existingIDs, nil
+}
+
+// StartIntegrityVerification sets VERIFYING integrity status. Returns false
+// if the backup is already being verified
+func (r *BackupRepository) StartIntegrityVerification(backupID uuid.UUID) (bool, error) {
+	result := storage.
+		GetDb().
+		Model(&Backup{}).
+		Where(
+			"id = ? AND (integrity_status IS NULL OR integrity_status <> ?)",
+			backupID,
+			BackupIntegrityStatusVerifying,
+		).
+		Update("integrity_status", BackupIntegrityStatusVerifying)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// UpdateIntegrity updates only integrity fields, so concurrent changes of
+// the backup are not overwritten
+func (r *BackupRepository) UpdateIntegrity(
+	backupID uuid.UUID,
+	status *BackupIntegrityStatus,
+	message *string,
+	checkedAt *time.Time,
+) error {
+	return storage.
+		GetDb().
+		Model(&Backup{}).
+		Where("id = ?", backupID).
+		Updates(map[string]any{
+			"integrity_status":     status,
+			"integrity_message":    message,
+			"integrity_checked_at": checkedAt,
+		}).Error
+}
+
+// UpdatePin updates only pin fields, so concurrent changes of the backup are
+// not overwritten. All fields are nil to unpin the backup
+func (r *BackupRepository) UpdatePin(
+	backupID uuid.UUID,
+	pinnedAt *time.Time,
+	reason *string,
+	pinnedUntil *time.Time,
+	pinnedByUserID *uuid.UUID,
+) error {
+	return storage.
+		GetDb().
+		Model(&Backup{}).
+		Where("id = ?", backupID).
+		Updates(map[string]any{
+			"pinned_at":         pinnedAt,
+			"pin_reason":        reason,
+			"pinned_until":      pinnedUntil,
+			"pinned_by_user_id": pinnedByUserID,
+		}).Error
+}
+
+func (r *BackupRepository) FindWithExpiredPin(now time.Time) ([]*Backup, error) {
+	var backups []*Backup
+
+	if err := storage.
+		GetDb().
+		Where("pinned_at IS NOT NULL AND pinned_until <= ?", now).
+		Find(&backups).Error; err != nil {
+		return nil, err
+	}
+
+	return backups, nil
+}
+
+func (r *BackupRepository) UpdateDeletionLockedUntil(
+	backupID uuid.UUID,
+	deletionLockedUntil *time.Time,
+) error {
+	return storage.
+		GetDb().
+		Model(&Backup{}).
+		Where("id = ?", backupID).
+		Update("deletion_locked_until", deletionLockedUntil).Error
+}
+
+// FindWithExpiredDeletionLock returns backups whose deletion was postponed
+// because of the storage lock and the lock is expired
+func (r *BackupRepository) FindWithExpiredDeletionLock(now time.Time) ([]*Backup, error) {
+	var backups []*Backup
+
+	if err := storage.
+		GetDb().
+		Where("deletion_locked_until IS NOT NULL AND deletion_locked_until <= ?", now).
+		Find(&backups).Error; err != nil {
+		return nil, err
+	}
+
+	return backups, nil
+}
+
+// FindToArchive returns completed backups of the backup config (not of its
+// schedules) placed in the storage and created before the time
+func (r *BackupRepository) FindToArchive(
+	databaseID uuid.UUID,
+	storageID uuid.UUID,
+	createdBefore time.Time,
+) ([]*Backup, error) {
+	var backups []*Backup
+
+	db := storage.
+		GetDb().
+		Where(
+			"database_id = ? AND storage_id = ? AND status = ? AND created_at < ?",
+			databaseID,
+			storageID,
+			BackupStatusCompleted,
+			createdBefore,
+		)
+
+	if err := whereScheduleID(db, nil).
+		Order("created_at ASC").
+		Find(&backups).Error; err != nil {
+		return nil, err
+	}
+
+	return backups, nil
+}
+
+// UpdateStorage moves the backup to the archive storage. The backup is
+// updated only if it is still in the source storage, so a backup deleted or
+// moved meanwhile is reported as not updated
+func (r *BackupRepository) UpdateStorage(
+	backupID uuid.UUID,
+	fromStorageID uuid.UUID,
+	toStorageID uuid.UUID,
+	archivedAt time.Time,
+) (bool, error) {
+	result := storage.
+		GetDb().
+		Model(&Backup{}).
+		Where("id = ? AND storage_id = ?", backupID, fromStorageID).
+		Updates(map[string]any{
+			"storage_id":  toStorageID,
+			"archived_at": archivedAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *BackupRepository) FindByIntegrityStatus(
+	status BackupIntegrityStatus,
+) ([]*Backup, error) {
+	var backups []*Backup
+
+	if err := storage.
+		GetDb().
+		Where("integrity_status = ?", status).
+		Find(&backups).Error; err != nil {
+		return nil, err
+	}
+
+	return backups, nil
+}
+
+// whereScheduleID limits the query to backups of the backup schedule, or to
+// backups of the backup config (manual ones included) if scheduleID is nil
+func whereScheduleID(db *gorm.DB, scheduleID *uuid.UUID) *gorm.DB {
+	if scheduleID == nil {
+		return db.Where("schedule_id IS NULL")
+	}
+
+	return db.Where("schedule_id = ?", *scheduleID)
+}
@@ -0,0 +1,97 @@
+package backups
+
+import (
+	"fmt"
+	"time"
+
+	backups_config "databasus-backend/internal/features/backups/config"
+)
+
+type gfsBucket struct {
+	count    int
+	periodOf func(t time.Time) string
+}
+
+// selectBackupsToDeleteByGfs returns backups which are out of the GFS
+// retention policy. Backups must be sorted by creation time, newest first.
+//
+// For every level (hourly, daily, weekly, monthly, yearly) the newest
+// completed backup of each of the last N periods containing backups is kept.
+// A backup kept by any level is not deleted. Failed and canceled backups are
+// deleted once they are older than the oldest kept backup, in progress
+// backups are never deleted
+func selectBackupsToDeleteByGfs(
+	backupConfig *backups_config.BackupConfig,
+	backups []*Backup,
+) []*Backup {
+	buckets := []gfsBucket{
+		{backupConfig.GfsHourlyCount, func(t time.Time) string {
+			return t.Format("2006-01-02T15")
+		}},
+		{backupConfig.GfsDailyCount, func(t time.Time) string {
+			return t.Format("2006-01-02")
+		}},
+		{backupConfig.GfsWeeklyCount, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}},
+		{backupConfig.GfsMonthlyCount, func(t time.Time) string {
+			return t.Format("2006-01")
+		}},
+		{backupConfig.GfsYearlyCount, func(t time.Time) string {
+			return t.Format("2006")
+		}},
+	}
+
+	keptBackups := make(map[*Backup]bool)
+
+	for _, bucket := range buckets {
+		if bucket.count <= 0 {
+			continue
+		}
+
+		seenPeriods := make(map[string]bool)
+
+		for _, backup := range backups {
+			if len(seenPeriods) >= bucket.count {
+				break
+			}
+
+			if backup.Status != BackupStatusCompleted {
+				continue
+			}
+
+			period := bucket.periodOf(backup.CreatedAt.UTC())
+			if seenPeriods[period] {
+				continue
+			}
+
+			seenPeriods[period] = true
+			keptBackups[backup] = true
+		}
+	}
+
+	var oldestKeptBackupTime *time.Time
+	for _, backup := range backups {
+		if keptBackups[backup] {
+			oldestKeptBackupTime = &backup.CreatedAt
+		}
+	}
+
+	backupsToDelete := make([]*Backup, 0)
+
+	for _, backup := range backups {
+		switch backup.Status {
+		case BackupStatusCompleted:
+			if !keptBackups[backup] {
+				backupsToDelete = append(backupsToDelete, backup)
+			}
+		case BackupStatusFailed, BackupStatusCanceled:
+			if oldestKeptBackupTime != nil && backup.CreatedAt.Before(*oldestKeptBackupTime) {
+				backupsToDelete = append(backupsToDelete, backup)
+			}
+		}
+	}
+
+	return backupsToDelete
+}
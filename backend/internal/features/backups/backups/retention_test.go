@@ -0,0 +1,119 @@
+package backups
+
+import (
+	"testing"
+	"time"
+
+	backups_config "databasus-backend/internal/features/backups/config"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SelectBackupsToDeleteByGfs_WithDailyCount_KeepsNewestBackupOfEachDay(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	// 3 backups per day for 5 days, newest first
+	backups := make([]*Backup, 0)
+	for day := range 5 {
+		for hour := range 3 {
+			backups = append(backups, createGfsTestBackup(
+				now.Add(-time.Duration(day)*24*time.Hour-time.Duration(hour)*time.Hour),
+				BackupStatusCompleted,
+			))
+		}
+	}
+
+	backupConfig := &backups_config.BackupConfig{
+		RetentionPolicyType: backups_config.RetentionPolicyTypeGfs,
+		GfsDailyCount:       3,
+	}
+
+	backupsToDelete := selectBackupsToDeleteByGfs(backupConfig, backups)
+
+	assert.Len(t, backupsToDelete, 12)
+	assert.NotContains(t, backupsToDelete, backups[0])
+	assert.NotContains(t, backupsToDelete, backups[3])
+	assert.NotContains(t, backupsToDelete, backups[6])
+	assert.Contains(t, backupsToDelete, backups[9])
+}
+
+func Test_SelectBackupsToDeleteByGfs_WithSeveralLevels_KeepsUnionOfLevels(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	// daily backups for 100 days, newest first
+	backups := make([]*Backup, 0)
+	for day := range 100 {
+		backups = append(backups, createGfsTestBackup(
+			now.Add(-time.Duration(day)*24*time.Hour),
+			BackupStatusCompleted,
+		))
+	}
+
+	backupConfig := &backups_config.BackupConfig{
+		RetentionPolicyType: backups_config.RetentionPolicyTypeGfs,
+		GfsDailyCount:       7,
+		GfsWeeklyCount:      4,
+		GfsMonthlyCount:     3,
+	}
+
+	backupsToDelete := selectBackupsToDeleteByGfs(backupConfig, backups)
+	keptBackups := filterNotDeletedBackups(backups, backupsToDelete)
+
+	// 7 daily, weekly ones of the 2nd-4th weeks (the 1st week is already
+	// kept by daily), monthly ones of February and January (March is kept)
+	assert.Len(t, keptBackups, 12)
+
+	for _, backup := range backups[:7] {
+		assert.Contains(t, keptBackups, backup)
+	}
+
+	// 2024-02-29 and 2024-01-31 are the newest backups of previous months
+	assert.Contains(t, keptBackups, backups[10])
+	assert.Contains(t, keptBackups, backups[39])
+}
+
+func Test_SelectBackupsToDeleteByGfs_WithFailedBackups_DeletesOnlyOlderThanKept(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	backups := []*Backup{
+		createGfsTestBackup(now, BackupStatusInProgress),
+		createGfsTestBackup(now.Add(-1*time.Hour), BackupStatusFailed),
+		createGfsTestBackup(now.Add(-24*time.Hour), BackupStatusCompleted),
+		createGfsTestBackup(now.Add(-48*time.Hour), BackupStatusCanceled),
+		createGfsTestBackup(now.Add(-72*time.Hour), BackupStatusCompleted),
+	}
+
+	backupConfig := &backups_config.BackupConfig{
+		RetentionPolicyType: backups_config.RetentionPolicyTypeGfs,
+		GfsDailyCount:       1,
+	}
+
+	backupsToDelete := selectBackupsToDeleteByGfs(backupConfig, backups)
+
+	assert.Equal(t, []*Backup{backups[3], backups[4]}, backupsToDelete)
+}
+
+func createGfsTestBackup(createdAt time.Time, status BackupStatus) *Backup {
+	return &Backup{
+		ID:        uuid.New(),
+		Status:    status,
+		CreatedAt: createdAt,
+	}
+}
+
+func filterNotDeletedBackups(backups []*Backup, backupsToDelete []*Backup) []*Backup {
+	deleted := make(map[*Backup]bool)
+	for _, backup := range backupsToDelete {
+		deleted[backup] = true
+	}
+
+	keptBackups := make([]*Backup, 0)
+	for _, backup := range backups {
+		if !deleted[backup] {
+			keptBackups = append(keptBackups, backup)
+		}
+	}
+
+	return keptBackups
+}
@@ -11,20 +11,30 @@ import (
 	"strings"
 	"time"
 
+	"databasus-backend/internal/config"
 	audit_logs "databasus-backend/internal/features/audit_logs"
+	common "databasus-backend/internal/features/backups/backups/common"
 	"databasus-backend/internal/features/backups/backups/encryption"
 	backups_config "databasus-backend/internal/features/backups/config"
+	backups_pauses "databasus-backend/internal/features/backups/pauses"
 	"databasus-backend/internal/features/databases"
 	encryption_secrets "databasus-backend/internal/features/encryption/secrets"
 	"databasus-backend/internal/features/notifiers"
 	"databasus-backend/internal/features/storages"
+	storages_common "databasus-backend/internal/features/storages/common"
 	users_models "databasus-backend/internal/features/users/models"
 	workspaces_services "databasus-backend/internal/features/workspaces/services"
 	util_encryption "databasus-backend/internal/util/encryption"
+	"databasus-backend/internal/util/jobs"
+	"databasus-backend/internal/util/period"
 
 	"github.com/google/uuid"
 )
 
+// deletion of a backup with a locked file is retried after this delay when
+// the storage does not tell until when the file is locked
+const lockedBackupDeletionRetryDelay = 1 * time.Hour
+
 type BackupService struct {
 	databaseService     *databases.DatabaseService
 	storageService      *storages.StorageService
@@ -44,19 +54,31 @@ type BackupService struct {
 	workspaceService     *workspaces_services.WorkspaceService
 	auditLogService      *audit_logs.AuditLogService
 	backupContextManager *BackupContextManager
+	workerPool           *BackupWorkerPool
+	backupPauseService   *backups_pauses.BackupPauseService
 }
 
 func (s *BackupService) AddBackupRemoveListener(listener BackupRemoveListener) {
 	s.backupRemoveListeners = append(s.backupRemoveListeners, listener)
 }
 
-func (s *BackupService) OnBeforeBackupsStorageChange(databaseID uuid.UUID) error {
-	err := s.deleteDbBackups(databaseID)
+// OnBeforeBackupsStorageChange removes backups of the database config from
+// the previous primary storage. Backups of schedules are kept, because
+// schedules have their own storages
+func (s *BackupService) OnBeforeBackupsStorageChange(
+	databaseID uuid.UUID,
+	oldStorageID uuid.UUID,
+) error {
+	backups, err := s.backupRepository.FindByDatabaseIDAndStorageID(
+		databaseID,
+		nil,
+		oldStorageID,
+	)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return s.deleteBackups(backups)
 }
 
 func (s *BackupService) OnBeforeDatabaseRemove(databaseID uuid.UUID) error {
@@ -89,7 +111,9 @@ func (s *BackupService) MakeBackupWithAuth(
 		return errors.New("insufficient permissions to create backup for this database")
 	}
 
-	go s.MakeBackup(databaseID, true)
+	if err := s.EnqueueBackup(databaseID, nil, true); err != nil {
+		return err
+	}
 
 	s.auditLogService.WriteAuditLog(
 		fmt.Sprintf("Backup manually initiated for database: %s", database.Name),
@@ -173,10 +197,31 @@ func (s *BackupService) DeleteBackup(
 		return errors.New("insufficient permissions to delete backup for this database")
 	}
 
-	if backup.Status == BackupStatusInProgress {
+	if backup.Status == BackupStatusInProgress || backup.Status == BackupStatusQueued {
 		return errors.New("backup is in progress")
 	}
 
+	if backup.IsPinned(time.Now().UTC()) {
+		return errBackupPinned
+	}
+
+	storage, err := s.storageService.GetStorageByID(backup.StorageID)
+	if err != nil {
+		return err
+	}
+
+	lockedUntil, err := storage.GetFileLockedUntil(s.fieldEncryptor, backup.ID)
+	if err != nil {
+		return err
+	}
+
+	if lockedUntil != nil {
+		return fmt.Errorf(
+			"backup file is locked by the storage until %s, it can be deleted after that",
+			lockedUntil.Format(time.RFC3339),
+		)
+	}
+
 	s.auditLogService.WriteAuditLog(
 		fmt.Sprintf(
 			"Backup deleted for database: %s (ID: %s)",
@@ -190,54 +235,212 @@ func (s *BackupService) DeleteBackup(
 	return s.deleteBackup(backup)
 }
 
-func (s *BackupService) MakeBackup(databaseID uuid.UUID, isLastTry bool) {
-	database, err := s.databaseService.GetDatabaseByID(databaseID)
+// EnqueueBackup puts a backup of the database into queue and starts queued
+// backups if workers are free. scheduleID is set for backups of a backup
+// schedule. Each schedule of the database and manual backups have at most
+// one queued or running backup: a backup which is already queued is not
+// queued again, while a running one returns an error, so the scheduled run
+// is retried after it is finished
+func (s *BackupService) EnqueueBackup(
+	databaseID uuid.UUID,
+	scheduleID *uuid.UUID,
+	isManual bool,
+) error {
+	backupConfig, err := s.backupConfigService.GetBackupConfigBySchedule(databaseID, scheduleID)
 	if err != nil {
-		s.logger.Error("Failed to get database by ID", "error", err)
-		return
+		return err
 	}
 
-	lastBackup, err := s.backupRepository.FindLastByDatabaseID(databaseID)
+	if backupConfig.StorageID == nil {
+		return errors.New("backup config storage ID is not defined")
+	}
+
+	backup := &Backup{
+		DatabaseID: databaseID,
+		StorageID:  *backupConfig.StorageID,
+		ScheduleID: scheduleID,
+
+		Status:   BackupStatusQueued,
+		Type:     common.BackupTypeDefault,
+		IsManual: isManual,
+
+		BackupSizeMb: 0,
+
+		CreatedAt: time.Now().UTC(),
+	}
+
+	activeBackup, err := s.backupRepository.QueueIfNotActive(backup)
 	if err != nil {
-		s.logger.Error("Failed to find last backup by database ID", "error", err)
-		return
+		return err
+	}
+
+	// the scheduled run is retried after the running backup is finished,
+	// while a queued backup already covers it
+	if activeBackup != nil && activeBackup.Status == BackupStatusInProgress {
+		return errors.New("backup is already in progress")
 	}
 
-	if lastBackup != nil && lastBackup.Status == BackupStatusInProgress {
-		s.logger.Error("Backup is in progress")
+	go s.DispatchQueuedBackups()
+
+	return nil
+}
+
+// DispatchQueuedBackups starts queued backups while the worker pool has free
+// slots. Backups which cannot be started because of host or storage limits
+// stay in queue, so other backups may pass them
+func (s *BackupService) DispatchQueuedBackups() {
+	s.workerPool.dispatchMu.Lock()
+	defer s.workerPool.dispatchMu.Unlock()
+
+	// queued backups are started after restart
+	if config.IsDraining() {
 		return
 	}
 
-	backupConfig, err := s.backupConfigService.GetBackupConfigByDbId(databaseID)
+	queuedBackups, err := s.backupRepository.FindQueued()
 	if err != nil {
-		s.logger.Error("Failed to get backup config by database ID", "error", err)
+		s.logger.Error("Failed to find queued backups", "error", err)
 		return
 	}
 
-	if backupConfig.StorageID == nil {
-		s.logger.Error("Backup config storage ID is not defined")
-		return
+	for _, backup := range queuedBackups {
+		if s.workerPool.IsFull() {
+			return
+		}
+
+		database, err := s.databaseService.GetDatabaseByID(backup.DatabaseID)
+		if err != nil {
+			s.logger.Error("Failed to get database of queued backup", "error", err)
+			continue
+		}
+
+		// scheduled backups and retries queued before a pause or a blackout
+		// window started wait for its end
+		if !backup.IsManual {
+			isBlocked, err := s.isQueuedBackupBlocked(backup, database)
+			if err != nil {
+				s.logger.Error("Failed to check queued backup", "backupId", backup.ID, "error", err)
+				continue
+			}
+
+			if isBlocked {
+				continue
+			}
+		}
+
+		if !s.workerPool.TryAcquire(backup.ID, database.GetHostKey(), backup.StorageID) {
+			continue
+		}
+
+		leaseExpiresAt := jobs.GetLeaseExpiresAt()
+
+		isClaimed, err := s.backupRepository.ClaimQueued(backup.ID, leaseExpiresAt)
+		if err != nil || !isClaimed {
+			if err != nil {
+				s.logger.Error("Failed to start queued backup", "backupId", backup.ID, "error", err)
+			}
+
+			s.workerPool.Release(backup.ID)
+			continue
+		}
+
+		backup.Status = BackupStatusInProgress
+		backup.Attempts++
+		backup.LeaseExpiresAt = &leaseExpiresAt
+
+		go func() {
+			stopHeartbeat := jobs.StartHeartbeat(s.logger, jobs.HeartbeatInterval, func() error {
+				return s.backupRepository.ExtendLease(backup.ID, jobs.GetLeaseExpiresAt())
+			})
+
+			defer func() {
+				stopHeartbeat()
+				s.workerPool.Release(backup.ID)
+				s.DispatchQueuedBackups()
+			}()
+
+			s.executeBackup(backup, database)
+		}()
 	}
+}
 
-	storage, err := s.storageService.GetStorageByID(*backupConfig.StorageID)
+// getScheduledBackupBlockReason returns why scheduled backups of the config
+// cannot be started now (a blackout window or a pause of the database's
+// workspace), empty if they can. Manual backups are never blocked
+func (s *BackupService) getScheduledBackupBlockReason(
+	backupConfig *backups_config.BackupConfig,
+	database *databases.Database,
+	now time.Time,
+) (string, error) {
+	if backupConfig.IsInBlackoutWindow(now) {
+		return "blackout window", nil
+	}
+
+	pause, err := s.backupPauseService.GetActivePause(database.WorkspaceID)
 	if err != nil {
-		s.logger.Error("Failed to get storage by ID", "error", err)
-		return
+		return "", err
 	}
 
-	backup := &Backup{
-		DatabaseID: databaseID,
-		StorageID:  storage.ID,
+	if pause != nil {
+		return "backups paused: " + pause.Reason, nil
+	}
+
+	return "", nil
+}
 
-		Status: BackupStatusInProgress,
+func (s *BackupService) isQueuedBackupBlocked(
+	backup *Backup,
+	database *databases.Database,
+) (bool, error) {
+	backupConfig, err := s.GetBackupConfigOfBackup(backup)
+	if err != nil {
+		return false, err
+	}
 
-		BackupSizeMb: 0,
+	if backupConfig == nil {
+		return false, nil
+	}
 
-		CreatedAt: time.Now().UTC(),
+	blockReason, err := s.getScheduledBackupBlockReason(backupConfig, database, time.Now().UTC())
+	if err != nil {
+		return false, err
 	}
 
-	if err := s.backupRepository.Save(backup); err != nil {
-		s.logger.Error("Failed to save backup", "error", err)
+	return blockReason != "", nil
+}
+
+// GetRunningBackupsCount returns the number of backups running in this
+// instance, used to drain them on shutdown
+func (s *BackupService) GetRunningBackupsCount() int {
+	return s.workerPool.GetRunningCount()
+}
+
+func (s *BackupService) executeBackup(backup *Backup, database *databases.Database) {
+	databaseID := database.ID
+
+	failBackup := func(errMsg string) {
+		backup.FailMessage = &errMsg
+		backup.Status = BackupStatusFailed
+
+		if err := s.backupRepository.Save(backup); err != nil {
+			s.logger.Error("Failed to save backup", "error", err)
+		}
+	}
+
+	backupConfig, err := s.backupConfigService.GetBackupConfigBySchedule(
+		databaseID,
+		backup.ScheduleID,
+	)
+	if err != nil {
+		s.logger.Error("Failed to get backup config by database ID", "error", err)
+		failBackup(fmt.Sprintf("failed to get backup config: %v", err))
+		return
+	}
+
+	storage, err := s.storageService.GetStorageByID(backup.StorageID)
+	if err != nil {
+		s.logger.Error("Failed to get storage by ID", "error", err)
+		failBackup(fmt.Sprintf("failed to get storage: %v", err))
 		return
 	}
 
@@ -254,7 +457,9 @@ func (s *BackupService) MakeBackup(databaseID uuid.UUID, isLastTry bool) {
 		}
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(
+		withBackupRetainUntil(context.Background(), backupConfig, backup),
+	)
 	s.backupContextManager.RegisterBackup(backup.ID, cancel)
 	defer s.backupContextManager.UnregisterBackup(backup.ID)
 
@@ -273,9 +478,24 @@ func (s *BackupService) MakeBackup(databaseID uuid.UUID, isLastTry bool) {
 		isCancelled := strings.Contains(errMsg, "backup cancelled") ||
 			strings.Contains(errMsg, "context canceled") ||
 			errors.Is(err, context.Canceled)
-		isShutdown := strings.Contains(errMsg, "shutdown")
+		isShutdown := strings.Contains(errMsg, "shutdown") || config.IsShouldShutdown()
+
+		// the backup is started again after restart, a graceful shutdown is
+		// not counted as a failed attempt and nobody is notified
+		if isShutdown {
+			backup.Status = BackupStatusQueued
+			backup.Attempts--
+			backup.BackupDurationMs = 0
+			backup.BackupSizeMb = 0
 
-		if isCancelled && !isShutdown {
+			if err := s.backupRepository.Save(backup); err != nil {
+				s.logger.Error("Failed to requeue backup interrupted by shutdown", "error", err)
+			}
+
+			return
+		}
+
+		if isCancelled {
 			backup.Status = BackupStatusCanceled
 			backup.BackupDurationMs = time.Since(start).Milliseconds()
 			backup.BackupSizeMb = 0
@@ -327,6 +547,8 @@ func (s *BackupService) MakeBackup(databaseID uuid.UUID, isLastTry bool) {
 			&errMsg,
 		)
 
+		s.scheduleRetry(backupConfig, backup)
+
 		return
 	}
 
@@ -338,6 +560,16 @@ func (s *BackupService) MakeBackup(databaseID uuid.UUID, isLastTry bool) {
 		backup.EncryptionSalt = backupMetadata.EncryptionSalt
 		backup.EncryptionIV = backupMetadata.EncryptionIV
 		backup.Encryption = backupMetadata.Encryption
+		backup.WalStartLsn = backupMetadata.WalStartLsn
+		backup.WalStopLsn = backupMetadata.WalStopLsn
+		backup.BinlogFile = backupMetadata.BinlogFile
+		backup.BinlogPosition = backupMetadata.BinlogPosition
+		backup.GtidSet = backupMetadata.GtidSet
+		backup.Checksum = backupMetadata.Checksum
+
+		if backupMetadata.Type != "" {
+			backup.Type = backupMetadata.Type
+		}
 	}
 
 	if err := s.backupRepository.Save(backup); err != nil {
@@ -357,9 +589,7 @@ func (s *BackupService) MakeBackup(databaseID uuid.UUID, isLastTry bool) {
 		)
 	}
 
-	if backup.Status != BackupStatusCompleted && !isLastTry {
-		return
-	}
+	s.startReplication(backup, backupConfig)
 
 	s.SendBackupNotification(
 		backupConfig,
@@ -369,6 +599,82 @@ func (s *BackupService) MakeBackup(databaseID uuid.UUID, isLastTry bool) {
 	)
 }
 
+// GetRemainedBackupTryCount returns the number of remaining backup tries for a given backup.
+// If the backup is not failed or the backup config does not allow retries, it returns 0.
+// If the backup is failed and the backup config allows retries, it returns the number of remaining tries.
+// If the backup is failed and the backup config does not allow retries, it returns 0.
+func (s *BackupService) GetRemainedBackupTryCount(lastBackup *Backup) int {
+	if lastBackup == nil {
+		return 0
+	}
+
+	if lastBackup.Status != BackupStatusFailed {
+		return 0
+	}
+
+	backupConfig, err := s.GetBackupConfigOfBackup(lastBackup)
+	if err != nil {
+		s.logger.Error("Failed to get backup config by database ID", "error", err)
+		return 0
+	}
+
+	if !backupConfig.IsRetryIfFailed {
+		return 0
+	}
+
+	maxFailedTriesCount := backupConfig.MaxFailedTriesCount
+
+	// tries are counted separately for each schedule
+	lastBackups, err := s.backupRepository.FindByDatabaseIDWithLimit(
+		lastBackup.DatabaseID,
+		lastBackup.ScheduleID,
+		maxFailedTriesCount,
+	)
+	if err != nil {
+		s.logger.Error("Failed to find last backups by database ID", "error", err)
+		return 0
+	}
+
+	lastFailedBackups := make([]*Backup, 0)
+
+	for _, backup := range lastBackups {
+		if backup.Status == BackupStatusFailed {
+			lastFailedBackups = append(lastFailedBackups, backup)
+		}
+	}
+
+	return maxFailedTriesCount - len(lastFailedBackups)
+}
+
+// scheduleRetry makes the scheduler queue the failed backup again on its
+// next run while failed tries remain
+func (s *BackupService) scheduleRetry(backupConfig *backups_config.BackupConfig, backup *Backup) {
+	if !backupConfig.IsBackupsEnabled || s.GetRemainedBackupTryCount(backup) <= 0 {
+		return
+	}
+
+	if err := s.backupConfigService.SetNextRunAt(backupConfig, time.Now().UTC()); err != nil {
+		s.logger.Error("Failed to schedule retry of failed backup", "error", err)
+	}
+}
+
+// GetBackupConfigOfBackup returns the config of the schedule which created
+// the backup. Backups of removed schedules get the backup config of the
+// database, e.g. to send notifications about them
+func (s *BackupService) GetBackupConfigOfBackup(
+	backup *Backup,
+) (*backups_config.BackupConfig, error) {
+	backupConfig, err := s.backupConfigService.GetBackupConfigBySchedule(
+		backup.DatabaseID,
+		backup.ScheduleID,
+	)
+	if errors.Is(err, backups_config.ErrBackupScheduleNotFound) {
+		return s.backupConfigService.GetBackupConfigByDbId(backup.DatabaseID)
+	}
+
+	return backupConfig, err
+}
+
 func (s *BackupService) SendBackupNotification(
 	backupConfig *backups_config.BackupConfig,
 	backup *Backup,
@@ -407,6 +713,24 @@ func (s *BackupService) SendBackupNotification(
 				database.Name,
 				workspace.Name,
 			)
+		case backups_config.NotificationBackupCleanupSkipped:
+			title = fmt.Sprintf(
+				"⚠️ Old backups kept for database \"%s\" (workspace \"%s\")",
+				database.Name,
+				workspace.Name,
+			)
+		case backups_config.NotificationBackupVerificationFailed:
+			title = fmt.Sprintf(
+				"❌ Backup verification failed for database \"%s\" (workspace \"%s\")",
+				database.Name,
+				workspace.Name,
+			)
+		case backups_config.NotificationBackupReplicationFailed:
+			title = fmt.Sprintf(
+				"❌ Backup replication failed for database \"%s\" (workspace \"%s\")",
+				database.Name,
+				workspace.Name,
+			)
 		}
 
 		message := ""
@@ -447,6 +771,181 @@ func (s *BackupService) GetBackup(backupID uuid.UUID) (*Backup, error) {
 	return s.backupRepository.FindByID(backupID)
 }
 
+// GetCompletedBackupsByStorageID returns completed backups which primary
+// file is placed in the storage
+func (s *BackupService) GetCompletedBackupsByStorageID(storageID uuid.UUID) ([]*Backup, error) {
+	return s.backupRepository.FindByStorageIdAndStatus(storageID, BackupStatusCompleted)
+}
+
+// GetCompletedBackupCopiesByStorageID returns completed copies of backups
+// replicated into the storage
+func (s *BackupService) GetCompletedBackupCopiesByStorageID(
+	storageID uuid.UUID,
+) ([]*BackupCopy, error) {
+	return s.backupRepository.FindCopiesByStorageIdAndStatus(
+		storageID,
+		BackupCopyStatusCompleted,
+	)
+}
+
+// FilterExistingBackupIDs returns IDs of the given list which belong to
+// backups of any status
+func (s *BackupService) FilterExistingBackupIDs(ids []uuid.UUID) ([]uuid.UUID, error) {
+	return s.backupRepository.FindExistingIDs(ids)
+}
+
+// GetOldestCompletedBackupByType returns the oldest completed backup of the given
+// type for the database or nil if there is no such backup
+func (s *BackupService) GetOldestCompletedBackupByType(
+	databaseID uuid.UUID,
+	backupType common.BackupType,
+) (*Backup, error) {
+	return s.backupRepository.FindOldestByDatabaseIdStatusAndType(
+		databaseID,
+		BackupStatusCompleted,
+		backupType,
+	)
+}
+
+// GetCompletedBackupsWithBinlogCoordinates returns completed MySQL and
+// MariaDB backups which can be a base for point-in-time recovery, oldest first
+func (s *BackupService) GetCompletedBackupsWithBinlogCoordinates(
+	databaseID uuid.UUID,
+) ([]*Backup, error) {
+	return s.backupRepository.FindWithBinlogCoordinatesByDatabaseIdAndStatus(
+		databaseID,
+		BackupStatusCompleted,
+	)
+}
+
+// GetLatestRestorableBackup returns the newest completed backup of the
+// database which can be restored into a running database (data directory
+// archives and server dumps are excluded) or nil if there is no such backup
+func (s *BackupService) GetLatestRestorableBackup(databaseID uuid.UUID) (*Backup, error) {
+	return s.backupRepository.FindLastByDatabaseIdAndStatusExcludingTypes(
+		databaseID,
+		BackupStatusCompleted,
+		[]common.BackupType{
+			common.BackupTypeBaseBackup,
+			common.BackupTypePhysical,
+			common.BackupTypeServerDump,
+		},
+	)
+}
+
+// GetBackupsToDeleteByRetention returns backups of the database which are out
+// of the retention policy of the backup config. Only backups of the schedule
+// of the config are considered, pinned backups are never returned. Expired backups protected by
+// KeepMinBackupsCount are returned separately and must not be deleted
+func (s *BackupService) GetBackupsToDeleteByRetention(
+	backupConfig *backups_config.BackupConfig,
+) ([]*Backup, []*Backup, error) {
+	expiredBackups, err := s.getExpiredBackups(backupConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	expiredBackups = filterUnpinnedBackups(expiredBackups, time.Now().UTC())
+
+	if backupConfig.KeepMinBackupsCount <= 0 || len(expiredBackups) == 0 {
+		return expiredBackups, []*Backup{}, nil
+	}
+
+	lastCompletedBackups, err := s.backupRepository.FindLastByDatabaseIdAndStatusWithLimit(
+		backupConfig.DatabaseID,
+		backupConfig.ScheduleID,
+		BackupStatusCompleted,
+		backupConfig.KeepMinBackupsCount,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	protectedBackupIDs := make(map[uuid.UUID]bool)
+	for _, backup := range lastCompletedBackups {
+		protectedBackupIDs[backup.ID] = true
+	}
+
+	backupsToDelete := make([]*Backup, 0)
+	keptBackups := make([]*Backup, 0)
+
+	for _, backup := range expiredBackups {
+		if protectedBackupIDs[backup.ID] {
+			keptBackups = append(keptBackups, backup)
+		} else {
+			backupsToDelete = append(backupsToDelete, backup)
+		}
+	}
+
+	return backupsToDelete, keptBackups, nil
+}
+
+// PreviewRetentionWithAuth returns backups which would be deleted by the
+// (not yet saved) retention policy of the backup config. Nothing is deleted
+func (s *BackupService) PreviewRetentionWithAuth(
+	user *users_models.User,
+	backupConfig *backups_config.BackupConfig,
+) (*PreviewRetentionResponse, error) {
+	if err := backupConfig.Validate(); err != nil {
+		return nil, err
+	}
+
+	database, err := s.databaseService.GetDatabase(user, backupConfig.DatabaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	backupsToDelete, keptBackups, err := s.GetBackupsToDeleteByRetention(backupConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	totalBackupsCount, err := s.backupRepository.CountByDatabaseID(database.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PreviewRetentionResponse{
+		BackupsToDelete:       backupsToDelete,
+		BackupsKeptByMinCount: keptBackups,
+		TotalBackupsCount:     totalBackupsCount,
+	}, nil
+}
+
+func (s *BackupService) getExpiredBackups(
+	backupConfig *backups_config.BackupConfig,
+) ([]*Backup, error) {
+	if backupConfig.IsGfsRetention() {
+		databaseBackups, err := s.backupRepository.FindByDatabaseID(backupConfig.DatabaseID)
+		if err != nil {
+			return nil, err
+		}
+
+		// GFS is set only on backup configs, backups of backup schedules
+		// are kept by retention of their schedules
+		configBackups := make([]*Backup, 0, len(databaseBackups))
+		for _, backup := range databaseBackups {
+			if backup.ScheduleID == nil {
+				configBackups = append(configBackups, backup)
+			}
+		}
+
+		return selectBackupsToDeleteByGfs(backupConfig, configBackups), nil
+	}
+
+	if backupConfig.StorePeriod == period.PeriodForever {
+		return []*Backup{}, nil
+	}
+
+	dateBeforeBackupsShouldBeDeleted := time.Now().UTC().Add(-backupConfig.StorePeriod.ToDuration())
+
+	return s.backupRepository.FindBackupsBeforeDate(
+		backupConfig.DatabaseID,
+		backupConfig.ScheduleID,
+		dateBeforeBackupsShouldBeDeleted,
+	)
+}
+
 func (s *BackupService) CancelBackup(
 	user *users_models.User,
 	backupID uuid.UUID,
@@ -473,12 +972,25 @@ func (s *BackupService) CancelBackup(
 		return errors.New("insufficient permissions to cancel backup for this database")
 	}
 
-	if backup.Status != BackupStatusInProgress {
-		return errors.New("backup is not in progress")
-	}
+	switch backup.Status {
+	case BackupStatusQueued:
+		isCancelled, err := s.backupRepository.CancelQueued(backupID)
+		if err != nil {
+			return err
+		}
 
-	if err := s.backupContextManager.CancelBackup(backupID); err != nil {
-		return err
+		// the backup has been started meanwhile
+		if !isCancelled {
+			if err := s.backupContextManager.CancelBackup(backupID); err != nil {
+				return err
+			}
+		}
+	case BackupStatusInProgress:
+		if err := s.backupContextManager.CancelBackup(backupID); err != nil {
+			return err
+		}
+	default:
+		return errors.New("backup is not in progress")
 	}
 
 	s.auditLogService.WriteAuditLog(
@@ -543,6 +1055,21 @@ func (s *BackupService) GetBackupFile(
 	return reader, backup, database, nil
 }
 
+// withBackupRetainUntil sets when retention deletes the backup, so storages
+// with object lock keep the backup file immutable until then
+func withBackupRetainUntil(
+	ctx context.Context,
+	backupConfig *backups_config.BackupConfig,
+	backup *Backup,
+) context.Context {
+	retentionEnd, ok := backupConfig.GetRetentionEnd(backup.CreatedAt)
+	if !ok {
+		return ctx
+	}
+
+	return storages_common.WithRetainUntil(ctx, retentionEnd)
+}
+
 func (s *BackupService) deleteBackup(backup *Backup) error {
 	for _, listener := range s.backupRemoveListeners {
 		if err := listener.OnBeforeBackupRemove(backup); err != nil {
@@ -556,6 +1083,12 @@ func (s *BackupService) deleteBackup(backup *Backup) error {
 	}
 
 	err = storage.DeleteFile(s.fieldEncryptor, backup.ID)
+	if errors.Is(err, storages_common.ErrFileLocked) {
+		// the file cannot be removed before the lock expires, so the backup
+		// is kept to remove the file later (see deleteBackupsWithExpiredLock)
+		return s.postponeBackupDeletion(backup, storage)
+	}
+
 	if err != nil {
 		// we do not return error here, because sometimes clean up performed
 		// before unavailable storage removal or change - therefore we should
@@ -563,22 +1096,61 @@ func (s *BackupService) deleteBackup(backup *Backup) error {
 		s.logger.Error("Failed to delete backup file", "error", err)
 	}
 
+	s.deleteBackupCopiesFiles(backup)
+
 	return s.backupRepository.DeleteByID(backup.ID)
 }
 
-func (s *BackupService) deleteDbBackups(databaseID uuid.UUID) error {
-	dbBackupsInProgress, err := s.backupRepository.FindByDatabaseIdAndStatus(
-		databaseID,
-		BackupStatusInProgress,
+func (s *BackupService) postponeBackupDeletion(
+	backup *Backup,
+	storage *storages.Storage,
+) error {
+	lockedUntil, err := storage.GetFileLockedUntil(s.fieldEncryptor, backup.ID)
+	if err != nil || lockedUntil == nil {
+		if err != nil {
+			s.logger.Error("Failed to check backup file lock", "backupId", backup.ID, "error", err)
+		}
+
+		retryAt := time.Now().UTC().Add(lockedBackupDeletionRetryDelay)
+		lockedUntil = &retryAt
+	}
+
+	if err := s.backupRepository.UpdateDeletionLockedUntil(backup.ID, lockedUntil); err != nil {
+		return err
+	}
+	backup.DeletionLockedUntil = lockedUntil
+
+	s.logger.Warn(
+		"Backup file is locked by the storage, deletion is postponed",
+		"backupId",
+		backup.ID,
+		"lockedUntil",
+		lockedUntil,
 	)
+
+	return nil
+}
+
+// deleteBackupsWithExpiredLock deletes backups whose deletion was postponed
+// because their files were locked by the storage. Backups of removed
+// databases are removed together with the database, their locked files are
+// left to the bucket lifecycle rules
+func (s *BackupService) deleteBackupsWithExpiredLock() error {
+	backups, err := s.backupRepository.FindWithExpiredDeletionLock(time.Now().UTC())
 	if err != nil {
 		return err
 	}
 
-	if len(dbBackupsInProgress) > 0 {
-		return errors.New("backup is in progress, storage cannot be removed")
+	for _, backup := range backups {
+		if err := s.deleteBackup(backup); err != nil {
+			s.logger.Error("Failed to delete backup", "backupId", backup.ID, "error", err)
+		}
 	}
 
+	return nil
+}
+
+func (s *BackupService) deleteDbBackups(databaseID uuid.UUID) error {
 	dbBackups, err := s.backupRepository.FindByDatabaseID(
 		databaseID,
 	)
@@ -586,8 +1158,24 @@ func (s *BackupService) deleteDbBackups(databaseID uuid.UUID) error {
 		return err
 	}
 
-	for _, dbBackup := range dbBackups {
-		err := s.deleteBackup(dbBackup)
+	return s.deleteBackups(dbBackups)
+}
+
+func (s *BackupService) deleteBackups(backups []*Backup) error {
+	for _, backup := range backups {
+		if backup.Status == BackupStatusInProgress {
+			return errors.New("backup is in progress, storage cannot be removed")
+		}
+	}
+
+	if len(filterUnpinnedBackups(backups, time.Now().UTC())) < len(backups) {
+		return errors.New(
+			"database has pinned backups, a workspace admin must unpin them before removal",
+		)
+	}
+
+	for _, backup := range backups {
+		err := s.deleteBackup(backup)
 		if err != nil {
 			return err
 		}
@@ -604,9 +1192,9 @@ func (s *BackupService) getBackupReader(backupID uuid.UUID) (io.ReadCloser, erro
 		return nil, fmt.Errorf("failed to find backup: %w", err)
 	}
 
-	storage, err := s.storageService.GetStorageByID(backup.StorageID)
+	storage, err := s.GetReadableBackupStorage(backup)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get storage: %w", err)
+		return nil, err
 	}
 
 	fileReader, err := storage.GetFile(s.fieldEncryptor, backup.ID)
@@ -614,9 +1202,18 @@ func (s *BackupService) getBackupReader(backupID uuid.UUID) (io.ReadCloser, erro
 		return nil, fmt.Errorf("failed to get backup file: %w", err)
 	}
 
+	return s.decryptBackupReader(backup, fileReader)
+}
+
+// decryptBackupReader wraps the backup file reader with DecryptionReader
+// when the backup is encrypted. The file reader is closed on error
+func (s *BackupService) decryptBackupReader(
+	backup *Backup,
+	fileReader io.ReadCloser,
+) (io.ReadCloser, error) {
 	// If not encrypted, return raw reader
 	if backup.Encryption == backups_config.BackupEncryptionNone {
-		s.logger.Info("Returning non-encrypted backup", "backupId", backupID)
+		s.logger.Info("Returning non-encrypted backup", "backupId", backup.ID)
 		return fileReader, nil
 	}
 
@@ -676,7 +1273,7 @@ func (s *BackupService) getBackupReader(backupID uuid.UUID) (io.ReadCloser, erro
 		return nil, fmt.Errorf("failed to create decrypting reader: %w", err)
 	}
 
-	s.logger.Info("Returning encrypted backup with decryption", "backupId", backupID)
+	s.logger.Info("Returning encrypted backup with decryption", "backupId", backup.ID)
 
 	return &decryptionReaderCloser{
 		decryptionReader,
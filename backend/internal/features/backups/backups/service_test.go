@@ -7,8 +7,10 @@ import (
 	"testing"
 	"time"
 
+	"databasus-backend/internal/config"
 	common "databasus-backend/internal/features/backups/backups/common"
 	backups_config "databasus-backend/internal/features/backups/config"
+	backups_pauses "databasus-backend/internal/features/backups/pauses"
 	"databasus-backend/internal/features/databases"
 	encryption_secrets "databasus-backend/internal/features/encryption/secrets"
 	"databasus-backend/internal/features/notifiers"
@@ -65,6 +67,8 @@ func Test_BackupExecuted_NotificationSent(t *testing.T) {
 			workspaces_services.GetWorkspaceService(),
 			nil,
 			NewBackupContextManager(),
+			NewBackupWorkerPool(1, 1, 1),
+			backups_pauses.GetBackupPauseService(),
 		}
 
 		// Set up expectations
@@ -78,7 +82,7 @@ func Test_BackupExecuted_NotificationSent(t *testing.T) {
 			}),
 		).Once()
 
-		backupService.MakeBackup(database.ID, true)
+		enqueueBackupAndWait(t, backupService, database.ID)
 
 		// Verify all expectations were met
 		mockNotificationSender.AssertExpectations(t)
@@ -113,9 +117,11 @@ func Test_BackupExecuted_NotificationSent(t *testing.T) {
 			workspaces_services.GetWorkspaceService(),
 			nil,
 			NewBackupContextManager(),
+			NewBackupWorkerPool(1, 1, 1),
+			backups_pauses.GetBackupPauseService(),
 		}
 
-		backupService.MakeBackup(database.ID, true)
+		enqueueBackupAndWait(t, backupService, database.ID)
 
 		// Verify all expectations were met
 		mockNotificationSender.AssertExpectations(t)
@@ -138,6 +144,8 @@ func Test_BackupExecuted_NotificationSent(t *testing.T) {
 			workspaces_services.GetWorkspaceService(),
 			nil,
 			NewBackupContextManager(),
+			NewBackupWorkerPool(1, 1, 1),
+			backups_pauses.GetBackupPauseService(),
 		}
 
 		// capture arguments
@@ -155,7 +163,7 @@ func Test_BackupExecuted_NotificationSent(t *testing.T) {
 			capturedMessage = args.Get(2).(string)
 		}).Once()
 
-		backupService.MakeBackup(database.ID, true)
+		enqueueBackupAndWait(t, backupService, database.ID)
 
 		// Verify expectations were met
 		mockNotificationSender.AssertExpectations(t)
@@ -169,6 +177,29 @@ func Test_BackupExecuted_NotificationSent(t *testing.T) {
 	})
 }
 
+// enqueueBackupAndWait queues a manual backup and waits until the worker
+// running it is released, so all notifications are already sent
+func enqueueBackupAndWait(t *testing.T, backupService *BackupService, databaseID uuid.UUID) {
+	t.Helper()
+
+	assert.NoError(t, backupService.EnqueueBackup(databaseID, nil, true))
+
+	deadline := time.Now().UTC().Add(10 * time.Second)
+	for time.Now().UTC().Before(deadline) {
+		lastBackup, err := backupRepository.FindLastByDatabaseID(databaseID)
+		if err == nil && lastBackup != nil &&
+			lastBackup.Status != BackupStatusQueued &&
+			lastBackup.Status != BackupStatusInProgress &&
+			backupService.workerPool.GetRunningCount() == 0 {
+			return
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	t.Fatal("backup was not finished in time")
+}
+
 type CreateFailedBackupUsecase struct {
 }
 
@@ -201,3 +232,33 @@ func (uc *CreateSuccessBackupUsecase) Execute(
 		Encryption:     backups_config.BackupEncryptionNone,
 	}, nil
 }
+
+func Test_EnqueueBackup_WhenDraining_BackupStaysQueued(t *testing.T) {
+	defer config.ResetShutdownForTesting()
+
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	database, _ := createTestDatabaseWithBackups(workspace, owner, router)
+
+	config.StartDraining()
+
+	backupService := GetBackupService()
+	assert.NoError(t, backupService.EnqueueBackup(database.ID, nil, true))
+
+	backupService.DispatchQueuedBackups()
+
+	queuedBackups, err := backupRepository.FindByDatabaseIdAndStatus(
+		database.ID,
+		BackupStatusQueued,
+	)
+	assert.NoError(t, err)
+	assert.Len(t, queuedBackups, 1)
+	assert.Equal(t, 0, backupService.GetRunningBackupsCount())
+
+	for _, backup := range queuedBackups {
+		_, err := backupRepository.CancelQueued(backup.ID)
+		assert.NoError(t, err)
+	}
+}
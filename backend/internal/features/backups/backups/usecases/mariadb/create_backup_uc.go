@@ -78,7 +78,11 @@ func (uc *CreateMariadbBackupUsecase) Execute(
 		return nil, fmt.Errorf("failed to decrypt database password: %w", err)
 	}
 
-	args := uc.buildMariadbDumpArgs(mdb)
+	args := uc.buildMariadbDumpArgs(
+		mdb,
+		backupConfig.IsBinlogArchivingEnabled,
+		backupConfig.BackupMode == backups_config.BackupModeSchemaOnly,
+	)
 
 	return uc.streamToStorage(
 		ctx,
@@ -100,6 +104,8 @@ func (uc *CreateMariadbBackupUsecase) Execute(
 
 func (uc *CreateMariadbBackupUsecase) buildMariadbDumpArgs(
 	mdb *mariadbtypes.MariadbDatabase,
+	isBinlogArchivingEnabled bool,
+	isSchemaOnly bool,
 ) []string {
 	args := []string{
 		"--host=" + mdb.Host,
@@ -113,13 +119,27 @@ func (uc *CreateMariadbBackupUsecase) buildMariadbDumpArgs(
 		"--verbose",
 	}
 
+	if isBinlogArchivingEnabled {
+		// writes binlog coordinates and GTID position into the dump header
+		// as comments. Requires RELOAD and BINLOG MONITOR privileges
+		args = append(args, "--master-data=2", "--gtid")
+	}
+
 	args = append(args, "--compress")
 
 	if mdb.IsHttps {
 		args = append(args, "--ssl")
 	}
 
+	if isSchemaOnly {
+		args = append(args, "--no-data")
+	}
+
 	if mdb.Database != nil && *mdb.Database != "" {
+		for _, table := range mdb.ExcludeTables {
+			args = append(args, "--ignore-table="+*mdb.Database+"."+table)
+		}
+
 		args = append(args, *mdb.Database)
 	}
 
@@ -187,7 +207,8 @@ func (uc *CreateMariadbBackupUsecase) streamToStorage(
 		return nil, err
 	}
 
-	zstdWriter, err := zstd.NewWriter(finalWriter,
+	hashingWriter := common.NewHashingWriter(finalWriter)
+	zstdWriter, err := zstd.NewWriter(hashingWriter,
 		zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(zstdStorageCompressionLevel)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create zstd writer: %w", err)
@@ -204,13 +225,20 @@ func (uc *CreateMariadbBackupUsecase) streamToStorage(
 		return nil, fmt.Errorf("start %s: %w", filepath.Base(mariadbBin), err)
 	}
 
+	var dumpReader io.Reader = pgStdout
+	var binlogCoordinatesReader *common.BinlogCoordinatesReader
+	if backupConfig.IsBinlogArchivingEnabled {
+		binlogCoordinatesReader = common.NewBinlogCoordinatesReader(pgStdout)
+		dumpReader = binlogCoordinatesReader
+	}
+
 	copyResultCh := make(chan error, 1)
 	bytesWrittenCh := make(chan int64, 1)
 	go func() {
 		bytesWritten, err := uc.copyWithShutdownCheck(
 			ctx,
 			countingWriter,
-			pgStdout,
+			dumpReader,
 			backupProgressListener,
 		)
 		bytesWrittenCh <- bytesWritten
@@ -253,9 +281,34 @@ func (uc *CreateMariadbBackupUsecase) streamToStorage(
 		return nil, fmt.Errorf("save to storage: %w", saveErr)
 	}
 
+	checksum := hashingWriter.GetChecksum()
+	backupMetadata.Checksum = &checksum
+
+	if binlogCoordinatesReader != nil {
+		uc.setBinlogCoordinates(&backupMetadata, binlogCoordinatesReader)
+	}
+
 	return &backupMetadata, nil
 }
 
+// setBinlogCoordinates saves binlog coordinates of the dump. Without them
+// the dump is still restorable, but cannot be a base for point-in-time
+// recovery (e.g. binary logging is disabled on the server)
+func (uc *CreateMariadbBackupUsecase) setBinlogCoordinates(
+	backupMetadata *common.BackupMetadata,
+	binlogCoordinatesReader *common.BinlogCoordinatesReader,
+) {
+	coordinates := binlogCoordinatesReader.GetCoordinates()
+	if coordinates == nil {
+		uc.logger.Warn(
+			"Binlog coordinates are not found in MariaDB dump, check binary logging is enabled",
+		)
+		return
+	}
+
+	backupMetadata.SetBinlogCoordinates(coordinates)
+}
+
 func (uc *CreateMariadbBackupUsecase) createTempMyCnfFile(
 	mdbConfig *mariadbtypes.MariadbDatabase,
 	password string,
@@ -10,6 +10,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -67,14 +68,22 @@ func (uc *CreateMongodbBackupUsecase) Execute(
 		return nil, fmt.Errorf("database name is required for mongodump backups")
 	}
 
+	if backupConfig.IsOplogArchivingEnabled && len(mdb.ExcludeCollections) > 0 {
+		return nil, fmt.Errorf("excluded collections are not supported in oplog mode")
+	}
+
+	if backupConfig.BackupMode == backups_config.BackupModeSchemaOnly {
+		return nil, fmt.Errorf("schema-only backups are not supported for MongoDB")
+	}
+
 	decryptedPassword, err := uc.fieldEncryptor.Decrypt(db.ID, mdb.Password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decrypt database password: %w", err)
 	}
 
-	args := uc.buildMongodumpArgs(mdb, decryptedPassword)
+	args := uc.buildMongodumpArgs(mdb, decryptedPassword, backupConfig.IsOplogArchivingEnabled)
 
-	return uc.streamToStorage(
+	backupMetadata, err := uc.streamToStorage(
 		ctx,
 		backupID,
 		backupConfig,
@@ -87,21 +96,44 @@ func (uc *CreateMongodbBackupUsecase) Execute(
 		storage,
 		backupProgressListener,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	if backupConfig.IsOplogArchivingEnabled {
+		backupMetadata.Type = common.BackupTypeOplogDump
+	}
+
+	return backupMetadata, nil
 }
 
+// buildMongodumpArgs dumps the configured database. In oplog mode the whole
+// instance is dumped with --oplog (--oplog cannot be combined with --db),
+// so the dump is consistent at the moment it finishes
 func (uc *CreateMongodbBackupUsecase) buildMongodumpArgs(
 	mdb *mongodbtypes.MongodbDatabase,
 	password string,
+	isOplogMode bool,
 ) []string {
 	uri := mdb.BuildMongodumpURI(password)
 
 	args := []string{
 		"--uri=" + uri,
-		"--db=" + mdb.Database,
 		"--archive",
 		"--gzip",
 	}
 
+	if isOplogMode {
+		args = append(args, "--oplog")
+	} else {
+		args = append(args, "--db="+mdb.Database)
+
+		// --excludeCollection requires --db, so it is not allowed in oplog mode
+		for _, collection := range mdb.ExcludeCollections {
+			args = append(args, "--excludeCollection="+collection)
+		}
+	}
+
 	// Use numParallelCollections based on CPU count
 	// Cap between 1 and 16 to balance performance and resource usage
 	parallelCollections := max(1, min(mdb.CpuCount, 16))
@@ -171,7 +203,8 @@ func (uc *CreateMongodbBackupUsecase) streamToStorage(
 		return nil, err
 	}
 
-	countingWriter := common.NewCountingWriter(finalWriter)
+	hashingWriter := common.NewHashingWriter(finalWriter)
+	countingWriter := common.NewCountingWriter(hashingWriter)
 
 	saveErrCh := make(chan error, 1)
 	go func() {
@@ -229,6 +262,9 @@ func (uc *CreateMongodbBackupUsecase) streamToStorage(
 		return nil, fmt.Errorf("save to storage: %w", saveErr)
 	}
 
+	checksum := hashingWriter.GetChecksum()
+	backupMetadata.Checksum = &checksum
+
 	return &backupMetadata, nil
 }
 
@@ -420,6 +456,14 @@ func (uc *CreateMongodbBackupUsecase) buildMongodumpErrorMessage(
 ) error {
 	stderrStr := string(stderrOutput)
 
+	if strings.Contains(stderrStr, "oplog") &&
+		(strings.Contains(stderrStr, "replica set") || strings.Contains(stderrStr, "not found")) {
+		return fmt.Errorf(
+			"oplog mode requires a replica set member, standalone servers have no oplog. stderr: %s",
+			stderrStr,
+		)
+	}
+
 	if len(stderrStr) > 0 {
 		return fmt.Errorf(
 			"%s failed: %w\nstderr: %s",
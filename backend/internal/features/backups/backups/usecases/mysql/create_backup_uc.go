@@ -78,7 +78,11 @@ func (uc *CreateMysqlBackupUsecase) Execute(
 		return nil, fmt.Errorf("failed to decrypt database password: %w", err)
 	}
 
-	args := uc.buildMysqldumpArgs(my)
+	args := uc.buildMysqldumpArgs(
+		my,
+		backupConfig.IsBinlogArchivingEnabled,
+		backupConfig.BackupMode == backups_config.BackupModeSchemaOnly,
+	)
 
 	return uc.streamToStorage(
 		ctx,
@@ -98,7 +102,11 @@ func (uc *CreateMysqlBackupUsecase) Execute(
 	)
 }
 
-func (uc *CreateMysqlBackupUsecase) buildMysqldumpArgs(my *mysqltypes.MysqlDatabase) []string {
+func (uc *CreateMysqlBackupUsecase) buildMysqldumpArgs(
+	my *mysqltypes.MysqlDatabase,
+	isBinlogArchivingEnabled bool,
+	isSchemaOnly bool,
+) []string {
 	args := []string{
 		"--host=" + my.Host,
 		"--port=" + strconv.Itoa(my.Port),
@@ -107,24 +115,48 @@ func (uc *CreateMysqlBackupUsecase) buildMysqldumpArgs(my *mysqltypes.MysqlDatab
 		"--routines",
 		"--triggers",
 		"--events",
-		"--set-gtid-purged=OFF",
 		"--quick",
 		"--verbose",
 	}
 
+	if isBinlogArchivingEnabled {
+		args = append(args, uc.getBinlogCoordinatesArgs(my.Version)...)
+	} else {
+		args = append(args, "--set-gtid-purged=OFF")
+	}
+
 	args = append(args, uc.getNetworkCompressionArgs(my.Version)...)
 
 	if my.IsHttps {
 		args = append(args, "--ssl-mode=REQUIRED")
 	}
 
+	if isSchemaOnly {
+		args = append(args, "--no-data")
+	}
+
 	if my.Database != nil && *my.Database != "" {
+		for _, table := range my.ExcludeTables {
+			args = append(args, "--ignore-table="+*my.Database+"."+table)
+		}
+
 		args = append(args, *my.Database)
 	}
 
 	return args
 }
 
+// getBinlogCoordinatesArgs makes mysqldump write binlog coordinates (and GTID
+// set on 8.0+) of the dump into its header as comments, so the dump is still
+// restorable as is. Requires RELOAD and REPLICATION CLIENT privileges
+func (uc *CreateMysqlBackupUsecase) getBinlogCoordinatesArgs(version tools.MysqlVersion) []string {
+	if version == tools.MysqlVersion57 {
+		return []string{"--master-data=2", "--set-gtid-purged=OFF"}
+	}
+
+	return []string{"--source-data=2", "--set-gtid-purged=COMMENTED"}
+}
+
 func (uc *CreateMysqlBackupUsecase) getNetworkCompressionArgs(version tools.MysqlVersion) []string {
 	const zstdCompressionLevel = 5
 
@@ -202,7 +234,8 @@ func (uc *CreateMysqlBackupUsecase) streamToStorage(
 		return nil, err
 	}
 
-	zstdWriter, err := zstd.NewWriter(finalWriter,
+	hashingWriter := common.NewHashingWriter(finalWriter)
+	zstdWriter, err := zstd.NewWriter(hashingWriter,
 		zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(zstdStorageCompressionLevel)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create zstd writer: %w", err)
@@ -219,13 +252,20 @@ func (uc *CreateMysqlBackupUsecase) streamToStorage(
 		return nil, fmt.Errorf("start %s: %w", filepath.Base(mysqlBin), err)
 	}
 
+	var dumpReader io.Reader = pgStdout
+	var binlogCoordinatesReader *common.BinlogCoordinatesReader
+	if backupConfig.IsBinlogArchivingEnabled {
+		binlogCoordinatesReader = common.NewBinlogCoordinatesReader(pgStdout)
+		dumpReader = binlogCoordinatesReader
+	}
+
 	copyResultCh := make(chan error, 1)
 	bytesWrittenCh := make(chan int64, 1)
 	go func() {
 		bytesWritten, err := uc.copyWithShutdownCheck(
 			ctx,
 			countingWriter,
-			pgStdout,
+			dumpReader,
 			backupProgressListener,
 		)
 		bytesWrittenCh <- bytesWritten
@@ -268,9 +308,34 @@ func (uc *CreateMysqlBackupUsecase) streamToStorage(
 		return nil, fmt.Errorf("save to storage: %w", saveErr)
 	}
 
+	checksum := hashingWriter.GetChecksum()
+	backupMetadata.Checksum = &checksum
+
+	if binlogCoordinatesReader != nil {
+		uc.setBinlogCoordinates(&backupMetadata, binlogCoordinatesReader)
+	}
+
 	return &backupMetadata, nil
 }
 
+// setBinlogCoordinates saves binlog coordinates of the dump. Without them
+// the dump is still restorable, but cannot be a base for point-in-time
+// recovery (e.g. binary logging is disabled on the server)
+func (uc *CreateMysqlBackupUsecase) setBinlogCoordinates(
+	backupMetadata *common.BackupMetadata,
+	binlogCoordinatesReader *common.BinlogCoordinatesReader,
+) {
+	coordinates := binlogCoordinatesReader.GetCoordinates()
+	if coordinates == nil {
+		uc.logger.Warn(
+			"Binlog coordinates are not found in MySQL dump, check binary logging is enabled",
+		)
+		return
+	}
+
+	backupMetadata.SetBinlogCoordinates(coordinates)
+}
+
 func (uc *CreateMysqlBackupUsecase) createTempMyCnfFile(
 	myConfig *mysqltypes.MysqlDatabase,
 	password string,
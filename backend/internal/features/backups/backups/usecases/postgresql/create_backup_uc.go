@@ -10,6 +10,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -40,6 +41,11 @@ const (
 	exitCodeConnectionError  = 2
 )
 
+var (
+	walStartPointRegex = regexp.MustCompile(`write-ahead log start point: ([0-9A-F]+/[0-9A-F]+)`)
+	walEndPointRegex   = regexp.MustCompile(`write-ahead log end point: ([0-9A-F]+/[0-9A-F]+)`)
+)
+
 type CreatePostgresqlBackupUsecase struct {
 	logger           *slog.Logger
 	secretKeyService *encryption_secrets.SecretKeyService
@@ -61,6 +67,53 @@ func (uc *CreatePostgresqlBackupUsecase) Execute(
 		completedMBs float64,
 	),
 ) (*common.BackupMetadata, error) {
+	pg := db.Postgresql
+
+	if pg == nil {
+		return nil, fmt.Errorf("postgresql database configuration is required for backups")
+	}
+
+	decryptedPassword, err := uc.fieldEncryptor.Decrypt(db.ID, pg.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt database password: %w", err)
+	}
+
+	if backupConfig.IsWalArchivingEnabled {
+		return uc.executeBaseBackup(
+			ctx,
+			backupID,
+			backupConfig,
+			db,
+			storage,
+			decryptedPassword,
+			backupProgressListener,
+		)
+	}
+
+	if backupConfig.BackupMethod == backups_config.BackupMethodPhysical {
+		return uc.executePhysicalBackup(
+			ctx,
+			backupID,
+			backupConfig,
+			db,
+			storage,
+			decryptedPassword,
+			backupProgressListener,
+		)
+	}
+
+	if backupConfig.BackupMethod == backups_config.BackupMethodServer {
+		return uc.executeServerBackup(
+			ctx,
+			backupID,
+			backupConfig,
+			db,
+			storage,
+			decryptedPassword,
+			backupProgressListener,
+		)
+	}
+
 	uc.logger.Info(
 		"Creating PostgreSQL backup via pg_dump custom format",
 		"databaseId",
@@ -69,21 +122,13 @@ func (uc *CreatePostgresqlBackupUsecase) Execute(
 		storage.ID,
 	)
 
-	pg := db.Postgresql
-
-	if pg == nil {
-		return nil, fmt.Errorf("postgresql database configuration is required for pg_dump backups")
-	}
-
 	if pg.Database == nil || *pg.Database == "" {
 		return nil, fmt.Errorf("database name is required for pg_dump backups")
 	}
 
 	args := uc.buildPgDumpArgs(pg)
-
-	decryptedPassword, err := uc.fieldEncryptor.Decrypt(db.ID, pg.Password)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decrypt database password: %w", err)
+	if backupConfig.BackupMode == backups_config.BackupModeSchemaOnly {
+		args = append(args, "--schema-only")
 	}
 
 	return uc.streamToStorage(
@@ -92,7 +137,7 @@ func (uc *CreatePostgresqlBackupUsecase) Execute(
 		backupConfig,
 		tools.GetPostgresqlExecutable(
 			pg.Version,
-			"pg_dump",
+			tools.PostgresqlExecutablePgDump,
 			config.GetEnv().EnvMode,
 			config.GetEnv().PostgresesInstallDir,
 		),
@@ -100,6 +145,86 @@ func (uc *CreatePostgresqlBackupUsecase) Execute(
 		decryptedPassword,
 		storage,
 		db,
+		common.BackupTypeDefault,
+		backupProgressListener,
+	)
+}
+
+// executeBaseBackup takes a physical base backup via pg_basebackup. WAL is not
+// included into the archive because it is continuously archived by the WAL
+// archiving service, so the base backup is only restorable together with
+// the archived WAL segments
+func (uc *CreatePostgresqlBackupUsecase) executeBaseBackup(
+	ctx context.Context,
+	backupID uuid.UUID,
+	backupConfig *backups_config.BackupConfig,
+	db *databases.Database,
+	storage *storages.Storage,
+	password string,
+	backupProgressListener func(completedMBs float64),
+) (*common.BackupMetadata, error) {
+	uc.logger.Info(
+		"Creating PostgreSQL base backup via pg_basebackup",
+		"databaseId",
+		db.ID,
+		"storageId",
+		storage.ID,
+	)
+
+	return uc.streamToStorage(
+		ctx,
+		backupID,
+		backupConfig,
+		tools.GetPostgresqlExecutable(
+			db.Postgresql.Version,
+			tools.PostgresqlExecutablePgBasebackup,
+			config.GetEnv().EnvMode,
+			config.GetEnv().PostgresesInstallDir,
+		),
+		uc.buildPgBasebackupArgs(db.Postgresql, "none"),
+		password,
+		storage,
+		db,
+		common.BackupTypeBaseBackup,
+		backupProgressListener,
+	)
+}
+
+// executePhysicalBackup takes a self-contained physical backup via
+// pg_basebackup. WAL generated during the backup is fetched into the archive,
+// so the extracted data directory can be started without any WAL archive
+func (uc *CreatePostgresqlBackupUsecase) executePhysicalBackup(
+	ctx context.Context,
+	backupID uuid.UUID,
+	backupConfig *backups_config.BackupConfig,
+	db *databases.Database,
+	storage *storages.Storage,
+	password string,
+	backupProgressListener func(completedMBs float64),
+) (*common.BackupMetadata, error) {
+	uc.logger.Info(
+		"Creating PostgreSQL physical backup via pg_basebackup",
+		"databaseId",
+		db.ID,
+		"storageId",
+		storage.ID,
+	)
+
+	return uc.streamToStorage(
+		ctx,
+		backupID,
+		backupConfig,
+		tools.GetPostgresqlExecutable(
+			db.Postgresql.Version,
+			tools.PostgresqlExecutablePgBasebackup,
+			config.GetEnv().EnvMode,
+			config.GetEnv().PostgresesInstallDir,
+		),
+		uc.buildPgBasebackupArgs(db.Postgresql, "fetch"),
+		password,
+		storage,
+		db,
+		common.BackupTypePhysical,
 		backupProgressListener,
 	)
 }
@@ -114,6 +239,7 @@ func (uc *CreatePostgresqlBackupUsecase) streamToStorage(
 	password string,
 	storage *storages.Storage,
 	db *databases.Database,
+	backupType common.BackupType,
 	backupProgressListener func(completedMBs float64),
 ) (*common.BackupMetadata, error) {
 	uc.logger.Info("Streaming PostgreSQL backup to storage", "pgBin", pgBin, "args", args)
@@ -167,7 +293,8 @@ func (uc *CreatePostgresqlBackupUsecase) streamToStorage(
 		return nil, err
 	}
 
-	countingWriter := common.NewCountingWriter(finalWriter)
+	hashingWriter := common.NewHashingWriter(finalWriter)
+	countingWriter := common.NewCountingWriter(hashingWriter)
 
 	// The backup ID becomes the object key / filename in storage
 
@@ -240,6 +367,16 @@ func (uc *CreatePostgresqlBackupUsecase) streamToStorage(
 		return nil, fmt.Errorf("save to storage: %w", saveErr)
 	}
 
+	backupMetadata.Type = backupType
+	if backupType.IsDataDirectoryArchive() {
+		backupMetadata.WalStartLsn, backupMetadata.WalStopLsn = parseBasebackupWalPositions(
+			string(stderrOutput),
+		)
+	}
+
+	checksum := hashingWriter.GetChecksum()
+	backupMetadata.Checksum = &checksum
+
 	return &backupMetadata, nil
 }
 
@@ -335,10 +472,50 @@ func (uc *CreatePostgresqlBackupUsecase) buildPgDumpArgs(pg *pgtypes.PostgresqlD
 		args = append(args, "-n", schema)
 	}
 
+	for _, table := range pg.ExcludeTables {
+		args = append(args, "--exclude-table="+quotePgDumpTablePattern(table))
+	}
+
+	for _, table := range pg.ExcludeTableData {
+		args = append(args, "--exclude-table-data="+quotePgDumpTablePattern(table))
+	}
+
 	compressionArgs := uc.getCompressionArgs(pg.Version)
 	return append(args, compressionArgs...)
 }
 
+// quotePgDumpTablePattern quotes "schema.table" so pg_dump matches the exact
+// names: pg_dump treats them as patterns and folds unquoted names to lower case
+func quotePgDumpTablePattern(qualifiedName string) string {
+	schemaName, tableName, _ := strings.Cut(qualifiedName, ".")
+
+	quote := func(identifier string) string {
+		return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+	}
+
+	return quote(schemaName) + "." + quote(tableName)
+}
+
+// buildPgBasebackupArgs builds pg_basebackup args. walMethod is "none" when
+// WAL is archived separately or "fetch" to include WAL into the archive
+// (streaming WAL is not possible when the archive is written to stdout)
+func (uc *CreatePostgresqlBackupUsecase) buildPgBasebackupArgs(
+	pg *pgtypes.PostgresqlDatabase,
+	walMethod string,
+) []string {
+	return []string{
+		"-D", "-", // write tar archive to stdout
+		"-Ft",
+		"-X", walMethod,
+		"--checkpoint=fast",
+		"--no-password",
+		"-h", pg.Host,
+		"-p", strconv.Itoa(pg.Port),
+		"-U", pg.Username,
+		"--verbose",
+	}
+}
+
 func (uc *CreatePostgresqlBackupUsecase) getCompressionArgs(
 	version tools.PostgresqlVersion,
 ) []string {
@@ -764,6 +941,25 @@ func (uc *CreatePostgresqlBackupUsecase) createTempPgpassFile(
 	return pgpassFile, nil
 }
 
+// parseBasebackupWalPositions extracts WAL start and end LSNs from pg_basebackup
+// verbose output, for example:
+// "pg_basebackup: write-ahead log start point: 0/2000028 on timeline 1"
+func parseBasebackupWalPositions(stderr string) (*string, *string) {
+	var startLsn, stopLsn *string
+
+	for line := range strings.SplitSeq(stderr, "\n") {
+		if match := walStartPointRegex.FindStringSubmatch(line); match != nil {
+			startLsn = &match[1]
+		}
+
+		if match := walEndPointRegex.FindStringSubmatch(line); match != nil {
+			stopLsn = &match[1]
+		}
+	}
+
+	return startLsn, stopLsn
+}
+
 func containsIgnoreCase(str, substr string) bool {
 	return strings.Contains(strings.ToLower(str), strings.ToLower(substr))
 }
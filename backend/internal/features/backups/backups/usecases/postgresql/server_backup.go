@@ -0,0 +1,381 @@
+package usecases_postgresql
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"databasus-backend/internal/config"
+	common "databasus-backend/internal/features/backups/backups/common"
+	backups_config "databasus-backend/internal/features/backups/config"
+	"databasus-backend/internal/features/databases"
+	pgtypes "databasus-backend/internal/features/databases/databases/postgresql"
+	"databasus-backend/internal/features/storages"
+	"databasus-backend/internal/util/tools"
+
+	"github.com/google/uuid"
+)
+
+const pgAuthidPermissionDeniedText = "permission denied for table pg_authid"
+
+// executeServerBackup backs up the whole server into a single tar archive:
+// globals (roles and tablespaces) via pg_dumpall --globals-only followed by
+// custom format dump of each database. Each part is spooled to a temporary
+// file first because tar headers require the size of the entry upfront
+func (uc *CreatePostgresqlBackupUsecase) executeServerBackup(
+	parentCtx context.Context,
+	backupID uuid.UUID,
+	backupConfig *backups_config.BackupConfig,
+	db *databases.Database,
+	storage *storages.Storage,
+	password string,
+	backupProgressListener func(completedMBs float64),
+) (*common.BackupMetadata, error) {
+	uc.logger.Info(
+		"Creating PostgreSQL server backup via pg_dumpall and pg_dump",
+		"databaseId",
+		db.ID,
+		"storageId",
+		storage.ID,
+	)
+
+	ctx, cancel := uc.createBackupContext(parentCtx)
+	defer cancel()
+
+	databaseNames, err := db.Postgresql.ListServerDatabases(uc.logger, uc.fieldEncryptor, db.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(databaseNames) == 0 {
+		return nil, fmt.Errorf("no databases found on the server")
+	}
+
+	pgpassFile, err := uc.setupPgpassFile(db.Postgresql, password)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = os.RemoveAll(filepath.Dir(pgpassFile))
+	}()
+
+	storageReader, storageWriter := io.Pipe()
+
+	finalWriter, encryptionWriter, backupMetadata, err := uc.setupBackupEncryption(
+		backupID,
+		backupConfig,
+		storageWriter,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	hashingWriter := common.NewHashingWriter(finalWriter)
+	countingWriter := common.NewCountingWriter(hashingWriter)
+
+	saveErrCh := make(chan error, 1)
+	go func() {
+		saveErr := storage.SaveFile(ctx, uc.fieldEncryptor, uc.logger, backupID, storageReader)
+		saveErrCh <- saveErr
+	}()
+
+	archiveErr := uc.writeServerArchive(
+		ctx,
+		db.Postgresql,
+		databaseNames,
+		pgpassFile,
+		password,
+		countingWriter,
+		backupProgressListener,
+	)
+
+	select {
+	case <-ctx.Done():
+		uc.cleanupOnCancellation(encryptionWriter, storageWriter, saveErrCh)
+		return nil, uc.checkCancellationReason()
+	default:
+	}
+
+	if archiveErr != nil {
+		storageWriter.CloseWithError(archiveErr)
+		<-saveErrCh
+		return nil, archiveErr
+	}
+
+	if err := uc.closeWriters(encryptionWriter, storageWriter); err != nil {
+		<-saveErrCh
+		return nil, err
+	}
+
+	if saveErr := <-saveErrCh; saveErr != nil {
+		if err := uc.checkCancellation(ctx); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("save to storage: %w", saveErr)
+	}
+
+	if backupProgressListener != nil {
+		backupProgressListener(float64(countingWriter.GetBytesWritten()) / (1024 * 1024))
+	}
+
+	backupMetadata.Type = common.BackupTypeServerDump
+
+	checksum := hashingWriter.GetChecksum()
+	backupMetadata.Checksum = &checksum
+
+	return &backupMetadata, nil
+}
+
+func (uc *CreatePostgresqlBackupUsecase) writeServerArchive(
+	ctx context.Context,
+	pg *pgtypes.PostgresqlDatabase,
+	databaseNames []string,
+	pgpassFile string,
+	password string,
+	countingWriter *common.CountingWriter,
+	backupProgressListener func(completedMBs float64),
+) error {
+	tarWriter := tar.NewWriter(countingWriter)
+
+	globalsFile, err := uc.dumpServerGlobals(ctx, pg, pgpassFile, password)
+	if err != nil {
+		return err
+	}
+
+	err = uc.appendServerArchivePart(
+		ctx,
+		tarWriter,
+		common.ServerDumpGlobalsEntry,
+		globalsFile,
+		countingWriter,
+		backupProgressListener,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, databaseName := range databaseNames {
+		uc.logger.Info("Dumping server database", "database", databaseName)
+
+		dumpFile, err := uc.dumpServerDatabase(ctx, pg, databaseName, pgpassFile, password)
+		if err != nil {
+			return err
+		}
+
+		err = uc.appendServerArchivePart(
+			ctx,
+			tarWriter,
+			common.ServerDumpDatabaseEntry(databaseName),
+			dumpFile,
+			countingWriter,
+			backupProgressListener,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return fmt.Errorf("failed to finalize server backup archive: %w", err)
+	}
+
+	return nil
+}
+
+// dumpServerGlobals dumps roles and tablespaces. Managed services often do
+// not allow reading pg_authid, in this case roles are dumped without passwords
+func (uc *CreatePostgresqlBackupUsecase) dumpServerGlobals(
+	ctx context.Context,
+	pg *pgtypes.PostgresqlDatabase,
+	pgpassFile string,
+	password string,
+) (string, error) {
+	pgBin := tools.GetPostgresqlExecutable(
+		pg.Version,
+		tools.PostgresqlExecutablePgDumpall,
+		config.GetEnv().EnvMode,
+		config.GetEnv().PostgresesInstallDir,
+	)
+
+	args := []string{
+		"--globals-only",
+		"--no-password",
+		"-h", pg.Host,
+		"-p", strconv.Itoa(pg.Port),
+		"-U", pg.Username,
+	}
+	if pg.Database != nil && *pg.Database != "" {
+		args = append(args, "-l", *pg.Database)
+	}
+
+	globalsFile, stderrOutput, err := uc.runPgToolToFile(
+		ctx,
+		pg,
+		pgBin,
+		args,
+		pgpassFile,
+		password,
+	)
+	if err != nil && containsIgnoreCase(string(stderrOutput), pgAuthidPermissionDeniedText) {
+		uc.logger.Warn("Cannot read role passwords, dumping globals without them")
+
+		args = append(args, "--no-role-passwords")
+		globalsFile, stderrOutput, err = uc.runPgToolToFile(
+			ctx,
+			pg,
+			pgBin,
+			args,
+			pgpassFile,
+			password,
+		)
+	}
+	if err != nil {
+		return "", uc.buildPgDumpErrorMessage(err, stderrOutput, pgBin, args, password)
+	}
+
+	return globalsFile, nil
+}
+
+func (uc *CreatePostgresqlBackupUsecase) dumpServerDatabase(
+	ctx context.Context,
+	pg *pgtypes.PostgresqlDatabase,
+	databaseName string,
+	pgpassFile string,
+	password string,
+) (string, error) {
+	pgBin := tools.GetPostgresqlExecutable(
+		pg.Version,
+		tools.PostgresqlExecutablePgDump,
+		config.GetEnv().EnvMode,
+		config.GetEnv().PostgresesInstallDir,
+	)
+
+	args := []string{
+		"-Fc",
+		"--no-password",
+		"-h", pg.Host,
+		"-p", strconv.Itoa(pg.Port),
+		"-U", pg.Username,
+		"-d", databaseName,
+		"--verbose",
+	}
+	args = append(args, uc.getCompressionArgs(pg.Version)...)
+
+	dumpFile, stderrOutput, err := uc.runPgToolToFile(ctx, pg, pgBin, args, pgpassFile, password)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed to dump database '%s': %w",
+			databaseName,
+			uc.buildPgDumpErrorMessage(err, stderrOutput, pgBin, args, password),
+		)
+	}
+
+	return dumpFile, nil
+}
+
+// runPgToolToFile runs the tool with stdout redirected to a new temporary file
+// and returns the file path with captured stderr. The file is removed on error
+func (uc *CreatePostgresqlBackupUsecase) runPgToolToFile(
+	ctx context.Context,
+	pg *pgtypes.PostgresqlDatabase,
+	pgBin string,
+	args []string,
+	pgpassFile string,
+	password string,
+) (string, []byte, error) {
+	outputFile, err := os.CreateTemp(config.GetEnv().TempFolder, "server_backup_*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, pgBin, args...)
+	uc.logger.Info("Executing PostgreSQL backup command", "command", cmd.String())
+
+	err = uc.setupPgEnvironment(cmd, pgpassFile, pg.IsHttps, password, pg.CpuCount, pgBin)
+	if err != nil {
+		_ = outputFile.Close()
+		_ = os.Remove(outputFile.Name())
+		return "", nil, err
+	}
+
+	stderrBuffer := &bytes.Buffer{}
+	cmd.Stdout = outputFile
+	cmd.Stderr = stderrBuffer
+
+	runErr := cmd.Run()
+	closeErr := outputFile.Close()
+
+	if runErr == nil && closeErr != nil {
+		runErr = fmt.Errorf("failed to close temporary file: %w", closeErr)
+	}
+
+	if runErr != nil {
+		_ = os.Remove(outputFile.Name())
+		if err := uc.checkCancellation(ctx); err != nil {
+			return "", nil, err
+		}
+		return "", stderrBuffer.Bytes(), runErr
+	}
+
+	return outputFile.Name(), stderrBuffer.Bytes(), nil
+}
+
+// appendServerArchivePart copies the file into the archive and removes it
+func (uc *CreatePostgresqlBackupUsecase) appendServerArchivePart(
+	ctx context.Context,
+	tarWriter *tar.Writer,
+	entryName string,
+	partFile string,
+	countingWriter *common.CountingWriter,
+	backupProgressListener func(completedMBs float64),
+) error {
+	defer func() {
+		if err := os.Remove(partFile); err != nil {
+			uc.logger.Error("Failed to remove server backup part", "file", partFile, "error", err)
+		}
+	}()
+
+	file, err := os.Open(partFile)
+	if err != nil {
+		return fmt.Errorf("failed to open server backup part: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat server backup part: %w", err)
+	}
+
+	err = tarWriter.WriteHeader(&tar.Header{
+		Name:    entryName,
+		Mode:    common.ServerDumpEntryFileMode,
+		Size:    info.Size(),
+		ModTime: time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write archive header for %s: %w", entryName, err)
+	}
+
+	var partProgressListener func(completedMBs float64)
+	if backupProgressListener != nil {
+		writtenBeforeMB := float64(countingWriter.GetBytesWritten()) / (1024 * 1024)
+		partProgressListener = func(completedMBs float64) {
+			backupProgressListener(writtenBeforeMB + completedMBs)
+		}
+	}
+
+	if _, err := uc.copyWithShutdownCheck(ctx, tarWriter, file, partProgressListener); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %w", entryName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package backups
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// BackupWorkerPool keeps track of running backups and limits how many of
+// them run at once: in total, against the same database host and into the
+// same storage. Queued backups are started only when a slot is acquired
+type BackupWorkerPool struct {
+	// serializes picking of queued backups, so a backup is not started twice
+	dispatchMu sync.Mutex
+
+	mu      sync.Mutex
+	running map[uuid.UUID]backupSlot
+
+	maxConcurrent int
+	maxPerHost    int
+	maxPerStorage int
+}
+
+type backupSlot struct {
+	hostKey   string
+	storageID uuid.UUID
+}
+
+func NewBackupWorkerPool(maxConcurrent, maxPerHost, maxPerStorage int) *BackupWorkerPool {
+	return &BackupWorkerPool{
+		running:       make(map[uuid.UUID]backupSlot),
+		maxConcurrent: maxConcurrent,
+		maxPerHost:    maxPerHost,
+		maxPerStorage: maxPerStorage,
+	}
+}
+
+// TryAcquire takes a slot for the backup if none of the limits is reached
+func (p *BackupWorkerPool) TryAcquire(
+	backupID uuid.UUID,
+	hostKey string,
+	storageID uuid.UUID,
+) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, isRunning := p.running[backupID]; isRunning {
+		return false
+	}
+
+	if len(p.running) >= p.maxConcurrent {
+		return false
+	}
+
+	hostCount := 0
+	storageCount := 0
+	for _, slot := range p.running {
+		if slot.hostKey == hostKey {
+			hostCount++
+		}
+
+		if slot.storageID == storageID {
+			storageCount++
+		}
+	}
+
+	if hostCount >= p.maxPerHost || storageCount >= p.maxPerStorage {
+		return false
+	}
+
+	p.running[backupID] = backupSlot{hostKey: hostKey, storageID: storageID}
+
+	return true
+}
+
+func (p *BackupWorkerPool) Release(backupID uuid.UUID) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.running, backupID)
+}
+
+func (p *BackupWorkerPool) IsRunning(backupID uuid.UUID) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, isRunning := p.running[backupID]
+
+	return isRunning
+}
+
+func (p *BackupWorkerPool) IsFull() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.running) >= p.maxConcurrent
+}
+
+func (p *BackupWorkerPool) GetRunningCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.running)
+}
@@ -0,0 +1,50 @@
+package backups
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TryAcquire_WhenGlobalLimitReached_SlotNotAcquired(t *testing.T) {
+	pool := NewBackupWorkerPool(2, 10, 10)
+
+	assert.True(t, pool.TryAcquire(uuid.New(), "db1:5432", uuid.New()))
+	assert.True(t, pool.TryAcquire(uuid.New(), "db2:5432", uuid.New()))
+	assert.True(t, pool.IsFull())
+
+	assert.False(t, pool.TryAcquire(uuid.New(), "db3:5432", uuid.New()))
+	assert.Equal(t, 2, pool.GetRunningCount())
+}
+
+func Test_TryAcquire_WhenHostLimitReached_OtherHostsAcquired(t *testing.T) {
+	pool := NewBackupWorkerPool(10, 1, 10)
+
+	assert.True(t, pool.TryAcquire(uuid.New(), "db1:5432", uuid.New()))
+	assert.False(t, pool.TryAcquire(uuid.New(), "db1:5432", uuid.New()))
+	assert.True(t, pool.TryAcquire(uuid.New(), "db1:5433", uuid.New()))
+}
+
+func Test_TryAcquire_WhenStorageLimitReached_OtherStoragesAcquired(t *testing.T) {
+	pool := NewBackupWorkerPool(10, 10, 1)
+	storageID := uuid.New()
+
+	assert.True(t, pool.TryAcquire(uuid.New(), "db1:5432", storageID))
+	assert.False(t, pool.TryAcquire(uuid.New(), "db2:5432", storageID))
+	assert.True(t, pool.TryAcquire(uuid.New(), "db2:5432", uuid.New()))
+}
+
+func Test_Release_WhenSlotReleased_NextBackupAcquired(t *testing.T) {
+	pool := NewBackupWorkerPool(1, 1, 1)
+	backupID := uuid.New()
+	storageID := uuid.New()
+
+	assert.True(t, pool.TryAcquire(backupID, "db1:5432", storageID))
+	assert.False(t, pool.TryAcquire(backupID, "db1:5432", storageID))
+	assert.False(t, pool.TryAcquire(uuid.New(), "db1:5432", storageID))
+
+	pool.Release(backupID)
+
+	assert.True(t, pool.TryAcquire(uuid.New(), "db1:5432", storageID))
+}
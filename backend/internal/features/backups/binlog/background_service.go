@@ -0,0 +1,725 @@
+package backups_binlog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"databasus-backend/internal/config"
+	"databasus-backend/internal/features/backups/backups"
+	backups_config "databasus-backend/internal/features/backups/config"
+	"databasus-backend/internal/features/databases"
+	"databasus-backend/internal/features/storages"
+	"databasus-backend/internal/util/encryption"
+	"databasus-backend/internal/util/tools"
+
+	"github.com/google/uuid"
+)
+
+const (
+	binlogUploadInterval = 10 * time.Second
+	// binlogFlushInterval bounds the amount of changes which are not
+	// archived yet: the current binary log is uploaded only after rotation
+	binlogFlushInterval  = 5 * time.Minute
+	receiverRestartDelay = 30 * time.Second
+	receiverServerIDBase = 1000000
+)
+
+type binlogReceiver struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// binlogConnection holds everything required to connect to the server
+// with the mysql client and mysqlbinlog (or their MariaDB counterparts)
+type binlogConnection struct {
+	myCnfContent    string
+	clientBin       string
+	binlogBin       string
+	statusQuery     string
+	serverIDArgName string
+}
+
+// BinlogArchivingBackgroundService keeps mysqlbinlog streaming binary logs
+// of each database with enabled binlog archiving, uploads completed files
+// into the storage and removes binary logs which are not needed to replay
+// any of the remaining backups
+type BinlogArchivingBackgroundService struct {
+	binlogService       *BinlogService
+	backupService       *backups.BackupService
+	backupConfigService *backups_config.BackupConfigService
+	databaseService     *databases.DatabaseService
+	storageService      *storages.StorageService
+	fieldEncryptor      encryption.FieldEncryptor
+	logger              *slog.Logger
+
+	receivers map[uuid.UUID]*binlogReceiver
+	mu        sync.Mutex
+}
+
+func (s *BinlogArchivingBackgroundService) Run() {
+	s.logger.Info("Starting binlog archiving background service")
+
+	for {
+		if config.IsShouldShutdown() {
+			s.stopAllReceivers()
+			return
+		}
+
+		if err := s.syncReceivers(); err != nil {
+			s.logger.Error("Failed to sync binlog receivers", "error", err)
+		}
+
+		if err := s.cleanOldFiles(); err != nil {
+			s.logger.Error("Failed to clean old binlog files", "error", err)
+		}
+
+		time.Sleep(1 * time.Minute)
+	}
+}
+
+// Stop stops all binlog receivers and waits for them to exit. It is called on
+// shutdown after config.Shutdown, so receivers are not started again
+func (s *BinlogArchivingBackgroundService) Stop() {
+	s.stopAllReceivers()
+}
+
+func (s *BinlogArchivingBackgroundService) OnBeforeDatabaseRemove(databaseID uuid.UUID) error {
+	s.stopReceiver(databaseID)
+	_ = os.RemoveAll(getBinlogDirectory(databaseID))
+
+	return s.binlogService.DeleteDatabaseBinlogFiles(databaseID)
+}
+
+func (s *BinlogArchivingBackgroundService) syncReceivers() error {
+	enabledBackupConfigs, err := s.backupConfigService.GetBackupConfigsWithEnabledBackups()
+	if err != nil {
+		return err
+	}
+
+	binlogDatabaseIDs := make([]uuid.UUID, 0)
+	for _, backupConfig := range enabledBackupConfigs {
+		if backupConfig.IsBinlogArchivingEnabled && backupConfig.StorageID != nil {
+			binlogDatabaseIDs = append(binlogDatabaseIDs, backupConfig.DatabaseID)
+		}
+	}
+
+	s.mu.Lock()
+	runningDatabaseIDs := make([]uuid.UUID, 0, len(s.receivers))
+	for databaseID := range s.receivers {
+		runningDatabaseIDs = append(runningDatabaseIDs, databaseID)
+	}
+
+	for _, databaseID := range binlogDatabaseIDs {
+		if _, isRunning := s.receivers[databaseID]; isRunning {
+			continue
+		}
+
+		if config.IsShouldShutdown() {
+			break
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		receiver := &binlogReceiver{cancel, make(chan struct{})}
+		s.receivers[databaseID] = receiver
+
+		go s.runReceiver(ctx, databaseID, receiver.done)
+		s.logger.Info("Started binlog receiver", "databaseId", databaseID)
+	}
+	s.mu.Unlock()
+
+	for _, databaseID := range runningDatabaseIDs {
+		if slices.Contains(binlogDatabaseIDs, databaseID) {
+			continue
+		}
+
+		if s.stopReceiver(databaseID) {
+			_ = os.RemoveAll(getBinlogDirectory(databaseID))
+		}
+	}
+
+	return nil
+}
+
+func (s *BinlogArchivingBackgroundService) cleanOldFiles() error {
+	enabledBackupConfigs, err := s.backupConfigService.GetBackupConfigsWithEnabledBackups()
+	if err != nil {
+		return err
+	}
+
+	for _, backupConfig := range enabledBackupConfigs {
+		baseBackups, err := s.backupService.GetCompletedBackupsWithBinlogCoordinates(
+			backupConfig.DatabaseID,
+		)
+		if err != nil {
+			s.logger.Error(
+				"Failed to get backups with binlog coordinates",
+				"databaseId",
+				backupConfig.DatabaseID,
+				"error",
+				err,
+			)
+			continue
+		}
+
+		if len(baseBackups) == 0 {
+			if !backupConfig.IsBinlogArchivingEnabled {
+				// no backups left to replay binary logs on
+				if err := s.binlogService.DeleteDatabaseBinlogFiles(
+					backupConfig.DatabaseID,
+				); err != nil {
+					s.logger.Error(
+						"Failed to delete binlog files",
+						"databaseId",
+						backupConfig.DatabaseID,
+						"error",
+						err,
+					)
+				}
+			}
+
+			continue
+		}
+
+		if err := s.binlogService.DeleteFilesBeforeBackup(baseBackups[0]); err != nil {
+			s.logger.Error(
+				"Failed to delete old binlog files",
+				"databaseId",
+				backupConfig.DatabaseID,
+				"error",
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+func (s *BinlogArchivingBackgroundService) runReceiver(
+	ctx context.Context,
+	databaseID uuid.UUID,
+	done chan struct{},
+) {
+	defer close(done)
+
+	for {
+		if ctx.Err() != nil || config.IsShouldShutdown() {
+			return
+		}
+
+		if err := s.receiveBinlogs(ctx, databaseID); err != nil && ctx.Err() == nil {
+			s.logger.Error("Binlog receiver failed", "databaseId", databaseID, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(receiverRestartDelay):
+		}
+	}
+}
+
+// receiveBinlogs runs mysqlbinlog until it exits, uploads completed binary
+// logs into the storage and periodically rotates the binary log meanwhile
+func (s *BinlogArchivingBackgroundService) receiveBinlogs(
+	ctx context.Context,
+	databaseID uuid.UUID,
+) error {
+	database, err := s.databaseService.GetDatabaseByID(databaseID)
+	if err != nil {
+		return err
+	}
+
+	backupConfig, err := s.backupConfigService.GetBackupConfigByDbId(databaseID)
+	if err != nil {
+		return err
+	}
+
+	if backupConfig.StorageID == nil {
+		return errors.New("backup config storage ID is not defined")
+	}
+
+	storage, err := s.storageService.GetStorageByID(*backupConfig.StorageID)
+	if err != nil {
+		return err
+	}
+
+	connection, err := s.getBinlogConnection(database)
+	if err != nil {
+		return err
+	}
+
+	myCnfFile, err := createTempMyCnfFile(connection.myCnfContent)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.RemoveAll(filepath.Dir(myCnfFile))
+	}()
+
+	binlogDir := getBinlogDirectory(databaseID)
+	if err := os.MkdirAll(binlogDir, 0700); err != nil {
+		return fmt.Errorf("failed to create binlog directory: %w", err)
+	}
+
+	startFileName, err := s.getStartFileName(ctx, databaseID, binlogDir, connection, myCnfFile)
+	if err != nil {
+		return err
+	}
+
+	receiverCtx, stopUploads := context.WithCancel(ctx)
+	uploadsDone := make(chan struct{})
+	go func() {
+		defer close(uploadsDone)
+		s.runUploadsLoop(receiverCtx, backupConfig, storage, binlogDir, connection, myCnfFile)
+	}()
+
+	_, receiveErr := runMysqlTool(
+		ctx,
+		connection.binlogBin,
+		myCnfFile,
+		"--read-from-remote-server",
+		"--raw",
+		"--stop-never",
+		fmt.Sprintf("%s=%d", connection.serverIDArgName, getReceiverServerID(databaseID)),
+		"--result-file="+binlogDir+string(os.PathSeparator),
+		startFileName,
+	)
+
+	stopUploads()
+	<-uploadsDone
+
+	if ctx.Err() == nil && !config.IsShouldShutdown() {
+		if err := s.archiveCompletedFiles(ctx, backupConfig, storage, binlogDir); err != nil {
+			s.logger.Error(
+				"Failed to archive binlog files",
+				"databaseId",
+				databaseID,
+				"error",
+				err,
+			)
+		}
+	}
+
+	return receiveErr
+}
+
+func (s *BinlogArchivingBackgroundService) runUploadsLoop(
+	ctx context.Context,
+	backupConfig *backups_config.BackupConfig,
+	storage *storages.Storage,
+	binlogDir string,
+	connection *binlogConnection,
+	myCnfFile string,
+) {
+	uploadTicker := time.NewTicker(binlogUploadInterval)
+	defer uploadTicker.Stop()
+
+	flushTicker := time.NewTicker(binlogFlushInterval)
+	defer flushTicker.Stop()
+
+	lastFlushedStatus := ""
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-flushTicker.C:
+			if config.IsShouldShutdown() {
+				return
+			}
+
+			status, err := s.flushIfChanged(ctx, connection, myCnfFile, lastFlushedStatus)
+			if err != nil {
+				s.logger.Error(
+					"Failed to flush binary logs",
+					"databaseId",
+					backupConfig.DatabaseID,
+					"error",
+					err,
+				)
+				continue
+			}
+
+			lastFlushedStatus = status
+		case <-uploadTicker.C:
+			if config.IsShouldShutdown() {
+				return
+			}
+
+			if err := s.archiveCompletedFiles(ctx, backupConfig, storage, binlogDir); err != nil {
+				s.logger.Error(
+					"Failed to archive binlog files",
+					"databaseId",
+					backupConfig.DatabaseID,
+					"error",
+					err,
+				)
+			}
+		}
+	}
+}
+
+// flushIfChanged rotates the binary log when anything was written since the
+// previous rotation, so the current file becomes complete and gets archived.
+// Returns the binary log status right after the rotation
+func (s *BinlogArchivingBackgroundService) flushIfChanged(
+	ctx context.Context,
+	connection *binlogConnection,
+	myCnfFile string,
+	lastFlushedStatus string,
+) (string, error) {
+	status, err := getBinlogStatus(ctx, connection, myCnfFile)
+	if err != nil {
+		return lastFlushedStatus, err
+	}
+
+	if status == lastFlushedStatus {
+		return lastFlushedStatus, nil
+	}
+
+	if _, err := runMysqlTool(
+		ctx,
+		connection.clientBin,
+		myCnfFile,
+		"-N",
+		"-e",
+		"FLUSH BINARY LOGS",
+	); err != nil {
+		return lastFlushedStatus, fmt.Errorf(
+			"failed to rotate binary log, the user must have RELOAD privilege: %w",
+			err,
+		)
+	}
+
+	return getBinlogStatus(ctx, connection, myCnfFile)
+}
+
+// archiveCompletedFiles uploads binary logs except the newest one, which
+// is still being written. Uploaded files are removed locally
+func (s *BinlogArchivingBackgroundService) archiveCompletedFiles(
+	ctx context.Context,
+	backupConfig *backups_config.BackupConfig,
+	storage *storages.Storage,
+	binlogDir string,
+) error {
+	fileNames, err := getLocalBinlogFileNames(binlogDir)
+	if err != nil {
+		return err
+	}
+
+	if len(fileNames) < 2 {
+		return nil
+	}
+
+	for _, fileName := range fileNames[:len(fileNames)-1] {
+		filePath := filepath.Join(binlogDir, fileName)
+
+		if err := s.binlogService.ArchiveBinlogFile(ctx, backupConfig, storage, filePath); err != nil {
+			return err
+		}
+
+		if err := os.Remove(filePath); err != nil {
+			s.logger.Error(
+				"Failed to remove archived binlog file",
+				"filePath",
+				filePath,
+				"error",
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// getStartFileName returns the binary log to start streaming from: the
+// newest local file (it may be incomplete), the last archived one, the
+// one of the latest backup coordinates or the current server binary log
+func (s *BinlogArchivingBackgroundService) getStartFileName(
+	ctx context.Context,
+	databaseID uuid.UUID,
+	binlogDir string,
+	connection *binlogConnection,
+	myCnfFile string,
+) (string, error) {
+	localFileNames, err := getLocalBinlogFileNames(binlogDir)
+	if err != nil {
+		return "", err
+	}
+
+	if len(localFileNames) > 0 {
+		return localFileNames[len(localFileNames)-1], nil
+	}
+
+	lastArchivedFile, err := s.binlogService.GetLastBinlogFile(databaseID)
+	if err != nil {
+		return "", err
+	}
+
+	if lastArchivedFile != nil {
+		return lastArchivedFile.FileName, nil
+	}
+
+	baseBackups, err := s.backupService.GetCompletedBackupsWithBinlogCoordinates(databaseID)
+	if err != nil {
+		return "", err
+	}
+
+	if len(baseBackups) > 0 {
+		return *baseBackups[len(baseBackups)-1].BinlogFile, nil
+	}
+
+	status, err := getBinlogStatus(ctx, connection, myCnfFile)
+	if err != nil {
+		return "", err
+	}
+
+	fileName, _, _ := strings.Cut(status, "\t")
+	if !IsBinlogFileName(fileName) {
+		return "", errors.New("binary logging is not enabled on the server (log_bin is OFF)")
+	}
+
+	return fileName, nil
+}
+
+func (s *BinlogArchivingBackgroundService) getBinlogConnection(
+	database *databases.Database,
+) (*binlogConnection, error) {
+	env := config.GetEnv()
+
+	switch database.Type {
+	case databases.DatabaseTypeMysql:
+		my := database.Mysql
+		if my == nil {
+			return nil, errors.New("MySQL database settings are not defined")
+		}
+
+		password, err := s.fieldEncryptor.Decrypt(database.ID, my.Password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt database password: %w", err)
+		}
+
+		myCnfContent := buildMyCnfContent(my.Username, password, my.Host, my.Port)
+		if my.IsHttps {
+			myCnfContent += "ssl-mode=REQUIRED\n"
+		}
+
+		statusQuery := "SHOW MASTER STATUS"
+		if my.Version == tools.MysqlVersion84 || my.Version == tools.MysqlVersion9 {
+			statusQuery = "SHOW BINARY LOG STATUS"
+		}
+
+		return &binlogConnection{
+			myCnfContent: myCnfContent,
+			clientBin: tools.GetMysqlExecutable(
+				my.Version,
+				tools.MysqlExecutableMysql,
+				env.EnvMode,
+				env.MysqlInstallDir,
+			),
+			binlogBin: tools.GetMysqlExecutable(
+				my.Version,
+				tools.MysqlExecutableMysqlbinlog,
+				env.EnvMode,
+				env.MysqlInstallDir,
+			),
+			statusQuery:     statusQuery,
+			serverIDArgName: "--connection-server-id",
+		}, nil
+	case databases.DatabaseTypeMariadb:
+		mariadb := database.Mariadb
+		if mariadb == nil {
+			return nil, errors.New("MariaDB database settings are not defined")
+		}
+
+		password, err := s.fieldEncryptor.Decrypt(database.ID, mariadb.Password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt database password: %w", err)
+		}
+
+		myCnfContent := buildMyCnfContent(
+			mariadb.Username,
+			password,
+			mariadb.Host,
+			mariadb.Port,
+		)
+		if mariadb.IsHttps {
+			myCnfContent += "ssl=true\n"
+		} else {
+			myCnfContent += "ssl=false\n"
+		}
+
+		return &binlogConnection{
+			myCnfContent: myCnfContent,
+			clientBin: tools.GetMariadbExecutable(
+				tools.MariadbExecutableMariadb,
+				mariadb.Version,
+				env.EnvMode,
+				env.MariadbInstallDir,
+			),
+			binlogBin: tools.GetMariadbExecutable(
+				tools.MariadbExecutableMariadbBinlog,
+				mariadb.Version,
+				env.EnvMode,
+				env.MariadbInstallDir,
+			),
+			statusQuery:     "SHOW MASTER STATUS",
+			serverIDArgName: "--stop-never-slave-server-id",
+		}, nil
+	default:
+		return nil, errors.New("binlog archiving is supported only for MySQL and MariaDB databases")
+	}
+}
+
+// stopReceiver stops receiver of the database and waits for it to
+// exit. Returns false if there was no running receiver
+func (s *BinlogArchivingBackgroundService) stopReceiver(databaseID uuid.UUID) bool {
+	s.mu.Lock()
+	receiver, isRunning := s.receivers[databaseID]
+	delete(s.receivers, databaseID)
+	s.mu.Unlock()
+
+	if !isRunning {
+		return false
+	}
+
+	receiver.cancel()
+	<-receiver.done
+
+	s.logger.Info("Stopped binlog receiver", "databaseId", databaseID)
+	return true
+}
+
+func (s *BinlogArchivingBackgroundService) stopAllReceivers() {
+	s.mu.Lock()
+	databaseIDs := make([]uuid.UUID, 0, len(s.receivers))
+	for databaseID := range s.receivers {
+		databaseIDs = append(databaseIDs, databaseID)
+	}
+	s.mu.Unlock()
+
+	for _, databaseID := range databaseIDs {
+		s.stopReceiver(databaseID)
+	}
+}
+
+// getBinlogStatus returns the current binary log file and position
+// separated by tab
+func getBinlogStatus(
+	ctx context.Context,
+	connection *binlogConnection,
+	myCnfFile string,
+) (string, error) {
+	output, err := runMysqlTool(
+		ctx,
+		connection.clientBin,
+		myCnfFile,
+		"-N",
+		"-e",
+		connection.statusQuery,
+	)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed to get binary log status, the user must have REPLICATION CLIENT privilege: %w",
+			err,
+		)
+	}
+
+	fields := strings.Split(strings.TrimSpace(string(output)), "\t")
+	if len(fields) < 2 {
+		return "", errors.New("binary logging is not enabled on the server (log_bin is OFF)")
+	}
+
+	return fields[0] + "\t" + fields[1], nil
+}
+
+func runMysqlTool(
+	ctx context.Context,
+	executable string,
+	myCnfFile string,
+	args ...string,
+) ([]byte, error) {
+	// --defaults-file must be the first argument
+	fullArgs := append([]string{"--defaults-file=" + myCnfFile}, args...)
+
+	cmd := exec.CommandContext(ctx, executable, fullArgs...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%s failed: %w – stderr: %s",
+			filepath.Base(executable),
+			err,
+			strings.TrimSpace(stderr.String()),
+		)
+	}
+
+	return output, nil
+}
+
+func getLocalBinlogFileNames(binlogDir string) ([]string, error) {
+	entries, err := os.ReadDir(binlogDir)
+	if err != nil {
+		return nil, err
+	}
+
+	fileNames := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !IsBinlogFileName(entry.Name()) {
+			continue
+		}
+
+		fileNames = append(fileNames, entry.Name())
+	}
+
+	slices.Sort(fileNames)
+	return fileNames, nil
+}
+
+func getBinlogDirectory(databaseID uuid.UUID) string {
+	return filepath.Join(config.GetEnv().DataFolder, "binlog", databaseID.String())
+}
+
+// getReceiverServerID returns replica server ID mysqlbinlog connects with.
+// It must be unique among replicas of the server and stable across restarts
+func getReceiverServerID(databaseID uuid.UUID) uint32 {
+	return receiverServerIDBase + crc32.ChecksumIEEE(databaseID[:])%receiverServerIDBase
+}
+
+func buildMyCnfContent(username, password, host string, port int) string {
+	return fmt.Sprintf(`[client]
+user=%s
+password="%s"
+host=%s
+port=%s
+`, username, tools.EscapeMysqlPassword(password), host, strconv.Itoa(port))
+}
+
+func createTempMyCnfFile(content string) (string, error) {
+	tempDir, err := os.MkdirTemp(config.GetEnv().TempFolder, "mycnf_"+uuid.New().String())
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	myCnfFile := filepath.Join(tempDir, ".my.cnf")
+	if err := os.WriteFile(myCnfFile, []byte(content), 0600); err != nil {
+		return "", fmt.Errorf("failed to write .my.cnf: %w", err)
+	}
+
+	return myCnfFile, nil
+}
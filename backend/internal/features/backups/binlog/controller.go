@@ -0,0 +1,50 @@
+package backups_binlog
+
+import (
+	"net/http"
+
+	users_middleware "databasus-backend/internal/features/users/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type BinlogController struct {
+	binlogService *BinlogService
+}
+
+func (c *BinlogController) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/binlog/database/:id/recovery-window", c.GetRecoveryWindow)
+}
+
+// GetRecoveryWindow
+// @Summary Get binlog point-in-time recovery window
+// @Description Get the time range the MySQL or MariaDB database can be recovered to using backups and archived binary logs
+// @Tags binlog
+// @Produce json
+// @Param id path string true "Database ID"
+// @Success 200 {object} RecoveryWindowResponse
+// @Failure 400 {object} map[string]string "Invalid database ID"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Router /binlog/database/{id}/recovery-window [get]
+func (c *BinlogController) GetRecoveryWindow(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid database ID"})
+		return
+	}
+
+	response, err := c.binlogService.GetRecoveryWindow(user, id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
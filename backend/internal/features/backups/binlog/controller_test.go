@@ -0,0 +1,150 @@
+package backups_binlog
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"databasus-backend/internal/features/backups/backups"
+	backups_config "databasus-backend/internal/features/backups/config"
+	"databasus-backend/internal/features/databases"
+	"databasus-backend/internal/features/notifiers"
+	"databasus-backend/internal/features/storages"
+	users_enums "databasus-backend/internal/features/users/enums"
+	users_testing "databasus-backend/internal/features/users/testing"
+	workspaces_controllers "databasus-backend/internal/features/workspaces/controllers"
+	workspaces_testing "databasus-backend/internal/features/workspaces/testing"
+	test_utils "databasus-backend/internal/util/testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestRouter() *gin.Engine {
+	return workspaces_testing.CreateTestRouter(
+		workspaces_controllers.GetWorkspaceController(),
+		workspaces_controllers.GetMembershipController(),
+		databases.GetDatabaseController(),
+		backups_config.GetBackupConfigController(),
+		backups.GetBackupController(),
+		GetBinlogController(),
+	)
+}
+
+func Test_GetRecoveryWindow_WhenBinlogArchivingDisabled_ReturnsEmptyWindow(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+	storage := storages.CreateTestStorage(workspace.ID)
+	notifier := notifiers.CreateTestNotifier(workspace.ID)
+	database := databases.CreateTestDatabase(workspace.ID, storage, notifier)
+
+	defer func() {
+		databases.RemoveTestDatabase(database)
+		time.Sleep(50 * time.Millisecond)
+		notifiers.RemoveTestNotifier(notifier)
+		storages.RemoveTestStorage(storage.ID)
+		workspaces_testing.RemoveTestWorkspace(workspace, router)
+	}()
+
+	backups_config.EnableBackupsForTestDatabase(database.ID, storage)
+
+	var response RecoveryWindowResponse
+	test_utils.MakeGetRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/binlog/database/"+database.ID.String()+"/recovery-window",
+		"Bearer "+owner.Token,
+		http.StatusOK,
+		&response,
+	)
+
+	assert.False(t, response.IsBinlogArchivingEnabled)
+	assert.Equal(t, int64(0), response.FilesCount)
+	assert.Nil(t, response.EarliestRecoveryTime)
+	assert.Nil(t, response.LatestRecoveryTime)
+}
+
+func Test_GetRecoveryWindow_WhenUserIsNotWorkspaceMember_ReturnsBadRequest(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+	storage := storages.CreateTestStorage(workspace.ID)
+	notifier := notifiers.CreateTestNotifier(workspace.ID)
+	database := databases.CreateTestDatabase(workspace.ID, storage, notifier)
+
+	defer func() {
+		databases.RemoveTestDatabase(database)
+		time.Sleep(50 * time.Millisecond)
+		notifiers.RemoveTestNotifier(notifier)
+		storages.RemoveTestStorage(storage.ID)
+		workspaces_testing.RemoveTestWorkspace(workspace, router)
+	}()
+
+	outsider := users_testing.CreateTestUser(users_enums.UserRoleMember)
+
+	test_utils.MakeGetRequest(
+		t,
+		router,
+		"/api/v1/binlog/database/"+database.ID.String()+"/recovery-window",
+		"Bearer "+outsider.Token,
+		http.StatusBadRequest,
+	)
+}
+
+func Test_SelectBinlogFilesForRecovery_WhenTargetTimeSet_StopsAfterFileContainingTarget(
+	t *testing.T,
+) {
+	now := time.Now().UTC()
+	files := []*BinlogFile{
+		createTestBinlogFile("binlog.000001", now.Add(-4*time.Hour)),
+		createTestBinlogFile("binlog.000002", now.Add(-3*time.Hour)),
+		createTestBinlogFile("binlog.000003", now.Add(-2*time.Hour)),
+		createTestBinlogFile("binlog.000004", now.Add(-1*time.Hour)),
+	}
+	targetTime := now.Add(-150 * time.Minute)
+
+	selectedFiles, err := selectBinlogFilesForRecovery(files, "binlog.000002", &targetTime)
+
+	assert.NoError(t, err)
+	assert.Len(t, selectedFiles, 2)
+	assert.Equal(t, "binlog.000002", selectedFiles[0].FileName)
+	assert.Equal(t, "binlog.000003", selectedFiles[1].FileName)
+}
+
+func Test_SelectBinlogFilesForRecovery_WhenTargetTimeNotSet_ReturnsAllFilesFromStart(
+	t *testing.T,
+) {
+	now := time.Now().UTC()
+	files := []*BinlogFile{
+		createTestBinlogFile("binlog.000001", now.Add(-3*time.Hour)),
+		createTestBinlogFile("binlog.000002", now.Add(-2*time.Hour)),
+		createTestBinlogFile("binlog.000003", now.Add(-1*time.Hour)),
+	}
+
+	selectedFiles, err := selectBinlogFilesForRecovery(files, "binlog.000002", nil)
+
+	assert.NoError(t, err)
+	assert.Len(t, selectedFiles, 2)
+	assert.Equal(t, "binlog.000002", selectedFiles[0].FileName)
+	assert.Equal(t, "binlog.000003", selectedFiles[1].FileName)
+}
+
+func Test_SelectBinlogFilesForRecovery_WhenStartFileNotArchived_ReturnsError(t *testing.T) {
+	files := []*BinlogFile{
+		createTestBinlogFile("binlog.000005", time.Now().UTC()),
+	}
+
+	_, err := selectBinlogFilesForRecovery(files, "binlog.000002", nil)
+
+	assert.Error(t, err)
+}
+
+func createTestBinlogFile(fileName string, createdAt time.Time) *BinlogFile {
+	return &BinlogFile{
+		ID:        uuid.New(),
+		FileName:  fileName,
+		CreatedAt: createdAt,
+	}
+}
@@ -0,0 +1,60 @@
+package backups_binlog
+
+import (
+	"sync"
+
+	"databasus-backend/internal/features/backups/backups"
+	backups_config "databasus-backend/internal/features/backups/config"
+	"databasus-backend/internal/features/databases"
+	encryption_secrets "databasus-backend/internal/features/encryption/secrets"
+	"databasus-backend/internal/features/storages"
+	"databasus-backend/internal/util/encryption"
+	"databasus-backend/internal/util/logger"
+
+	"github.com/google/uuid"
+)
+
+var binlogFileRepository = &BinlogFileRepository{}
+
+var binlogService = &BinlogService{
+	binlogFileRepository,
+	backups.GetBackupService(),
+	backups_config.GetBackupConfigService(),
+	databases.GetDatabaseService(),
+	storages.GetStorageService(),
+	encryption_secrets.GetSecretKeyService(),
+	encryption.GetFieldEncryptor(),
+	logger.GetLogger(),
+}
+
+var binlogArchivingBackgroundService = &BinlogArchivingBackgroundService{
+	binlogService,
+	backups.GetBackupService(),
+	backups_config.GetBackupConfigService(),
+	databases.GetDatabaseService(),
+	storages.GetStorageService(),
+	encryption.GetFieldEncryptor(),
+	logger.GetLogger(),
+	map[uuid.UUID]*binlogReceiver{},
+	sync.Mutex{},
+}
+
+var binlogController = &BinlogController{
+	binlogService,
+}
+
+func SetupDependencies() {
+	databases.GetDatabaseService().AddDbRemoveListener(binlogArchivingBackgroundService)
+}
+
+func GetBinlogService() *BinlogService {
+	return binlogService
+}
+
+func GetBinlogArchivingBackgroundService() *BinlogArchivingBackgroundService {
+	return binlogArchivingBackgroundService
+}
+
+func GetBinlogController() *BinlogController {
+	return binlogController
+}
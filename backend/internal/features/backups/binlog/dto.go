@@ -0,0 +1,13 @@
+package backups_binlog
+
+import "time"
+
+type RecoveryWindowResponse struct {
+	IsBinlogArchivingEnabled bool  `json:"isBinlogArchivingEnabled"`
+	FilesCount               int64 `json:"filesCount"`
+
+	// Recovery window bounds, nil until there is at least one completed
+	// backup with binlog coordinates followed by archived binary logs
+	EarliestRecoveryTime *time.Time `json:"earliestRecoveryTime"`
+	LatestRecoveryTime   *time.Time `json:"latestRecoveryTime"`
+}
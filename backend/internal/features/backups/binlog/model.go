@@ -0,0 +1,38 @@
+package backups_binlog
+
+import (
+	backups_config "databasus-backend/internal/features/backups/config"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// binary log files are named <basename>.<sequence number>, e.g. binlog.000042
+var binlogFileNameRegex = regexp.MustCompile(`^[A-Za-z0-9_.-]+\.[0-9]{6,}$`)
+
+// BinlogFile is a MySQL or MariaDB binary log file received via
+// mysqlbinlog --read-from-remote-server and uploaded into the storage
+type BinlogFile struct {
+	ID uuid.UUID `json:"id" gorm:"column:id;type:uuid;primaryKey"`
+
+	DatabaseID uuid.UUID `json:"databaseId" gorm:"column:database_id;type:uuid;not null"`
+	StorageID  uuid.UUID `json:"storageId"  gorm:"column:storage_id;type:uuid;not null"`
+
+	FileName  string `json:"fileName"  gorm:"column:file_name;type:text;not null"`
+	SizeBytes int64  `json:"sizeBytes" gorm:"column:size_bytes;default:0"`
+
+	EncryptionSalt *string                         `json:"-"          gorm:"column:encryption_salt"`
+	EncryptionIV   *string                         `json:"-"          gorm:"column:encryption_iv"`
+	Encryption     backups_config.BackupEncryption `json:"encryption" gorm:"column:encryption;type:text;not null;default:'NONE'"`
+
+	CreatedAt time.Time `json:"createdAt" gorm:"column:created_at"`
+}
+
+func (f *BinlogFile) TableName() string {
+	return "binlog_files"
+}
+
+func IsBinlogFileName(fileName string) bool {
+	return binlogFileNameRegex.MatchString(fileName)
+}
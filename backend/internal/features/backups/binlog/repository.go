@@ -0,0 +1,145 @@
+package backups_binlog
+
+import (
+	"databasus-backend/internal/storage"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BinlogFileRepository struct{}
+
+// Create inserts the file keeping its ID, because the ID is used as
+// the file ID in the storage and is known before the upload
+func (r *BinlogFileRepository) Create(binlogFile *BinlogFile) error {
+	if binlogFile.DatabaseID == uuid.Nil || binlogFile.StorageID == uuid.Nil {
+		return errors.New("database ID and storage ID are required")
+	}
+
+	if binlogFile.ID == uuid.Nil {
+		binlogFile.ID = uuid.New()
+	}
+
+	return storage.GetDb().Create(binlogFile).Error
+}
+
+func (r *BinlogFileRepository) FindByDatabaseIDAndFileName(
+	databaseID uuid.UUID,
+	fileName string,
+) (*BinlogFile, error) {
+	var binlogFile BinlogFile
+
+	if err := storage.
+		GetDb().
+		Where("database_id = ? AND file_name = ?", databaseID, fileName).
+		First(&binlogFile).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &binlogFile, nil
+}
+
+func (r *BinlogFileRepository) FindByDatabaseID(databaseID uuid.UUID) ([]*BinlogFile, error) {
+	var binlogFiles []*BinlogFile
+
+	if err := storage.
+		GetDb().
+		Where("database_id = ?", databaseID).
+		Order("file_name ASC").
+		Find(&binlogFiles).Error; err != nil {
+		return nil, err
+	}
+
+	return binlogFiles, nil
+}
+
+func (r *BinlogFileRepository) FindLastByDatabaseID(databaseID uuid.UUID) (*BinlogFile, error) {
+	var binlogFile BinlogFile
+
+	if err := storage.
+		GetDb().
+		Where("database_id = ?", databaseID).
+		Order("created_at DESC").
+		First(&binlogFile).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &binlogFile, nil
+}
+
+func (r *BinlogFileRepository) FindFilesBeforeDate(
+	databaseID uuid.UUID,
+	date time.Time,
+) ([]*BinlogFile, error) {
+	var binlogFiles []*BinlogFile
+
+	if err := storage.
+		GetDb().
+		Where("database_id = ? AND created_at < ?", databaseID, date).
+		Order("created_at ASC").
+		Find(&binlogFiles).Error; err != nil {
+		return nil, err
+	}
+
+	return binlogFiles, nil
+}
+
+func (r *BinlogFileRepository) FindByStorageID(storageID uuid.UUID) ([]*BinlogFile, error) {
+	var binlogFiles []*BinlogFile
+
+	if err := storage.
+		GetDb().
+		Where("storage_id = ?", storageID).
+		Order("created_at ASC").
+		Find(&binlogFiles).Error; err != nil {
+		return nil, err
+	}
+
+	return binlogFiles, nil
+}
+
+// FindExistingIDs returns IDs of the given list which have a binlog file row
+func (r *BinlogFileRepository) FindExistingIDs(ids []uuid.UUID) ([]uuid.UUID, error) {
+	existingIDs := make([]uuid.UUID, 0)
+	if len(ids) == 0 {
+		return existingIDs, nil
+	}
+
+	if err := storage.
+		GetDb().
+		Model(&BinlogFile{}).
+		Where("id IN ?", ids).
+		Pluck("id", &existingIDs).Error; err != nil {
+		return nil, err
+	}
+
+	return existingIDs, nil
+}
+
+func (r *BinlogFileRepository) CountByDatabaseID(databaseID uuid.UUID) (int64, error) {
+	var count int64
+
+	if err := storage.
+		GetDb().
+		Model(&BinlogFile{}).
+		Where("database_id = ?", databaseID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *BinlogFileRepository) DeleteByID(id uuid.UUID) error {
+	return storage.GetDb().Delete(&BinlogFile{}, "id = ?", id).Error
+}
@@ -0,0 +1,447 @@
+package backups_binlog
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"databasus-backend/internal/features/backups/backups"
+	backup_encryption "databasus-backend/internal/features/backups/backups/encryption"
+	backups_config "databasus-backend/internal/features/backups/config"
+	"databasus-backend/internal/features/databases"
+	encryption_secrets "databasus-backend/internal/features/encryption/secrets"
+	"databasus-backend/internal/features/storages"
+	users_models "databasus-backend/internal/features/users/models"
+	util_encryption "databasus-backend/internal/util/encryption"
+
+	"github.com/google/uuid"
+)
+
+type BinlogService struct {
+	binlogFileRepository *BinlogFileRepository
+	backupService        *backups.BackupService
+	backupConfigService  *backups_config.BackupConfigService
+	databaseService      *databases.DatabaseService
+	storageService       *storages.StorageService
+	secretKeyService     *encryption_secrets.SecretKeyService
+	fieldEncryptor       util_encryption.FieldEncryptor
+	logger               *slog.Logger
+}
+
+func (s *BinlogService) GetRecoveryWindow(
+	user *users_models.User,
+	databaseID uuid.UUID,
+) (*RecoveryWindowResponse, error) {
+	database, err := s.databaseService.GetDatabase(user, databaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	backupConfig, err := s.backupConfigService.GetBackupConfigByDbId(database.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	filesCount, err := s.binlogFileRepository.CountByDatabaseID(database.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &RecoveryWindowResponse{
+		IsBinlogArchivingEnabled: backupConfig.IsBinlogArchivingEnabled,
+		FilesCount:               filesCount,
+	}
+
+	baseBackups, err := s.backupService.GetCompletedBackupsWithBinlogCoordinates(database.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	lastFile, err := s.binlogFileRepository.FindLastByDatabaseID(database.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(baseBackups) == 0 || lastFile == nil {
+		return response, nil
+	}
+
+	oldestBaseBackup := baseBackups[0]
+	earliestRecoveryTime := oldestBaseBackup.CreatedAt.Add(
+		time.Duration(oldestBaseBackup.BackupDurationMs) * time.Millisecond,
+	)
+	if lastFile.CreatedAt.Before(earliestRecoveryTime) {
+		return response, nil
+	}
+
+	response.EarliestRecoveryTime = &earliestRecoveryTime
+	response.LatestRecoveryTime = &lastFile.CreatedAt
+
+	return response, nil
+}
+
+// ArchiveBinlogFile uploads completed binary log into the storage (encrypted
+// if backups of the database are encrypted) and records it. Already archived
+// files are skipped, so the call is safe to repeat after restarts
+func (s *BinlogService) ArchiveBinlogFile(
+	ctx context.Context,
+	backupConfig *backups_config.BackupConfig,
+	storage *storages.Storage,
+	filePath string,
+) error {
+	fileName := filepath.Base(filePath)
+
+	existingFile, err := s.binlogFileRepository.FindByDatabaseIDAndFileName(
+		backupConfig.DatabaseID,
+		fileName,
+	)
+	if err != nil {
+		return err
+	}
+
+	if existingFile != nil {
+		return nil
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open binlog file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat binlog file: %w", err)
+	}
+
+	binlogFile := &BinlogFile{
+		ID:         uuid.New(),
+		DatabaseID: backupConfig.DatabaseID,
+		StorageID:  storage.ID,
+		FileName:   fileName,
+		SizeBytes:  fileInfo.Size(),
+		Encryption: backups_config.BackupEncryptionNone,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	var reader io.Reader = file
+	if backupConfig.Encryption == backups_config.BackupEncryptionEncrypted {
+		encryptedReader, err := s.encryptBinlogFile(binlogFile, file)
+		if err != nil {
+			return err
+		}
+
+		reader = encryptedReader
+	}
+
+	if err := storage.SaveFile(ctx, s.fieldEncryptor, s.logger, binlogFile.ID, reader); err != nil {
+		return fmt.Errorf("failed to upload binlog file %s: %w", fileName, err)
+	}
+
+	if err := s.binlogFileRepository.Create(binlogFile); err != nil {
+		_ = storage.DeleteFile(s.fieldEncryptor, binlogFile.ID)
+		return err
+	}
+
+	s.logger.Info(
+		"Binlog file archived",
+		"databaseId",
+		backupConfig.DatabaseID,
+		"fileName",
+		fileName,
+		"sizeBytes",
+		binlogFile.SizeBytes,
+	)
+
+	return nil
+}
+
+// DownloadBinlogFiles downloads (and decrypts) binary logs of the database
+// required to replay from the backup binlog coordinates up to the recovery
+// target into targetDir. When targetTime is nil, all archived binary logs
+// after the backup are downloaded. Returns paths of the downloaded files in
+// replay order
+func (s *BinlogService) DownloadBinlogFiles(
+	ctx context.Context,
+	backup *backups.Backup,
+	targetTime *time.Time,
+	targetDir string,
+) ([]string, error) {
+	if backup.BinlogFile == nil {
+		return nil, errors.New("backup has no binlog coordinates")
+	}
+
+	allFiles, err := s.binlogFileRepository.FindByDatabaseID(backup.DatabaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	binlogFiles, err := selectBinlogFilesForRecovery(allFiles, *backup.BinlogFile, targetTime)
+	if err != nil {
+		return nil, err
+	}
+
+	filePaths := make([]string, 0, len(binlogFiles))
+	for _, binlogFile := range binlogFiles {
+		filePath, err := s.downloadBinlogFile(ctx, binlogFile, targetDir)
+		if err != nil {
+			return nil, err
+		}
+
+		filePaths = append(filePaths, filePath)
+	}
+
+	return filePaths, nil
+}
+
+// DeleteFilesBeforeBackup removes binary logs which are not needed to
+// replay any backup starting from the given one
+func (s *BinlogService) DeleteFilesBeforeBackup(backup *backups.Backup) error {
+	if backup.BinlogFile == nil {
+		return nil
+	}
+
+	binlogFiles, err := s.binlogFileRepository.FindFilesBeforeDate(
+		backup.DatabaseID,
+		backup.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, binlogFile := range binlogFiles {
+		// the file with the backup coordinates is still needed
+		if binlogFile.FileName >= *backup.BinlogFile {
+			continue
+		}
+
+		if err := s.deleteBinlogFile(binlogFile); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *BinlogService) DeleteDatabaseBinlogFiles(databaseID uuid.UUID) error {
+	binlogFiles, err := s.binlogFileRepository.FindByDatabaseID(databaseID)
+	if err != nil {
+		return err
+	}
+
+	for _, binlogFile := range binlogFiles {
+		if err := s.deleteBinlogFile(binlogFile); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *BinlogService) GetLastBinlogFile(databaseID uuid.UUID) (*BinlogFile, error) {
+	return s.binlogFileRepository.FindLastByDatabaseID(databaseID)
+}
+
+func (s *BinlogService) GetBinlogFilesByStorageID(storageID uuid.UUID) ([]*BinlogFile, error) {
+	return s.binlogFileRepository.FindByStorageID(storageID)
+}
+
+// FilterExistingBinlogFileIDs returns IDs of the given list which belong
+// to binlog files
+func (s *BinlogService) FilterExistingBinlogFileIDs(ids []uuid.UUID) ([]uuid.UUID, error) {
+	return s.binlogFileRepository.FindExistingIDs(ids)
+}
+
+func (s *BinlogService) deleteBinlogFile(binlogFile *BinlogFile) error {
+	storage, err := s.storageService.GetStorageByID(binlogFile.StorageID)
+	if err != nil {
+		return err
+	}
+
+	if err := storage.DeleteFile(s.fieldEncryptor, binlogFile.ID); err != nil {
+		// proceed anyway, the same as for backups: storage
+		// may be unavailable or already cleaned up
+		s.logger.Error(
+			"Failed to delete binlog file",
+			"fileName",
+			binlogFile.FileName,
+			"error",
+			err,
+		)
+	}
+
+	return s.binlogFileRepository.DeleteByID(binlogFile.ID)
+}
+
+func (s *BinlogService) downloadBinlogFile(
+	ctx context.Context,
+	binlogFile *BinlogFile,
+	targetDir string,
+) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", fmt.Errorf("binlog download cancelled: %w", err)
+	}
+
+	storage, err := s.storageService.GetStorageByID(binlogFile.StorageID)
+	if err != nil {
+		return "", err
+	}
+
+	rawReader, err := storage.GetFile(s.fieldEncryptor, binlogFile.ID)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed to get binlog file %s from storage: %w",
+			binlogFile.FileName,
+			err,
+		)
+	}
+	defer func() {
+		_ = rawReader.Close()
+	}()
+
+	reader, err := s.decryptBinlogFile(binlogFile, rawReader)
+	if err != nil {
+		return "", err
+	}
+
+	filePath := filepath.Join(targetDir, binlogFile.FileName)
+	targetFile, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", fmt.Errorf("failed to create binlog file: %w", err)
+	}
+	defer func() {
+		_ = targetFile.Close()
+	}()
+
+	if _, err := io.Copy(targetFile, reader); err != nil {
+		return "", fmt.Errorf("failed to download binlog file %s: %w", binlogFile.FileName, err)
+	}
+
+	return filePath, nil
+}
+
+func (s *BinlogService) encryptBinlogFile(
+	binlogFile *BinlogFile,
+	file io.Reader,
+) (io.Reader, error) {
+	salt, err := backup_encryption.GenerateSalt()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	nonce, err := backup_encryption.GenerateNonce()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	masterKey, err := s.secretKeyService.GetSecretKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get master key: %w", err)
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+
+	encWriter, err := backup_encryption.NewEncryptionWriter(
+		pipeWriter,
+		masterKey,
+		binlogFile.ID,
+		salt,
+		nonce,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create encrypting writer: %w", err)
+	}
+
+	go func() {
+		_, copyErr := io.Copy(encWriter, file)
+		if copyErr == nil {
+			copyErr = encWriter.Close()
+		}
+
+		_ = pipeWriter.CloseWithError(copyErr)
+	}()
+
+	saltBase64 := base64.StdEncoding.EncodeToString(salt)
+	nonceBase64 := base64.StdEncoding.EncodeToString(nonce)
+	binlogFile.EncryptionSalt = &saltBase64
+	binlogFile.EncryptionIV = &nonceBase64
+	binlogFile.Encryption = backups_config.BackupEncryptionEncrypted
+
+	return pipeReader, nil
+}
+
+func (s *BinlogService) decryptBinlogFile(
+	binlogFile *BinlogFile,
+	reader io.Reader,
+) (io.Reader, error) {
+	if binlogFile.Encryption != backups_config.BackupEncryptionEncrypted {
+		return reader, nil
+	}
+
+	if binlogFile.EncryptionSalt == nil || binlogFile.EncryptionIV == nil {
+		return nil, errors.New("binlog file is encrypted but missing encryption metadata")
+	}
+
+	masterKey, err := s.secretKeyService.GetSecretKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get master key for decryption: %w", err)
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(*binlogFile.EncryptionSalt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode encryption salt: %w", err)
+	}
+
+	iv, err := base64.StdEncoding.DecodeString(*binlogFile.EncryptionIV)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode encryption IV: %w", err)
+	}
+
+	return backup_encryption.NewDecryptionReader(reader, masterKey, binlogFile.ID, salt, iv)
+}
+
+// selectBinlogFilesForRecovery returns files (sorted by name) to replay from
+// the file with backup coordinates up to the target time. The first file
+// archived after the target time contains the target itself, so it is
+// included as well
+func selectBinlogFilesForRecovery(
+	allFiles []*BinlogFile,
+	startFileName string,
+	targetTime *time.Time,
+) ([]*BinlogFile, error) {
+	selectedFiles := make([]*BinlogFile, 0)
+	isStartFound := false
+
+	for _, binlogFile := range allFiles {
+		if binlogFile.FileName == startFileName {
+			isStartFound = true
+		}
+
+		if !isStartFound {
+			continue
+		}
+
+		selectedFiles = append(selectedFiles, binlogFile)
+
+		if targetTime != nil && binlogFile.CreatedAt.After(*targetTime) {
+			break
+		}
+	}
+
+	if !isStartFound {
+		return nil, fmt.Errorf(
+			"binlog file %s the backup starts from is not archived",
+			startFileName,
+		)
+	}
+
+	return selectedFiles, nil
+}
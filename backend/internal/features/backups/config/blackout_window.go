@@ -0,0 +1,128 @@
+package backups_config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BlackoutWindow is a daily time range when scheduled backups and retries
+// are not started, e.g. during peak hours. Manual backups are not affected.
+// Times are "HH:MM" in the timezone of the backup interval, a window which
+// ends before it starts spans midnight (e.g. 22:00-02:00)
+type BlackoutWindow struct {
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime"`
+	// days of week (0 - Sunday ... 6 - Saturday, as in intervals) the window
+	// starts on, every day if empty
+	Weekdays []int `json:"weekdays,omitempty"`
+}
+
+func (w *BlackoutWindow) Validate() error {
+	startMinute, err := parseMinuteOfDay(w.StartTime)
+	if err != nil {
+		return err
+	}
+
+	endMinute, err := parseMinuteOfDay(w.EndTime)
+	if err != nil {
+		return err
+	}
+
+	if startMinute == endMinute {
+		return errors.New("blackout window start and end time must differ")
+	}
+
+	for _, weekday := range w.Weekdays {
+		if weekday < 0 || weekday > 6 {
+			return errors.New("blackout window weekdays must be between 0 and 6")
+		}
+	}
+
+	return nil
+}
+
+// Contains reports whether the wall clock time belongs to the window. The
+// time must be in the timezone the window is defined in
+func (w *BlackoutWindow) Contains(localTime time.Time) bool {
+	startMinute, err := parseMinuteOfDay(w.StartTime)
+	if err != nil {
+		return false
+	}
+
+	endMinute, err := parseMinuteOfDay(w.EndTime)
+	if err != nil {
+		return false
+	}
+
+	minute := localTime.Hour()*60 + localTime.Minute()
+
+	if startMinute < endMinute {
+		return minute >= startMinute && minute < endMinute &&
+			w.isStartedOn(localTime.Weekday())
+	}
+
+	// the window spans midnight, its part after midnight belongs to the
+	// window started the day before
+	if minute >= startMinute {
+		return w.isStartedOn(localTime.Weekday())
+	}
+
+	if minute < endMinute {
+		return w.isStartedOn((localTime.Weekday() + 6) % 7)
+	}
+
+	return false
+}
+
+// String returns the window as "HH:MM-HH:MM" followed by its weekdays, e.g.
+// "09:00-18:00 (Mon, Fri)"
+func (w *BlackoutWindow) String() string {
+	if len(w.Weekdays) == 0 {
+		return w.StartTime + "-" + w.EndTime
+	}
+
+	weekdays := make([]string, 0, len(w.Weekdays))
+	for _, weekday := range w.Weekdays {
+		weekdays = append(weekdays, time.Weekday(weekday).String()[:3])
+	}
+
+	return fmt.Sprintf("%s-%s (%s)", w.StartTime, w.EndTime, strings.Join(weekdays, ", "))
+}
+
+func (w *BlackoutWindow) isStartedOn(weekday time.Weekday) bool {
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+
+	for _, windowWeekday := range w.Weekdays {
+		if time.Weekday(windowWeekday) == weekday {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseMinuteOfDay(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid blackout window time %q, expected HH:MM", value)
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func formatBlackoutWindows(blackoutWindows []BlackoutWindow) string {
+	if len(blackoutWindows) == 0 {
+		return "none"
+	}
+
+	formattedWindows := make([]string, 0, len(blackoutWindows))
+	for _, blackoutWindow := range blackoutWindows {
+		formattedWindows = append(formattedWindows, blackoutWindow.String())
+	}
+
+	return strings.Join(formattedWindows, "; ")
+}
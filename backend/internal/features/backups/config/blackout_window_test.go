@@ -0,0 +1,163 @@
+package backups_config
+
+import (
+	"testing"
+	"time"
+
+	backups_pauses "databasus-backend/internal/features/backups/pauses"
+	"databasus-backend/internal/features/intervals"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ValidateBlackoutWindow_WithValidWindows_NoError(t *testing.T) {
+	tests := []struct {
+		name           string
+		blackoutWindow BlackoutWindow
+	}{
+		{"daytime window", BlackoutWindow{StartTime: "09:00", EndTime: "18:00"}},
+		{"window over midnight", BlackoutWindow{StartTime: "22:00", EndTime: "02:00"}},
+		{
+			"window with weekdays",
+			BlackoutWindow{StartTime: "09:00", EndTime: "18:00", Weekdays: []int{1, 5}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, tt.blackoutWindow.Validate())
+		})
+	}
+}
+
+func Test_ValidateBlackoutWindow_WithInvalidWindows_ReturnsError(t *testing.T) {
+	tests := []struct {
+		name           string
+		blackoutWindow BlackoutWindow
+	}{
+		{"invalid time", BlackoutWindow{StartTime: "9am", EndTime: "18:00"}},
+		{"empty window", BlackoutWindow{StartTime: "09:00", EndTime: "09:00"}},
+		{
+			"invalid weekday",
+			BlackoutWindow{StartTime: "09:00", EndTime: "18:00", Weekdays: []int{7}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, tt.blackoutWindow.Validate())
+		})
+	}
+}
+
+func Test_BlackoutWindowContains_WithDaytimeWindow_ContainsOnlyTimeInside(t *testing.T) {
+	blackoutWindow := BlackoutWindow{StartTime: "09:00", EndTime: "18:00"}
+
+	assert.False(t, blackoutWindow.Contains(getTestMondayTime(8, 59)))
+	assert.True(t, blackoutWindow.Contains(getTestMondayTime(9, 0)))
+	assert.True(t, blackoutWindow.Contains(getTestMondayTime(17, 59)))
+	assert.False(t, blackoutWindow.Contains(getTestMondayTime(18, 0)))
+}
+
+func Test_BlackoutWindowContains_WithWindowOverMidnight_ContainsTimeOnBothDays(t *testing.T) {
+	blackoutWindow := BlackoutWindow{StartTime: "22:00", EndTime: "02:00"}
+
+	assert.True(t, blackoutWindow.Contains(getTestMondayTime(23, 0)))
+	assert.True(t, blackoutWindow.Contains(getTestMondayTime(1, 30)))
+	assert.False(t, blackoutWindow.Contains(getTestMondayTime(2, 0)))
+	assert.False(t, blackoutWindow.Contains(getTestMondayTime(21, 59)))
+}
+
+func Test_BlackoutWindowContains_WithWeekdays_ContainsOnlyTimeOnTheseDays(t *testing.T) {
+	blackoutWindow := BlackoutWindow{
+		StartTime: "09:00",
+		EndTime:   "18:00",
+		Weekdays:  []int{int(time.Tuesday)},
+	}
+
+	assert.False(t, blackoutWindow.Contains(getTestMondayTime(12, 0)))
+	assert.True(t, blackoutWindow.Contains(getTestMondayTime(12, 0).AddDate(0, 0, 1)))
+}
+
+func Test_BlackoutWindowContains_WithWindowOverMidnightAndWeekdays_ContainsNextMorning(
+	t *testing.T,
+) {
+	blackoutWindow := BlackoutWindow{
+		StartTime: "22:00",
+		EndTime:   "02:00",
+		Weekdays:  []int{int(time.Sunday)},
+	}
+
+	// the part after midnight belongs to the window started on Sunday
+	assert.True(t, blackoutWindow.Contains(getTestMondayTime(1, 0)))
+	assert.False(t, blackoutWindow.Contains(getTestMondayTime(23, 0)))
+}
+
+func Test_IsInBlackoutWindow_WithoutBlackoutWindows_NotInBlackoutWindow(t *testing.T) {
+	backupConfig := &BackupConfig{}
+
+	assert.False(t, backupConfig.IsInBlackoutWindow(getTestMondayTime(12, 0)))
+}
+
+func Test_IsInBlackoutWindow_WithIntervalTimezone_WindowEvaluatedInThisTimezone(t *testing.T) {
+	// 2026-10-19 12:00 UTC is 15:00 in Istanbul
+	now := getTestMondayTime(12, 0)
+
+	backupConfig := &BackupConfig{
+		BackupInterval: &intervals.Interval{
+			Interval: intervals.IntervalHourly,
+			Timezone: "Europe/Istanbul",
+		},
+		BlackoutWindows: []BlackoutWindow{{StartTime: "14:00", EndTime: "16:00"}},
+	}
+
+	assert.True(t, backupConfig.IsInBlackoutWindow(now))
+
+	backupConfig.BackupInterval.Timezone = "UTC"
+	assert.False(t, backupConfig.IsInBlackoutWindow(now))
+}
+
+func Test_GetUpcomingRuns_WhenRunInBlackoutWindow_RunPostponed(t *testing.T) {
+	backupConfig := createTestHourlyBackupConfig(getTestMondayTime(12, 0))
+	backupConfig.BlackoutWindows = []BlackoutWindow{{StartTime: "13:00", EndTime: "14:00"}}
+
+	upcomingRuns, err := getUpcomingRuns(backupConfig, nil, 3)
+	assert.NoError(t, err)
+	assert.Len(t, upcomingRuns, 3)
+
+	assert.False(t, upcomingRuns[0].IsPostponed)
+	assert.True(t, upcomingRuns[1].IsPostponed)
+	assert.Equal(t, "blackout window", upcomingRuns[1].PostponeReason)
+	assert.False(t, upcomingRuns[2].IsPostponed)
+}
+
+func Test_GetUpcomingRuns_WhenRunBeforePauseResumed_RunPostponed(t *testing.T) {
+	nextRunAt := getTestMondayTime(12, 0)
+	resumeAt := nextRunAt.Add(90 * time.Minute)
+	pause := &backups_pauses.BackupPause{Reason: "migration", ResumeAt: &resumeAt}
+
+	upcomingRuns, err := getUpcomingRuns(createTestHourlyBackupConfig(nextRunAt), pause, 3)
+	assert.NoError(t, err)
+	assert.Len(t, upcomingRuns, 3)
+
+	assert.True(t, upcomingRuns[0].IsPostponed)
+	assert.Equal(t, "backups paused: migration", upcomingRuns[0].PostponeReason)
+	assert.True(t, upcomingRuns[1].IsPostponed)
+	assert.False(t, upcomingRuns[2].IsPostponed)
+}
+
+// getTestMondayTime returns the time of 2026-10-19, which is Monday
+func getTestMondayTime(hour, minute int) time.Time {
+	return time.Date(2026, 10, 19, hour, minute, 0, 0, time.UTC)
+}
+
+func createTestHourlyBackupConfig(nextRunAt time.Time) *BackupConfig {
+	return &BackupConfig{
+		IsBackupsEnabled: true,
+		BackupInterval: &intervals.Interval{
+			Interval: intervals.IntervalHourly,
+			Timezone: "UTC",
+		},
+		NextRunAt: &nextRunAt,
+	}
+}
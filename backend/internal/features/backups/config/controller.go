@@ -3,6 +3,7 @@ package backups_config
 import (
 	"errors"
 	"net/http"
+	"strconv"
 
 	users_middleware "databasus-backend/internal/features/users/middleware"
 
@@ -17,6 +18,11 @@ type BackupConfigController struct {
 func (c *BackupConfigController) RegisterRoutes(router *gin.RouterGroup) {
 	router.POST("/backup-configs/save", c.SaveBackupConfig)
 	router.GET("/backup-configs/database/:id", c.GetBackupConfigByDbID)
+	router.GET("/backup-configs/database/:id/upcoming-runs", c.GetUpcomingRuns)
+	router.GET("/backup-configs/database/:id/schedules", c.GetSchedules)
+	router.POST("/backup-configs/database/:id/schedules", c.CreateSchedule)
+	router.PUT("/backup-configs/schedules/:id", c.UpdateSchedule)
+	router.DELETE("/backup-configs/schedules/:id", c.DeleteSchedule)
 	router.GET("/backup-configs/storage/:id/is-using", c.IsStorageUsing)
 	router.GET("/backup-configs/storage/:id/databases-count", c.CountDatabasesForStorage)
 	router.POST("/backup-configs/database/:id/transfer", c.TransferDatabase)
@@ -92,6 +98,195 @@ func (c *BackupConfigController) GetBackupConfigByDbID(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, backupConfig)
 }
 
+// GetUpcomingRuns
+// @Summary Get upcoming scheduled backups
+// @Description Get the next scheduled backup times of a database in UTC. Empty if scheduled backups are disabled
+// @Tags backup-configs
+// @Produce json
+// @Param id path string true "Database ID"
+// @Param count query int false "Number of upcoming runs (1-100, default 5)"
+// @Success 200 {object} UpcomingRunsResponse
+// @Failure 400 {object} map[string]string "Invalid database ID or count"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Router /backup-configs/database/{id}/upcoming-runs [get]
+func (c *BackupConfigController) GetUpcomingRuns(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid database ID"})
+		return
+	}
+
+	count := defaultUpcomingRunsCount
+	if countParam := ctx.Query("count"); countParam != "" {
+		count, err = strconv.Atoi(countParam)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid count"})
+			return
+		}
+	}
+
+	response, err := c.backupConfigService.GetUpcomingRunsWithAuth(user, id, count)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetSchedules
+// @Summary Get backup schedules of a database
+// @Description Get named backup schedules run in addition to the schedule of the backup configuration
+// @Tags backup-configs
+// @Produce json
+// @Param id path string true "Database ID"
+// @Success 200 {array} BackupSchedule
+// @Failure 400 {object} map[string]string "Invalid database ID"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Router /backup-configs/database/{id}/schedules [get]
+func (c *BackupConfigController) GetSchedules(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid database ID"})
+		return
+	}
+
+	schedules, err := c.backupConfigService.GetSchedulesWithAuth(user, id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, schedules)
+}
+
+// CreateSchedule
+// @Summary Create backup schedule
+// @Description Create a named backup schedule of a database with its own interval, backup mode (FULL or SCHEMA_ONLY), retention, storage and notifications
+// @Tags backup-configs
+// @Accept json
+// @Produce json
+// @Param id path string true "Database ID"
+// @Param request body BackupSchedule true "Backup schedule data"
+// @Success 200 {object} BackupSchedule
+// @Failure 400 {object} map[string]string "Invalid request or validation errors"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Router /backup-configs/database/{id}/schedules [post]
+func (c *BackupConfigController) CreateSchedule(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid database ID"})
+		return
+	}
+
+	var requestDTO BackupSchedule
+	if err := ctx.ShouldBindJSON(&requestDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	requestDTO.ID = uuid.Nil
+	requestDTO.DatabaseID = id
+
+	schedule, err := c.backupConfigService.SaveScheduleWithAuth(user, &requestDTO)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, schedule)
+}
+
+// UpdateSchedule
+// @Summary Update backup schedule
+// @Description Update a named backup schedule. The schedule stays attached to its database
+// @Tags backup-configs
+// @Accept json
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Param request body BackupSchedule true "Backup schedule data"
+// @Success 200 {object} BackupSchedule
+// @Failure 400 {object} map[string]string "Invalid request or validation errors"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Router /backup-configs/schedules/{id} [put]
+func (c *BackupConfigController) UpdateSchedule(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid schedule ID"})
+		return
+	}
+
+	var requestDTO BackupSchedule
+	if err := ctx.ShouldBindJSON(&requestDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	requestDTO.ID = id
+
+	schedule, err := c.backupConfigService.SaveScheduleWithAuth(user, &requestDTO)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, schedule)
+}
+
+// DeleteSchedule
+// @Summary Delete backup schedule
+// @Description Delete a named backup schedule. Backups created by the schedule are kept and are not cleaned up anymore
+// @Tags backup-configs
+// @Param id path string true "Schedule ID"
+// @Success 204
+// @Failure 400 {object} map[string]string "Invalid schedule ID or schedule not found"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Router /backup-configs/schedules/{id} [delete]
+func (c *BackupConfigController) DeleteSchedule(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid schedule ID"})
+		return
+	}
+
+	if err := c.backupConfigService.DeleteScheduleWithAuth(user, id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
 // IsStorageUsing
 // @Summary Check if storage is being used
 // @Description Check if a storage is currently being used by any backup configuration
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -443,6 +444,612 @@ func Test_SaveBackupConfig_WithEncryptionEncrypted_ConfigSaved(t *testing.T) {
 	assert.Equal(t, BackupEncryptionEncrypted, response.Encryption)
 }
 
+func Test_SaveBackupConfig_WithPhysicalBackupMethod_ConfigSaved(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	database := createTestDatabaseViaAPI("Test Database", workspace.ID, owner.Token, router)
+
+	timeOfDay := "04:00"
+	request := BackupConfig{
+		DatabaseID:       database.ID,
+		IsBackupsEnabled: true,
+		StorePeriod:      period.PeriodWeek,
+		BackupInterval: &intervals.Interval{
+			Interval:  intervals.IntervalDaily,
+			TimeOfDay: &timeOfDay,
+		},
+		SendNotificationsOn: []BackupNotificationType{
+			NotificationBackupFailed,
+		},
+		IsRetryIfFailed:     true,
+		MaxFailedTriesCount: 3,
+		Encryption:          BackupEncryptionNone,
+		BackupMethod:        BackupMethodPhysical,
+	}
+
+	var response BackupConfig
+	test_utils.MakePostRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/backup-configs/save",
+		"Bearer "+owner.Token,
+		request,
+		http.StatusOK,
+		&response,
+	)
+
+	assert.Equal(t, database.ID, response.DatabaseID)
+	assert.Equal(t, BackupMethodPhysical, response.BackupMethod)
+}
+
+func Test_SaveBackupConfig_WithServerBackupMethod_ConfigSaved(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	database := createTestDatabaseViaAPI("Test Database", workspace.ID, owner.Token, router)
+
+	timeOfDay := "04:00"
+	request := BackupConfig{
+		DatabaseID:       database.ID,
+		IsBackupsEnabled: true,
+		StorePeriod:      period.PeriodWeek,
+		BackupInterval: &intervals.Interval{
+			Interval:  intervals.IntervalDaily,
+			TimeOfDay: &timeOfDay,
+		},
+		SendNotificationsOn: []BackupNotificationType{
+			NotificationBackupFailed,
+		},
+		IsRetryIfFailed:     true,
+		MaxFailedTriesCount: 3,
+		Encryption:          BackupEncryptionNone,
+		BackupMethod:        BackupMethodServer,
+	}
+
+	var response BackupConfig
+	test_utils.MakePostRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/backup-configs/save",
+		"Bearer "+owner.Token,
+		request,
+		http.StatusOK,
+		&response,
+	)
+
+	assert.Equal(t, database.ID, response.DatabaseID)
+	assert.Equal(t, BackupMethodServer, response.BackupMethod)
+}
+
+func Test_GetUpcomingRuns_WithDailyInterval_RunsReturnedAtTimeOfDay(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	database := createTestDatabaseViaAPI("Test Database", workspace.ID, owner.Token, router)
+
+	timeOfDay := "04:00"
+	request := BackupConfig{
+		DatabaseID:       database.ID,
+		IsBackupsEnabled: true,
+		StorePeriod:      period.PeriodWeek,
+		BackupInterval: &intervals.Interval{
+			Interval:  intervals.IntervalDaily,
+			TimeOfDay: &timeOfDay,
+		},
+		SendNotificationsOn: []BackupNotificationType{
+			NotificationBackupFailed,
+		},
+		Encryption: BackupEncryptionNone,
+	}
+
+	test_utils.MakePostRequest(
+		t,
+		router,
+		"/api/v1/backup-configs/save",
+		"Bearer "+owner.Token,
+		request,
+		http.StatusOK,
+	)
+
+	var response UpcomingRunsResponse
+	test_utils.MakeGetRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/backup-configs/database/"+database.ID.String()+"/upcoming-runs?count=3",
+		"Bearer "+owner.Token,
+		http.StatusOK,
+		&response,
+	)
+
+	assert.Equal(t, database.ID, response.DatabaseID)
+	assert.Len(t, response.UpcomingRuns, 3)
+	assert.Equal(t, 4, response.UpcomingRuns[1].RunAt.UTC().Hour())
+	assert.Equal(
+		t,
+		24*time.Hour,
+		response.UpcomingRuns[2].RunAt.Sub(response.UpcomingRuns[1].RunAt),
+	)
+}
+
+func Test_GetUpcomingRuns_WhenBackupsDisabled_NoRunsReturned(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	database := createTestDatabaseViaAPI("Test Database", workspace.ID, owner.Token, router)
+
+	var response UpcomingRunsResponse
+	test_utils.MakeGetRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/backup-configs/database/"+database.ID.String()+"/upcoming-runs",
+		"Bearer "+owner.Token,
+		http.StatusOK,
+		&response,
+	)
+
+	assert.Empty(t, response.UpcomingRuns)
+}
+
+func Test_GetUpcomingRuns_WithSchedule_ScheduleRunsReturnedWithScheduleID(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	database := createTestDatabaseViaAPI("Test Database", workspace.ID, owner.Token, router)
+	storage := createTestStorage(workspace.ID)
+
+	var schedule BackupSchedule
+	test_utils.MakePostRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/backup-configs/database/"+database.ID.String()+"/schedules",
+		"Bearer "+owner.Token,
+		createTestScheduleRequest("Hourly", storage.ID),
+		http.StatusOK,
+		&schedule,
+	)
+
+	var response UpcomingRunsResponse
+	test_utils.MakeGetRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/backup-configs/database/"+database.ID.String()+"/upcoming-runs?count=3",
+		"Bearer "+owner.Token,
+		http.StatusOK,
+		&response,
+	)
+
+	assert.Len(t, response.ScheduleUpcomingRuns, 1)
+	assert.Equal(t, schedule.ID, response.ScheduleUpcomingRuns[0].ScheduleID)
+	assert.Len(t, response.ScheduleUpcomingRuns[0].UpcomingRuns, 3)
+	assert.Equal(
+		t,
+		time.Hour,
+		response.ScheduleUpcomingRuns[0].UpcomingRuns[2].RunAt.
+			Sub(response.ScheduleUpcomingRuns[0].UpcomingRuns[1].RunAt),
+	)
+}
+
+func Test_GetUpcomingRuns_WhenUserIsNotWorkspaceMember_ReturnsBadRequest(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	nonMember := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	database := createTestDatabaseViaAPI("Test Database", workspace.ID, owner.Token, router)
+
+	test_utils.MakeGetRequest(
+		t,
+		router,
+		"/api/v1/backup-configs/database/"+database.ID.String()+"/upcoming-runs",
+		"Bearer "+nonMember.Token,
+		http.StatusBadRequest,
+	)
+}
+
+func Test_SaveBackupConfig_WithUnknownBackupMethod_ReturnsBadRequest(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	database := createTestDatabaseViaAPI("Test Database", workspace.ID, owner.Token, router)
+
+	timeOfDay := "04:00"
+	request := BackupConfig{
+		DatabaseID:       database.ID,
+		IsBackupsEnabled: true,
+		StorePeriod:      period.PeriodWeek,
+		BackupInterval: &intervals.Interval{
+			Interval:  intervals.IntervalDaily,
+			TimeOfDay: &timeOfDay,
+		},
+		Encryption:   BackupEncryptionNone,
+		BackupMethod: "INCREMENTAL",
+	}
+
+	test_utils.MakePostRequest(
+		t,
+		router,
+		"/api/v1/backup-configs/save",
+		"Bearer "+owner.Token,
+		request,
+		http.StatusBadRequest,
+	)
+}
+
+func Test_SaveBackupConfig_WithSecondaryStorages_StorageMarkedAsUsing(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	database := createTestDatabaseViaAPI("Test Database", workspace.ID, owner.Token, router)
+	primaryStorage := createTestStorage(workspace.ID)
+	secondaryStorage := storages.CreateTestS3Storage(workspace.ID)
+
+	timeOfDay := "04:00"
+	request := BackupConfig{
+		DatabaseID:       database.ID,
+		IsBackupsEnabled: true,
+		StorePeriod:      period.PeriodWeek,
+		BackupInterval: &intervals.Interval{
+			Interval:  intervals.IntervalDaily,
+			TimeOfDay: &timeOfDay,
+		},
+		Storage:           primaryStorage,
+		SecondaryStorages: []storages.Storage{*secondaryStorage},
+		Encryption:        BackupEncryptionNone,
+	}
+
+	var response BackupConfig
+	test_utils.MakePostRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/backup-configs/save",
+		"Bearer "+owner.Token,
+		request,
+		http.StatusOK,
+		&response,
+	)
+
+	assert.Len(t, response.SecondaryStorages, 1)
+	assert.Equal(t, secondaryStorage.ID, response.SecondaryStorages[0].ID)
+
+	var isUsingResponse map[string]bool
+	test_utils.MakeGetRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/backup-configs/storage/"+secondaryStorage.ID.String()+"/is-using",
+		"Bearer "+owner.Token,
+		http.StatusOK,
+		&isUsingResponse,
+	)
+	assert.True(t, isUsingResponse["isUsing"])
+}
+
+func Test_SaveBackupConfig_WithLocalSecondaryOfLocalStorage_ReturnsBadRequest(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	database := createTestDatabaseViaAPI("Test Database", workspace.ID, owner.Token, router)
+	primaryStorage := createTestStorage(workspace.ID)
+	secondaryStorage := createTestStorage(workspace.ID)
+
+	timeOfDay := "04:00"
+	request := BackupConfig{
+		DatabaseID:       database.ID,
+		IsBackupsEnabled: true,
+		StorePeriod:      period.PeriodWeek,
+		BackupInterval: &intervals.Interval{
+			Interval:  intervals.IntervalDaily,
+			TimeOfDay: &timeOfDay,
+		},
+		Storage:           primaryStorage,
+		SecondaryStorages: []storages.Storage{*secondaryStorage},
+		Encryption:        BackupEncryptionNone,
+	}
+
+	testResp := test_utils.MakePostRequest(
+		t,
+		router,
+		"/api/v1/backup-configs/save",
+		"Bearer "+owner.Token,
+		request,
+		http.StatusBadRequest,
+	)
+	assert.Contains(t, string(testResp.Body), ErrSecondaryStorageIsLocal.Error())
+}
+
+func Test_SaveBackupConfig_WithPrimaryStorageAsSecondary_ReturnsBadRequest(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	database := createTestDatabaseViaAPI("Test Database", workspace.ID, owner.Token, router)
+	storage := createTestStorage(workspace.ID)
+
+	timeOfDay := "04:00"
+	request := BackupConfig{
+		DatabaseID:       database.ID,
+		IsBackupsEnabled: true,
+		StorePeriod:      period.PeriodWeek,
+		BackupInterval: &intervals.Interval{
+			Interval:  intervals.IntervalDaily,
+			TimeOfDay: &timeOfDay,
+		},
+		Storage:           storage,
+		SecondaryStorages: []storages.Storage{*storage},
+		Encryption:        BackupEncryptionNone,
+	}
+
+	testResp := test_utils.MakePostRequest(
+		t,
+		router,
+		"/api/v1/backup-configs/save",
+		"Bearer "+owner.Token,
+		request,
+		http.StatusBadRequest,
+	)
+	assert.Contains(t, string(testResp.Body), ErrSecondaryStorageIsPrimary.Error())
+}
+
+func Test_SaveBackupConfig_WithPrimaryStorageAsArchive_ReturnsBadRequest(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	database := createTestDatabaseViaAPI("Test Database", workspace.ID, owner.Token, router)
+	storage := createTestStorage(workspace.ID)
+
+	timeOfDay := "04:00"
+	request := BackupConfig{
+		DatabaseID:       database.ID,
+		IsBackupsEnabled: true,
+		StorePeriod:      period.PeriodMonth,
+		BackupInterval: &intervals.Interval{
+			Interval:  intervals.IntervalDaily,
+			TimeOfDay: &timeOfDay,
+		},
+		Storage:          storage,
+		ArchiveStorageID: &storage.ID,
+		ArchiveAfterDays: 7,
+		Encryption:       BackupEncryptionNone,
+	}
+
+	testResp := test_utils.MakePostRequest(
+		t,
+		router,
+		"/api/v1/backup-configs/save",
+		"Bearer "+owner.Token,
+		request,
+		http.StatusBadRequest,
+	)
+	assert.Contains(t, string(testResp.Body), ErrArchiveStorageIsPrimary.Error())
+}
+
+func Test_SaveBackupConfig_WithLocalArchiveOfLocalStorage_ReturnsBadRequest(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	database := createTestDatabaseViaAPI("Test Database", workspace.ID, owner.Token, router)
+	primaryStorage := createTestStorage(workspace.ID)
+	archiveStorage := createTestStorage(workspace.ID)
+
+	timeOfDay := "04:00"
+	request := BackupConfig{
+		DatabaseID:       database.ID,
+		IsBackupsEnabled: true,
+		StorePeriod:      period.PeriodMonth,
+		BackupInterval: &intervals.Interval{
+			Interval:  intervals.IntervalDaily,
+			TimeOfDay: &timeOfDay,
+		},
+		Storage:          primaryStorage,
+		ArchiveStorageID: &archiveStorage.ID,
+		ArchiveAfterDays: 7,
+		Encryption:       BackupEncryptionNone,
+	}
+
+	testResp := test_utils.MakePostRequest(
+		t,
+		router,
+		"/api/v1/backup-configs/save",
+		"Bearer "+owner.Token,
+		request,
+		http.StatusBadRequest,
+	)
+	assert.Contains(t, string(testResp.Body), ErrArchiveStorageIsLocal.Error())
+}
+
+func Test_CreateSchedule_WithSchemaOnlyMode_ScheduleSavedAndStorageMarkedAsUsing(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	database := createTestDatabaseViaAPI("Test Database", workspace.ID, owner.Token, router)
+	storage := createTestStorage(workspace.ID)
+
+	request := createTestScheduleRequest("Hourly schema", storage.ID)
+	request.BackupMode = BackupModeSchemaOnly
+
+	var response BackupSchedule
+	test_utils.MakePostRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/backup-configs/database/"+database.ID.String()+"/schedules",
+		"Bearer "+owner.Token,
+		request,
+		http.StatusOK,
+		&response,
+	)
+
+	assert.NotEqual(t, uuid.Nil, response.ID)
+	assert.Equal(t, database.ID, response.DatabaseID)
+	assert.Equal(t, "Hourly schema", response.Name)
+	assert.Equal(t, BackupModeSchemaOnly, response.BackupMode)
+	assert.Equal(t, period.PeriodDay, response.StorePeriod)
+	assert.Equal(t, storage.ID, response.StorageID)
+
+	var schedules []BackupSchedule
+	test_utils.MakeGetRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/backup-configs/database/"+database.ID.String()+"/schedules",
+		"Bearer "+owner.Token,
+		http.StatusOK,
+		&schedules,
+	)
+	assert.Len(t, schedules, 1)
+
+	var isUsingResponse map[string]bool
+	test_utils.MakeGetRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/backup-configs/storage/"+storage.ID.String()+"/is-using",
+		"Bearer "+owner.Token,
+		http.StatusOK,
+		&isUsingResponse,
+	)
+	assert.True(t, isUsingResponse["isUsing"])
+}
+
+func Test_CreateSchedule_WithDuplicatedName_ReturnsBadRequest(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	database := createTestDatabaseViaAPI("Test Database", workspace.ID, owner.Token, router)
+	storage := createTestStorage(workspace.ID)
+
+	test_utils.MakePostRequest(
+		t,
+		router,
+		"/api/v1/backup-configs/database/"+database.ID.String()+"/schedules",
+		"Bearer "+owner.Token,
+		createTestScheduleRequest("Nightly", storage.ID),
+		http.StatusOK,
+	)
+
+	testResp := test_utils.MakePostRequest(
+		t,
+		router,
+		"/api/v1/backup-configs/database/"+database.ID.String()+"/schedules",
+		"Bearer "+owner.Token,
+		createTestScheduleRequest("nightly", storage.ID),
+		http.StatusBadRequest,
+	)
+	assert.Contains(t, string(testResp.Body), ErrBackupScheduleNameDuplicated.Error())
+}
+
+func Test_CreateSchedule_WithStorageFromOtherWorkspace_ReturnsBadRequest(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+	otherWorkspace := workspaces_testing.CreateTestWorkspace("Other Workspace", owner, router)
+
+	database := createTestDatabaseViaAPI("Test Database", workspace.ID, owner.Token, router)
+	otherStorage := createTestStorage(otherWorkspace.ID)
+
+	testResp := test_utils.MakePostRequest(
+		t,
+		router,
+		"/api/v1/backup-configs/database/"+database.ID.String()+"/schedules",
+		"Bearer "+owner.Token,
+		createTestScheduleRequest("Nightly", otherStorage.ID),
+		http.StatusBadRequest,
+	)
+	assert.Contains(t, string(testResp.Body), ErrScheduleStorageNotInWorkspace.Error())
+}
+
+func Test_UpdateSchedule_WhenUserIsNotWorkspaceMember_ReturnsBadRequest(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	nonMember := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	database := createTestDatabaseViaAPI("Test Database", workspace.ID, owner.Token, router)
+	storage := createTestStorage(workspace.ID)
+
+	var schedule BackupSchedule
+	test_utils.MakePostRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/backup-configs/database/"+database.ID.String()+"/schedules",
+		"Bearer "+owner.Token,
+		createTestScheduleRequest("Nightly", storage.ID),
+		http.StatusOK,
+		&schedule,
+	)
+
+	request := createTestScheduleRequest("Renamed", storage.ID)
+	test_utils.MakePutRequest(
+		t,
+		router,
+		"/api/v1/backup-configs/schedules/"+schedule.ID.String(),
+		"Bearer "+nonMember.Token,
+		request,
+		http.StatusBadRequest,
+	)
+
+	var updatedSchedule BackupSchedule
+	test_utils.MakePutRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/backup-configs/schedules/"+schedule.ID.String(),
+		"Bearer "+owner.Token,
+		request,
+		http.StatusOK,
+		&updatedSchedule,
+	)
+	assert.Equal(t, "Renamed", updatedSchedule.Name)
+	assert.Equal(t, schedule.BackupIntervalID, updatedSchedule.BackupIntervalID)
+}
+
+func Test_DeleteSchedule_ScheduleRemovedFromDatabaseSchedules(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	database := createTestDatabaseViaAPI("Test Database", workspace.ID, owner.Token, router)
+	storage := createTestStorage(workspace.ID)
+
+	var schedule BackupSchedule
+	test_utils.MakePostRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/backup-configs/database/"+database.ID.String()+"/schedules",
+		"Bearer "+owner.Token,
+		createTestScheduleRequest("Nightly", storage.ID),
+		http.StatusOK,
+		&schedule,
+	)
+
+	test_utils.MakeDeleteRequest(
+		t,
+		router,
+		"/api/v1/backup-configs/schedules/"+schedule.ID.String(),
+		"Bearer "+owner.Token,
+		http.StatusNoContent,
+	)
+
+	var schedules []BackupSchedule
+	test_utils.MakeGetRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/backup-configs/database/"+database.ID.String()+"/schedules",
+		"Bearer "+owner.Token,
+		http.StatusOK,
+		&schedules,
+	)
+	assert.Empty(t, schedules)
+}
+
 func Test_TransferDatabase_PermissionsEnforced(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -1473,6 +2080,21 @@ func createTestStorage(workspaceID uuid.UUID) *storages.Storage {
 	return storages.CreateTestStorage(workspaceID)
 }
 
+func createTestScheduleRequest(name string, storageID uuid.UUID) *BackupSchedule {
+	return &BackupSchedule{
+		Name:      name,
+		IsEnabled: true,
+		BackupInterval: &intervals.Interval{
+			Interval: intervals.IntervalHourly,
+		},
+		StorePeriod: period.PeriodDay,
+		StorageID:   storageID,
+		SendNotificationsOn: []BackupNotificationType{
+			NotificationBackupFailed,
+		},
+	}
+}
+
 func createTestRouterWithStorageForTransfer() *gin.Engine {
 	router := workspaces_testing.CreateTestRouter(
 		workspaces_controllers.GetWorkspaceController(),
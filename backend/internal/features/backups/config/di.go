@@ -1,6 +1,8 @@
 package backups_config
 
 import (
+	audit_logs "databasus-backend/internal/features/audit_logs"
+	backups_pauses "databasus-backend/internal/features/backups/pauses"
 	"databasus-backend/internal/features/databases"
 	"databasus-backend/internal/features/notifiers"
 	"databasus-backend/internal/features/storages"
@@ -14,6 +16,8 @@ var backupConfigService = &BackupConfigService{
 	storages.GetStorageService(),
 	notifiers.GetNotifierService(),
 	workspaces_services.GetWorkspaceService(),
+	audit_logs.GetAuditLogService(),
+	backups_pauses.GetBackupPauseService(),
 	nil,
 }
 var backupConfigController = &BackupConfigController{
@@ -1,6 +1,15 @@
 package backups_config
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultUpcomingRunsCount = 5
+	maxUpcomingRunsCount     = 100
+)
 
 type TransferDatabaseRequest struct {
 	TargetWorkspaceID       uuid.UUID   `json:"targetWorkspaceId"                 binding:"required"`
@@ -9,3 +18,24 @@ type TransferDatabaseRequest struct {
 	IsTransferWithNotifiers bool        `json:"isTransferWithNotifiers,omitempty"`
 	TargetNotifierIDs       []uuid.UUID `json:"targetNotifierIds,omitempty"`
 }
+
+type UpcomingRunsResponse struct {
+	DatabaseID   uuid.UUID     `json:"databaseId"`
+	UpcomingRuns []UpcomingRun `json:"upcomingRuns"`
+
+	ScheduleUpcomingRuns []ScheduleUpcomingRuns `json:"scheduleUpcomingRuns"`
+}
+
+type ScheduleUpcomingRuns struct {
+	ScheduleID   uuid.UUID     `json:"scheduleId"`
+	ScheduleName string        `json:"scheduleName"`
+	UpcomingRuns []UpcomingRun `json:"upcomingRuns"`
+}
+
+// UpcomingRun is a scheduled backup. A postponed run falls into a blackout
+// window or a pause, its backup is queued right after they end
+type UpcomingRun struct {
+	RunAt          time.Time `json:"runAt"`
+	IsPostponed    bool      `json:"isPostponed"`
+	PostponeReason string    `json:"postponeReason,omitempty"`
+}
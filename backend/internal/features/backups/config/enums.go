@@ -5,6 +5,14 @@ type BackupNotificationType string
 const (
 	NotificationBackupFailed  BackupNotificationType = "BACKUP_FAILED"
 	NotificationBackupSuccess BackupNotificationType = "BACKUP_SUCCESS"
+	// sent when cleanup kept expired backups to honor KeepMinBackupsCount
+	NotificationBackupCleanupSkipped BackupNotificationType = "BACKUP_CLEANUP_SKIPPED"
+	// sent when the latest backup failed to restore into the sandbox database
+	// or the restored data did not pass the sanity checks
+	NotificationBackupVerificationFailed BackupNotificationType = "BACKUP_VERIFICATION_FAILED"
+	// sent when the copy of the backup to a secondary storage failed after
+	// all attempts
+	NotificationBackupReplicationFailed BackupNotificationType = "BACKUP_REPLICATION_FAILED"
 )
 
 type BackupEncryption string
@@ -13,3 +21,27 @@ const (
 	BackupEncryptionNone      BackupEncryption = "NONE"
 	BackupEncryptionEncrypted BackupEncryption = "ENCRYPTED"
 )
+
+type BackupMethod string
+
+const (
+	BackupMethodLogical  BackupMethod = "LOGICAL"  // dump tools (pg_dump, mysqldump, etc.)
+	BackupMethodPhysical BackupMethod = "PHYSICAL" // PostgreSQL pg_basebackup of the whole cluster
+	// PostgreSQL pg_dump of every database on the server and pg_dumpall of
+	// globals (roles, tablespaces), stored as one grouped backup
+	BackupMethodServer BackupMethod = "SERVER"
+)
+
+type RetentionPolicyType string
+
+const (
+	RetentionPolicyTypeTimePeriod RetentionPolicyType = "TIME_PERIOD" // delete backups older than StorePeriod
+	RetentionPolicyTypeGfs        RetentionPolicyType = "GFS"         // grandfather-father-son rotation
+)
+
+type BackupMode string
+
+const (
+	BackupModeFull       BackupMode = "FULL"        // schema and data
+	BackupModeSchemaOnly BackupMode = "SCHEMA_ONLY" // schema without data of tables
+)
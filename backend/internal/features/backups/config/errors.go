@@ -27,4 +27,62 @@ var (
 	ErrTargetStorageNotSpecified = errors.New(
 		"target storage is not specified",
 	)
+	ErrWalArchivingNotSupported = errors.New(
+		"WAL archiving is supported only for PostgreSQL databases",
+	)
+	ErrBinlogArchivingNotSupported = errors.New(
+		"binlog archiving is supported only for MySQL and MariaDB databases",
+	)
+	ErrOplogArchivingNotSupported = errors.New(
+		"oplog archiving is supported only for MongoDB databases",
+	)
+	ErrOplogArchivingWithExcludedCollections = errors.New(
+		"oplog archiving dumps the whole instance and cannot be combined with excluded collections",
+	)
+	ErrSecondaryStorageIsPrimary = errors.New(
+		"secondary storage cannot be the same as the primary storage",
+	)
+	ErrSecondaryStorageDuplicated = errors.New(
+		"secondary storage is specified more than once",
+	)
+	ErrSecondaryStorageNotInWorkspace = errors.New(
+		"secondary storage does not belong to the same workspace as the database",
+	)
+	ErrSecondaryStorageIsLocal = errors.New(
+		"local storage cannot be the secondary storage of a local primary storage",
+	)
+	ErrArchiveStorageIsPrimary = errors.New(
+		"archive storage cannot be the same as the primary storage",
+	)
+	ErrArchiveStorageIsSecondary = errors.New(
+		"archive storage cannot be one of the secondary storages",
+	)
+	ErrArchiveStorageIsLocal = errors.New(
+		"local storage cannot be the archive storage of a local primary storage",
+	)
+	ErrArchiveStorageNotInWorkspace = errors.New(
+		"archive storage does not belong to the same workspace as the database",
+	)
+	ErrPhysicalBackupsNotSupported = errors.New(
+		"physical backups are supported only for PostgreSQL databases",
+	)
+	ErrServerBackupsNotSupported = errors.New(
+		"server backups are supported only for PostgreSQL databases",
+	)
+	ErrSchemaOnlyBackupsNotSupported = errors.New(
+		"schema-only backups are not supported for MongoDB databases",
+	)
+	ErrBackupScheduleNotFound = errors.New(
+		"backup schedule not found",
+	)
+	ErrBackupScheduleNameDuplicated = errors.New(
+		"backup schedule with this name already exists for the database",
+	)
+	ErrScheduleStorageNotInWorkspace = errors.New(
+		"schedule storage does not belong to the same workspace as the database",
+	)
+	ErrLockStorageRequiresRetentionEnd = errors.New(
+		"storages with object lock keep backups until retention deletes them, " +
+			"GFS retention and keeping backups forever cannot be used with them",
+	)
 )
@@ -3,5 +3,5 @@ package backups_config
 import "github.com/google/uuid"
 
 type BackupConfigStorageChangeListener interface {
-	OnBeforeBackupsStorageChange(dbID uuid.UUID) error
+	OnBeforeBackupsStorageChange(dbID uuid.UUID, oldStorageID uuid.UUID) error
 }
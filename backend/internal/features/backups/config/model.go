@@ -4,8 +4,10 @@ import (
 	"databasus-backend/internal/features/intervals"
 	"databasus-backend/internal/features/storages"
 	"databasus-backend/internal/util/period"
+	"encoding/json"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -24,6 +26,16 @@ type BackupConfig struct {
 	Storage   *storages.Storage `json:"storage"   gorm:"foreignKey:StorageID"`
 	StorageID *uuid.UUID        `json:"storageId" gorm:"column:storage_id;type:uuid;"`
 
+	// SecondaryStorages receive copies of each completed backup, replicated
+	// from the primary storage after upload
+	SecondaryStorages []storages.Storage `json:"secondaryStorages" gorm:"many2many:backup_config_secondary_storages;joinForeignKey:DatabaseID;joinReferences:StorageID"`
+
+	// ArchiveStorageID is the storage (usually a cheaper one) completed
+	// backups of the primary storage are moved to when they are older than
+	// ArchiveAfterDays. Archived backups are restored from it transparently
+	ArchiveStorageID *uuid.UUID `json:"archiveStorageId" gorm:"column:archive_storage_id;type:uuid"`
+	ArchiveAfterDays int        `json:"archiveAfterDays" gorm:"column:archive_after_days;type:int;not null;default:0"`
+
 	SendNotificationsOn       []BackupNotificationType `json:"sendNotificationsOn" gorm:"-"`
 	SendNotificationsOnString string                   `json:"-"                   gorm:"column:send_notifications_on;type:text;not null"`
 
@@ -31,6 +43,63 @@ type BackupConfig struct {
 	MaxFailedTriesCount int  `json:"maxFailedTriesCount" gorm:"column:max_failed_tries_count;type:int;not null"`
 
 	Encryption BackupEncryption `json:"encryption" gorm:"column:encryption;type:text;not null;default:'NONE'"`
+
+	// IsWalArchivingEnabled turns on continuous WAL archiving (PostgreSQL only).
+	// Scheduled backups become pg_basebackup base backups and WAL segments are
+	// streamed into the storage to allow point-in-time recovery
+	IsWalArchivingEnabled bool `json:"isWalArchivingEnabled" gorm:"column:is_wal_archiving_enabled;type:boolean;not null;default:false"`
+
+	// IsBinlogArchivingEnabled turns on continuous binary log archiving (MySQL
+	// and MariaDB only). Dumps record their binlog coordinates and binary logs
+	// are streamed into the storage to allow point-in-time recovery
+	IsBinlogArchivingEnabled bool `json:"isBinlogArchivingEnabled" gorm:"column:is_binlog_archiving_enabled;type:boolean;not null;default:false"`
+
+	// IsOplogArchivingEnabled turns on oplog mode (MongoDB replica sets only).
+	// Dumps cover the whole instance and are consistent via mongodump --oplog,
+	// oplog is sliced into the storage to allow point-in-time recovery
+	IsOplogArchivingEnabled bool `json:"isOplogArchivingEnabled" gorm:"column:is_oplog_archiving_enabled;type:boolean;not null;default:false"`
+
+	// BackupMethod selects logical dumps or physical copies of the data
+	// directory (PostgreSQL only). Physical backups are self-contained tar
+	// archives of the whole cluster, restored by laying out a data directory.
+	// Server backups dump all databases of the server together with roles
+	// and tablespaces (PostgreSQL only)
+	BackupMethod BackupMethod `json:"backupMethod" gorm:"column:backup_method;type:text;not null;default:'LOGICAL'"`
+
+	// BackupMode selects whether logical backups contain data of tables or
+	// only the schema. Schema-only backups are not supported for MongoDB
+	// and cannot be combined with physical backups or log archiving
+	BackupMode BackupMode `json:"backupMode" gorm:"column:backup_mode;type:text;not null;default:'FULL'"`
+
+	// RetentionPolicyType selects how old backups are cleaned up. With GFS
+	// the newest completed backup of each of the last N hours, days, ISO
+	// weeks, months and years is kept (periods are in UTC), StorePeriod
+	// is ignored
+	RetentionPolicyType RetentionPolicyType `json:"retentionPolicyType" gorm:"column:retention_policy_type;type:text;not null;default:'TIME_PERIOD'"`
+	GfsHourlyCount      int                 `json:"gfsHourlyCount"      gorm:"column:gfs_hourly_count;type:int;not null;default:0"`
+	GfsDailyCount       int                 `json:"gfsDailyCount"       gorm:"column:gfs_daily_count;type:int;not null;default:0"`
+	GfsWeeklyCount      int                 `json:"gfsWeeklyCount"      gorm:"column:gfs_weekly_count;type:int;not null;default:0"`
+	GfsMonthlyCount     int                 `json:"gfsMonthlyCount"     gorm:"column:gfs_monthly_count;type:int;not null;default:0"`
+	GfsYearlyCount      int                 `json:"gfsYearlyCount"      gorm:"column:gfs_yearly_count;type:int;not null;default:0"`
+
+	// KeepMinBackupsCount is the number of the last completed backups which
+	// are never deleted by cleanup, regardless of their age and retention
+	// policy. Protects from losing all backups when recent runs fail
+	KeepMinBackupsCount int `json:"keepMinBackupsCount" gorm:"column:keep_min_backups_count;type:int;not null;default:1"`
+
+	// NextRunAt is the next scheduled backup computed by the scheduler from
+	// BackupInterval. It is nil until computed and reset on each save of the
+	// config, so changes of the interval are applied
+	NextRunAt *time.Time `json:"nextRunAt" gorm:"column:next_run_at"`
+
+	// BlackoutWindows are daily time ranges when scheduled backups of the
+	// database (of backup schedules too) are not started
+	BlackoutWindows     []BlackoutWindow `json:"blackoutWindows" gorm:"-"`
+	BlackoutWindowsJSON string           `json:"-"               gorm:"column:blackout_windows;type:text;not null;default:'[]'"`
+
+	// ScheduleID is set on configs built from a BackupSchedule by
+	// BackupSchedule.ToBackupConfig, nil for the backup config itself
+	ScheduleID *uuid.UUID `json:"-" gorm:"-"`
 }
 
 func (h *BackupConfig) TableName() string {
@@ -38,33 +107,46 @@ func (h *BackupConfig) TableName() string {
 }
 
 func (b *BackupConfig) BeforeSave(tx *gorm.DB) error {
-	// Convert SendNotificationsOn array to string
-	if len(b.SendNotificationsOn) > 0 {
-		notificationTypes := make([]string, len(b.SendNotificationsOn))
+	b.SendNotificationsOnString = joinNotificationTypes(b.SendNotificationsOn)
 
-		for i, notificationType := range b.SendNotificationsOn {
-			notificationTypes[i] = string(notificationType)
+	if len(b.BlackoutWindows) > 0 {
+		data, err := json.Marshal(b.BlackoutWindows)
+		if err != nil {
+			return err
 		}
 
-		b.SendNotificationsOnString = strings.Join(notificationTypes, ",")
+		b.BlackoutWindowsJSON = string(data)
 	} else {
-		b.SendNotificationsOnString = ""
+		b.BlackoutWindowsJSON = "[]"
+	}
+
+	if b.BackupMethod == "" {
+		b.BackupMethod = BackupMethodLogical
+	}
+
+	if b.BackupMode == "" {
+		b.BackupMode = BackupModeFull
+	}
+
+	if b.RetentionPolicyType == "" {
+		b.RetentionPolicyType = RetentionPolicyTypeTimePeriod
+	}
+
+	if b.StorePeriod == "" && b.RetentionPolicyType == RetentionPolicyTypeGfs {
+		b.StorePeriod = period.PeriodForever
 	}
 
 	return nil
 }
 
 func (b *BackupConfig) AfterFind(tx *gorm.DB) error {
-	// Convert SendNotificationsOnString to array
-	if b.SendNotificationsOnString != "" {
-		notificationTypes := strings.Split(b.SendNotificationsOnString, ",")
-		b.SendNotificationsOn = make([]BackupNotificationType, len(notificationTypes))
+	b.SendNotificationsOn = splitNotificationTypes(b.SendNotificationsOnString)
 
-		for i, notificationType := range notificationTypes {
-			b.SendNotificationsOn[i] = BackupNotificationType(notificationType)
+	b.BlackoutWindows = []BlackoutWindow{}
+	if b.BlackoutWindowsJSON != "" {
+		if err := json.Unmarshal([]byte(b.BlackoutWindowsJSON), &b.BlackoutWindows); err != nil {
+			return err
 		}
-	} else {
-		b.SendNotificationsOn = []BackupNotificationType{}
 	}
 
 	return nil
@@ -76,19 +158,142 @@ func (b *BackupConfig) Validate() error {
 		return errors.New("backup interval is required")
 	}
 
-	if b.StorePeriod == "" {
-		return errors.New("store period is required")
+	if err := b.validateRetentionPolicy(); err != nil {
+		return err
+	}
+
+	if b.KeepMinBackupsCount < 0 {
+		return errors.New("keep min backups count cannot be negative")
 	}
 
 	if b.IsRetryIfFailed && b.MaxFailedTriesCount <= 0 {
 		return errors.New("max failed tries count must be greater than 0")
 	}
 
+	if b.ArchiveAfterDays < 0 {
+		return errors.New("archive after days cannot be negative")
+	}
+
+	if b.ArchiveStorageID != nil && b.ArchiveAfterDays == 0 {
+		return errors.New("archive after days must be greater than 0 when archive storage is set")
+	}
+
 	if b.Encryption != "" && b.Encryption != BackupEncryptionNone &&
 		b.Encryption != BackupEncryptionEncrypted {
 		return errors.New("encryption must be NONE or ENCRYPTED")
 	}
 
+	if b.BackupMethod != "" && b.BackupMethod != BackupMethodLogical &&
+		b.BackupMethod != BackupMethodPhysical && b.BackupMethod != BackupMethodServer {
+		return errors.New("backup method must be LOGICAL, PHYSICAL or SERVER")
+	}
+
+	if err := validateBackupMode(b.BackupMode); err != nil {
+		return err
+	}
+
+	for _, blackoutWindow := range b.BlackoutWindows {
+		if err := blackoutWindow.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if b.BackupMode == BackupModeSchemaOnly {
+		if b.BackupMethod == BackupMethodPhysical || b.BackupMethod == BackupMethodServer {
+			return errors.New("schema-only backups are supported only for LOGICAL backup method")
+		}
+
+		if b.IsWalArchivingEnabled || b.IsBinlogArchivingEnabled || b.IsOplogArchivingEnabled {
+			return errors.New("schema-only backups cannot be combined with log archiving")
+		}
+	}
+
+	return nil
+}
+
+// IsArchivingEnabled reports whether old backups are moved to the archive
+// storage
+func (b *BackupConfig) IsArchivingEnabled() bool {
+	return b.ArchiveStorageID != nil && b.ArchiveAfterDays > 0
+}
+
+func (b *BackupConfig) IsGfsRetention() bool {
+	return b.RetentionPolicyType == RetentionPolicyTypeGfs
+}
+
+// HasRetentionEnd reports whether it is known in advance when retention
+// deletes backups. It is not for GFS retention and backups kept forever
+func (b *BackupConfig) HasRetentionEnd() bool {
+	return !b.IsGfsRetention() && b.StorePeriod != period.PeriodForever && b.StorePeriod != ""
+}
+
+// GetRetentionEnd returns when retention deletes the backup created at the
+// time, see HasRetentionEnd
+func (b *BackupConfig) GetRetentionEnd(createdAt time.Time) (time.Time, bool) {
+	if !b.HasRetentionEnd() {
+		return time.Time{}, false
+	}
+
+	return createdAt.Add(b.StorePeriod.ToDuration()), true
+}
+
+// IsInBlackoutWindow reports whether scheduled backups cannot be started at
+// the moment because of blackout windows. Windows are evaluated in the
+// timezone of the backup interval
+func (b *BackupConfig) IsInBlackoutWindow(now time.Time) bool {
+	if len(b.BlackoutWindows) == 0 {
+		return false
+	}
+
+	location := time.UTC
+	if b.BackupInterval != nil {
+		intervalLocation, err := b.BackupInterval.GetLocation()
+		if err == nil {
+			location = intervalLocation
+		}
+	}
+
+	localNow := now.In(location)
+
+	for _, blackoutWindow := range b.BlackoutWindows {
+		if blackoutWindow.Contains(localNow) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetScheduleKey identifies the schedule the config belongs to: the ID of the
+// schedule for configs of backup schedules, the database ID otherwise
+func (b *BackupConfig) GetScheduleKey() uuid.UUID {
+	if b.ScheduleID != nil {
+		return *b.ScheduleID
+	}
+
+	return b.DatabaseID
+}
+
+func (b *BackupConfig) validateRetentionPolicy() error {
+	switch b.RetentionPolicyType {
+	case "", RetentionPolicyTypeTimePeriod:
+		if b.StorePeriod == "" {
+			return errors.New("store period is required")
+		}
+	case RetentionPolicyTypeGfs:
+		if b.GfsHourlyCount < 0 || b.GfsDailyCount < 0 || b.GfsWeeklyCount < 0 ||
+			b.GfsMonthlyCount < 0 || b.GfsYearlyCount < 0 {
+			return errors.New("GFS retention counts cannot be negative")
+		}
+
+		if b.GfsHourlyCount+b.GfsDailyCount+b.GfsWeeklyCount+
+			b.GfsMonthlyCount+b.GfsYearlyCount == 0 {
+			return errors.New("GFS retention requires at least one count greater than 0")
+		}
+	default:
+		return errors.New("retention policy type must be TIME_PERIOD or GFS")
+	}
+
 	return nil
 }
 
@@ -100,9 +305,200 @@ func (b *BackupConfig) Copy(newDatabaseID uuid.UUID) *BackupConfig {
 		BackupIntervalID:    uuid.Nil,
 		BackupInterval:      b.BackupInterval.Copy(),
 		StorageID:           b.StorageID,
+		SecondaryStorages:   b.SecondaryStorages,
+		ArchiveStorageID:    b.ArchiveStorageID,
+		ArchiveAfterDays:    b.ArchiveAfterDays,
 		SendNotificationsOn: b.SendNotificationsOn,
 		IsRetryIfFailed:     b.IsRetryIfFailed,
 		MaxFailedTriesCount: b.MaxFailedTriesCount,
 		Encryption:          b.Encryption,
+
+		IsWalArchivingEnabled:    b.IsWalArchivingEnabled,
+		IsBinlogArchivingEnabled: b.IsBinlogArchivingEnabled,
+		IsOplogArchivingEnabled:  b.IsOplogArchivingEnabled,
+		BackupMethod:             b.BackupMethod,
+		BackupMode:               b.BackupMode,
+
+		RetentionPolicyType: b.RetentionPolicyType,
+		GfsHourlyCount:      b.GfsHourlyCount,
+		GfsDailyCount:       b.GfsDailyCount,
+		GfsWeeklyCount:      b.GfsWeeklyCount,
+		GfsMonthlyCount:     b.GfsMonthlyCount,
+		GfsYearlyCount:      b.GfsYearlyCount,
+		KeepMinBackupsCount: b.KeepMinBackupsCount,
+
+		BlackoutWindows: b.BlackoutWindows,
+	}
+}
+
+// BackupSchedule is a named schedule of the database run in addition to the
+// schedule of its backup config (e.g. hourly schema-only backups kept for two
+// days next to nightly full ones kept for a month). The interval, backup mode,
+// retention, storage and notifications are of the schedule, other settings
+// (encryption, retries, backup method) are taken from the backup config
+type BackupSchedule struct {
+	ID         uuid.UUID `json:"id"         gorm:"column:id;type:uuid;primaryKey"`
+	DatabaseID uuid.UUID `json:"databaseId" gorm:"column:database_id;type:uuid;not null"`
+
+	Name      string `json:"name"      gorm:"column:name;type:text;not null"`
+	IsEnabled bool   `json:"isEnabled" gorm:"column:is_enabled;type:boolean;not null"`
+
+	BackupMode BackupMode `json:"backupMode" gorm:"column:backup_mode;type:text;not null;default:'FULL'"`
+
+	BackupIntervalID uuid.UUID           `json:"backupIntervalId"         gorm:"column:backup_interval_id;type:uuid;not null"`
+	BackupInterval   *intervals.Interval `json:"backupInterval,omitempty" gorm:"foreignKey:BackupIntervalID"`
+
+	// backups of the schedule older than StorePeriod are deleted, except the
+	// last KeepMinBackupsCount completed ones
+	StorePeriod         period.Period `json:"storePeriod"         gorm:"column:store_period;type:text;not null"`
+	KeepMinBackupsCount int           `json:"keepMinBackupsCount" gorm:"column:keep_min_backups_count;type:int;not null;default:0"`
+
+	StorageID uuid.UUID         `json:"storageId"         gorm:"column:storage_id;type:uuid;not null"`
+	Storage   *storages.Storage `json:"storage,omitempty" gorm:"foreignKey:StorageID"`
+
+	SendNotificationsOn       []BackupNotificationType `json:"sendNotificationsOn" gorm:"-"`
+	SendNotificationsOnString string                   `json:"-"                   gorm:"column:send_notifications_on;type:text;not null"`
+
+	// NextRunAt is computed by the scheduler and reset on each save of the
+	// schedule, the same as BackupConfig.NextRunAt
+	NextRunAt *time.Time `json:"nextRunAt" gorm:"column:next_run_at"`
+
+	CreatedAt time.Time `json:"createdAt" gorm:"column:created_at"`
+}
+
+func (s *BackupSchedule) TableName() string {
+	return "backup_schedules"
+}
+
+func (s *BackupSchedule) BeforeSave(tx *gorm.DB) error {
+	s.SendNotificationsOnString = joinNotificationTypes(s.SendNotificationsOn)
+
+	if s.BackupMode == "" {
+		s.BackupMode = BackupModeFull
+	}
+
+	return nil
+}
+
+func (s *BackupSchedule) AfterFind(tx *gorm.DB) error {
+	s.SendNotificationsOn = splitNotificationTypes(s.SendNotificationsOnString)
+
+	return nil
+}
+
+func (s *BackupSchedule) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return errors.New("schedule name is required")
+	}
+
+	if len(s.Name) > 100 {
+		return errors.New("schedule name must be at most 100 characters")
+	}
+
+	if s.BackupInterval == nil {
+		return errors.New("backup interval is required")
 	}
+
+	if s.StorePeriod == "" {
+		return errors.New("store period is required")
+	}
+
+	if s.KeepMinBackupsCount < 0 {
+		return errors.New("keep min backups count cannot be negative")
+	}
+
+	if s.StorageID == uuid.Nil {
+		return errors.New("storage is required")
+	}
+
+	return validateBackupMode(s.BackupMode)
+}
+
+// ToBackupConfig returns the backup config of the database with settings of
+// the schedule applied. It is used to run, notify about and clean up backups
+// of the schedule and is never saved
+func (s *BackupSchedule) ToBackupConfig(backupConfig *BackupConfig) *BackupConfig {
+	scheduleConfig := *backupConfig
+
+	scheduleConfig.ScheduleID = &s.ID
+	scheduleConfig.IsBackupsEnabled = s.IsEnabled
+	scheduleConfig.BackupIntervalID = s.BackupIntervalID
+	scheduleConfig.BackupInterval = s.BackupInterval
+	scheduleConfig.StorageID = &s.StorageID
+	scheduleConfig.Storage = s.Storage
+	scheduleConfig.SecondaryStorages = []storages.Storage{}
+	// backups of schedules are kept in the storage of the schedule
+	scheduleConfig.ArchiveStorageID = nil
+	scheduleConfig.ArchiveAfterDays = 0
+	scheduleConfig.SendNotificationsOn = s.SendNotificationsOn
+	scheduleConfig.NextRunAt = s.NextRunAt
+
+	scheduleConfig.RetentionPolicyType = RetentionPolicyTypeTimePeriod
+	scheduleConfig.StorePeriod = s.StorePeriod
+	scheduleConfig.GfsHourlyCount = 0
+	scheduleConfig.GfsDailyCount = 0
+	scheduleConfig.GfsWeeklyCount = 0
+	scheduleConfig.GfsMonthlyCount = 0
+	scheduleConfig.GfsYearlyCount = 0
+	scheduleConfig.KeepMinBackupsCount = s.KeepMinBackupsCount
+
+	scheduleConfig.BackupMode = s.BackupMode
+	if s.BackupMode == BackupModeSchemaOnly {
+		// schema-only dumps are plain logical dumps, they are never a base
+		// for point-in-time recovery
+		scheduleConfig.BackupMethod = BackupMethodLogical
+		scheduleConfig.IsWalArchivingEnabled = false
+		scheduleConfig.IsBinlogArchivingEnabled = false
+		scheduleConfig.IsOplogArchivingEnabled = false
+	}
+
+	return &scheduleConfig
+}
+
+func (s *BackupSchedule) Copy(newDatabaseID uuid.UUID) *BackupSchedule {
+	return &BackupSchedule{
+		DatabaseID:          newDatabaseID,
+		Name:                s.Name,
+		IsEnabled:           s.IsEnabled,
+		BackupMode:          s.BackupMode,
+		BackupIntervalID:    uuid.Nil,
+		BackupInterval:      s.BackupInterval.Copy(),
+		StorePeriod:         s.StorePeriod,
+		KeepMinBackupsCount: s.KeepMinBackupsCount,
+		StorageID:           s.StorageID,
+		SendNotificationsOn: s.SendNotificationsOn,
+	}
+}
+
+func validateBackupMode(backupMode BackupMode) error {
+	if backupMode != "" && backupMode != BackupModeFull && backupMode != BackupModeSchemaOnly {
+		return errors.New("backup mode must be FULL or SCHEMA_ONLY")
+	}
+
+	return nil
+}
+
+func joinNotificationTypes(notificationTypes []BackupNotificationType) string {
+	values := make([]string, len(notificationTypes))
+
+	for i, notificationType := range notificationTypes {
+		values[i] = string(notificationType)
+	}
+
+	return strings.Join(values, ",")
+}
+
+func splitNotificationTypes(value string) []BackupNotificationType {
+	if value == "" {
+		return []BackupNotificationType{}
+	}
+
+	values := strings.Split(value, ",")
+	notificationTypes := make([]BackupNotificationType, len(values))
+
+	for i, notificationType := range values {
+		notificationTypes[i] = BackupNotificationType(notificationType)
+	}
+
+	return notificationTypes
 }
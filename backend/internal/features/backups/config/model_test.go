@@ -0,0 +1,184 @@
+package backups_config
+
+import (
+	"testing"
+
+	"databasus-backend/internal/features/intervals"
+	"databasus-backend/internal/features/storages"
+	"databasus-backend/internal/util/period"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ScheduleToBackupConfig_WithScheduleSettings_ConfigSettingsOverridden(t *testing.T) {
+	backupConfig, schedule := createTestBackupConfigWithSchedule()
+
+	scheduleConfig := schedule.ToBackupConfig(backupConfig)
+
+	assert.Equal(t, schedule.ID, *scheduleConfig.ScheduleID)
+	assert.Equal(t, schedule.ID, scheduleConfig.GetScheduleKey())
+	assert.True(t, scheduleConfig.IsBackupsEnabled)
+	assert.Equal(t, schedule.BackupInterval, scheduleConfig.BackupInterval)
+	assert.Equal(t, schedule.StorageID, *scheduleConfig.StorageID)
+	assert.Empty(t, scheduleConfig.SecondaryStorages)
+	assert.False(t, scheduleConfig.IsArchivingEnabled())
+	assert.Equal(t, schedule.SendNotificationsOn, scheduleConfig.SendNotificationsOn)
+	assert.Equal(t, RetentionPolicyTypeTimePeriod, scheduleConfig.RetentionPolicyType)
+	assert.Equal(t, period.PeriodYear, scheduleConfig.StorePeriod)
+	assert.Equal(t, 0, scheduleConfig.GfsDailyCount)
+	assert.Equal(t, 2, scheduleConfig.KeepMinBackupsCount)
+}
+
+func Test_ScheduleToBackupConfig_WithOtherSettings_SettingsTakenFromConfig(t *testing.T) {
+	backupConfig, schedule := createTestBackupConfigWithSchedule()
+
+	scheduleConfig := schedule.ToBackupConfig(backupConfig)
+
+	assert.Equal(t, backupConfig.DatabaseID, scheduleConfig.DatabaseID)
+	assert.Equal(t, BackupEncryptionEncrypted, scheduleConfig.Encryption)
+	assert.True(t, scheduleConfig.IsRetryIfFailed)
+	assert.Equal(t, 3, scheduleConfig.MaxFailedTriesCount)
+	assert.Equal(t, BackupMethodPhysical, scheduleConfig.BackupMethod)
+}
+
+func Test_ScheduleToBackupConfig_WhenConverted_ConfigNotChanged(t *testing.T) {
+	backupConfig, schedule := createTestBackupConfigWithSchedule()
+	configStorageID := *backupConfig.StorageID
+
+	schedule.ToBackupConfig(backupConfig)
+
+	assert.Nil(t, backupConfig.ScheduleID)
+	assert.Equal(t, backupConfig.DatabaseID, backupConfig.GetScheduleKey())
+	assert.Equal(t, configStorageID, *backupConfig.StorageID)
+	assert.Len(t, backupConfig.SecondaryStorages, 1)
+	assert.True(t, backupConfig.IsArchivingEnabled())
+	assert.Equal(t, RetentionPolicyTypeGfs, backupConfig.RetentionPolicyType)
+}
+
+func Test_ScheduleToBackupConfig_WithSchemaOnlyMode_LogicalBackupsWithoutWalArchiving(
+	t *testing.T,
+) {
+	backupConfig, schedule := createTestBackupConfigWithSchedule()
+	backupConfig.IsWalArchivingEnabled = true
+	schedule.BackupMode = BackupModeSchemaOnly
+
+	scheduleConfig := schedule.ToBackupConfig(backupConfig)
+
+	assert.Equal(t, BackupModeSchemaOnly, scheduleConfig.BackupMode)
+	assert.Equal(t, BackupMethodLogical, scheduleConfig.BackupMethod)
+	assert.False(t, scheduleConfig.IsWalArchivingEnabled)
+	assert.True(t, backupConfig.IsWalArchivingEnabled)
+}
+
+func Test_ValidateBackupConfig_WithLogicalSchemaOnlyMode_NoError(t *testing.T) {
+	assert.NoError(t, createTestSchemaOnlyBackupConfig().Validate())
+}
+
+func Test_ValidateBackupConfig_WithPhysicalSchemaOnlyMode_ReturnsError(t *testing.T) {
+	backupConfig := createTestSchemaOnlyBackupConfig()
+	backupConfig.BackupMethod = BackupMethodPhysical
+
+	assert.Error(t, backupConfig.Validate())
+}
+
+func Test_ValidateBackupConfig_WithSchemaOnlyModeAndWalArchiving_ReturnsError(t *testing.T) {
+	backupConfig := createTestSchemaOnlyBackupConfig()
+	backupConfig.IsWalArchivingEnabled = true
+
+	assert.Error(t, backupConfig.Validate())
+}
+
+func Test_ValidateBackupConfig_WithUnknownBackupMode_ReturnsError(t *testing.T) {
+	backupConfig := createTestSchemaOnlyBackupConfig()
+	backupConfig.BackupMode = "DATA_ONLY"
+
+	assert.Error(t, backupConfig.Validate())
+}
+
+func Test_ValidateBackupConfig_WithArchiveAfterDays_ArchivingEnabled(t *testing.T) {
+	backupConfig := createTestArchivingBackupConfig()
+
+	assert.NoError(t, backupConfig.Validate())
+	assert.True(t, backupConfig.IsArchivingEnabled())
+}
+
+func Test_ValidateBackupConfig_WithArchiveStorageWithoutDays_ReturnsError(t *testing.T) {
+	backupConfig := createTestArchivingBackupConfig()
+	backupConfig.ArchiveAfterDays = 0
+
+	assert.Error(t, backupConfig.Validate())
+}
+
+func Test_ValidateBackupConfig_WithNegativeArchiveAfterDays_ReturnsError(t *testing.T) {
+	backupConfig := createTestArchivingBackupConfig()
+	backupConfig.ArchiveStorageID = nil
+	backupConfig.ArchiveAfterDays = -1
+
+	assert.Error(t, backupConfig.Validate())
+}
+
+func Test_ValidateBackupConfig_WithDaysWithoutArchiveStorage_ArchivingNotEnabled(t *testing.T) {
+	backupConfig := createTestArchivingBackupConfig()
+	backupConfig.ArchiveStorageID = nil
+
+	assert.NoError(t, backupConfig.Validate())
+	assert.False(t, backupConfig.IsArchivingEnabled())
+}
+
+func createTestBackupConfigWithSchedule() (*BackupConfig, *BackupSchedule) {
+	configStorageID := uuid.New()
+	archiveStorageID := uuid.New()
+	backupConfig := &BackupConfig{
+		DatabaseID:          uuid.New(),
+		IsBackupsEnabled:    false,
+		StorePeriod:         period.PeriodWeek,
+		StorageID:           &configStorageID,
+		SecondaryStorages:   []storages.Storage{{ID: uuid.New()}},
+		ArchiveStorageID:    &archiveStorageID,
+		ArchiveAfterDays:    7,
+		IsRetryIfFailed:     true,
+		MaxFailedTriesCount: 3,
+		Encryption:          BackupEncryptionEncrypted,
+		BackupMethod:        BackupMethodPhysical,
+		BackupMode:          BackupModeFull,
+		RetentionPolicyType: RetentionPolicyTypeGfs,
+		GfsDailyCount:       7,
+		KeepMinBackupsCount: 1,
+		SendNotificationsOn: []BackupNotificationType{NotificationBackupSuccess},
+	}
+
+	schedule := &BackupSchedule{
+		ID:                  uuid.New(),
+		DatabaseID:          backupConfig.DatabaseID,
+		Name:                "Monthly",
+		IsEnabled:           true,
+		BackupMode:          BackupModeFull,
+		BackupInterval:      &intervals.Interval{Interval: intervals.IntervalMonthly},
+		StorePeriod:         period.PeriodYear,
+		KeepMinBackupsCount: 2,
+		StorageID:           uuid.New(),
+		SendNotificationsOn: []BackupNotificationType{NotificationBackupFailed},
+	}
+
+	return backupConfig, schedule
+}
+
+func createTestSchemaOnlyBackupConfig() *BackupConfig {
+	return &BackupConfig{
+		BackupInterval: &intervals.Interval{Interval: intervals.IntervalDaily},
+		StorePeriod:    period.PeriodWeek,
+		BackupMode:     BackupModeSchemaOnly,
+	}
+}
+
+func createTestArchivingBackupConfig() *BackupConfig {
+	archiveStorageID := uuid.New()
+
+	return &BackupConfig{
+		BackupInterval:   &intervals.Interval{Interval: intervals.IntervalDaily},
+		StorePeriod:      period.PeriodMonth,
+		ArchiveStorageID: &archiveStorageID,
+		ArchiveAfterDays: 7,
+	}
+}
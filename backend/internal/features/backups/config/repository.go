@@ -1,8 +1,10 @@
 package backups_config
 
 import (
+	"databasus-backend/internal/features/intervals"
 	"databasus-backend/internal/storage"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -40,11 +42,19 @@ func (r *BackupConfigRepository) Save(
 
 		// Use Save which handles both create and update based on primary key
 		if err := tx.Save(backupConfig).
-			Omit("BackupInterval", "Storage").
+			Omit("BackupInterval", "Storage", "SecondaryStorages").
 			Error; err != nil {
 			return err
 		}
 
+		if err := tx.
+			Model(backupConfig).
+			Omit("SecondaryStorages.*").
+			Association("SecondaryStorages").
+			Replace(backupConfig.SecondaryStorages); err != nil {
+			return err
+		}
+
 		return nil
 	})
 
@@ -62,6 +72,7 @@ func (r *BackupConfigRepository) FindByDatabaseID(databaseID uuid.UUID) (*Backup
 		GetDb().
 		Preload("BackupInterval").
 		Preload("Storage").
+		Preload("SecondaryStorages").
 		Where("database_id = ?", databaseID).
 		First(&backupConfig).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -74,6 +85,28 @@ func (r *BackupConfigRepository) FindByDatabaseID(databaseID uuid.UUID) (*Backup
 	return &backupConfig, nil
 }
 
+func (r *BackupConfigRepository) FindByDatabaseIDs(
+	databaseIDs []uuid.UUID,
+) ([]*BackupConfig, error) {
+	var backupConfigs []*BackupConfig
+
+	if len(databaseIDs) == 0 {
+		return backupConfigs, nil
+	}
+
+	if err := storage.
+		GetDb().
+		Preload("BackupInterval").
+		Preload("Storage").
+		Preload("SecondaryStorages").
+		Where("database_id IN ?", databaseIDs).
+		Find(&backupConfigs).Error; err != nil {
+		return nil, err
+	}
+
+	return backupConfigs, nil
+}
+
 func (r *BackupConfigRepository) GetWithEnabledBackups() ([]*BackupConfig, error) {
 	var backupConfigs []*BackupConfig
 
@@ -81,6 +114,7 @@ func (r *BackupConfigRepository) GetWithEnabledBackups() ([]*BackupConfig, error
 		GetDb().
 		Preload("BackupInterval").
 		Preload("Storage").
+		Preload("SecondaryStorages").
 		Where("is_backups_enabled = ?", true).
 		Find(&backupConfigs).Error; err != nil {
 		return nil, err
@@ -89,13 +123,67 @@ func (r *BackupConfigRepository) GetWithEnabledBackups() ([]*BackupConfig, error
 	return backupConfigs, nil
 }
 
+// FindWithArchiving returns configs which move old backups to the archive
+// storage, regardless of whether backups are enabled
+func (r *BackupConfigRepository) FindWithArchiving() ([]*BackupConfig, error) {
+	var backupConfigs []*BackupConfig
+
+	if err := storage.
+		GetDb().
+		Preload("BackupInterval").
+		Preload("Storage").
+		Preload("SecondaryStorages").
+		Where("archive_storage_id IS NOT NULL AND archive_after_days > 0").
+		Find(&backupConfigs).Error; err != nil {
+		return nil, err
+	}
+
+	return backupConfigs, nil
+}
+
+// FindDueForBackup returns enabled configs whose next scheduled backup is
+// reached or not computed yet
+func (r *BackupConfigRepository) FindDueForBackup(now time.Time) ([]*BackupConfig, error) {
+	var backupConfigs []*BackupConfig
+
+	if err := storage.
+		GetDb().
+		Preload("BackupInterval").
+		Where("is_backups_enabled = ?", true).
+		Where("next_run_at IS NULL OR next_run_at <= ?", now).
+		Order("next_run_at ASC NULLS FIRST").
+		Find(&backupConfigs).Error; err != nil {
+		return nil, err
+	}
+
+	return backupConfigs, nil
+}
+
+func (r *BackupConfigRepository) UpdateNextRunAt(
+	databaseID uuid.UUID,
+	nextRunAt *time.Time,
+) error {
+	return storage.
+		GetDb().
+		Model(&BackupConfig{}).
+		Where("database_id = ?", databaseID).
+		Update("next_run_at", nextRunAt).
+		Error
+}
+
 func (r *BackupConfigRepository) IsStorageUsing(storageID uuid.UUID) (bool, error) {
 	var count int64
 
 	if err := storage.
 		GetDb().
 		Table("backup_configs").
-		Where("storage_id = ?", storageID).
+		Where(
+			"storage_id = ? OR archive_storage_id = ? OR database_id IN (?) OR database_id IN (?)",
+			storageID,
+			storageID,
+			r.secondaryStorageDatabasesIDsQuery(storageID),
+			r.scheduleStorageDatabasesIDsQuery(storageID),
+		).
 		Count(&count).Error; err != nil {
 		return false, err
 	}
@@ -111,10 +199,161 @@ func (r *BackupConfigRepository) GetDatabasesIDsByStorageID(
 	if err := storage.
 		GetDb().
 		Table("backup_configs").
-		Where("storage_id = ?", storageID).
+		Where(
+			"storage_id = ? OR archive_storage_id = ? OR database_id IN (?) OR database_id IN (?)",
+			storageID,
+			storageID,
+			r.secondaryStorageDatabasesIDsQuery(storageID),
+			r.scheduleStorageDatabasesIDsQuery(storageID),
+		).
 		Pluck("database_id", &databasesIDs).Error; err != nil {
 		return nil, err
 	}
 
 	return databasesIDs, nil
 }
+
+func (r *BackupConfigRepository) secondaryStorageDatabasesIDsQuery(storageID uuid.UUID) *gorm.DB {
+	return storage.
+		GetDb().
+		Table("backup_config_secondary_storages").
+		Select("database_id").
+		Where("storage_id = ?", storageID)
+}
+
+func (r *BackupConfigRepository) scheduleStorageDatabasesIDsQuery(storageID uuid.UUID) *gorm.DB {
+	return storage.
+		GetDb().
+		Table("backup_schedules").
+		Select("database_id").
+		Where("storage_id = ?", storageID)
+}
+
+func (r *BackupConfigRepository) SaveSchedule(schedule *BackupSchedule) (*BackupSchedule, error) {
+	db := storage.GetDb()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if schedule.BackupInterval != nil {
+			if schedule.BackupInterval.ID == uuid.Nil {
+				if err := tx.Create(schedule.BackupInterval).Error; err != nil {
+					return err
+				}
+			} else {
+				if err := tx.Save(schedule.BackupInterval).Error; err != nil {
+					return err
+				}
+			}
+
+			schedule.BackupIntervalID = schedule.BackupInterval.ID
+		}
+
+		if schedule.ID == uuid.Nil {
+			schedule.ID = uuid.New()
+			schedule.CreatedAt = time.Now().UTC()
+
+			return tx.Omit("BackupInterval", "Storage").Create(schedule).Error
+		}
+
+		return tx.Omit("BackupInterval", "Storage").Save(schedule).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+func (r *BackupConfigRepository) FindScheduleByID(id uuid.UUID) (*BackupSchedule, error) {
+	var schedule BackupSchedule
+
+	if err := storage.
+		GetDb().
+		Preload("BackupInterval").
+		Preload("Storage").
+		Where("id = ?", id).
+		First(&schedule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+func (r *BackupConfigRepository) FindSchedulesByDatabaseID(
+	databaseID uuid.UUID,
+) ([]*BackupSchedule, error) {
+	var schedules []*BackupSchedule
+
+	if err := storage.
+		GetDb().
+		Preload("BackupInterval").
+		Preload("Storage").
+		Where("database_id = ?", databaseID).
+		Order("created_at ASC").
+		Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+func (r *BackupConfigRepository) FindEnabledSchedules() ([]*BackupSchedule, error) {
+	var schedules []*BackupSchedule
+
+	if err := storage.
+		GetDb().
+		Preload("BackupInterval").
+		Preload("Storage").
+		Where("is_enabled = ?", true).
+		Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+// FindSchedulesDueForBackup returns enabled schedules whose next scheduled
+// backup is reached or not computed yet
+func (r *BackupConfigRepository) FindSchedulesDueForBackup(
+	now time.Time,
+) ([]*BackupSchedule, error) {
+	var schedules []*BackupSchedule
+
+	if err := storage.
+		GetDb().
+		Preload("BackupInterval").
+		Where("is_enabled = ?", true).
+		Where("next_run_at IS NULL OR next_run_at <= ?", now).
+		Order("next_run_at ASC NULLS FIRST").
+		Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+func (r *BackupConfigRepository) UpdateScheduleNextRunAt(
+	scheduleID uuid.UUID,
+	nextRunAt *time.Time,
+) error {
+	return storage.
+		GetDb().
+		Model(&BackupSchedule{}).
+		Where("id = ?", scheduleID).
+		Update("next_run_at", nextRunAt).
+		Error
+}
+
+func (r *BackupConfigRepository) DeleteSchedule(schedule *BackupSchedule) error {
+	return storage.GetDb().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&BackupSchedule{}, "id = ?", schedule.ID).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&intervals.Interval{}, "id = ?", schedule.BackupIntervalID).Error
+	})
+}
@@ -2,7 +2,12 @@ package backups_config
 
 import (
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
+	audit_logs "databasus-backend/internal/features/audit_logs"
+	backups_pauses "databasus-backend/internal/features/backups/pauses"
 	"databasus-backend/internal/features/databases"
 	"databasus-backend/internal/features/intervals"
 	"databasus-backend/internal/features/notifiers"
@@ -20,6 +25,8 @@ type BackupConfigService struct {
 	storageService         *storages.StorageService
 	notifierService        *notifiers.NotifierService
 	workspaceService       *workspaces_services.WorkspaceService
+	auditLogService        *audit_logs.AuditLogService
+	backupPauseService     *backups_pauses.BackupPauseService
 
 	dbStorageChangeListener BackupConfigStorageChangeListener
 }
@@ -66,17 +73,92 @@ func (s *BackupConfigService) SaveBackupConfigWithAuth(
 		return nil, errors.New("insufficient permissions to modify backup configuration")
 	}
 
+	if backupConfig.IsWalArchivingEnabled && database.Type != databases.DatabaseTypePostgres {
+		return nil, ErrWalArchivingNotSupported
+	}
+
+	if backupConfig.IsBinlogArchivingEnabled &&
+		database.Type != databases.DatabaseTypeMysql &&
+		database.Type != databases.DatabaseTypeMariadb {
+		return nil, ErrBinlogArchivingNotSupported
+	}
+
+	if backupConfig.IsOplogArchivingEnabled && database.Type != databases.DatabaseTypeMongodb {
+		return nil, ErrOplogArchivingNotSupported
+	}
+
+	if backupConfig.IsOplogArchivingEnabled && database.Mongodb != nil &&
+		len(database.Mongodb.ExcludeCollections) > 0 {
+		return nil, ErrOplogArchivingWithExcludedCollections
+	}
+
+	if backupConfig.BackupMethod == BackupMethodPhysical &&
+		database.Type != databases.DatabaseTypePostgres {
+		return nil, ErrPhysicalBackupsNotSupported
+	}
+
+	if backupConfig.BackupMethod == BackupMethodServer &&
+		database.Type != databases.DatabaseTypePostgres {
+		return nil, ErrServerBackupsNotSupported
+	}
+
+	if backupConfig.BackupMode == BackupModeSchemaOnly &&
+		database.Type == databases.DatabaseTypeMongodb {
+		return nil, ErrSchemaOnlyBackupsNotSupported
+	}
+
+	var primaryStorage *storages.Storage
 	if backupConfig.Storage != nil && backupConfig.Storage.ID != uuid.Nil {
-		storage, err := s.storageService.GetStorageByID(backupConfig.Storage.ID)
+		primaryStorage, err = s.storageService.GetStorageByID(backupConfig.Storage.ID)
 		if err != nil {
 			return nil, err
 		}
-		if storage.WorkspaceID != *database.WorkspaceID {
+		if primaryStorage.WorkspaceID != *database.WorkspaceID {
 			return nil, errors.New("storage does not belong to the same workspace as the database")
 		}
 	}
 
-	return s.SaveBackupConfig(backupConfig)
+	if err := s.validateSecondaryStorages(backupConfig, *database.WorkspaceID); err != nil {
+		return nil, err
+	}
+
+	if err := validateLockStoragesRetention(backupConfig, primaryStorage); err != nil {
+		return nil, err
+	}
+
+	if err := s.validateArchiveStorage(backupConfig, *database.WorkspaceID); err != nil {
+		return nil, err
+	}
+
+	existingConfig, err := s.GetBackupConfigByDbId(backupConfig.DatabaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	savedConfig, err := s.SaveBackupConfig(backupConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	previousBlackoutWindows := formatBlackoutWindows(nil)
+	if existingConfig != nil {
+		previousBlackoutWindows = formatBlackoutWindows(existingConfig.BlackoutWindows)
+	}
+
+	blackoutWindows := formatBlackoutWindows(savedConfig.BlackoutWindows)
+	if blackoutWindows != previousBlackoutWindows {
+		s.auditLogService.WriteAuditLog(
+			fmt.Sprintf(
+				"Backup blackout windows of database %s set to: %s",
+				database.Name,
+				blackoutWindows,
+			),
+			&user.ID,
+			database.WorkspaceID,
+		)
+	}
+
+	return savedConfig, nil
 }
 
 func (s *BackupConfigService) SaveBackupConfig(
@@ -95,16 +177,21 @@ func (s *BackupConfigService) SaveBackupConfig(
 	if existingConfig != nil {
 		// If storage is changing, notify the listener
 		if s.dbStorageChangeListener != nil &&
+			existingConfig.StorageID != nil &&
 			backupConfig.Storage != nil &&
 			!storageIDsEqual(existingConfig.StorageID, &backupConfig.Storage.ID) {
 			if err := s.dbStorageChangeListener.OnBeforeBackupsStorageChange(
 				backupConfig.DatabaseID,
+				*existingConfig.StorageID,
 			); err != nil {
 				return nil, err
 			}
 		}
 	}
 
+	// the scheduler computes the next run again from the saved interval
+	backupConfig.NextRunAt = nil
+
 	return s.backupConfigRepository.Save(backupConfig)
 }
 
@@ -173,6 +260,270 @@ func (s *BackupConfigService) GetBackupConfigsWithEnabledBackups() ([]*BackupCon
 	return s.backupConfigRepository.GetWithEnabledBackups()
 }
 
+// GetBackupConfigsWithArchiving returns configs which move old backups to
+// the archive storage
+func (s *BackupConfigService) GetBackupConfigsWithArchiving() ([]*BackupConfig, error) {
+	return s.backupConfigRepository.FindWithArchiving()
+}
+
+// GetBackupConfigsDueForBackup returns configs and backup schedules (as
+// configs, see BackupSchedule.ToBackupConfig) whose next backup is reached
+func (s *BackupConfigService) GetBackupConfigsDueForBackup(
+	now time.Time,
+) ([]*BackupConfig, error) {
+	dueBackupConfigs, err := s.backupConfigRepository.FindDueForBackup(now)
+	if err != nil {
+		return nil, err
+	}
+
+	dueSchedules, err := s.backupConfigRepository.FindSchedulesDueForBackup(now)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduleBackupConfigs, err := s.toScheduleBackupConfigs(dueSchedules)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(dueBackupConfigs, scheduleBackupConfigs...), nil
+}
+
+// GetScheduleBackupConfigsWithEnabledBackups returns enabled backup schedules
+// as configs, see BackupSchedule.ToBackupConfig
+func (s *BackupConfigService) GetScheduleBackupConfigsWithEnabledBackups() (
+	[]*BackupConfig,
+	error,
+) {
+	schedules, err := s.backupConfigRepository.FindEnabledSchedules()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toScheduleBackupConfigs(schedules)
+}
+
+// GetBackupConfigBySchedule returns the backup config of the database or,
+// if scheduleID is set, the config of the backup schedule
+func (s *BackupConfigService) GetBackupConfigBySchedule(
+	databaseID uuid.UUID,
+	scheduleID *uuid.UUID,
+) (*BackupConfig, error) {
+	backupConfig, err := s.GetBackupConfigByDbId(databaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if scheduleID == nil {
+		return backupConfig, nil
+	}
+
+	schedule, err := s.backupConfigRepository.FindScheduleByID(*scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if schedule == nil || schedule.DatabaseID != databaseID {
+		return nil, ErrBackupScheduleNotFound
+	}
+
+	return schedule.ToBackupConfig(backupConfig), nil
+}
+
+func (s *BackupConfigService) SetNextRunAt(backupConfig *BackupConfig, nextRunAt time.Time) error {
+	if backupConfig.ScheduleID != nil {
+		return s.backupConfigRepository.UpdateScheduleNextRunAt(
+			*backupConfig.ScheduleID,
+			&nextRunAt,
+		)
+	}
+
+	return s.backupConfigRepository.UpdateNextRunAt(backupConfig.DatabaseID, &nextRunAt)
+}
+
+func (s *BackupConfigService) GetSchedulesWithAuth(
+	user *users_models.User,
+	databaseID uuid.UUID,
+) ([]*BackupSchedule, error) {
+	_, err := s.databaseService.GetDatabase(user, databaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.backupConfigRepository.FindSchedulesByDatabaseID(databaseID)
+}
+
+// SaveScheduleWithAuth creates the backup schedule if its ID is not set or
+// updates the existing one. A schedule cannot be moved to another database
+func (s *BackupConfigService) SaveScheduleWithAuth(
+	user *users_models.User,
+	schedule *BackupSchedule,
+) (*BackupSchedule, error) {
+	if err := schedule.Validate(); err != nil {
+		return nil, err
+	}
+
+	if schedule.ID != uuid.Nil {
+		existingSchedule, err := s.backupConfigRepository.FindScheduleByID(schedule.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if existingSchedule == nil {
+			return nil, ErrBackupScheduleNotFound
+		}
+
+		schedule.DatabaseID = existingSchedule.DatabaseID
+		schedule.CreatedAt = existingSchedule.CreatedAt
+		schedule.BackupIntervalID = existingSchedule.BackupIntervalID
+		if schedule.BackupInterval != nil {
+			schedule.BackupInterval.ID = existingSchedule.BackupIntervalID
+		}
+	} else {
+		schedule.BackupIntervalID = uuid.Nil
+		if schedule.BackupInterval != nil {
+			schedule.BackupInterval.ID = uuid.Nil
+		}
+	}
+
+	database, err := s.getManageableDatabase(user, schedule.DatabaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if schedule.BackupMode == BackupModeSchemaOnly &&
+		database.Type == databases.DatabaseTypeMongodb {
+		return nil, ErrSchemaOnlyBackupsNotSupported
+	}
+
+	storage, err := s.storageService.GetStorageByID(schedule.StorageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if storage.WorkspaceID != *database.WorkspaceID {
+		return nil, ErrScheduleStorageNotInWorkspace
+	}
+
+	// backups of schedules are kept for StorePeriod, see ToBackupConfig
+	if storage.IsFileLockEnabled() && schedule.StorePeriod == period.PeriodForever {
+		return nil, ErrLockStorageRequiresRetentionEnd
+	}
+
+	databaseSchedules, err := s.backupConfigRepository.FindSchedulesByDatabaseID(database.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, databaseSchedule := range databaseSchedules {
+		if databaseSchedule.ID != schedule.ID &&
+			strings.EqualFold(databaseSchedule.Name, strings.TrimSpace(schedule.Name)) {
+			return nil, ErrBackupScheduleNameDuplicated
+		}
+	}
+
+	// backup config of the database is created on first access
+	if _, err := s.GetBackupConfigByDbId(database.ID); err != nil {
+		return nil, err
+	}
+
+	schedule.Name = strings.TrimSpace(schedule.Name)
+	schedule.Storage = nil
+	// the scheduler computes the next run again from the saved interval
+	schedule.NextRunAt = nil
+
+	if _, err := s.backupConfigRepository.SaveSchedule(schedule); err != nil {
+		return nil, err
+	}
+
+	return s.backupConfigRepository.FindScheduleByID(schedule.ID)
+}
+
+// DeleteScheduleWithAuth deletes the backup schedule. Backups created by the
+// schedule are kept and are not cleaned up anymore
+func (s *BackupConfigService) DeleteScheduleWithAuth(
+	user *users_models.User,
+	scheduleID uuid.UUID,
+) error {
+	schedule, err := s.backupConfigRepository.FindScheduleByID(scheduleID)
+	if err != nil {
+		return err
+	}
+
+	if schedule == nil {
+		return ErrBackupScheduleNotFound
+	}
+
+	if _, err := s.getManageableDatabase(user, schedule.DatabaseID); err != nil {
+		return err
+	}
+
+	return s.backupConfigRepository.DeleteSchedule(schedule)
+}
+
+// GetUpcomingRunsWithAuth returns the next scheduled backups of the database
+// and of each enabled backup schedule of the database. Runs are empty if
+// scheduled backups are disabled. Runs falling into a blackout window or the
+// active pause of the workspace are marked as postponed
+func (s *BackupConfigService) GetUpcomingRunsWithAuth(
+	user *users_models.User,
+	databaseID uuid.UUID,
+	count int,
+) (*UpcomingRunsResponse, error) {
+	if count < 1 || count > maxUpcomingRunsCount {
+		return nil, fmt.Errorf("count must be between 1 and %d", maxUpcomingRunsCount)
+	}
+
+	database, err := s.databaseService.GetDatabase(user, databaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	backupConfig, err := s.GetBackupConfigByDbId(databaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	pause, err := s.backupPauseService.GetActivePause(database.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	upcomingRuns, err := getUpcomingRuns(backupConfig, pause, count)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := s.backupConfigRepository.FindSchedulesByDatabaseID(databaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduleUpcomingRuns := make([]ScheduleUpcomingRuns, 0, len(schedules))
+	for _, schedule := range schedules {
+		if !schedule.IsEnabled {
+			continue
+		}
+
+		runs, err := getUpcomingRuns(schedule.ToBackupConfig(backupConfig), pause, count)
+		if err != nil {
+			return nil, err
+		}
+
+		scheduleUpcomingRuns = append(scheduleUpcomingRuns, ScheduleUpcomingRuns{
+			ScheduleID:   schedule.ID,
+			ScheduleName: schedule.Name,
+			UpcomingRuns: runs,
+		})
+	}
+
+	return &UpcomingRunsResponse{
+		DatabaseID:           databaseID,
+		UpcomingRuns:         upcomingRuns,
+		ScheduleUpcomingRuns: scheduleUpcomingRuns,
+	}, nil
+}
+
 func (s *BackupConfigService) OnDatabaseCopied(originalDatabaseID, newDatabaseID uuid.UUID) {
 	originalConfig, err := s.GetBackupConfigByDbId(originalDatabaseID)
 	if err != nil {
@@ -185,6 +536,15 @@ func (s *BackupConfigService) OnDatabaseCopied(originalDatabaseID, newDatabaseID
 	if err != nil {
 		return
 	}
+
+	schedules, err := s.backupConfigRepository.FindSchedulesByDatabaseID(originalDatabaseID)
+	if err != nil {
+		return
+	}
+
+	for _, schedule := range schedules {
+		_, _ = s.backupConfigRepository.SaveSchedule(schedule.Copy(newDatabaseID))
+	}
 }
 
 func (s *BackupConfigService) CreateDisabledBackupConfig(databaseID uuid.UUID) error {
@@ -207,10 +567,14 @@ func (s *BackupConfigService) initializeDefaultConfig(
 		SendNotificationsOn: []BackupNotificationType{
 			NotificationBackupFailed,
 			NotificationBackupSuccess,
+			NotificationBackupCleanupSkipped,
+			NotificationBackupVerificationFailed,
+			NotificationBackupReplicationFailed,
 		},
 		IsRetryIfFailed:     true,
 		MaxFailedTriesCount: 3,
 		Encryption:          BackupEncryptionNone,
+		KeepMinBackupsCount: 1,
 	})
 
 	return err
@@ -305,6 +669,18 @@ func (s *BackupConfigService) TransferDatabaseToWorkspace(
 		return ErrTargetStorageNotSpecified
 	}
 
+	// secondary and archive storages stay in the source workspace. Backups
+	// already archived are kept in the archive storage
+	if len(backupConfig.SecondaryStorages) > 0 || backupConfig.ArchiveStorageID != nil {
+		backupConfig.SecondaryStorages = []storages.Storage{}
+		backupConfig.ArchiveStorageID = nil
+		backupConfig.ArchiveAfterDays = 0
+
+		if _, err := s.backupConfigRepository.Save(backupConfig); err != nil {
+			return err
+		}
+	}
+
 	err = s.databaseService.TransferDatabaseToWorkspace(databaseID, request.TargetWorkspaceID)
 	if err != nil {
 		return err
@@ -320,6 +696,196 @@ func (s *BackupConfigService) TransferDatabaseToWorkspace(
 	return nil
 }
 
+func (s *BackupConfigService) getManageableDatabase(
+	user *users_models.User,
+	databaseID uuid.UUID,
+) (*databases.Database, error) {
+	database, err := s.databaseService.GetDatabase(user, databaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if database.WorkspaceID == nil {
+		return nil, ErrDatabaseHasNoWorkspace
+	}
+
+	canManage, err := s.workspaceService.CanUserManageDBs(*database.WorkspaceID, user)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage {
+		return nil, errors.New("insufficient permissions to modify backup configuration")
+	}
+
+	return database, nil
+}
+
+func (s *BackupConfigService) toScheduleBackupConfigs(
+	schedules []*BackupSchedule,
+) ([]*BackupConfig, error) {
+	scheduleBackupConfigs := make([]*BackupConfig, 0, len(schedules))
+	if len(schedules) == 0 {
+		return scheduleBackupConfigs, nil
+	}
+
+	databaseIDs := make([]uuid.UUID, 0, len(schedules))
+	for _, schedule := range schedules {
+		databaseIDs = append(databaseIDs, schedule.DatabaseID)
+	}
+
+	backupConfigs, err := s.backupConfigRepository.FindByDatabaseIDs(databaseIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	backupConfigsByDbID := make(map[uuid.UUID]*BackupConfig, len(backupConfigs))
+	for _, backupConfig := range backupConfigs {
+		backupConfigsByDbID[backupConfig.DatabaseID] = backupConfig
+	}
+
+	for _, schedule := range schedules {
+		backupConfig := backupConfigsByDbID[schedule.DatabaseID]
+		if backupConfig == nil {
+			// the config of the database is not created yet
+			backupConfig, err = s.GetBackupConfigByDbId(schedule.DatabaseID)
+			if err != nil {
+				return nil, err
+			}
+
+			backupConfigsByDbID[schedule.DatabaseID] = backupConfig
+		}
+
+		scheduleBackupConfigs = append(scheduleBackupConfigs, schedule.ToBackupConfig(backupConfig))
+	}
+
+	return scheduleBackupConfigs, nil
+}
+
+func (s *BackupConfigService) validateSecondaryStorages(
+	backupConfig *BackupConfig,
+	workspaceID uuid.UUID,
+) error {
+	primaryStorageID := backupConfig.StorageID
+	if backupConfig.Storage != nil && backupConfig.Storage.ID != uuid.Nil {
+		primaryStorageID = &backupConfig.Storage.ID
+	}
+
+	seenStorageIDs := make(map[uuid.UUID]bool)
+
+	for i, secondaryStorage := range backupConfig.SecondaryStorages {
+		if primaryStorageID != nil && secondaryStorage.ID == *primaryStorageID {
+			return ErrSecondaryStorageIsPrimary
+		}
+
+		if seenStorageIDs[secondaryStorage.ID] {
+			return ErrSecondaryStorageDuplicated
+		}
+		seenStorageIDs[secondaryStorage.ID] = true
+
+		storage, err := s.storageService.GetStorageByID(secondaryStorage.ID)
+		if err != nil {
+			return err
+		}
+
+		if storage.WorkspaceID != workspaceID {
+			return ErrSecondaryStorageNotInWorkspace
+		}
+
+		// local storages keep files in the same folder, the copy would be
+		// the primary backup file itself
+		if storage.Type == storages.StorageTypeLocal && primaryStorageID != nil {
+			primaryStorage, err := s.storageService.GetStorageByID(*primaryStorageID)
+			if err != nil {
+				return err
+			}
+
+			if primaryStorage.Type == storages.StorageTypeLocal {
+				return ErrSecondaryStorageIsLocal
+			}
+		}
+
+		backupConfig.SecondaryStorages[i] = *storage
+	}
+
+	return nil
+}
+
+// validateLockStoragesRetention rejects retention without a known end for
+// storages with object lock: files are locked until retention deletes them,
+// so without the end they would be saved unlocked
+func validateLockStoragesRetention(
+	backupConfig *BackupConfig,
+	primaryStorage *storages.Storage,
+) error {
+	if backupConfig.HasRetentionEnd() {
+		return nil
+	}
+
+	if primaryStorage != nil && primaryStorage.IsFileLockEnabled() {
+		return ErrLockStorageRequiresRetentionEnd
+	}
+
+	for _, secondaryStorage := range backupConfig.SecondaryStorages {
+		if secondaryStorage.IsFileLockEnabled() {
+			return ErrLockStorageRequiresRetentionEnd
+		}
+	}
+
+	return nil
+}
+
+func (s *BackupConfigService) validateArchiveStorage(
+	backupConfig *BackupConfig,
+	workspaceID uuid.UUID,
+) error {
+	if backupConfig.ArchiveStorageID == nil {
+		return nil
+	}
+
+	archiveStorageID := *backupConfig.ArchiveStorageID
+
+	primaryStorageID := backupConfig.StorageID
+	if backupConfig.Storage != nil && backupConfig.Storage.ID != uuid.Nil {
+		primaryStorageID = &backupConfig.Storage.ID
+	}
+
+	if primaryStorageID != nil && archiveStorageID == *primaryStorageID {
+		return ErrArchiveStorageIsPrimary
+	}
+
+	// copies and archived backups have the same file names, so they cannot
+	// share a storage
+	for _, secondaryStorage := range backupConfig.SecondaryStorages {
+		if secondaryStorage.ID == archiveStorageID {
+			return ErrArchiveStorageIsSecondary
+		}
+	}
+
+	storage, err := s.storageService.GetStorageByID(archiveStorageID)
+	if err != nil {
+		return err
+	}
+
+	if storage.WorkspaceID != workspaceID {
+		return ErrArchiveStorageNotInWorkspace
+	}
+
+	// local storages keep files in the same folder, archiving would delete
+	// the moved file together with the source one
+	if storage.Type == storages.StorageTypeLocal && primaryStorageID != nil {
+		primaryStorage, err := s.storageService.GetStorageByID(*primaryStorageID)
+		if err != nil {
+			return err
+		}
+
+		if primaryStorage.Type == storages.StorageTypeLocal {
+			return ErrArchiveStorageIsLocal
+		}
+	}
+
+	return nil
+}
+
 func (s *BackupConfigService) transferNotifiers(
 	user *users_models.User,
 	database *databases.Database,
@@ -376,3 +942,57 @@ func storageIDsEqual(id1, id2 *uuid.UUID) bool {
 	}
 	return *id1 == *id2
 }
+
+func getUpcomingRuns(
+	backupConfig *BackupConfig,
+	pause *backups_pauses.BackupPause,
+	count int,
+) ([]UpcomingRun, error) {
+	upcomingRuns := []UpcomingRun{}
+
+	if !backupConfig.IsBackupsEnabled || backupConfig.BackupInterval == nil {
+		return upcomingRuns, nil
+	}
+
+	// not computed yet, the scheduler computes it on its next run
+	nextRun := time.Now().UTC()
+	if backupConfig.NextRunAt != nil {
+		nextRun = backupConfig.NextRunAt.UTC()
+	}
+
+	for range count {
+		postponeReason := getRunPostponeReason(backupConfig, pause, nextRun)
+
+		upcomingRuns = append(upcomingRuns, UpcomingRun{
+			RunAt:          nextRun,
+			IsPostponed:    postponeReason != "",
+			PostponeReason: postponeReason,
+		})
+
+		var err error
+		nextRun, err = backupConfig.BackupInterval.NextRunAfter(nextRun)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return upcomingRuns, nil
+}
+
+// getRunPostponeReason returns why the scheduled backup is not started at
+// runAt, the same way the scheduler checks it, empty if it is started
+func getRunPostponeReason(
+	backupConfig *BackupConfig,
+	pause *backups_pauses.BackupPause,
+	runAt time.Time,
+) string {
+	if backupConfig.IsInBlackoutWindow(runAt) {
+		return "blackout window"
+	}
+
+	if pause != nil && pause.IsActive(runAt) {
+		return "backups paused: " + pause.Reason
+	}
+
+	return ""
+}
@@ -11,6 +11,7 @@ import (
 	"databasus-backend/internal/features/databases"
 	"databasus-backend/internal/features/intervals"
 	"databasus-backend/internal/features/storages"
+	s3_storage "databasus-backend/internal/features/storages/models/s3"
 	users_enums "databasus-backend/internal/features/users/enums"
 	users_testing "databasus-backend/internal/features/users/testing"
 	workspaces_controllers "databasus-backend/internal/features/workspaces/controllers"
@@ -227,3 +228,41 @@ func createTestRouterWithStorage() *gin.Engine {
 
 	return router
 }
+
+func Test_ValidateLockStoragesRetention_WithoutRetentionEnd_LockStoragesRejected(t *testing.T) {
+	lockStorage := &storages.Storage{
+		Type: storages.StorageTypeS3,
+		S3Storage: &s3_storage.S3Storage{
+			ObjectLockMode: s3_storage.ObjectLockModeCompliance,
+		},
+	}
+	unlockedStorage := &storages.Storage{
+		Type:      storages.StorageTypeS3,
+		S3Storage: &s3_storage.S3Storage{ObjectLockMode: s3_storage.ObjectLockModeNone},
+	}
+
+	timePeriodConfig := &BackupConfig{
+		RetentionPolicyType: RetentionPolicyTypeTimePeriod,
+		StorePeriod:         period.PeriodMonth,
+	}
+	assert.NoError(t, validateLockStoragesRetention(timePeriodConfig, lockStorage))
+
+	gfsConfig := &BackupConfig{RetentionPolicyType: RetentionPolicyTypeGfs}
+	assert.ErrorIs(
+		t,
+		validateLockStoragesRetention(gfsConfig, lockStorage),
+		ErrLockStorageRequiresRetentionEnd,
+	)
+	assert.NoError(t, validateLockStoragesRetention(gfsConfig, unlockedStorage))
+
+	foreverConfig := &BackupConfig{
+		RetentionPolicyType: RetentionPolicyTypeTimePeriod,
+		StorePeriod:         period.PeriodForever,
+		SecondaryStorages:   []storages.Storage{*lockStorage},
+	}
+	assert.ErrorIs(
+		t,
+		validateLockStoragesRetention(foreverConfig, unlockedStorage),
+		ErrLockStorageRequiresRetentionEnd,
+	)
+}
@@ -0,0 +1,389 @@
+package backups_oplog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"databasus-backend/internal/config"
+	"databasus-backend/internal/features/backups/backups"
+	common "databasus-backend/internal/features/backups/backups/common"
+	backups_config "databasus-backend/internal/features/backups/config"
+	"databasus-backend/internal/features/databases"
+	"databasus-backend/internal/features/storages"
+	"databasus-backend/internal/util/encryption"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// oplogSliceInterval bounds the amount of changes which are not
+	// archived yet
+	oplogSliceInterval = 5 * time.Minute
+	oplogSliceTimeout  = 30 * time.Minute
+	// large slices are split, the rest of entries goes to the next slice
+	maxSliceSizeBytes = 256 * 1024 * 1024
+)
+
+// OplogArchivingBackgroundService periodically reads new entries of
+// local.oplog.rs of each MongoDB database with enabled oplog archiving,
+// uploads them into the storage as slices and removes slices which are
+// not needed to replay any of the remaining oplog mode dumps
+type OplogArchivingBackgroundService struct {
+	oplogService        *OplogService
+	backupService       *backups.BackupService
+	backupConfigService *backups_config.BackupConfigService
+	databaseService     *databases.DatabaseService
+	storageService      *storages.StorageService
+	fieldEncryptor      encryption.FieldEncryptor
+	logger              *slog.Logger
+
+	lastSliceTimes map[uuid.UUID]time.Time
+	// held while oplogs are sliced, so Stop can wait for the running slice
+	sliceMu sync.Mutex
+}
+
+func (s *OplogArchivingBackgroundService) Run() {
+	s.logger.Info("Starting oplog archiving background service")
+
+	for {
+		if config.IsShouldShutdown() {
+			return
+		}
+
+		s.sliceMu.Lock()
+		if err := s.sliceOplogs(); err != nil {
+			s.logger.Error("Failed to slice oplogs", "error", err)
+		}
+		s.sliceMu.Unlock()
+
+		if err := s.cleanOldSlices(); err != nil {
+			s.logger.Error("Failed to clean old oplog slices", "error", err)
+		}
+
+		time.Sleep(1 * time.Minute)
+	}
+}
+
+// Stop waits for the running oplog slice to be cancelled. It is called on
+// shutdown after config.Shutdown, so slicing is not started again
+func (s *OplogArchivingBackgroundService) Stop() {
+	s.sliceMu.Lock()
+	defer s.sliceMu.Unlock()
+}
+
+func (s *OplogArchivingBackgroundService) OnBeforeDatabaseRemove(databaseID uuid.UUID) error {
+	return s.oplogService.DeleteDatabaseSlices(databaseID)
+}
+
+func (s *OplogArchivingBackgroundService) sliceOplogs() error {
+	enabledBackupConfigs, err := s.backupConfigService.GetBackupConfigsWithEnabledBackups()
+	if err != nil {
+		return err
+	}
+
+	for _, backupConfig := range enabledBackupConfigs {
+		if config.IsShouldShutdown() {
+			return nil
+		}
+
+		if !backupConfig.IsOplogArchivingEnabled || backupConfig.StorageID == nil {
+			delete(s.lastSliceTimes, backupConfig.DatabaseID)
+			continue
+		}
+
+		if time.Since(s.lastSliceTimes[backupConfig.DatabaseID]) < oplogSliceInterval {
+			continue
+		}
+
+		s.lastSliceTimes[backupConfig.DatabaseID] = time.Now().UTC()
+
+		if err := s.sliceOplog(backupConfig); err != nil {
+			s.logger.Error(
+				"Failed to slice oplog",
+				"databaseId",
+				backupConfig.DatabaseID,
+				"error",
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+func (s *OplogArchivingBackgroundService) cleanOldSlices() error {
+	enabledBackupConfigs, err := s.backupConfigService.GetBackupConfigsWithEnabledBackups()
+	if err != nil {
+		return err
+	}
+
+	for _, backupConfig := range enabledBackupConfigs {
+		oldestDump, err := s.backupService.GetOldestCompletedBackupByType(
+			backupConfig.DatabaseID,
+			common.BackupTypeOplogDump,
+		)
+		if err != nil {
+			s.logger.Error(
+				"Failed to get oldest oplog mode dump",
+				"databaseId",
+				backupConfig.DatabaseID,
+				"error",
+				err,
+			)
+			continue
+		}
+
+		if oldestDump == nil {
+			if !backupConfig.IsOplogArchivingEnabled {
+				// no dumps left to replay oplog on
+				if err := s.oplogService.DeleteDatabaseSlices(backupConfig.DatabaseID); err != nil {
+					s.logger.Error(
+						"Failed to delete oplog slices",
+						"databaseId",
+						backupConfig.DatabaseID,
+						"error",
+						err,
+					)
+				}
+			}
+
+			continue
+		}
+
+		if err := s.oplogService.DeleteSlicesBeforeBackup(oldestDump); err != nil {
+			s.logger.Error(
+				"Failed to delete old oplog slices",
+				"databaseId",
+				backupConfig.DatabaseID,
+				"error",
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// sliceOplog reads oplog entries written after the last archived slice
+// into a BSON file and uploads it as a new slice
+func (s *OplogArchivingBackgroundService) sliceOplog(
+	backupConfig *backups_config.BackupConfig,
+) error {
+	ctx, cancel := context.WithTimeout(context.Background(), oplogSliceTimeout)
+	defer cancel()
+
+	database, err := s.databaseService.GetDatabaseByID(backupConfig.DatabaseID)
+	if err != nil {
+		return err
+	}
+
+	if database.Type != databases.DatabaseTypeMongodb || database.Mongodb == nil {
+		return errors.New("oplog archiving is supported only for MongoDB databases")
+	}
+
+	storage, err := s.storageService.GetStorageByID(*backupConfig.StorageID)
+	if err != nil {
+		return err
+	}
+
+	password, err := s.fieldEncryptor.Decrypt(database.ID, database.Mongodb.Password)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt database password: %w", err)
+	}
+
+	client, err := mongo.Connect(
+		ctx,
+		options.Client().ApplyURI(database.Mongodb.BuildMongodumpURI(password)),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+	defer func() {
+		_ = client.Disconnect(context.Background())
+	}()
+
+	oplogCollection := client.Database("local").Collection("oplog.rs")
+
+	lastSlice, err := s.oplogService.GetLastSlice(database.ID)
+	if err != nil {
+		return err
+	}
+
+	filter, startTs, err := s.getNewEntriesFilter(ctx, oplogCollection, database.ID, lastSlice)
+	if err != nil {
+		return err
+	}
+
+	sliceFile, err := os.CreateTemp(config.GetEnv().TempFolder, "oplog_*.bson")
+	if err != nil {
+		return fmt.Errorf("failed to create oplog slice file: %w", err)
+	}
+	defer func() {
+		_ = sliceFile.Close()
+		_ = os.Remove(sliceFile.Name())
+	}()
+
+	slice, err := writeOplogEntries(ctx, oplogCollection, filter, sliceFile)
+	if err != nil {
+		return err
+	}
+
+	if slice == nil {
+		return nil
+	}
+
+	if err := sliceFile.Close(); err != nil {
+		return fmt.Errorf("failed to write oplog slice file: %w", err)
+	}
+
+	slice.StartTs = startTs
+
+	return s.oplogService.ArchiveSlice(
+		ctx,
+		backupConfig,
+		storage,
+		slice,
+		sliceFile.Name(),
+	)
+}
+
+// getNewEntriesFilter returns filter of entries to put into the next slice
+// and the encoded timestamp the slice starts after. The first slice starts
+// from the newest entry: earlier changes are covered by the next dump
+func (s *OplogArchivingBackgroundService) getNewEntriesFilter(
+	ctx context.Context,
+	oplogCollection *mongo.Collection,
+	databaseID uuid.UUID,
+	lastSlice *OplogSlice,
+) (bson.D, int64, error) {
+	if lastSlice == nil {
+		newestTs, err := findBoundaryTimestamp(ctx, oplogCollection, -1)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return buildTsFilter("$gte", newestTs), EncodeTimestamp(newestTs) - 1, nil
+	}
+
+	oldestTs, err := findBoundaryTimestamp(ctx, oplogCollection, 1)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if EncodeTimestamp(oldestTs) > lastSlice.EndTs {
+		// entries after the last slice were overwritten, recovery through
+		// this gap is impossible until the next dump is taken
+		s.logger.Error(
+			"Oplog rolled over since the last slice, increase oplog size or slice more often",
+			"databaseId",
+			databaseID,
+			"lastSliceEndTime",
+			lastSlice.EndTime,
+		)
+
+		return buildTsFilter("$gte", oldestTs), EncodeTimestamp(oldestTs) - 1, nil
+	}
+
+	return buildTsFilter("$gt", DecodeTimestamp(lastSlice.EndTs)), lastSlice.EndTs, nil
+}
+
+func buildTsFilter(operator string, ts primitive.Timestamp) bson.D {
+	return bson.D{{Key: "ts", Value: bson.D{{Key: operator, Value: ts}}}}
+}
+
+// findBoundaryTimestamp returns timestamp of the oldest (order 1)
+// or the newest (order -1) oplog entry
+func findBoundaryTimestamp(
+	ctx context.Context,
+	oplogCollection *mongo.Collection,
+	order int,
+) (primitive.Timestamp, error) {
+	var entry struct {
+		Ts primitive.Timestamp `bson:"ts"`
+	}
+
+	err := oplogCollection.FindOne(
+		ctx,
+		bson.D{},
+		options.FindOne().SetSort(bson.D{{Key: "$natural", Value: order}}),
+	).Decode(&entry)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return primitive.Timestamp{}, errors.New("oplog is empty")
+		}
+
+		return primitive.Timestamp{}, fmt.Errorf(
+			"failed to read oplog, oplog mode requires a replica set member and the user must be able to read the local database: %w",
+			err,
+		)
+	}
+
+	return entry.Ts, nil
+}
+
+// writeOplogEntries writes raw BSON entries matching the filter into the
+// file, which gives the same format as mongodump oplog.bson. Returns nil
+// if there are no new entries
+func writeOplogEntries(
+	ctx context.Context,
+	oplogCollection *mongo.Collection,
+	filter bson.D,
+	file *os.File,
+) (*OplogSlice, error) {
+	cursor, err := oplogCollection.Find(
+		ctx,
+		filter,
+		options.Find().SetSort(bson.D{{Key: "$natural", Value: 1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query oplog: %w", err)
+	}
+	defer func() {
+		_ = cursor.Close(context.Background())
+	}()
+
+	slice := &OplogSlice{}
+
+	for cursor.Next(ctx) {
+		if config.IsShouldShutdown() {
+			return nil, errors.New("oplog slicing cancelled due to shutdown")
+		}
+
+		t, i, ok := cursor.Current.Lookup("ts").TimestampOK()
+		if !ok {
+			return nil, errors.New("oplog entry has no timestamp")
+		}
+
+		if _, err := file.Write(cursor.Current); err != nil {
+			return nil, fmt.Errorf("failed to write oplog slice file: %w", err)
+		}
+
+		slice.EndTs = EncodeTimestamp(primitive.Timestamp{T: t, I: i})
+		slice.EndTime = time.Unix(int64(t), 0).UTC()
+		slice.EntriesCount++
+		slice.SizeBytes += int64(len(cursor.Current))
+
+		if slice.SizeBytes >= maxSliceSizeBytes {
+			break
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read oplog: %w", err)
+	}
+
+	if slice.EntriesCount == 0 {
+		return nil, nil
+	}
+
+	return slice, nil
+}
@@ -0,0 +1,50 @@
+package backups_oplog
+
+import (
+	"net/http"
+
+	users_middleware "databasus-backend/internal/features/users/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type OplogController struct {
+	oplogService *OplogService
+}
+
+func (c *OplogController) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/oplog/database/:id/recovery-window", c.GetRecoveryWindow)
+}
+
+// GetRecoveryWindow
+// @Summary Get oplog point-in-time recovery window
+// @Description Get the time range the MongoDB instance can be recovered to using oplog mode dumps and archived oplog slices
+// @Tags oplog
+// @Produce json
+// @Param id path string true "Database ID"
+// @Success 200 {object} RecoveryWindowResponse
+// @Failure 400 {object} map[string]string "Invalid database ID"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Router /oplog/database/{id}/recovery-window [get]
+func (c *OplogController) GetRecoveryWindow(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid database ID"})
+		return
+	}
+
+	response, err := c.oplogService.GetRecoveryWindow(user, id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,164 @@
+package backups_oplog
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"databasus-backend/internal/features/backups/backups"
+	backups_config "databasus-backend/internal/features/backups/config"
+	"databasus-backend/internal/features/databases"
+	"databasus-backend/internal/features/notifiers"
+	"databasus-backend/internal/features/storages"
+	users_enums "databasus-backend/internal/features/users/enums"
+	users_testing "databasus-backend/internal/features/users/testing"
+	workspaces_controllers "databasus-backend/internal/features/workspaces/controllers"
+	workspaces_testing "databasus-backend/internal/features/workspaces/testing"
+	test_utils "databasus-backend/internal/util/testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func createTestRouter() *gin.Engine {
+	return workspaces_testing.CreateTestRouter(
+		workspaces_controllers.GetWorkspaceController(),
+		workspaces_controllers.GetMembershipController(),
+		databases.GetDatabaseController(),
+		backups_config.GetBackupConfigController(),
+		backups.GetBackupController(),
+		GetOplogController(),
+	)
+}
+
+func Test_GetRecoveryWindow_WhenOplogArchivingDisabled_ReturnsEmptyWindow(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+	storage := storages.CreateTestStorage(workspace.ID)
+	notifier := notifiers.CreateTestNotifier(workspace.ID)
+	database := databases.CreateTestDatabase(workspace.ID, storage, notifier)
+
+	defer func() {
+		databases.RemoveTestDatabase(database)
+		time.Sleep(50 * time.Millisecond)
+		notifiers.RemoveTestNotifier(notifier)
+		storages.RemoveTestStorage(storage.ID)
+		workspaces_testing.RemoveTestWorkspace(workspace, router)
+	}()
+
+	backups_config.EnableBackupsForTestDatabase(database.ID, storage)
+
+	var response RecoveryWindowResponse
+	test_utils.MakeGetRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/oplog/database/"+database.ID.String()+"/recovery-window",
+		"Bearer "+owner.Token,
+		http.StatusOK,
+		&response,
+	)
+
+	assert.False(t, response.IsOplogArchivingEnabled)
+	assert.Equal(t, int64(0), response.SlicesCount)
+	assert.Nil(t, response.EarliestRecoveryTime)
+	assert.Nil(t, response.LatestRecoveryTime)
+}
+
+func Test_GetRecoveryWindow_WhenUserIsNotWorkspaceMember_ReturnsBadRequest(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+	storage := storages.CreateTestStorage(workspace.ID)
+	notifier := notifiers.CreateTestNotifier(workspace.ID)
+	database := databases.CreateTestDatabase(workspace.ID, storage, notifier)
+
+	defer func() {
+		databases.RemoveTestDatabase(database)
+		time.Sleep(50 * time.Millisecond)
+		notifiers.RemoveTestNotifier(notifier)
+		storages.RemoveTestStorage(storage.ID)
+		workspaces_testing.RemoveTestWorkspace(workspace, router)
+	}()
+
+	outsider := users_testing.CreateTestUser(users_enums.UserRoleMember)
+
+	test_utils.MakeGetRequest(
+		t,
+		router,
+		"/api/v1/oplog/database/"+database.ID.String()+"/recovery-window",
+		"Bearer "+outsider.Token,
+		http.StatusBadRequest,
+	)
+}
+
+func Test_SelectSlicesForRecovery_WhenTargetTimeSet_StopsAfterSliceContainingTarget(
+	t *testing.T,
+) {
+	dumpStartTime := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	slices := createTestSliceChain(dumpStartTime.Add(-10*time.Minute), 5, 10*time.Minute)
+	targetTime := dumpStartTime.Add(15 * time.Minute)
+
+	selectedSlices, err := selectSlicesForRecovery(slices, dumpStartTime, &targetTime)
+
+	assert.NoError(t, err)
+	assert.Len(t, selectedSlices, 3)
+	assert.Equal(t, slices[0].ID, selectedSlices[0].ID)
+	assert.Equal(t, slices[2].ID, selectedSlices[2].ID)
+}
+
+func Test_SelectSlicesForRecovery_WhenSlicesHaveGap_ReturnsError(t *testing.T) {
+	dumpStartTime := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	slices := createTestSliceChain(dumpStartTime.Add(-10*time.Minute), 3, 10*time.Minute)
+	slices[2].StartTs = slices[1].EndTs + 100
+
+	_, err := selectSlicesForRecovery(slices, dumpStartTime, nil)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "gap")
+}
+
+func Test_SelectSlicesForRecovery_WhenSlicesStartAfterDump_ReturnsError(t *testing.T) {
+	dumpStartTime := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	slices := createTestSliceChain(dumpStartTime.Add(1*time.Hour), 2, 10*time.Minute)
+
+	_, err := selectSlicesForRecovery(slices, dumpStartTime, nil)
+
+	assert.Error(t, err)
+}
+
+func Test_EncodeTimestamp_WhenDecoded_ReturnsSameTimestampAndKeepsOrder(t *testing.T) {
+	earlierTs := primitive.Timestamp{T: 1792324800, I: 7}
+	laterTs := primitive.Timestamp{T: 1792324801, I: 1}
+
+	assert.Equal(t, earlierTs, DecodeTimestamp(EncodeTimestamp(earlierTs)))
+	assert.Less(t, EncodeTimestamp(earlierTs), EncodeTimestamp(laterTs))
+}
+
+// createTestSliceChain creates continuous slices, the first one starting at startTime
+func createTestSliceChain(
+	startTime time.Time,
+	count int,
+	sliceDuration time.Duration,
+) []*OplogSlice {
+	slices := make([]*OplogSlice, 0, count)
+	startTs := EncodeTimestamp(primitive.Timestamp{T: uint32(startTime.Unix()), I: 0})
+
+	for i := range count {
+		endTime := startTime.Add(time.Duration(i+1) * sliceDuration)
+		endTs := EncodeTimestamp(primitive.Timestamp{T: uint32(endTime.Unix()), I: 1})
+
+		slices = append(slices, &OplogSlice{
+			ID:      uuid.New(),
+			StartTs: startTs,
+			EndTs:   endTs,
+			EndTime: endTime,
+		})
+
+		startTs = endTs
+	}
+
+	return slices
+}
@@ -0,0 +1,61 @@
+package backups_oplog
+
+import (
+	"sync"
+	"time"
+
+	"databasus-backend/internal/features/backups/backups"
+	backups_config "databasus-backend/internal/features/backups/config"
+	"databasus-backend/internal/features/databases"
+	encryption_secrets "databasus-backend/internal/features/encryption/secrets"
+	"databasus-backend/internal/features/storages"
+	"databasus-backend/internal/util/encryption"
+	"databasus-backend/internal/util/logger"
+
+	"github.com/google/uuid"
+)
+
+var oplogSliceRepository = &OplogSliceRepository{}
+
+var oplogService = &OplogService{
+	oplogSliceRepository,
+	backups.GetBackupService(),
+	backups_config.GetBackupConfigService(),
+	databases.GetDatabaseService(),
+	storages.GetStorageService(),
+	encryption_secrets.GetSecretKeyService(),
+	encryption.GetFieldEncryptor(),
+	logger.GetLogger(),
+}
+
+var oplogArchivingBackgroundService = &OplogArchivingBackgroundService{
+	oplogService,
+	backups.GetBackupService(),
+	backups_config.GetBackupConfigService(),
+	databases.GetDatabaseService(),
+	storages.GetStorageService(),
+	encryption.GetFieldEncryptor(),
+	logger.GetLogger(),
+	map[uuid.UUID]time.Time{},
+	sync.Mutex{},
+}
+
+var oplogController = &OplogController{
+	oplogService,
+}
+
+func SetupDependencies() {
+	databases.GetDatabaseService().AddDbRemoveListener(oplogArchivingBackgroundService)
+}
+
+func GetOplogService() *OplogService {
+	return oplogService
+}
+
+func GetOplogArchivingBackgroundService() *OplogArchivingBackgroundService {
+	return oplogArchivingBackgroundService
+}
+
+func GetOplogController() *OplogController {
+	return oplogController
+}
@@ -0,0 +1,13 @@
+package backups_oplog
+
+import "time"
+
+type RecoveryWindowResponse struct {
+	IsOplogArchivingEnabled bool  `json:"isOplogArchivingEnabled"`
+	SlicesCount             int64 `json:"slicesCount"`
+
+	// Recovery window bounds, nil until there is at least one completed
+	// oplog mode dump followed by archived oplog slices
+	EarliestRecoveryTime *time.Time `json:"earliestRecoveryTime"`
+	LatestRecoveryTime   *time.Time `json:"latestRecoveryTime"`
+}
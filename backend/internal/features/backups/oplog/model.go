@@ -0,0 +1,47 @@
+package backups_oplog
+
+import (
+	backups_config "databasus-backend/internal/features/backups/config"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OplogSlice is a part of MongoDB oplog (local.oplog.rs entries with
+// StartTs < ts <= EndTs) uploaded into the storage as a BSON file.
+// Timestamps are encoded with EncodeTimestamp
+type OplogSlice struct {
+	ID uuid.UUID `json:"id" gorm:"column:id;type:uuid;primaryKey"`
+
+	DatabaseID uuid.UUID `json:"databaseId" gorm:"column:database_id;type:uuid;not null"`
+	StorageID  uuid.UUID `json:"storageId"  gorm:"column:storage_id;type:uuid;not null"`
+
+	StartTs int64 `json:"startTs" gorm:"column:start_ts;not null"`
+	EndTs   int64 `json:"endTs"   gorm:"column:end_ts;not null"`
+	// EndTime is the time of the last entry of the slice
+	EndTime time.Time `json:"endTime" gorm:"column:end_time;not null"`
+
+	EntriesCount int64 `json:"entriesCount" gorm:"column:entries_count;default:0"`
+	SizeBytes    int64 `json:"sizeBytes"    gorm:"column:size_bytes;default:0"`
+
+	EncryptionSalt *string                         `json:"-"          gorm:"column:encryption_salt"`
+	EncryptionIV   *string                         `json:"-"          gorm:"column:encryption_iv"`
+	Encryption     backups_config.BackupEncryption `json:"encryption" gorm:"column:encryption;type:text;not null;default:'NONE'"`
+
+	CreatedAt time.Time `json:"createdAt" gorm:"column:created_at"`
+}
+
+func (s *OplogSlice) TableName() string {
+	return "oplog_slices"
+}
+
+// EncodeTimestamp packs oplog timestamp into a single ordered number
+// (seconds in the high 32 bits, increment in the low 32 bits)
+func EncodeTimestamp(ts primitive.Timestamp) int64 {
+	return int64(ts.T)<<32 | int64(ts.I)
+}
+
+func DecodeTimestamp(encodedTs int64) primitive.Timestamp {
+	return primitive.Timestamp{T: uint32(encodedTs >> 32), I: uint32(encodedTs)}
+}
@@ -0,0 +1,125 @@
+package backups_oplog
+
+import (
+	"databasus-backend/internal/storage"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type OplogSliceRepository struct{}
+
+// Create inserts the slice keeping its ID, because the ID is used as
+// the file ID in the storage and is known before the upload
+func (r *OplogSliceRepository) Create(slice *OplogSlice) error {
+	if slice.DatabaseID == uuid.Nil || slice.StorageID == uuid.Nil {
+		return errors.New("database ID and storage ID are required")
+	}
+
+	if slice.ID == uuid.Nil {
+		slice.ID = uuid.New()
+	}
+
+	return storage.GetDb().Create(slice).Error
+}
+
+func (r *OplogSliceRepository) FindByDatabaseID(databaseID uuid.UUID) ([]*OplogSlice, error) {
+	var slices []*OplogSlice
+
+	if err := storage.
+		GetDb().
+		Where("database_id = ?", databaseID).
+		Order("end_time ASC, end_ts ASC").
+		Find(&slices).Error; err != nil {
+		return nil, err
+	}
+
+	return slices, nil
+}
+
+func (r *OplogSliceRepository) FindLastByDatabaseID(databaseID uuid.UUID) (*OplogSlice, error) {
+	var slice OplogSlice
+
+	if err := storage.
+		GetDb().
+		Where("database_id = ?", databaseID).
+		Order("end_time DESC, end_ts DESC").
+		First(&slice).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &slice, nil
+}
+
+func (r *OplogSliceRepository) FindSlicesEndedBefore(
+	databaseID uuid.UUID,
+	date time.Time,
+) ([]*OplogSlice, error) {
+	var slices []*OplogSlice
+
+	if err := storage.
+		GetDb().
+		Where("database_id = ? AND end_time < ?", databaseID, date).
+		Order("end_time ASC").
+		Find(&slices).Error; err != nil {
+		return nil, err
+	}
+
+	return slices, nil
+}
+
+func (r *OplogSliceRepository) FindByStorageID(storageID uuid.UUID) ([]*OplogSlice, error) {
+	var slices []*OplogSlice
+
+	if err := storage.
+		GetDb().
+		Where("storage_id = ?", storageID).
+		Order("created_at ASC").
+		Find(&slices).Error; err != nil {
+		return nil, err
+	}
+
+	return slices, nil
+}
+
+// FindExistingIDs returns IDs of the given list which have an oplog slice row
+func (r *OplogSliceRepository) FindExistingIDs(ids []uuid.UUID) ([]uuid.UUID, error) {
+	existingIDs := make([]uuid.UUID, 0)
+	if len(ids) == 0 {
+		return existingIDs, nil
+	}
+
+	if err := storage.
+		GetDb().
+		Model(&OplogSlice{}).
+		Where("id IN ?", ids).
+		Pluck("id", &existingIDs).Error; err != nil {
+		return nil, err
+	}
+
+	return existingIDs, nil
+}
+
+func (r *OplogSliceRepository) CountByDatabaseID(databaseID uuid.UUID) (int64, error) {
+	var count int64
+
+	if err := storage.
+		GetDb().
+		Model(&OplogSlice{}).
+		Where("database_id = ?", databaseID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *OplogSliceRepository) DeleteByID(id uuid.UUID) error {
+	return storage.GetDb().Delete(&OplogSlice{}, "id = ?", id).Error
+}
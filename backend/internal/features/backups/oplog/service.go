@@ -0,0 +1,424 @@
+package backups_oplog
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"databasus-backend/internal/features/backups/backups"
+	common "databasus-backend/internal/features/backups/backups/common"
+	backup_encryption "databasus-backend/internal/features/backups/backups/encryption"
+	backups_config "databasus-backend/internal/features/backups/config"
+	"databasus-backend/internal/features/databases"
+	encryption_secrets "databasus-backend/internal/features/encryption/secrets"
+	"databasus-backend/internal/features/storages"
+	users_models "databasus-backend/internal/features/users/models"
+	util_encryption "databasus-backend/internal/util/encryption"
+
+	"github.com/google/uuid"
+)
+
+// oplog timestamps are taken from the MongoDB server clock while backup
+// times are taken from the Databasus clock, so slices around the backup
+// start are selected with a margin. Replaying oplog entries already present
+// in the dump is safe, because oplog entries are idempotent
+const oplogClockSkewMargin = 5 * time.Minute
+
+type OplogService struct {
+	oplogSliceRepository *OplogSliceRepository
+	backupService        *backups.BackupService
+	backupConfigService  *backups_config.BackupConfigService
+	databaseService      *databases.DatabaseService
+	storageService       *storages.StorageService
+	secretKeyService     *encryption_secrets.SecretKeyService
+	fieldEncryptor       util_encryption.FieldEncryptor
+	logger               *slog.Logger
+}
+
+func (s *OplogService) GetRecoveryWindow(
+	user *users_models.User,
+	databaseID uuid.UUID,
+) (*RecoveryWindowResponse, error) {
+	database, err := s.databaseService.GetDatabase(user, databaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	backupConfig, err := s.backupConfigService.GetBackupConfigByDbId(database.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	slicesCount, err := s.oplogSliceRepository.CountByDatabaseID(database.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &RecoveryWindowResponse{
+		IsOplogArchivingEnabled: backupConfig.IsOplogArchivingEnabled,
+		SlicesCount:             slicesCount,
+	}
+
+	oldestDump, err := s.backupService.GetOldestCompletedBackupByType(
+		database.ID,
+		common.BackupTypeOplogDump,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	lastSlice, err := s.oplogSliceRepository.FindLastByDatabaseID(database.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if oldestDump == nil || lastSlice == nil {
+		return response, nil
+	}
+
+	earliestRecoveryTime := GetDumpConsistencyTime(oldestDump)
+	if lastSlice.EndTime.Before(earliestRecoveryTime) {
+		return response, nil
+	}
+
+	response.EarliestRecoveryTime = &earliestRecoveryTime
+	response.LatestRecoveryTime = &lastSlice.EndTime
+
+	return response, nil
+}
+
+// ArchiveSlice uploads BSON file with oplog entries into the storage
+// (encrypted if backups of the database are encrypted) and records it
+func (s *OplogService) ArchiveSlice(
+	ctx context.Context,
+	backupConfig *backups_config.BackupConfig,
+	storage *storages.Storage,
+	slice *OplogSlice,
+	filePath string,
+) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open oplog slice file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat oplog slice file: %w", err)
+	}
+
+	slice.ID = uuid.New()
+	slice.DatabaseID = backupConfig.DatabaseID
+	slice.StorageID = storage.ID
+	slice.SizeBytes = fileInfo.Size()
+	slice.Encryption = backups_config.BackupEncryptionNone
+	slice.CreatedAt = time.Now().UTC()
+
+	var reader io.Reader = file
+	if backupConfig.Encryption == backups_config.BackupEncryptionEncrypted {
+		encryptedReader, err := s.encryptSlice(slice, file)
+		if err != nil {
+			return err
+		}
+
+		reader = encryptedReader
+	}
+
+	if err := storage.SaveFile(ctx, s.fieldEncryptor, s.logger, slice.ID, reader); err != nil {
+		return fmt.Errorf("failed to upload oplog slice: %w", err)
+	}
+
+	if err := s.oplogSliceRepository.Create(slice); err != nil {
+		_ = storage.DeleteFile(s.fieldEncryptor, slice.ID)
+		return err
+	}
+
+	s.logger.Info(
+		"Oplog slice archived",
+		"databaseId",
+		backupConfig.DatabaseID,
+		"entriesCount",
+		slice.EntriesCount,
+		"endTime",
+		slice.EndTime,
+	)
+
+	return nil
+}
+
+// DownloadSlices downloads (and decrypts) oplog slices required to replay
+// from the oplog mode dump up to the recovery target and concatenates them
+// into a single BSON file. When targetTime is nil, all archived slices after
+// the dump are downloaded
+func (s *OplogService) DownloadSlices(
+	ctx context.Context,
+	backup *backups.Backup,
+	targetTime *time.Time,
+	targetFilePath string,
+) error {
+	allSlices, err := s.oplogSliceRepository.FindByDatabaseID(backup.DatabaseID)
+	if err != nil {
+		return err
+	}
+
+	slices, err := selectSlicesForRecovery(allSlices, backup.CreatedAt, targetTime)
+	if err != nil {
+		return err
+	}
+
+	targetFile, err := os.OpenFile(targetFilePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create oplog file: %w", err)
+	}
+	defer func() {
+		_ = targetFile.Close()
+	}()
+
+	for _, slice := range slices {
+		if err := s.downloadSlice(ctx, slice, targetFile); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteSlicesBeforeBackup removes slices which are not needed to replay
+// any dump starting from the given one
+func (s *OplogService) DeleteSlicesBeforeBackup(backup *backups.Backup) error {
+	slices, err := s.oplogSliceRepository.FindSlicesEndedBefore(
+		backup.DatabaseID,
+		backup.CreatedAt.Add(-oplogClockSkewMargin),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, slice := range slices {
+		if err := s.deleteSlice(slice); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *OplogService) DeleteDatabaseSlices(databaseID uuid.UUID) error {
+	slices, err := s.oplogSliceRepository.FindByDatabaseID(databaseID)
+	if err != nil {
+		return err
+	}
+
+	for _, slice := range slices {
+		if err := s.deleteSlice(slice); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *OplogService) GetLastSlice(databaseID uuid.UUID) (*OplogSlice, error) {
+	return s.oplogSliceRepository.FindLastByDatabaseID(databaseID)
+}
+
+func (s *OplogService) GetSlicesByStorageID(storageID uuid.UUID) ([]*OplogSlice, error) {
+	return s.oplogSliceRepository.FindByStorageID(storageID)
+}
+
+// FilterExistingSliceIDs returns IDs of the given list which belong
+// to oplog slices
+func (s *OplogService) FilterExistingSliceIDs(ids []uuid.UUID) ([]uuid.UUID, error) {
+	return s.oplogSliceRepository.FindExistingIDs(ids)
+}
+
+// GetDumpConsistencyTime returns the time oplog mode dump is consistent
+// at: mongodump --oplog captures changes made until the dump finishes
+func GetDumpConsistencyTime(backup *backups.Backup) time.Time {
+	return backup.CreatedAt.Add(time.Duration(backup.BackupDurationMs) * time.Millisecond)
+}
+
+func (s *OplogService) deleteSlice(slice *OplogSlice) error {
+	storage, err := s.storageService.GetStorageByID(slice.StorageID)
+	if err != nil {
+		return err
+	}
+
+	if err := storage.DeleteFile(s.fieldEncryptor, slice.ID); err != nil {
+		// proceed anyway, the same as for backups: storage
+		// may be unavailable or already cleaned up
+		s.logger.Error("Failed to delete oplog slice", "sliceId", slice.ID, "error", err)
+	}
+
+	return s.oplogSliceRepository.DeleteByID(slice.ID)
+}
+
+func (s *OplogService) downloadSlice(
+	ctx context.Context,
+	slice *OplogSlice,
+	targetFile io.Writer,
+) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("oplog download cancelled: %w", err)
+	}
+
+	storage, err := s.storageService.GetStorageByID(slice.StorageID)
+	if err != nil {
+		return err
+	}
+
+	rawReader, err := storage.GetFile(s.fieldEncryptor, slice.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get oplog slice %s from storage: %w", slice.ID, err)
+	}
+	defer func() {
+		_ = rawReader.Close()
+	}()
+
+	reader, err := s.decryptSlice(slice, rawReader)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(targetFile, reader); err != nil {
+		return fmt.Errorf("failed to download oplog slice %s: %w", slice.ID, err)
+	}
+
+	return nil
+}
+
+func (s *OplogService) encryptSlice(slice *OplogSlice, file io.Reader) (io.Reader, error) {
+	salt, err := backup_encryption.GenerateSalt()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	nonce, err := backup_encryption.GenerateNonce()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	masterKey, err := s.secretKeyService.GetSecretKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get master key: %w", err)
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+
+	encWriter, err := backup_encryption.NewEncryptionWriter(
+		pipeWriter,
+		masterKey,
+		slice.ID,
+		salt,
+		nonce,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create encrypting writer: %w", err)
+	}
+
+	go func() {
+		_, copyErr := io.Copy(encWriter, file)
+		if copyErr == nil {
+			copyErr = encWriter.Close()
+		}
+
+		_ = pipeWriter.CloseWithError(copyErr)
+	}()
+
+	saltBase64 := base64.StdEncoding.EncodeToString(salt)
+	nonceBase64 := base64.StdEncoding.EncodeToString(nonce)
+	slice.EncryptionSalt = &saltBase64
+	slice.EncryptionIV = &nonceBase64
+	slice.Encryption = backups_config.BackupEncryptionEncrypted
+
+	return pipeReader, nil
+}
+
+func (s *OplogService) decryptSlice(slice *OplogSlice, reader io.Reader) (io.Reader, error) {
+	if slice.Encryption != backups_config.BackupEncryptionEncrypted {
+		return reader, nil
+	}
+
+	if slice.EncryptionSalt == nil || slice.EncryptionIV == nil {
+		return nil, errors.New("oplog slice is encrypted but missing encryption metadata")
+	}
+
+	masterKey, err := s.secretKeyService.GetSecretKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get master key for decryption: %w", err)
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(*slice.EncryptionSalt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode encryption salt: %w", err)
+	}
+
+	iv, err := base64.StdEncoding.DecodeString(*slice.EncryptionIV)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode encryption IV: %w", err)
+	}
+
+	return backup_encryption.NewDecryptionReader(reader, masterKey, slice.ID, salt, iv)
+}
+
+// selectSlicesForRecovery returns slices (sorted by end time) to replay
+// from the dump start up to the target time. The first slice must start
+// before the dump and slices must follow each other without gaps
+func selectSlicesForRecovery(
+	allSlices []*OplogSlice,
+	dumpStartTime time.Time,
+	targetTime *time.Time,
+) ([]*OplogSlice, error) {
+	startTime := dumpStartTime.Add(-oplogClockSkewMargin)
+
+	firstIndex := -1
+	for i, slice := range allSlices {
+		if sliceStartTime(slice).After(startTime) {
+			break
+		}
+
+		firstIndex = i
+	}
+
+	if firstIndex == -1 {
+		return nil, errors.New("archived oplog does not cover the backup start")
+	}
+
+	selectedSlices := []*OplogSlice{allSlices[firstIndex]}
+	for _, slice := range allSlices[firstIndex+1:] {
+		if targetTime != nil && selectedSlices[len(selectedSlices)-1].EndTime.After(*targetTime) {
+			break
+		}
+
+		previousSlice := selectedSlices[len(selectedSlices)-1]
+		if slice.StartTs != previousSlice.EndTs {
+			return nil, fmt.Errorf(
+				"archived oplog has a gap after %s (oplog rolled over while archiving was not running)",
+				previousSlice.EndTime.Format(time.RFC3339),
+			)
+		}
+
+		selectedSlices = append(selectedSlices, slice)
+	}
+
+	lastSlice := selectedSlices[len(selectedSlices)-1]
+	if targetTime != nil && lastSlice.EndTime.Before(*targetTime) {
+		return nil, fmt.Errorf(
+			"archived oplog ends at %s, before the recovery target",
+			lastSlice.EndTime.Format(time.RFC3339),
+		)
+	}
+
+	return selectedSlices, nil
+}
+
+func sliceStartTime(slice *OplogSlice) time.Time {
+	return time.Unix(int64(DecodeTimestamp(slice.StartTs).T), 0).UTC()
+}
@@ -0,0 +1,122 @@
+package backups_pauses
+
+import (
+	"net/http"
+
+	users_middleware "databasus-backend/internal/features/users/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type BackupPauseController struct {
+	backupPauseService *BackupPauseService
+}
+
+func (c *BackupPauseController) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/backup-pauses", c.GetPauses)
+	router.POST("/backup-pauses/pause", c.PauseBackups)
+	router.POST("/backup-pauses/resume", c.ResumeBackups)
+}
+
+// GetPauses
+// @Summary Get active backup pauses
+// @Description Get the global pause and, if workspace_id is passed, the pause of the workspace
+// @Tags backup-pauses
+// @Produce json
+// @Param workspace_id query string false "Workspace ID"
+// @Success 200 {array} BackupPause
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /backup-pauses [get]
+func (c *BackupPauseController) GetPauses(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var workspaceID *uuid.UUID
+	if workspaceIDStr := ctx.Query("workspace_id"); workspaceIDStr != "" {
+		id, err := uuid.Parse(workspaceIDStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid workspace_id"})
+			return
+		}
+
+		workspaceID = &id
+	}
+
+	pauses, err := c.backupPauseService.GetPausesWithAuth(user, workspaceID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pauses)
+}
+
+// PauseBackups
+// @Summary Pause scheduled backups
+// @Description Pause scheduled backups and retries of the workspace or, without workspaceId, of all workspaces (admins only). Manual backups still can be made
+// @Tags backup-pauses
+// @Accept json
+// @Produce json
+// @Param request body PauseBackupsRequest true "Pause data"
+// @Success 200 {object} BackupPause
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /backup-pauses/pause [post]
+func (c *BackupPauseController) PauseBackups(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var request PauseBackupsRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pause, err := c.backupPauseService.PauseWithAuth(user, &request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pause)
+}
+
+// ResumeBackups
+// @Summary Resume scheduled backups
+// @Description Resume scheduled backups of the workspace or, without workspaceId, remove the global pause (admins only)
+// @Tags backup-pauses
+// @Accept json
+// @Produce json
+// @Param request body ResumeBackupsRequest true "Resume data"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /backup-pauses/resume [post]
+func (c *BackupPauseController) ResumeBackups(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var request ResumeBackupsRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.backupPauseService.ResumeWithAuth(user, request.WorkspaceID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Backups resumed successfully"})
+}
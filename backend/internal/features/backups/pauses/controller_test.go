@@ -0,0 +1,153 @@
+package backups_pauses
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	users_enums "databasus-backend/internal/features/users/enums"
+	users_testing "databasus-backend/internal/features/users/testing"
+	workspaces_controllers "databasus-backend/internal/features/workspaces/controllers"
+	workspaces_testing "databasus-backend/internal/features/workspaces/testing"
+	test_utils "databasus-backend/internal/util/testing"
+)
+
+func createTestRouter() *gin.Engine {
+	return workspaces_testing.CreateTestRouter(
+		workspaces_controllers.GetWorkspaceController(),
+		workspaces_controllers.GetMembershipController(),
+		GetBackupPauseController(),
+	)
+}
+
+func Test_PauseBackups_WorkspacePausedAndResumed(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+	defer workspaces_testing.RemoveTestWorkspace(workspace, router)
+
+	resumeAt := time.Now().UTC().Add(24 * time.Hour)
+
+	var pause BackupPause
+	test_utils.MakePostRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/backup-pauses/pause",
+		"Bearer "+owner.Token,
+		PauseBackupsRequest{
+			WorkspaceID: &workspace.ID,
+			Reason:      "Migration",
+			ResumeAt:    &resumeAt,
+		},
+		http.StatusOK,
+		&pause,
+	)
+
+	assert.Equal(t, workspace.ID, *pause.WorkspaceID)
+	assert.Equal(t, "Migration", pause.Reason)
+	assert.NotNil(t, pause.ResumeAt)
+
+	activePause, err := GetBackupPauseService().GetActivePause(&workspace.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, activePause)
+
+	var pauses []BackupPause
+	test_utils.MakeGetRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/backup-pauses?workspace_id="+workspace.ID.String(),
+		"Bearer "+owner.Token,
+		http.StatusOK,
+		&pauses,
+	)
+
+	assert.Len(t, pauses, 1)
+	assert.Equal(t, pause.ID, pauses[0].ID)
+
+	test_utils.MakePostRequest(
+		t,
+		router,
+		"/api/v1/backup-pauses/resume",
+		"Bearer "+owner.Token,
+		ResumeBackupsRequest{WorkspaceID: &workspace.ID},
+		http.StatusOK,
+	)
+
+	activePause, err = GetBackupPauseService().GetActivePause(&workspace.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, activePause)
+}
+
+func Test_PauseBackups_WhenUserIsWorkspaceViewer_ReturnsBadRequest(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+	defer workspaces_testing.RemoveTestWorkspace(workspace, router)
+
+	viewer := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspaces_testing.AddMemberToWorkspace(
+		workspace,
+		viewer,
+		users_enums.WorkspaceRoleViewer,
+		owner.Token,
+		router,
+	)
+
+	testResp := test_utils.MakePostRequest(
+		t,
+		router,
+		"/api/v1/backup-pauses/pause",
+		"Bearer "+viewer.Token,
+		PauseBackupsRequest{WorkspaceID: &workspace.ID, Reason: "Migration"},
+		http.StatusBadRequest,
+	)
+
+	assert.Contains(t, string(testResp.Body), "insufficient permissions")
+}
+
+func Test_PauseBackups_WhenGlobalPauseByMember_ReturnsBadRequest(t *testing.T) {
+	router := createTestRouter()
+	member := users_testing.CreateTestUser(users_enums.UserRoleMember)
+
+	testResp := test_utils.MakePostRequest(
+		t,
+		router,
+		"/api/v1/backup-pauses/pause",
+		"Bearer "+member.Token,
+		PauseBackupsRequest{Reason: "Migration"},
+		http.StatusBadRequest,
+	)
+
+	assert.Contains(t, string(testResp.Body), "only admins")
+}
+
+func Test_PauseBackups_WhenResumeTimeInPast_ReturnsBadRequest(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+	defer workspaces_testing.RemoveTestWorkspace(workspace, router)
+
+	resumeAt := time.Now().UTC().Add(-time.Hour)
+
+	testResp := test_utils.MakePostRequest(
+		t,
+		router,
+		"/api/v1/backup-pauses/pause",
+		"Bearer "+owner.Token,
+		PauseBackupsRequest{
+			WorkspaceID: &workspace.ID,
+			Reason:      "Migration",
+			ResumeAt:    &resumeAt,
+		},
+		http.StatusBadRequest,
+	)
+
+	assert.Contains(t, string(testResp.Body), "resume time must be in the future")
+
+	activePause, err := GetBackupPauseService().GetActivePause(&workspace.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, activePause)
+}
@@ -0,0 +1,26 @@
+package backups_pauses
+
+import (
+	audit_logs "databasus-backend/internal/features/audit_logs"
+	workspaces_services "databasus-backend/internal/features/workspaces/services"
+	"databasus-backend/internal/util/logger"
+)
+
+var backupPauseRepository = &BackupPauseRepository{}
+var backupPauseService = &BackupPauseService{
+	backupPauseRepository,
+	workspaces_services.GetWorkspaceService(),
+	audit_logs.GetAuditLogService(),
+	logger.GetLogger(),
+}
+var backupPauseController = &BackupPauseController{
+	backupPauseService,
+}
+
+func GetBackupPauseService() *BackupPauseService {
+	return backupPauseService
+}
+
+func GetBackupPauseController() *BackupPauseController {
+	return backupPauseController
+}
@@ -0,0 +1,20 @@
+package backups_pauses
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PauseBackupsRequest struct {
+	// nil to pause backups of all workspaces (admins only)
+	WorkspaceID *uuid.UUID `json:"workspaceId"`
+	Reason      string     `json:"reason"      binding:"required"`
+	// nil to pause until resumed manually
+	ResumeAt *time.Time `json:"resumeAt"`
+}
+
+type ResumeBackupsRequest struct {
+	// nil to resume the global pause (admins only)
+	WorkspaceID *uuid.UUID `json:"workspaceId"`
+}
@@ -0,0 +1,33 @@
+package backups_pauses
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BackupPause stops scheduled backups and retries of all databases of the
+// workspace or, if WorkspaceID is nil, of all workspaces (e.g. during a
+// migration). Manual backups still can be made. Healthcheck notifications
+// of paused databases are not sent. The pause ends at ResumeAt or when it
+// is resumed manually
+type BackupPause struct {
+	ID              uuid.UUID  `json:"id"              gorm:"column:id;type:uuid;primaryKey"`
+	WorkspaceID     *uuid.UUID `json:"workspaceId"     gorm:"column:workspace_id;type:uuid"`
+	Reason          string     `json:"reason"          gorm:"column:reason;type:text;not null"`
+	ResumeAt        *time.Time `json:"resumeAt"        gorm:"column:resume_at"`
+	CreatedByUserID *uuid.UUID `json:"createdByUserId" gorm:"column:created_by_user_id;type:uuid"`
+	CreatedAt       time.Time  `json:"createdAt"       gorm:"column:created_at"`
+}
+
+func (BackupPause) TableName() string {
+	return "backup_pauses"
+}
+
+func (p *BackupPause) IsGlobal() bool {
+	return p.WorkspaceID == nil
+}
+
+func (p *BackupPause) IsActive(now time.Time) bool {
+	return p.ResumeAt == nil || now.Before(*p.ResumeAt)
+}
@@ -0,0 +1,61 @@
+package backups_pauses
+
+import (
+	"errors"
+
+	"databasus-backend/internal/storage"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BackupPauseRepository struct{}
+
+func (r *BackupPauseRepository) Save(pause *BackupPause) error {
+	if pause.ID == uuid.Nil {
+		pause.ID = uuid.New()
+		return storage.GetDb().Create(pause).Error
+	}
+
+	return storage.GetDb().Save(pause).Error
+}
+
+// FindByWorkspaceID returns the pause of the workspace or the global pause
+// if workspaceID is nil
+func (r *BackupPauseRepository) FindByWorkspaceID(workspaceID *uuid.UUID) (*BackupPause, error) {
+	var pause BackupPause
+
+	query := storage.GetDb()
+	if workspaceID == nil {
+		query = query.Where("workspace_id IS NULL")
+	} else {
+		query = query.Where("workspace_id = ?", *workspaceID)
+	}
+
+	if err := query.First(&pause).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &pause, nil
+}
+
+func (r *BackupPauseRepository) FindAll() ([]*BackupPause, error) {
+	var pauses = make([]*BackupPause, 0)
+
+	if err := storage.
+		GetDb().
+		Order("created_at ASC").
+		Find(&pauses).Error; err != nil {
+		return nil, err
+	}
+
+	return pauses, nil
+}
+
+func (r *BackupPauseRepository) DeleteByID(id uuid.UUID) error {
+	return storage.GetDb().Delete(&BackupPause{}, "id = ?", id).Error
+}
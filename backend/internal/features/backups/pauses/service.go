@@ -0,0 +1,263 @@
+package backups_pauses
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	audit_logs "databasus-backend/internal/features/audit_logs"
+	users_enums "databasus-backend/internal/features/users/enums"
+	users_models "databasus-backend/internal/features/users/models"
+	workspaces_services "databasus-backend/internal/features/workspaces/services"
+
+	"github.com/google/uuid"
+)
+
+const maxReasonLength = 500
+
+type BackupPauseService struct {
+	backupPauseRepository *BackupPauseRepository
+	workspaceService      *workspaces_services.WorkspaceService
+	auditLogService       *audit_logs.AuditLogService
+	logger                *slog.Logger
+}
+
+// PauseWithAuth pauses backups of the workspace or, if WorkspaceID is not
+// set, of all workspaces. Pausing a paused workspace replaces the reason and
+// the resume time
+func (s *BackupPauseService) PauseWithAuth(
+	user *users_models.User,
+	request *PauseBackupsRequest,
+) (*BackupPause, error) {
+	if err := s.checkCanManagePause(user, request.WorkspaceID); err != nil {
+		return nil, err
+	}
+
+	reason := strings.TrimSpace(request.Reason)
+	if reason == "" {
+		return nil, errors.New("pause reason is required")
+	}
+
+	if len(reason) > maxReasonLength {
+		return nil, fmt.Errorf("pause reason must be at most %d characters", maxReasonLength)
+	}
+
+	now := time.Now().UTC()
+
+	var resumeAt *time.Time
+	if request.ResumeAt != nil {
+		if !request.ResumeAt.After(now) {
+			return nil, errors.New("resume time must be in the future")
+		}
+
+		utcResumeAt := request.ResumeAt.UTC()
+		resumeAt = &utcResumeAt
+	}
+
+	pause, err := s.backupPauseRepository.FindByWorkspaceID(request.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pause == nil {
+		pause = &BackupPause{
+			WorkspaceID: request.WorkspaceID,
+		}
+	}
+
+	pause.Reason = reason
+	pause.ResumeAt = resumeAt
+	pause.CreatedByUserID = &user.ID
+	pause.CreatedAt = now
+
+	if err := s.backupPauseRepository.Save(pause); err != nil {
+		return nil, err
+	}
+
+	resumeMessage := "until resumed manually"
+	if resumeAt != nil {
+		resumeMessage = "until " + resumeAt.Format(time.RFC3339)
+	}
+
+	s.auditLogService.WriteAuditLog(
+		fmt.Sprintf("%s paused %s. Reason: %s", getPauseScope(pause), resumeMessage, reason),
+		&user.ID,
+		pause.WorkspaceID,
+	)
+
+	return pause, nil
+}
+
+// ResumeWithAuth resumes backups of the workspace or, if workspaceID is nil,
+// removes the global pause
+func (s *BackupPauseService) ResumeWithAuth(
+	user *users_models.User,
+	workspaceID *uuid.UUID,
+) error {
+	if err := s.checkCanManagePause(user, workspaceID); err != nil {
+		return err
+	}
+
+	pause, err := s.backupPauseRepository.FindByWorkspaceID(workspaceID)
+	if err != nil {
+		return err
+	}
+
+	if pause == nil || !pause.IsActive(time.Now().UTC()) {
+		return errors.New("backups are not paused")
+	}
+
+	if err := s.backupPauseRepository.DeleteByID(pause.ID); err != nil {
+		return err
+	}
+
+	s.auditLogService.WriteAuditLog(
+		fmt.Sprintf("%s resumed", getPauseScope(pause)),
+		&user.ID,
+		pause.WorkspaceID,
+	)
+
+	return nil
+}
+
+// GetPausesWithAuth returns active pauses affecting the workspace: its own
+// pause and the global one. Only the global pause is returned if workspaceID
+// is nil
+func (s *BackupPauseService) GetPausesWithAuth(
+	user *users_models.User,
+	workspaceID *uuid.UUID,
+) ([]*BackupPause, error) {
+	if workspaceID != nil {
+		canAccess, _, err := s.workspaceService.CanUserAccessWorkspace(*workspaceID, user)
+		if err != nil {
+			return nil, err
+		}
+		if !canAccess {
+			return nil, errors.New("insufficient permissions to view workspace")
+		}
+	}
+
+	now := time.Now().UTC()
+	pauses := make([]*BackupPause, 0)
+
+	globalPause, err := s.backupPauseRepository.FindByWorkspaceID(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if globalPause != nil && globalPause.IsActive(now) {
+		pauses = append(pauses, globalPause)
+	}
+
+	if workspaceID != nil {
+		workspacePause, err := s.backupPauseRepository.FindByWorkspaceID(workspaceID)
+		if err != nil {
+			return nil, err
+		}
+
+		if workspacePause != nil && workspacePause.IsActive(now) {
+			pauses = append(pauses, workspacePause)
+		}
+	}
+
+	return pauses, nil
+}
+
+// GetActivePause returns the pause stopping scheduled backups of databases
+// of the workspace (its own pause or the global one), nil if not paused
+func (s *BackupPauseService) GetActivePause(workspaceID *uuid.UUID) (*BackupPause, error) {
+	now := time.Now().UTC()
+
+	if workspaceID != nil {
+		workspacePause, err := s.backupPauseRepository.FindByWorkspaceID(workspaceID)
+		if err != nil {
+			return nil, err
+		}
+
+		if workspacePause != nil && workspacePause.IsActive(now) {
+			return workspacePause, nil
+		}
+	}
+
+	globalPause, err := s.backupPauseRepository.FindByWorkspaceID(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if globalPause != nil && globalPause.IsActive(now) {
+		return globalPause, nil
+	}
+
+	return nil, nil
+}
+
+// ResumeExpiredPauses removes pauses whose resume time is reached. Expired
+// pauses already do not stop backups, they are removed to record the resume
+// in the audit log
+func (s *BackupPauseService) ResumeExpiredPauses() error {
+	pauses, err := s.backupPauseRepository.FindAll()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	for _, pause := range pauses {
+		if pause.IsActive(now) {
+			continue
+		}
+
+		if err := s.backupPauseRepository.DeleteByID(pause.ID); err != nil {
+			return err
+		}
+
+		s.logger.Info(
+			"Backups pause expired",
+			"pauseId",
+			pause.ID,
+			"workspaceId",
+			pause.WorkspaceID,
+		)
+
+		s.auditLogService.WriteAuditLog(
+			fmt.Sprintf("%s resumed automatically", getPauseScope(pause)),
+			nil,
+			pause.WorkspaceID,
+		)
+	}
+
+	return nil
+}
+
+func (s *BackupPauseService) checkCanManagePause(
+	user *users_models.User,
+	workspaceID *uuid.UUID,
+) error {
+	if workspaceID == nil {
+		if user.Role != users_enums.UserRoleAdmin {
+			return errors.New("only admins can pause backups of all workspaces")
+		}
+
+		return nil
+	}
+
+	canManage, err := s.workspaceService.CanUserManageWorkspace(*workspaceID, user)
+	if err != nil {
+		return err
+	}
+	if !canManage {
+		return errors.New("insufficient permissions to pause backups of this workspace")
+	}
+
+	return nil
+}
+
+func getPauseScope(pause *BackupPause) string {
+	if pause.IsGlobal() {
+		return "Backups of all workspaces"
+	}
+
+	return "Workspace backups"
+}
@@ -0,0 +1,71 @@
+package backups_reconciliation
+
+import (
+	"log/slog"
+	"time"
+
+	"databasus-backend/internal/config"
+	"databasus-backend/internal/features/storages"
+)
+
+const reconciliationInterval = 24 * time.Hour
+
+type ReconciliationBackgroundService struct {
+	reconciliationService *ReconciliationService
+	storageService        *storages.StorageService
+	logger                *slog.Logger
+}
+
+func (s *ReconciliationBackgroundService) Run() {
+	if err := s.reconciliationService.FailReconciliationsInProgress(); err != nil {
+		s.logger.Error("Failed to fail reconciliations in progress", "error", err)
+	}
+
+	for {
+		if config.IsShouldShutdown() {
+			return
+		}
+
+		s.reconcileDueStorages()
+
+		time.Sleep(1 * time.Hour)
+	}
+}
+
+// reconcileDueStorages reconciles storages one by one, because listing
+// may be heavy for large buckets
+func (s *ReconciliationBackgroundService) reconcileDueStorages() {
+	storages, err := s.storageService.GetAllStorages()
+	if err != nil {
+		s.logger.Error("Failed to get storages", "error", err)
+		return
+	}
+
+	for _, storage := range storages {
+		if config.IsShouldShutdown() {
+			return
+		}
+
+		report, err := s.reconciliationService.GetReconciliationReport(storage.ID)
+		if err != nil {
+			s.logger.Error("Failed to get reconciliation report", "error", err)
+			continue
+		}
+
+		if !isReconciliationDue(report, time.Now().UTC()) {
+			continue
+		}
+
+		if _, err := s.reconciliationService.ReconcileStorage(storage); err != nil {
+			s.logger.Error("Failed to reconcile storage", "storageId", storage.ID, "error", err)
+		}
+	}
+}
+
+func isReconciliationDue(report *ReconciliationReport, now time.Time) bool {
+	if report == nil {
+		return true
+	}
+
+	return !report.StartedAt.Add(reconciliationInterval).After(now)
+}
@@ -0,0 +1,114 @@
+package backups_reconciliation
+
+import (
+	"net/http"
+
+	users_middleware "databasus-backend/internal/features/users/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ReconciliationController struct {
+	reconciliationService *ReconciliationService
+}
+
+func (c *ReconciliationController) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/storages/:id/reconciliation", c.GetReconciliationReport)
+	router.POST("/storages/:id/reconciliation", c.StartReconciliation)
+	router.POST("/storages/:id/reconciliation/purge-orphans", c.PurgeOrphanedFiles)
+}
+
+// GetReconciliationReport
+// @Summary Get storage reconciliation report
+// @Description Get the last comparison of the storage content with backups, backup copies and WAL segments. Returns null if the storage has not been reconciled yet
+// @Tags storages
+// @Produce json
+// @Param id path string true "Storage ID"
+// @Success 200 {object} ReconciliationReport
+// @Failure 400 {object} map[string]string "Invalid storage ID"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Router /storages/{id}/reconciliation [get]
+func (c *ReconciliationController) GetReconciliationReport(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid storage ID"})
+		return
+	}
+
+	report, err := c.reconciliationService.GetReconciliationReportWithAuth(user, id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+// StartReconciliation
+// @Summary Start storage reconciliation
+// @Description Start comparison of the storage content with backups in background. Storages are also reconciled automatically once a day
+// @Tags storages
+// @Param id path string true "Storage ID"
+// @Success 204
+// @Failure 400 {object} map[string]string "Invalid storage ID or reconciliation is in progress"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Router /storages/{id}/reconciliation [post]
+func (c *ReconciliationController) StartReconciliation(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid storage ID"})
+		return
+	}
+
+	if err := c.reconciliationService.StartReconciliationWithAuth(user, id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// PurgeOrphanedFiles
+// @Summary Purge orphaned storage files
+// @Description Delete files reported as orphaned by the last reconciliation. Files referenced by a backup or a WAL segment since then are kept
+// @Tags storages
+// @Produce json
+// @Param id path string true "Storage ID"
+// @Success 200 {object} PurgeOrphanedFilesResponse
+// @Failure 400 {object} map[string]string "Invalid storage ID or no completed reconciliation"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Router /storages/{id}/reconciliation/purge-orphans [post]
+func (c *ReconciliationController) PurgeOrphanedFiles(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid storage ID"})
+		return
+	}
+
+	response, err := c.reconciliationService.PurgeOrphanedFilesWithAuth(user, id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,143 @@
+package backups_reconciliation
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"databasus-backend/internal/config"
+	"databasus-backend/internal/features/storages"
+	storages_common "databasus-backend/internal/features/storages/common"
+	users_enums "databasus-backend/internal/features/users/enums"
+	users_testing "databasus-backend/internal/features/users/testing"
+	workspaces_controllers "databasus-backend/internal/features/workspaces/controllers"
+	workspaces_testing "databasus-backend/internal/features/workspaces/testing"
+	test_utils "databasus-backend/internal/util/testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestRouter() *gin.Engine {
+	return workspaces_testing.CreateTestRouter(
+		workspaces_controllers.GetWorkspaceController(),
+		workspaces_controllers.GetMembershipController(),
+		GetReconciliationController(),
+	)
+}
+
+func Test_ReconcileStorage_WithUnreferencedOldFile_FileReportedAndPurged(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+	storage := storages.CreateTestStorage(workspace.ID)
+
+	defer func() {
+		storages.RemoveTestStorage(storage.ID)
+		workspaces_testing.RemoveTestWorkspace(workspace, router)
+	}()
+
+	orphanedFileID := uuid.New()
+	orphanedFilePath := filepath.Join(config.GetEnv().DataFolder, orphanedFileID.String())
+	assert.NoError(t, os.WriteFile(orphanedFilePath, []byte("orphaned backup"), 0o600))
+	defer func() {
+		_ = os.Remove(orphanedFilePath)
+	}()
+
+	oldTime := time.Now().UTC().Add(-48 * time.Hour)
+	assert.NoError(t, os.Chtimes(orphanedFilePath, oldTime, oldTime))
+
+	_, err := GetReconciliationService().ReconcileStorage(storage)
+	assert.NoError(t, err)
+
+	var report ReconciliationReport
+	test_utils.MakeGetRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/storages/"+storage.ID.String()+"/reconciliation",
+		"Bearer "+owner.Token,
+		http.StatusOK,
+		&report,
+	)
+
+	assert.Equal(t, ReconciliationStatusCompleted, report.Status)
+
+	isOrphanReported := false
+	for _, file := range report.Files {
+		if file.FileID == orphanedFileID {
+			isOrphanReported = true
+			assert.Equal(t, ReconciliationFileKindOrphaned, file.Kind)
+		}
+	}
+	assert.True(t, isOrphanReported)
+
+	var response PurgeOrphanedFilesResponse
+	test_utils.MakePostRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/storages/"+storage.ID.String()+"/reconciliation/purge-orphans",
+		"Bearer "+owner.Token,
+		nil,
+		http.StatusOK,
+		&response,
+	)
+
+	assert.GreaterOrEqual(t, response.DeletedFilesCount, 1)
+
+	_, err = os.Stat(orphanedFilePath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func Test_PurgeOrphanedFiles_WhenStorageNotReconciled_ReturnsBadRequest(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+	storage := storages.CreateTestStorage(workspace.ID)
+
+	defer func() {
+		storages.RemoveTestStorage(storage.ID)
+		workspaces_testing.RemoveTestWorkspace(workspace, router)
+	}()
+
+	test_utils.MakePostRequest(
+		t,
+		router,
+		"/api/v1/storages/"+storage.ID.String()+"/reconciliation/purge-orphans",
+		"Bearer "+owner.Token,
+		nil,
+		http.StatusBadRequest,
+	)
+}
+
+func Test_CompareStorageFiles_WithRecentAndMissingFiles_RecentFilesAreNotOrphaned(t *testing.T) {
+	now := time.Now().UTC()
+	orphanedBefore := now.Add(-orphanGracePeriod)
+
+	expectedListedID := uuid.New()
+	expectedMissingID := uuid.New()
+	oldOrphanID := uuid.New()
+	recentOrphanID := uuid.New()
+
+	listedFiles := []storages_common.StorageFile{
+		{FileID: expectedListedID, ModifiedAt: now.Add(-72 * time.Hour)},
+		{FileID: oldOrphanID, ModifiedAt: now.Add(-72 * time.Hour)},
+		{FileID: recentOrphanID, ModifiedAt: now.Add(-1 * time.Hour)},
+	}
+
+	expectedFiles := []expectedFile{
+		{FileID: expectedListedID, Source: ReconciliationFileSourceBackup},
+		{FileID: expectedMissingID, Source: ReconciliationFileSourceWalSegment},
+	}
+
+	orphanedFiles, missingFiles := compareStorageFiles(listedFiles, expectedFiles, orphanedBefore)
+
+	assert.Len(t, orphanedFiles, 1)
+	assert.Equal(t, oldOrphanID, orphanedFiles[0].FileID)
+
+	assert.Len(t, missingFiles, 1)
+	assert.Equal(t, expectedMissingID, missingFiles[0].FileID)
+	assert.Equal(t, ReconciliationFileSourceWalSegment, missingFiles[0].Source)
+}
@@ -0,0 +1,56 @@
+package backups_reconciliation
+
+import (
+	"sync"
+
+	audit_logs "databasus-backend/internal/features/audit_logs"
+	"databasus-backend/internal/features/backups/backups"
+	backups_binlog "databasus-backend/internal/features/backups/binlog"
+	backups_oplog "databasus-backend/internal/features/backups/oplog"
+	backups_wal "databasus-backend/internal/features/backups/wal"
+	"databasus-backend/internal/features/storages"
+	workspaces_services "databasus-backend/internal/features/workspaces/services"
+	"databasus-backend/internal/util/encryption"
+	"databasus-backend/internal/util/logger"
+
+	"github.com/google/uuid"
+)
+
+var reconciliationRepository = &ReconciliationRepository{}
+
+var reconciliationService = &ReconciliationService{
+	reconciliationRepository,
+	storages.GetStorageService(),
+	backups.GetBackupService(),
+	backups_wal.GetWalService(),
+	backups_binlog.GetBinlogService(),
+	backups_oplog.GetOplogService(),
+	workspaces_services.GetWorkspaceService(),
+	audit_logs.GetAuditLogService(),
+	encryption.GetFieldEncryptor(),
+	logger.GetLogger(),
+	map[uuid.UUID]bool{},
+	sync.Mutex{},
+}
+
+var reconciliationBackgroundService = &ReconciliationBackgroundService{
+	reconciliationService,
+	storages.GetStorageService(),
+	logger.GetLogger(),
+}
+
+var reconciliationController = &ReconciliationController{
+	reconciliationService,
+}
+
+func GetReconciliationService() *ReconciliationService {
+	return reconciliationService
+}
+
+func GetReconciliationBackgroundService() *ReconciliationBackgroundService {
+	return reconciliationBackgroundService
+}
+
+func GetReconciliationController() *ReconciliationController {
+	return reconciliationController
+}
@@ -0,0 +1,9 @@
+package backups_reconciliation
+
+type PurgeOrphanedFilesResponse struct {
+	DeletedFilesCount int   `json:"deletedFilesCount"`
+	DeletedSizeBytes  int64 `json:"deletedSizeBytes"`
+	// files referenced by a backup or a WAL segment since the reconciliation
+	SkippedFilesCount int `json:"skippedFilesCount"`
+	FailedFilesCount  int `json:"failedFilesCount"`
+}
@@ -0,0 +1,29 @@
+package backups_reconciliation
+
+type ReconciliationStatus string
+
+const (
+	ReconciliationStatusInProgress ReconciliationStatus = "IN_PROGRESS"
+	ReconciliationStatusCompleted  ReconciliationStatus = "COMPLETED"
+	ReconciliationStatusFailed     ReconciliationStatus = "FAILED"
+)
+
+type ReconciliationFileKind string
+
+const (
+	// the file is placed in the storage, but nothing references it
+	ReconciliationFileKindOrphaned ReconciliationFileKind = "ORPHANED"
+	// the file is referenced by a backup, a backup copy, a WAL segment,
+	// a binlog file or an oplog slice, but it is not placed in the storage
+	ReconciliationFileKindMissing ReconciliationFileKind = "MISSING"
+)
+
+type ReconciliationFileSource string
+
+const (
+	ReconciliationFileSourceBackup     ReconciliationFileSource = "BACKUP"
+	ReconciliationFileSourceBackupCopy ReconciliationFileSource = "BACKUP_COPY"
+	ReconciliationFileSourceWalSegment ReconciliationFileSource = "WAL_SEGMENT"
+	ReconciliationFileSourceBinlogFile ReconciliationFileSource = "BINLOG_FILE"
+	ReconciliationFileSourceOplogSlice ReconciliationFileSource = "OPLOG_SLICE"
+)
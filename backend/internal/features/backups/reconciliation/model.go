@@ -0,0 +1,53 @@
+package backups_reconciliation
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReconciliationReport is the result of the last comparison of the storage
+// content with backups, backup copies, WAL segments, binlog files and
+// oplog slices referencing it
+type ReconciliationReport struct {
+	StorageID uuid.UUID `json:"storageId" gorm:"column:storage_id;type:uuid;primaryKey"`
+
+	Status      ReconciliationStatus `json:"status"      gorm:"column:status;type:text;not null"`
+	FailMessage *string              `json:"failMessage" gorm:"column:fail_message;type:text"`
+
+	ListedFilesCount   int   `json:"listedFilesCount"   gorm:"column:listed_files_count;type:int;not null;default:0"`
+	OrphanedFilesCount int   `json:"orphanedFilesCount" gorm:"column:orphaned_files_count;type:int;not null;default:0"`
+	OrphanedSizeBytes  int64 `json:"orphanedSizeBytes"  gorm:"column:orphaned_size_bytes;type:bigint;not null;default:0"`
+	MissingFilesCount  int   `json:"missingFilesCount"  gorm:"column:missing_files_count;type:int;not null;default:0"`
+
+	StartedAt   time.Time  `json:"startedAt"   gorm:"column:started_at"`
+	CompletedAt *time.Time `json:"completedAt" gorm:"column:completed_at"`
+
+	Files []ReconciliationFile `json:"files" gorm:"foreignKey:StorageID;references:StorageID"`
+}
+
+func (r *ReconciliationReport) TableName() string {
+	return "storage_reconciliation_reports"
+}
+
+// ReconciliationFile is an orphaned or a missing file found by the last
+// reconciliation of the storage
+type ReconciliationFile struct {
+	ID        uuid.UUID `json:"id"        gorm:"column:id;type:uuid;primaryKey;default:gen_random_uuid()"`
+	StorageID uuid.UUID `json:"storageId" gorm:"column:storage_id;type:uuid;not null"`
+	FileID    uuid.UUID `json:"fileId"    gorm:"column:file_id;type:uuid;not null"`
+
+	Kind ReconciliationFileKind `json:"kind" gorm:"column:kind;type:text;not null"`
+
+	// Source and DatabaseID are set only for missing files
+	Source     *ReconciliationFileSource `json:"source"     gorm:"column:source;type:text"`
+	DatabaseID *uuid.UUID                `json:"databaseId" gorm:"column:database_id;type:uuid"`
+
+	// SizeBytes and ModifiedAt are set only for orphaned files
+	SizeBytes  int64      `json:"sizeBytes"  gorm:"column:size_bytes;type:bigint;not null;default:0"`
+	ModifiedAt *time.Time `json:"modifiedAt" gorm:"column:modified_at"`
+}
+
+func (f *ReconciliationFile) TableName() string {
+	return "storage_reconciliation_files"
+}
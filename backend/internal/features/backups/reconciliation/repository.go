@@ -0,0 +1,75 @@
+package backups_reconciliation
+
+import (
+	"errors"
+
+	"databasus-backend/internal/storage"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ReconciliationRepository struct{}
+
+// SaveReport saves the report and replaces its files
+func (r *ReconciliationRepository) SaveReport(report *ReconciliationReport) error {
+	return storage.GetDb().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Files").Save(report).Error; err != nil {
+			return err
+		}
+
+		if err := tx.
+			Where("storage_id = ?", report.StorageID).
+			Delete(&ReconciliationFile{}).Error; err != nil {
+			return err
+		}
+
+		for i := range report.Files {
+			report.Files[i].ID = uuid.Nil
+			report.Files[i].StorageID = report.StorageID
+
+			if err := tx.Create(&report.Files[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *ReconciliationRepository) FindReportByStorageID(
+	storageID uuid.UUID,
+) (*ReconciliationReport, error) {
+	var report ReconciliationReport
+
+	if err := storage.
+		GetDb().
+		Preload("Files", func(db *gorm.DB) *gorm.DB {
+			return db.Order("kind ASC, file_id ASC")
+		}).
+		Where("storage_id = ?", storageID).
+		First(&report).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+func (r *ReconciliationRepository) FindReportsByStatus(
+	status ReconciliationStatus,
+) ([]*ReconciliationReport, error) {
+	var reports []*ReconciliationReport
+
+	if err := storage.
+		GetDb().
+		Where("status = ?", status).
+		Find(&reports).Error; err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
@@ -0,0 +1,600 @@
+package backups_reconciliation
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	audit_logs "databasus-backend/internal/features/audit_logs"
+	"databasus-backend/internal/features/backups/backups"
+	backups_binlog "databasus-backend/internal/features/backups/binlog"
+	backups_oplog "databasus-backend/internal/features/backups/oplog"
+	backups_wal "databasus-backend/internal/features/backups/wal"
+	"databasus-backend/internal/features/storages"
+	storages_common "databasus-backend/internal/features/storages/common"
+	users_models "databasus-backend/internal/features/users/models"
+	workspaces_services "databasus-backend/internal/features/workspaces/services"
+	"databasus-backend/internal/util/encryption"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// files modified recently are never reported as orphaned: WAL segment,
+	// binlog file and oplog slice rows are created only after the upload
+	orphanGracePeriod = 24 * time.Hour
+	// limits IDs passed to a single IN query
+	referencesCheckBatchSize = 1000
+)
+
+// expectedFile is a file which must be placed in the storage
+type expectedFile struct {
+	FileID     uuid.UUID
+	DatabaseID uuid.UUID
+	Source     ReconciliationFileSource
+}
+
+type ReconciliationService struct {
+	reconciliationRepository *ReconciliationRepository
+	storageService           *storages.StorageService
+	backupService            *backups.BackupService
+	walService               *backups_wal.WalService
+	binlogService            *backups_binlog.BinlogService
+	oplogService             *backups_oplog.OplogService
+	workspaceService         *workspaces_services.WorkspaceService
+	auditLogService          *audit_logs.AuditLogService
+	fieldEncryptor           encryption.FieldEncryptor
+	logger                   *slog.Logger
+
+	runningStorageIDs map[uuid.UUID]bool
+	mu                sync.Mutex
+}
+
+func (s *ReconciliationService) GetReconciliationReportWithAuth(
+	user *users_models.User,
+	storageID uuid.UUID,
+) (*ReconciliationReport, error) {
+	storage, err := s.storageService.GetStorage(user, storageID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.reconciliationRepository.FindReportByStorageID(storage.ID)
+}
+
+// StartReconciliationWithAuth reconciles the storage in background. The
+// result is available via GetReconciliationReportWithAuth
+func (s *ReconciliationService) StartReconciliationWithAuth(
+	user *users_models.User,
+	storageID uuid.UUID,
+) error {
+	if _, err := s.storageService.GetStorage(user, storageID); err != nil {
+		return err
+	}
+
+	// sensitive data is hidden in the storage returned above
+	storage, err := s.storageService.GetStorageByID(storageID)
+	if err != nil {
+		return err
+	}
+
+	if !s.tryLockStorage(storage.ID) {
+		return errors.New("reconciliation of the storage is already in progress")
+	}
+
+	go func() {
+		defer s.unlockStorage(storage.ID)
+
+		if _, err := s.reconcileStorage(storage); err != nil {
+			s.logger.Error("Failed to reconcile storage", "storageId", storage.ID, "error", err)
+		}
+	}()
+
+	return nil
+}
+
+// ReconcileStorage compares files placed in the storage with backups,
+// backup copies, WAL segments, binlog files and oplog slices referencing
+// it and saves the report
+func (s *ReconciliationService) ReconcileStorage(
+	storage *storages.Storage,
+) (*ReconciliationReport, error) {
+	if !s.tryLockStorage(storage.ID) {
+		return nil, errors.New("reconciliation of the storage is already in progress")
+	}
+	defer s.unlockStorage(storage.ID)
+
+	return s.reconcileStorage(storage)
+}
+
+// PurgeOrphanedFilesWithAuth deletes orphaned files found by the last
+// reconciliation. Each file is checked again right before the deletion, so
+// files referenced since the reconciliation are kept
+func (s *ReconciliationService) PurgeOrphanedFilesWithAuth(
+	user *users_models.User,
+	storageID uuid.UUID,
+) (*PurgeOrphanedFilesResponse, error) {
+	storage, err := s.storageService.GetStorageByID(storageID)
+	if err != nil {
+		return nil, err
+	}
+
+	canManage, err := s.workspaceService.CanUserManageDBs(storage.WorkspaceID, user)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage {
+		return nil, storages.ErrInsufficientPermissionsToManageStorage
+	}
+
+	if !s.tryLockStorage(storage.ID) {
+		return nil, errors.New("reconciliation of the storage is in progress")
+	}
+	defer s.unlockStorage(storage.ID)
+
+	report, err := s.reconciliationRepository.FindReportByStorageID(storage.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if report == nil || report.Status != ReconciliationStatusCompleted {
+		return nil, errors.New("storage has no completed reconciliation report")
+	}
+
+	orphanedFileIDs := make([]uuid.UUID, 0)
+	for _, file := range report.Files {
+		if file.Kind == ReconciliationFileKindOrphaned {
+			orphanedFileIDs = append(orphanedFileIDs, file.FileID)
+		}
+	}
+
+	referencedFileIDs, err := s.findReferencedFileIDs(orphanedFileIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PurgeOrphanedFilesResponse{}
+	remainingFiles := make([]ReconciliationFile, 0, len(report.Files))
+
+	for _, file := range report.Files {
+		if file.Kind != ReconciliationFileKindOrphaned {
+			remainingFiles = append(remainingFiles, file)
+			continue
+		}
+
+		if referencedFileIDs[file.FileID] {
+			response.SkippedFilesCount++
+			continue
+		}
+
+		if err := storage.DeleteFile(s.fieldEncryptor, file.FileID); err != nil {
+			s.logger.Error(
+				"Failed to delete orphaned file",
+				"storageId",
+				storage.ID,
+				"fileId",
+				file.FileID,
+				"error",
+				err,
+			)
+
+			response.FailedFilesCount++
+			remainingFiles = append(remainingFiles, file)
+			continue
+		}
+
+		response.DeletedFilesCount++
+		response.DeletedSizeBytes += file.SizeBytes
+	}
+
+	report.Files = remainingFiles
+	report.OrphanedFilesCount = 0
+	report.OrphanedSizeBytes = 0
+	for _, file := range remainingFiles {
+		if file.Kind == ReconciliationFileKindOrphaned {
+			report.OrphanedFilesCount++
+			report.OrphanedSizeBytes += file.SizeBytes
+		}
+	}
+
+	if err := s.reconciliationRepository.SaveReport(report); err != nil {
+		return nil, err
+	}
+
+	s.auditLogService.WriteAuditLog(
+		fmt.Sprintf(
+			"Orphaned files purged from storage: %s (deleted: %d, failed: %d)",
+			storage.Name,
+			response.DeletedFilesCount,
+			response.FailedFilesCount,
+		),
+		&user.ID,
+		&storage.WorkspaceID,
+	)
+
+	return response, nil
+}
+
+func (s *ReconciliationService) GetReconciliationReport(
+	storageID uuid.UUID,
+) (*ReconciliationReport, error) {
+	return s.reconciliationRepository.FindReportByStorageID(storageID)
+}
+
+// FailReconciliationsInProgress marks reconciliations interrupted by
+// application restart as failed
+func (s *ReconciliationService) FailReconciliationsInProgress() error {
+	reports, err := s.reconciliationRepository.FindReportsByStatus(
+		ReconciliationStatusInProgress,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, report := range reports {
+		failMessage := "reconciliation was interrupted by application restart"
+		report.Status = ReconciliationStatusFailed
+		report.FailMessage = &failMessage
+
+		if err := s.reconciliationRepository.SaveReport(report); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *ReconciliationService) reconcileStorage(
+	storage *storages.Storage,
+) (*ReconciliationReport, error) {
+	report := &ReconciliationReport{
+		StorageID: storage.ID,
+		Status:    ReconciliationStatusInProgress,
+		StartedAt: time.Now().UTC(),
+	}
+
+	if err := s.reconciliationRepository.SaveReport(report); err != nil {
+		return nil, err
+	}
+
+	// expected files are loaded before the listing, so files uploaded
+	// during the listing are not reported as missing
+	expectedFiles, err := s.getExpectedFiles(storage.ID)
+	if err != nil {
+		return s.failReport(report, fmt.Errorf("failed to get expected files: %w", err))
+	}
+
+	listedFiles, err := storage.ListFiles(s.fieldEncryptor)
+	if err != nil {
+		return s.failReport(report, fmt.Errorf("failed to list storage files: %w", err))
+	}
+
+	orphanCandidates, missingCandidates := compareStorageFiles(
+		listedFiles,
+		expectedFiles,
+		report.StartedAt.Add(-orphanGracePeriod),
+	)
+
+	orphanedFiles, err := s.filterUnreferencedFiles(orphanCandidates)
+	if err != nil {
+		return s.failReport(report, fmt.Errorf("failed to check orphaned files: %w", err))
+	}
+
+	missingFiles, err := s.filterStillExpectedFiles(missingCandidates)
+	if err != nil {
+		return s.failReport(report, fmt.Errorf("failed to check missing files: %w", err))
+	}
+
+	report.Files = make([]ReconciliationFile, 0, len(orphanedFiles)+len(missingFiles))
+
+	for _, file := range orphanedFiles {
+		modifiedAt := file.ModifiedAt
+
+		report.Files = append(report.Files, ReconciliationFile{
+			FileID:     file.FileID,
+			Kind:       ReconciliationFileKindOrphaned,
+			SizeBytes:  file.SizeBytes,
+			ModifiedAt: &modifiedAt,
+		})
+		report.OrphanedSizeBytes += file.SizeBytes
+	}
+
+	for _, file := range missingFiles {
+		source := file.Source
+		databaseID := file.DatabaseID
+
+		report.Files = append(report.Files, ReconciliationFile{
+			FileID:     file.FileID,
+			Kind:       ReconciliationFileKindMissing,
+			Source:     &source,
+			DatabaseID: &databaseID,
+		})
+
+		if file.Source == ReconciliationFileSourceBackup {
+			if err := s.backupService.MarkBackupFileMissing(
+				file.FileID,
+				"backup file is not found in the storage by reconciliation",
+			); err != nil {
+				s.logger.Error("Failed to mark backup as missing", "backupId", file.FileID, "error", err)
+			}
+		}
+	}
+
+	completedAt := time.Now().UTC()
+	report.Status = ReconciliationStatusCompleted
+	report.ListedFilesCount = len(listedFiles)
+	report.OrphanedFilesCount = len(orphanedFiles)
+	report.MissingFilesCount = len(missingFiles)
+	report.CompletedAt = &completedAt
+
+	if err := s.reconciliationRepository.SaveReport(report); err != nil {
+		return nil, err
+	}
+
+	if len(orphanedFiles) > 0 || len(missingFiles) > 0 {
+		s.logger.Warn(
+			"Storage content does not match backups",
+			"storageId",
+			storage.ID,
+			"orphanedFiles",
+			len(orphanedFiles),
+			"missingFiles",
+			len(missingFiles),
+		)
+	}
+
+	return report, nil
+}
+
+func (s *ReconciliationService) failReport(
+	report *ReconciliationReport,
+	reason error,
+) (*ReconciliationReport, error) {
+	failMessage := reason.Error()
+	completedAt := time.Now().UTC()
+
+	report.Status = ReconciliationStatusFailed
+	report.FailMessage = &failMessage
+	report.CompletedAt = &completedAt
+
+	if err := s.reconciliationRepository.SaveReport(report); err != nil {
+		return nil, err
+	}
+
+	return report, reason
+}
+
+func (s *ReconciliationService) getExpectedFiles(storageID uuid.UUID) ([]expectedFile, error) {
+	expectedFiles := make([]expectedFile, 0)
+
+	completedBackups, err := s.backupService.GetCompletedBackupsByStorageID(storageID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, backup := range completedBackups {
+		expectedFiles = append(expectedFiles, expectedFile{
+			FileID:     backup.ID,
+			DatabaseID: backup.DatabaseID,
+			Source:     ReconciliationFileSourceBackup,
+		})
+	}
+
+	backupCopies, err := s.backupService.GetCompletedBackupCopiesByStorageID(storageID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, backupCopy := range backupCopies {
+		backup, err := s.backupService.GetBackup(backupCopy.BackupID)
+		if err != nil {
+			return nil, err
+		}
+
+		expectedFiles = append(expectedFiles, expectedFile{
+			FileID:     backupCopy.BackupID,
+			DatabaseID: backup.DatabaseID,
+			Source:     ReconciliationFileSourceBackupCopy,
+		})
+	}
+
+	walSegments, err := s.walService.GetSegmentsByStorageID(storageID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, segment := range walSegments {
+		expectedFiles = append(expectedFiles, expectedFile{
+			FileID:     segment.ID,
+			DatabaseID: segment.DatabaseID,
+			Source:     ReconciliationFileSourceWalSegment,
+		})
+	}
+
+	binlogFiles, err := s.binlogService.GetBinlogFilesByStorageID(storageID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, binlogFile := range binlogFiles {
+		expectedFiles = append(expectedFiles, expectedFile{
+			FileID:     binlogFile.ID,
+			DatabaseID: binlogFile.DatabaseID,
+			Source:     ReconciliationFileSourceBinlogFile,
+		})
+	}
+
+	oplogSlices, err := s.oplogService.GetSlicesByStorageID(storageID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, slice := range oplogSlices {
+		expectedFiles = append(expectedFiles, expectedFile{
+			FileID:     slice.ID,
+			DatabaseID: slice.DatabaseID,
+			Source:     ReconciliationFileSourceOplogSlice,
+		})
+	}
+
+	return expectedFiles, nil
+}
+
+// filterUnreferencedFiles drops files referenced by any backup, WAL
+// segment, binlog file or oplog slice. Several storages may share the same location (e.g. local
+// storages), so references of other storages are respected as well
+func (s *ReconciliationService) filterUnreferencedFiles(
+	files []storages_common.StorageFile,
+) ([]storages_common.StorageFile, error) {
+	fileIDs := make([]uuid.UUID, 0, len(files))
+	for _, file := range files {
+		fileIDs = append(fileIDs, file.FileID)
+	}
+
+	referencedFileIDs, err := s.findReferencedFileIDs(fileIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	unreferencedFiles := make([]storages_common.StorageFile, 0)
+	for _, file := range files {
+		if !referencedFileIDs[file.FileID] {
+			unreferencedFiles = append(unreferencedFiles, file)
+		}
+	}
+
+	return unreferencedFiles, nil
+}
+
+// filterStillExpectedFiles drops files which rows have been deleted
+// during the reconciliation (e.g. by the retention cleanup)
+func (s *ReconciliationService) filterStillExpectedFiles(
+	files []expectedFile,
+) ([]expectedFile, error) {
+	fileIDs := make([]uuid.UUID, 0, len(files))
+	for _, file := range files {
+		fileIDs = append(fileIDs, file.FileID)
+	}
+
+	referencedFileIDs, err := s.findReferencedFileIDs(fileIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	stillExpectedFiles := make([]expectedFile, 0)
+	for _, file := range files {
+		if referencedFileIDs[file.FileID] {
+			stillExpectedFiles = append(stillExpectedFiles, file)
+		}
+	}
+
+	return stillExpectedFiles, nil
+}
+
+func (s *ReconciliationService) findReferencedFileIDs(
+	fileIDs []uuid.UUID,
+) (map[uuid.UUID]bool, error) {
+	referencedFileIDs := make(map[uuid.UUID]bool)
+
+	for start := 0; start < len(fileIDs); start += referencesCheckBatchSize {
+		end := min(start+referencesCheckBatchSize, len(fileIDs))
+		batch := fileIDs[start:end]
+
+		backupIDs, err := s.backupService.FilterExistingBackupIDs(batch)
+		if err != nil {
+			return nil, err
+		}
+
+		segmentIDs, err := s.walService.FilterExistingSegmentIDs(batch)
+		if err != nil {
+			return nil, err
+		}
+
+		binlogFileIDs, err := s.binlogService.FilterExistingBinlogFileIDs(batch)
+		if err != nil {
+			return nil, err
+		}
+
+		sliceIDs, err := s.oplogService.FilterExistingSliceIDs(batch)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range backupIDs {
+			referencedFileIDs[id] = true
+		}
+
+		for _, id := range binlogFileIDs {
+			referencedFileIDs[id] = true
+		}
+
+		for _, id := range sliceIDs {
+			referencedFileIDs[id] = true
+		}
+
+		for _, id := range segmentIDs {
+			referencedFileIDs[id] = true
+		}
+	}
+
+	return referencedFileIDs, nil
+}
+
+func (s *ReconciliationService) tryLockStorage(storageID uuid.UUID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.runningStorageIDs[storageID] {
+		return false
+	}
+
+	s.runningStorageIDs[storageID] = true
+
+	return true
+}
+
+func (s *ReconciliationService) unlockStorage(storageID uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.runningStorageIDs, storageID)
+}
+
+// compareStorageFiles returns listed files which are not expected
+// (modified before orphanedBefore only) and expected files which are
+// not listed
+func compareStorageFiles(
+	listedFiles []storages_common.StorageFile,
+	expectedFiles []expectedFile,
+	orphanedBefore time.Time,
+) ([]storages_common.StorageFile, []expectedFile) {
+	listedFileIDs := make(map[uuid.UUID]bool, len(listedFiles))
+	for _, file := range listedFiles {
+		listedFileIDs[file.FileID] = true
+	}
+
+	expectedFileIDs := make(map[uuid.UUID]bool, len(expectedFiles))
+	for _, file := range expectedFiles {
+		expectedFileIDs[file.FileID] = true
+	}
+
+	orphanedFiles := make([]storages_common.StorageFile, 0)
+	for _, file := range listedFiles {
+		if expectedFileIDs[file.FileID] || !file.ModifiedAt.Before(orphanedBefore) {
+			continue
+		}
+
+		orphanedFiles = append(orphanedFiles, file)
+	}
+
+	missingFiles := make([]expectedFile, 0)
+	for _, file := range expectedFiles {
+		if !listedFileIDs[file.FileID] {
+			missingFiles = append(missingFiles, file)
+		}
+	}
+
+	return orphanedFiles, missingFiles
+}
@@ -0,0 +1,81 @@
+package backups_verification
+
+import (
+	"log/slog"
+	"time"
+
+	"databasus-backend/internal/config"
+)
+
+type VerificationBackgroundService struct {
+	verificationService *VerificationService
+	logger              *slog.Logger
+}
+
+func (s *VerificationBackgroundService) Run() {
+	if err := s.verificationService.FailVerificationsInProgress(); err != nil {
+		s.logger.Error("Failed to fail verifications in progress", "error", err)
+	}
+
+	for {
+		if config.IsDraining() {
+			return
+		}
+
+		s.verifyDueBackups()
+
+		time.Sleep(1 * time.Minute)
+	}
+}
+
+// verifyDueBackups verifies backups one by one, because each verification
+// is a full restore and sandboxes may be shared between databases
+func (s *VerificationBackgroundService) verifyDueBackups() {
+	verificationConfigs, err := s.verificationService.GetEnabledVerificationConfigs()
+	if err != nil {
+		s.logger.Error("Failed to get enabled verification configs", "error", err)
+		return
+	}
+
+	for _, verificationConfig := range verificationConfigs {
+		if config.IsDraining() {
+			return
+		}
+
+		lastResult, err := s.verificationService.GetLastVerificationResult(
+			verificationConfig.DatabaseID,
+		)
+		if err != nil {
+			s.logger.Error("Failed to get last verification result", "error", err)
+			continue
+		}
+
+		if !isVerificationDue(verificationConfig, lastResult, time.Now().UTC()) {
+			continue
+		}
+
+		if _, err := s.verificationService.VerifyLatestBackup(verificationConfig); err != nil {
+			s.logger.Error(
+				"Failed to verify backup",
+				"databaseId",
+				verificationConfig.DatabaseID,
+				"error",
+				err,
+			)
+		}
+	}
+}
+
+func isVerificationDue(
+	verificationConfig *VerificationConfig,
+	lastResult *VerificationResult,
+	now time.Time,
+) bool {
+	if lastResult == nil {
+		return true
+	}
+
+	interval := time.Duration(verificationConfig.IntervalHours) * time.Hour
+
+	return !lastResult.CreatedAt.Add(interval).After(now)
+}
@@ -0,0 +1,124 @@
+package backups_verification
+
+import (
+	"net/http"
+
+	users_middleware "databasus-backend/internal/features/users/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type VerificationController struct {
+	verificationService *VerificationService
+}
+
+func (c *VerificationController) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/backup-verification/database/:id/config", c.GetVerificationConfig)
+	router.POST("/backup-verification/config", c.SaveVerificationConfig)
+	router.GET("/backup-verification/database/:id/results", c.GetVerificationResults)
+}
+
+// GetVerificationConfig
+// @Summary Get backup verification config
+// @Description Get the config of periodic restore verification of the database backups
+// @Tags backup-verification
+// @Produce json
+// @Param id path string true "Database ID"
+// @Success 200 {object} VerificationConfig
+// @Failure 400 {object} map[string]string "Invalid database ID"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Router /backup-verification/database/{id}/config [get]
+func (c *VerificationController) GetVerificationConfig(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid database ID"})
+		return
+	}
+
+	config, err := c.verificationService.GetVerificationConfigWithAuth(user, id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, config)
+}
+
+// SaveVerificationConfig
+// @Summary Save backup verification config
+// @Description Create or update periodic restore verification of the database backups. The sandbox database is overwritten on each verification
+// @Tags backup-verification
+// @Accept json
+// @Produce json
+// @Param request body VerificationConfig true "Verification config"
+// @Success 200 {object} VerificationConfig
+// @Failure 400 {object} map[string]string "Invalid input data"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Router /backup-verification/config [post]
+func (c *VerificationController) SaveVerificationConfig(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var request VerificationConfig
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	config, err := c.verificationService.SaveVerificationConfigWithAuth(user, &request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, config)
+}
+
+// GetVerificationResults
+// @Summary Get backup verification results
+// @Description Get the latest restore verification results of the database backups, newest first
+// @Tags backup-verification
+// @Produce json
+// @Param id path string true "Database ID"
+// @Param limit query int false "Number of results" default(100)
+// @Success 200 {array} VerificationResult
+// @Failure 400 {object} map[string]string "Invalid database ID"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Router /backup-verification/database/{id}/results [get]
+func (c *VerificationController) GetVerificationResults(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid database ID"})
+		return
+	}
+
+	var request GetVerificationResultsRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	results, err := c.verificationService.GetVerificationResultsWithAuth(user, id, request.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, results)
+}
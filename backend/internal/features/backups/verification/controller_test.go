@@ -0,0 +1,143 @@
+package backups_verification
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"databasus-backend/internal/features/backups/backups"
+	backups_config "databasus-backend/internal/features/backups/config"
+	"databasus-backend/internal/features/databases"
+	"databasus-backend/internal/features/notifiers"
+	"databasus-backend/internal/features/storages"
+	users_enums "databasus-backend/internal/features/users/enums"
+	users_testing "databasus-backend/internal/features/users/testing"
+	workspaces_controllers "databasus-backend/internal/features/workspaces/controllers"
+	workspaces_testing "databasus-backend/internal/features/workspaces/testing"
+	test_utils "databasus-backend/internal/util/testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestRouter() *gin.Engine {
+	return workspaces_testing.CreateTestRouter(
+		workspaces_controllers.GetWorkspaceController(),
+		workspaces_controllers.GetMembershipController(),
+		databases.GetDatabaseController(),
+		backups_config.GetBackupConfigController(),
+		backups.GetBackupController(),
+		GetVerificationController(),
+	)
+}
+
+func Test_SaveVerificationConfig_WithSandboxDatabase_ConfigSavedWithChecks(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+	storage := storages.CreateTestStorage(workspace.ID)
+	notifier := notifiers.CreateTestNotifier(workspace.ID)
+	database := databases.CreateTestDatabase(workspace.ID, storage, notifier)
+	sandboxDatabase := databases.CreateTestDatabase(workspace.ID, storage, notifier)
+
+	defer func() {
+		databases.RemoveTestDatabase(database)
+		databases.RemoveTestDatabase(sandboxDatabase)
+		time.Sleep(50 * time.Millisecond)
+		notifiers.RemoveTestNotifier(notifier)
+		storages.RemoveTestStorage(storage.ID)
+		workspaces_testing.RemoveTestWorkspace(workspace, router)
+	}()
+
+	request := VerificationConfig{
+		DatabaseID:        database.ID,
+		IsEnabled:         true,
+		SandboxDatabaseID: &sandboxDatabase.ID,
+		IntervalHours:     12,
+		Checks: []VerificationCheck{
+			{Type: VerificationCheckTypeTableExists, Table: "public.users"},
+			{Type: VerificationCheckTypeMinRowCount, Table: "orders", MinRowCount: 10},
+			{Type: VerificationCheckTypeCustomQuery, Query: "SELECT COUNT(*) > 0 FROM users"},
+		},
+	}
+
+	test_utils.MakePostRequest(
+		t,
+		router,
+		"/api/v1/backup-verification/config",
+		"Bearer "+owner.Token,
+		request,
+		http.StatusOK,
+	)
+
+	var config VerificationConfig
+	test_utils.MakeGetRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/backup-verification/database/"+database.ID.String()+"/config",
+		"Bearer "+owner.Token,
+		http.StatusOK,
+		&config,
+	)
+
+	assert.True(t, config.IsEnabled)
+	assert.Equal(t, sandboxDatabase.ID, *config.SandboxDatabaseID)
+	assert.Equal(t, 12, config.IntervalHours)
+	assert.Len(t, config.Checks, 3)
+	assert.Equal(t, VerificationCheckTypeTableExists, config.Checks[0].Type)
+	assert.Equal(t, int64(10), config.Checks[1].MinRowCount)
+	assert.Equal(t, VerificationCheckTypeCustomQuery, config.Checks[2].Type)
+}
+
+func Test_SaveVerificationConfig_WithSandboxHavingEnabledBackups_ReturnsBadRequest(
+	t *testing.T,
+) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+	storage := storages.CreateTestStorage(workspace.ID)
+	notifier := notifiers.CreateTestNotifier(workspace.ID)
+	database := databases.CreateTestDatabase(workspace.ID, storage, notifier)
+	sandboxDatabase := databases.CreateTestDatabase(workspace.ID, storage, notifier)
+
+	defer func() {
+		databases.RemoveTestDatabase(database)
+		databases.RemoveTestDatabase(sandboxDatabase)
+		time.Sleep(50 * time.Millisecond)
+		notifiers.RemoveTestNotifier(notifier)
+		storages.RemoveTestStorage(storage.ID)
+		workspaces_testing.RemoveTestWorkspace(workspace, router)
+	}()
+
+	backups_config.EnableBackupsForTestDatabase(sandboxDatabase.ID, storage)
+
+	request := VerificationConfig{
+		DatabaseID:        database.ID,
+		IsEnabled:         true,
+		SandboxDatabaseID: &sandboxDatabase.ID,
+		IntervalHours:     24,
+	}
+
+	response := test_utils.MakePostRequest(
+		t,
+		router,
+		"/api/v1/backup-verification/config",
+		"Bearer "+owner.Token,
+		request,
+		http.StatusBadRequest,
+	)
+
+	assert.Contains(t, string(response.Body), "sandbox database has enabled backups")
+}
+
+func Test_CheckQueryValue_WithDifferentValues_PassesOnlyTruthyValues(t *testing.T) {
+	assert.NoError(t, checkQueryValue(true))
+	assert.NoError(t, checkQueryValue(int64(5)))
+	assert.NoError(t, checkQueryValue([]byte("1")))
+	assert.NoError(t, checkQueryValue("t"))
+
+	assert.Error(t, checkQueryValue(false))
+	assert.Error(t, checkQueryValue(int64(0)))
+	assert.Error(t, checkQueryValue([]byte("0")))
+	assert.Error(t, checkQueryValue(nil))
+}
@@ -0,0 +1,51 @@
+package backups_verification
+
+import (
+	audit_logs "databasus-backend/internal/features/audit_logs"
+	"databasus-backend/internal/features/backups/backups"
+	backups_config "databasus-backend/internal/features/backups/config"
+	"databasus-backend/internal/features/databases"
+	"databasus-backend/internal/features/restores"
+	workspaces_services "databasus-backend/internal/features/workspaces/services"
+	"databasus-backend/internal/util/encryption"
+	"databasus-backend/internal/util/logger"
+)
+
+var verificationRepository = &VerificationRepository{}
+
+var verificationService = &VerificationService{
+	verificationRepository,
+	backups.GetBackupService(),
+	backups_config.GetBackupConfigService(),
+	databases.GetDatabaseService(),
+	restores.GetRestoreService(),
+	workspaces_services.GetWorkspaceService(),
+	audit_logs.GetAuditLogService(),
+	encryption.GetFieldEncryptor(),
+	logger.GetLogger(),
+}
+
+var verificationBackgroundService = &VerificationBackgroundService{
+	verificationService,
+	logger.GetLogger(),
+}
+
+var verificationController = &VerificationController{
+	verificationService,
+}
+
+func SetupDependencies() {
+	databases.GetDatabaseService().AddDbRemoveListener(verificationService)
+}
+
+func GetVerificationService() *VerificationService {
+	return verificationService
+}
+
+func GetVerificationBackgroundService() *VerificationBackgroundService {
+	return verificationBackgroundService
+}
+
+func GetVerificationController() *VerificationController {
+	return verificationController
+}
@@ -0,0 +1,5 @@
+package backups_verification
+
+type GetVerificationResultsRequest struct {
+	Limit int `form:"limit"`
+}
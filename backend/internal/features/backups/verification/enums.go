@@ -0,0 +1,24 @@
+package backups_verification
+
+type VerificationStatus string
+
+const (
+	VerificationStatusInProgress VerificationStatus = "IN_PROGRESS"
+	VerificationStatusPassed     VerificationStatus = "PASSED"
+	VerificationStatusFailed     VerificationStatus = "FAILED"
+)
+
+type VerificationCheckType string
+
+const (
+	// VerificationCheckTypeTableExists checks the table (collection for
+	// MongoDB) exists in the restored database
+	VerificationCheckTypeTableExists VerificationCheckType = "TABLE_EXISTS"
+	// VerificationCheckTypeMinRowCount checks the table (collection for
+	// MongoDB) has at least MinRowCount rows (documents)
+	VerificationCheckTypeMinRowCount VerificationCheckType = "MIN_ROW_COUNT"
+	// VerificationCheckTypeCustomQuery runs the SQL query which must return
+	// a single value; the check passes when the value is true or non-zero.
+	// Not supported for MongoDB
+	VerificationCheckTypeCustomQuery VerificationCheckType = "CUSTOM_QUERY"
+)
@@ -0,0 +1,139 @@
+package backups_verification
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// VerificationConfig describes how the latest backup of the database is
+// periodically restored into a sandbox database and checked
+type VerificationConfig struct {
+	DatabaseID uuid.UUID `json:"databaseId" gorm:"column:database_id;type:uuid;primaryKey"`
+
+	IsEnabled bool `json:"isEnabled" gorm:"column:is_enabled;type:boolean;not null;default:false"`
+
+	// SandboxDatabaseID is a database of the same workspace and type the
+	// backup is restored into. Its content is overwritten on each run
+	SandboxDatabaseID *uuid.UUID `json:"sandboxDatabaseId" gorm:"column:sandbox_database_id;type:uuid"`
+
+	IntervalHours int `json:"intervalHours" gorm:"column:interval_hours;type:int;not null;default:24"`
+
+	Checks []VerificationCheck `json:"checks" gorm:"foreignKey:DatabaseID;references:DatabaseID"`
+}
+
+func (c *VerificationConfig) TableName() string {
+	return "verification_configs"
+}
+
+func (c *VerificationConfig) BeforeSave(tx *gorm.DB) error {
+	return c.Validate()
+}
+
+func (c *VerificationConfig) Validate() error {
+	if c.IntervalHours <= 0 {
+		return errors.New("interval hours must be greater than 0")
+	}
+
+	if c.IsEnabled && c.SandboxDatabaseID == nil {
+		return errors.New("sandbox database is required")
+	}
+
+	if c.SandboxDatabaseID != nil && *c.SandboxDatabaseID == c.DatabaseID {
+		return errors.New("sandbox database cannot be the verified database itself")
+	}
+
+	for i := range c.Checks {
+		if err := c.Checks[i].Validate(); err != nil {
+			return fmt.Errorf("check #%d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// VerificationCheck is a sanity check executed against the sandbox database
+// after the backup is restored
+type VerificationCheck struct {
+	ID         uuid.UUID `json:"id"         gorm:"column:id;type:uuid;primaryKey;default:gen_random_uuid()"`
+	DatabaseID uuid.UUID `json:"databaseId" gorm:"column:database_id;type:uuid;not null"`
+
+	Type VerificationCheckType `json:"type" gorm:"column:type;type:text;not null"`
+
+	// Table is a table (optionally schema qualified for PostgreSQL)
+	// or a MongoDB collection
+	Table       string `json:"table"       gorm:"column:table_name;type:text;not null;default:''"`
+	MinRowCount int64  `json:"minRowCount" gorm:"column:min_row_count;type:bigint;not null;default:0"`
+	Query       string `json:"query"       gorm:"column:query;type:text;not null;default:''"`
+
+	Position int `json:"position" gorm:"column:position;type:int;not null;default:0"`
+}
+
+func (c *VerificationCheck) TableName() string {
+	return "verification_checks"
+}
+
+func (c *VerificationCheck) Validate() error {
+	switch c.Type {
+	case VerificationCheckTypeTableExists:
+		if strings.TrimSpace(c.Table) == "" {
+			return errors.New("table name is required")
+		}
+	case VerificationCheckTypeMinRowCount:
+		if strings.TrimSpace(c.Table) == "" {
+			return errors.New("table name is required")
+		}
+
+		if c.MinRowCount < 0 {
+			return errors.New("min row count must be greater than or equal to 0")
+		}
+	case VerificationCheckTypeCustomQuery:
+		if strings.TrimSpace(c.Query) == "" {
+			return errors.New("query is required")
+		}
+	default:
+		return fmt.Errorf("unknown check type: %s", c.Type)
+	}
+
+	return nil
+}
+
+func (c *VerificationCheck) String() string {
+	switch c.Type {
+	case VerificationCheckTypeTableExists:
+		return fmt.Sprintf("table %s exists", c.Table)
+	case VerificationCheckTypeMinRowCount:
+		return fmt.Sprintf("table %s has at least %d rows", c.Table, c.MinRowCount)
+	default:
+		return "custom query"
+	}
+}
+
+// VerificationResult is the outcome of the verification of a single backup
+type VerificationResult struct {
+	ID uuid.UUID `json:"id" gorm:"column:id;type:uuid;primaryKey;default:gen_random_uuid()"`
+
+	DatabaseID        uuid.UUID `json:"databaseId"        gorm:"column:database_id;type:uuid;not null"`
+	BackupID          uuid.UUID `json:"backupId"          gorm:"column:backup_id;type:uuid;not null"`
+	SandboxDatabaseID uuid.UUID `json:"sandboxDatabaseId" gorm:"column:sandbox_database_id;type:uuid;not null"`
+
+	Status      VerificationStatus `json:"status"      gorm:"column:status;type:text;not null"`
+	FailMessage *string            `json:"failMessage" gorm:"column:fail_message;type:text"`
+
+	PassedChecksCount int `json:"passedChecksCount" gorm:"column:passed_checks_count;type:int;not null;default:0"`
+	TotalChecksCount  int `json:"totalChecksCount"  gorm:"column:total_checks_count;type:int;not null;default:0"`
+
+	RestoreDurationMs int64 `json:"restoreDurationMs" gorm:"column:restore_duration_ms;default:0"`
+	DurationMs        int64 `json:"durationMs"        gorm:"column:duration_ms;default:0"`
+
+	CreatedAt   time.Time  `json:"createdAt"   gorm:"column:created_at"`
+	CompletedAt *time.Time `json:"completedAt" gorm:"column:completed_at"`
+}
+
+func (r *VerificationResult) TableName() string {
+	return "verification_results"
+}
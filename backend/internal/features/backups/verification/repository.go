@@ -0,0 +1,174 @@
+package backups_verification
+
+import (
+	"errors"
+
+	"databasus-backend/internal/storage"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type VerificationRepository struct{}
+
+// SaveConfig saves the config and replaces its checks
+func (r *VerificationRepository) SaveConfig(config *VerificationConfig) error {
+	return storage.GetDb().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Checks").Save(config).Error; err != nil {
+			return err
+		}
+
+		if err := tx.
+			Where("database_id = ?", config.DatabaseID).
+			Delete(&VerificationCheck{}).Error; err != nil {
+			return err
+		}
+
+		for i := range config.Checks {
+			config.Checks[i].ID = uuid.Nil
+			config.Checks[i].DatabaseID = config.DatabaseID
+			config.Checks[i].Position = i
+
+			if err := tx.Create(&config.Checks[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *VerificationRepository) FindConfigByDatabaseID(
+	databaseID uuid.UUID,
+) (*VerificationConfig, error) {
+	var config VerificationConfig
+
+	if err := storage.
+		GetDb().
+		Preload("Checks", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Where("database_id = ?", databaseID).
+		First(&config).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+func (r *VerificationRepository) FindEnabledConfigs() ([]*VerificationConfig, error) {
+	var configs []*VerificationConfig
+
+	if err := storage.
+		GetDb().
+		Preload("Checks", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Where("is_enabled = ?", true).
+		Find(&configs).Error; err != nil {
+		return nil, err
+	}
+
+	return configs, nil
+}
+
+// DetachSandboxDatabase disables verification of all databases which use
+// the database as a sandbox
+func (r *VerificationRepository) DetachSandboxDatabase(sandboxDatabaseID uuid.UUID) error {
+	return storage.
+		GetDb().
+		Model(&VerificationConfig{}).
+		Where("sandbox_database_id = ?", sandboxDatabaseID).
+		Updates(map[string]any{
+			"is_enabled":          false,
+			"sandbox_database_id": nil,
+		}).Error
+}
+
+func (r *VerificationRepository) DeleteByDatabaseID(databaseID uuid.UUID) error {
+	return storage.GetDb().Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Where("database_id = ?", databaseID).
+			Delete(&VerificationResult{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.
+			Where("database_id = ?", databaseID).
+			Delete(&VerificationCheck{}).Error; err != nil {
+			return err
+		}
+
+		return tx.
+			Where("database_id = ?", databaseID).
+			Delete(&VerificationConfig{}).Error
+	})
+}
+
+func (r *VerificationRepository) SaveResult(result *VerificationResult) error {
+	db := storage.GetDb()
+
+	if result.ID == uuid.Nil {
+		result.ID = uuid.New()
+		return db.Create(result).Error
+	}
+
+	return db.Save(result).Error
+}
+
+func (r *VerificationRepository) FindLastResultByDatabaseID(
+	databaseID uuid.UUID,
+) (*VerificationResult, error) {
+	var result VerificationResult
+
+	if err := storage.
+		GetDb().
+		Where("database_id = ?", databaseID).
+		Order("created_at DESC").
+		First(&result).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r *VerificationRepository) FindResultsByDatabaseIDWithLimit(
+	databaseID uuid.UUID,
+	limit int,
+) ([]*VerificationResult, error) {
+	var results []*VerificationResult
+
+	if err := storage.
+		GetDb().
+		Where("database_id = ?", databaseID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&results).Error; err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (r *VerificationRepository) FindResultsByStatus(
+	status VerificationStatus,
+) ([]*VerificationResult, error) {
+	var results []*VerificationResult
+
+	if err := storage.
+		GetDb().
+		Where("status = ?", status).
+		Find(&results).Error; err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
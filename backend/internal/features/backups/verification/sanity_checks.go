@@ -0,0 +1,311 @@
+package backups_verification
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"databasus-backend/internal/features/databases"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// sanityChecker runs verification checks against the sandbox database
+type sanityChecker interface {
+	Check(ctx context.Context, check *VerificationCheck) error
+	Close(ctx context.Context) error
+}
+
+// newSanityChecker connects to the sandbox database. The password must be
+// already decrypted
+func newSanityChecker(
+	ctx context.Context,
+	sandboxDB *databases.Database,
+	password string,
+) (sanityChecker, error) {
+	switch sandboxDB.Type {
+	case databases.DatabaseTypePostgres:
+		pg := sandboxDB.Postgresql
+		if pg == nil || pg.Database == nil || *pg.Database == "" {
+			return nil, errors.New("sandbox database name is required")
+		}
+
+		sslMode := "disable"
+		if pg.IsHttps {
+			sslMode = "require"
+		}
+
+		connStr := fmt.Sprintf(
+			"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s default_query_exec_mode=simple_protocol",
+			pg.Host,
+			pg.Port,
+			pg.Username,
+			password,
+			*pg.Database,
+			sslMode,
+		)
+
+		conn, err := pgx.Connect(ctx, connStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to sandbox database: %w", err)
+		}
+
+		return &postgresqlSanityChecker{conn}, nil
+	case databases.DatabaseTypeMysql, databases.DatabaseTypeMariadb:
+		host, port, username, database, isHttps := "", 0, "", (*string)(nil), false
+		if sandboxDB.Mysql != nil {
+			m := sandboxDB.Mysql
+			host, port, username, database, isHttps = m.Host, m.Port, m.Username, m.Database, m.IsHttps
+		} else if sandboxDB.Mariadb != nil {
+			m := sandboxDB.Mariadb
+			host, port, username, database, isHttps = m.Host, m.Port, m.Username, m.Database, m.IsHttps
+		}
+
+		if database == nil || *database == "" {
+			return nil, errors.New("sandbox database name is required")
+		}
+
+		dsn := fmt.Sprintf(
+			"%s:%s@tcp(%s:%d)/%s?parseTime=true&timeout=15s&tls=%t&charset=utf8mb4",
+			username,
+			password,
+			host,
+			port,
+			*database,
+			isHttps,
+		)
+
+		db, err := sql.Open("mysql", dsn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to sandbox database: %w", err)
+		}
+
+		if err := db.PingContext(ctx); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("failed to connect to sandbox database: %w", err)
+		}
+
+		return &mysqlSanityChecker{db}, nil
+	case databases.DatabaseTypeMongodb:
+		m := sandboxDB.Mongodb
+		if m == nil || m.Database == "" {
+			return nil, errors.New("sandbox database name is required")
+		}
+
+		authDB := m.AuthDatabase
+		if authDB == "" {
+			authDB = "admin"
+		}
+
+		uri := fmt.Sprintf(
+			"mongodb://%s:%s@%s:%d/%s?authSource=%s&tls=%t&connectTimeoutMS=15000",
+			m.Username,
+			password,
+			m.Host,
+			m.Port,
+			m.Database,
+			authDB,
+			m.IsHttps,
+		)
+
+		client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to sandbox database: %w", err)
+		}
+
+		return &mongodbSanityChecker{client, client.Database(m.Database)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported database type: %s", sandboxDB.Type)
+	}
+}
+
+type postgresqlSanityChecker struct {
+	conn *pgx.Conn
+}
+
+func (c *postgresqlSanityChecker) Check(ctx context.Context, check *VerificationCheck) error {
+	switch check.Type {
+	case VerificationCheckTypeTableExists:
+		var isExists bool
+		if err := c.conn.
+			QueryRow(ctx, "SELECT to_regclass($1) IS NOT NULL", check.Table).
+			Scan(&isExists); err != nil {
+			return err
+		}
+
+		if !isExists {
+			return fmt.Errorf("table %s does not exist", check.Table)
+		}
+
+		return nil
+	case VerificationCheckTypeMinRowCount:
+		table := pgx.Identifier(strings.Split(check.Table, ".")).Sanitize()
+
+		var count int64
+		if err := c.conn.QueryRow(ctx, "SELECT COUNT(*) FROM "+table).Scan(&count); err != nil {
+			return err
+		}
+
+		return checkRowCount(check, count)
+	case VerificationCheckTypeCustomQuery:
+		var value any
+		if err := c.conn.QueryRow(ctx, check.Query).Scan(&value); err != nil {
+			return err
+		}
+
+		return checkQueryValue(value)
+	default:
+		return fmt.Errorf("unknown check type: %s", check.Type)
+	}
+}
+
+func (c *postgresqlSanityChecker) Close(ctx context.Context) error {
+	return c.conn.Close(ctx)
+}
+
+type mysqlSanityChecker struct {
+	db *sql.DB
+}
+
+func (c *mysqlSanityChecker) Check(ctx context.Context, check *VerificationCheck) error {
+	switch check.Type {
+	case VerificationCheckTypeTableExists:
+		var count int64
+		if err := c.db.QueryRowContext(
+			ctx,
+			"SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?",
+			check.Table,
+		).Scan(&count); err != nil {
+			return err
+		}
+
+		if count == 0 {
+			return fmt.Errorf("table %s does not exist", check.Table)
+		}
+
+		return nil
+	case VerificationCheckTypeMinRowCount:
+		table := "`" + strings.ReplaceAll(check.Table, "`", "``") + "`"
+
+		var count int64
+		if err := c.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table).Scan(&count); err != nil {
+			return err
+		}
+
+		return checkRowCount(check, count)
+	case VerificationCheckTypeCustomQuery:
+		var value any
+		if err := c.db.QueryRowContext(ctx, check.Query).Scan(&value); err != nil {
+			return err
+		}
+
+		return checkQueryValue(value)
+	default:
+		return fmt.Errorf("unknown check type: %s", check.Type)
+	}
+}
+
+func (c *mysqlSanityChecker) Close(_ context.Context) error {
+	return c.db.Close()
+}
+
+type mongodbSanityChecker struct {
+	client   *mongo.Client
+	database *mongo.Database
+}
+
+func (c *mongodbSanityChecker) Check(ctx context.Context, check *VerificationCheck) error {
+	switch check.Type {
+	case VerificationCheckTypeTableExists:
+		names, err := c.database.ListCollectionNames(ctx, bson.M{"name": check.Table})
+		if err != nil {
+			return err
+		}
+
+		if len(names) == 0 {
+			return fmt.Errorf("collection %s does not exist", check.Table)
+		}
+
+		return nil
+	case VerificationCheckTypeMinRowCount:
+		count, err := c.database.Collection(check.Table).CountDocuments(ctx, bson.M{})
+		if err != nil {
+			return err
+		}
+
+		return checkRowCount(check, count)
+	case VerificationCheckTypeCustomQuery:
+		return errors.New("custom query checks are not supported for MongoDB")
+	default:
+		return fmt.Errorf("unknown check type: %s", check.Type)
+	}
+}
+
+func (c *mongodbSanityChecker) Close(ctx context.Context) error {
+	return c.client.Disconnect(ctx)
+}
+
+func checkRowCount(check *VerificationCheck, count int64) error {
+	if count < check.MinRowCount {
+		return fmt.Errorf(
+			"%s has %d rows, expected at least %d",
+			check.Table,
+			count,
+			check.MinRowCount,
+		)
+	}
+
+	return nil
+}
+
+// checkQueryValue passes when the value returned by a custom query is
+// true, a non-zero number or a string with such a value
+func checkQueryValue(value any) error {
+	isPassed := false
+
+	switch v := value.(type) {
+	case bool:
+		isPassed = v
+	case int64:
+		isPassed = v != 0
+	case int32:
+		isPassed = v != 0
+	case int16:
+		isPassed = v != 0
+	case float64:
+		isPassed = v != 0
+	case float32:
+		isPassed = v != 0
+	case []byte:
+		isPassed = isTruthyString(string(v))
+	case string:
+		isPassed = isTruthyString(v)
+	}
+
+	if !isPassed {
+		return fmt.Errorf("query returned %v, expected true or a non-zero number", value)
+	}
+
+	return nil
+}
+
+func isTruthyString(value string) bool {
+	value = strings.TrimSpace(value)
+
+	if isTrue, err := strconv.ParseBool(value); err == nil {
+		return isTrue
+	}
+
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		return number != 0
+	}
+
+	return false
+}
@@ -0,0 +1,445 @@
+package backups_verification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	audit_logs "databasus-backend/internal/features/audit_logs"
+	"databasus-backend/internal/features/backups/backups"
+	backups_config "databasus-backend/internal/features/backups/config"
+	"databasus-backend/internal/features/databases"
+	"databasus-backend/internal/features/restores"
+	users_models "databasus-backend/internal/features/users/models"
+	workspaces_services "databasus-backend/internal/features/workspaces/services"
+	"databasus-backend/internal/util/encryption"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultIntervalHours = 24
+	maxResultsLimit      = 100
+	checksTimeout        = 10 * time.Minute
+)
+
+type VerificationService struct {
+	verificationRepository *VerificationRepository
+	backupService          *backups.BackupService
+	backupConfigService    *backups_config.BackupConfigService
+	databaseService        *databases.DatabaseService
+	restoreService         *restores.RestoreService
+	workspaceService       *workspaces_services.WorkspaceService
+	auditLogService        *audit_logs.AuditLogService
+	fieldEncryptor         encryption.FieldEncryptor
+	logger                 *slog.Logger
+}
+
+func (s *VerificationService) OnBeforeDatabaseRemove(databaseID uuid.UUID) error {
+	if err := s.verificationRepository.DetachSandboxDatabase(databaseID); err != nil {
+		return err
+	}
+
+	return s.verificationRepository.DeleteByDatabaseID(databaseID)
+}
+
+func (s *VerificationService) GetVerificationConfigWithAuth(
+	user *users_models.User,
+	databaseID uuid.UUID,
+) (*VerificationConfig, error) {
+	database, err := s.databaseService.GetDatabase(user, databaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := s.verificationRepository.FindConfigByDatabaseID(database.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if config == nil {
+		return &VerificationConfig{
+			DatabaseID:    database.ID,
+			IsEnabled:     false,
+			IntervalHours: defaultIntervalHours,
+			Checks:        []VerificationCheck{},
+		}, nil
+	}
+
+	return config, nil
+}
+
+func (s *VerificationService) SaveVerificationConfigWithAuth(
+	user *users_models.User,
+	config *VerificationConfig,
+) (*VerificationConfig, error) {
+	database, err := s.databaseService.GetDatabaseByID(config.DatabaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if database.WorkspaceID == nil {
+		return nil, errors.New("cannot configure verification for database without workspace")
+	}
+
+	canManage, err := s.workspaceService.CanUserManageDBs(*database.WorkspaceID, user)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage {
+		return nil, errors.New("insufficient permissions to modify verification config")
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	if config.SandboxDatabaseID != nil {
+		sandboxDB, err := s.databaseService.GetDatabaseByID(*config.SandboxDatabaseID)
+		if err != nil {
+			return nil, errors.New("sandbox database not found")
+		}
+
+		if err := s.validateSandboxDatabase(database, sandboxDB); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.verificationRepository.SaveConfig(config); err != nil {
+		return nil, err
+	}
+
+	s.auditLogService.WriteAuditLog(
+		fmt.Sprintf("Backup verification config updated for database: %s", database.Name),
+		&user.ID,
+		database.WorkspaceID,
+	)
+
+	return s.verificationRepository.FindConfigByDatabaseID(config.DatabaseID)
+}
+
+func (s *VerificationService) GetVerificationResultsWithAuth(
+	user *users_models.User,
+	databaseID uuid.UUID,
+	limit int,
+) ([]*VerificationResult, error) {
+	database, err := s.databaseService.GetDatabase(user, databaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 || limit > maxResultsLimit {
+		limit = maxResultsLimit
+	}
+
+	return s.verificationRepository.FindResultsByDatabaseIDWithLimit(database.ID, limit)
+}
+
+// VerifyLatestBackup restores the latest completed backup of the database
+// into the sandbox database, runs the checks and records the result. Nil is
+// returned when there is nothing new to verify
+func (s *VerificationService) VerifyLatestBackup(
+	config *VerificationConfig,
+) (*VerificationResult, error) {
+	if config.SandboxDatabaseID == nil {
+		return nil, errors.New("sandbox database is not configured")
+	}
+
+	backup, err := s.backupService.GetLatestRestorableBackup(config.DatabaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if backup == nil {
+		return nil, nil
+	}
+
+	lastResult, err := s.verificationRepository.FindLastResultByDatabaseID(config.DatabaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastResult != nil && lastResult.BackupID == backup.ID {
+		return nil, nil
+	}
+
+	result := &VerificationResult{
+		DatabaseID:        config.DatabaseID,
+		BackupID:          backup.ID,
+		SandboxDatabaseID: *config.SandboxDatabaseID,
+		Status:            VerificationStatusInProgress,
+		TotalChecksCount:  len(config.Checks),
+		CreatedAt:         time.Now().UTC(),
+	}
+
+	if err := s.verificationRepository.SaveResult(result); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info(
+		"Verifying backup",
+		"databaseId",
+		config.DatabaseID,
+		"backupId",
+		backup.ID,
+		"sandboxDatabaseId",
+		*config.SandboxDatabaseID,
+	)
+
+	verifyErr := s.verifyBackup(config, backup, result)
+
+	completedAt := time.Now().UTC()
+	result.CompletedAt = &completedAt
+	result.DurationMs = completedAt.Sub(result.CreatedAt).Milliseconds()
+
+	if verifyErr != nil {
+		failMessage := verifyErr.Error()
+		result.FailMessage = &failMessage
+		result.Status = VerificationStatusFailed
+	} else {
+		result.Status = VerificationStatusPassed
+	}
+
+	if err := s.verificationRepository.SaveResult(result); err != nil {
+		return nil, err
+	}
+
+	if verifyErr != nil {
+		s.logger.Error(
+			"Backup verification failed",
+			"databaseId",
+			config.DatabaseID,
+			"backupId",
+			backup.ID,
+			"error",
+			verifyErr,
+		)
+
+		s.sendVerificationFailedNotification(backup, verifyErr)
+	} else {
+		s.logger.Info("Backup verification passed", "databaseId", config.DatabaseID, "backupId", backup.ID)
+	}
+
+	return result, nil
+}
+
+// FailVerificationsInProgress fails verifications interrupted by
+// application restart
+func (s *VerificationService) FailVerificationsInProgress() error {
+	results, err := s.verificationRepository.FindResultsByStatus(VerificationStatusInProgress)
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		failMessage := "Backup verification failed due to application restart"
+		result.FailMessage = &failMessage
+		result.Status = VerificationStatusFailed
+
+		if err := s.verificationRepository.SaveResult(result); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *VerificationService) GetEnabledVerificationConfigs() ([]*VerificationConfig, error) {
+	return s.verificationRepository.FindEnabledConfigs()
+}
+
+func (s *VerificationService) GetLastVerificationResult(
+	databaseID uuid.UUID,
+) (*VerificationResult, error) {
+	return s.verificationRepository.FindLastResultByDatabaseID(databaseID)
+}
+
+func (s *VerificationService) verifyBackup(
+	config *VerificationConfig,
+	backup *backups.Backup,
+	result *VerificationResult,
+) error {
+	database, err := s.databaseService.GetDatabaseByID(config.DatabaseID)
+	if err != nil {
+		return err
+	}
+
+	sandboxDB, err := s.databaseService.GetDatabaseByID(*config.SandboxDatabaseID)
+	if err != nil {
+		return fmt.Errorf("failed to get sandbox database: %w", err)
+	}
+
+	// the sandbox may have been changed since the config was saved
+	if err := s.validateSandboxDatabase(database, sandboxDB); err != nil {
+		return err
+	}
+
+	password, err := s.decryptSandboxPassword(sandboxDB)
+	if err != nil {
+		return err
+	}
+
+	restoreStart := time.Now().UTC()
+
+	if err := s.restoreService.RestoreBackup(
+		backup,
+		buildSandboxRestoreRequest(sandboxDB, password),
+	); err != nil {
+		return fmt.Errorf("failed to restore backup into sandbox database: %w", err)
+	}
+
+	result.RestoreDurationMs = time.Since(restoreStart).Milliseconds()
+
+	if len(config.Checks) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), checksTimeout)
+	defer cancel()
+
+	checker, err := newSanityChecker(ctx, sandboxDB, password)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := checker.Close(ctx); err != nil {
+			s.logger.Error("Failed to close sandbox database connection", "error", err)
+		}
+	}()
+
+	failedChecks := make([]string, 0)
+
+	for i := range config.Checks {
+		check := &config.Checks[i]
+
+		if err := checker.Check(ctx, check); err != nil {
+			failedChecks = append(failedChecks, fmt.Sprintf("%s: %s", check.String(), err.Error()))
+			continue
+		}
+
+		result.PassedChecksCount++
+	}
+
+	if len(failedChecks) > 0 {
+		return fmt.Errorf(
+			"%d of %d checks failed:\n%s",
+			len(failedChecks),
+			len(config.Checks),
+			strings.Join(failedChecks, "\n"),
+		)
+	}
+
+	return nil
+}
+
+// validateSandboxDatabase protects from restoring into a database which is
+// not meant to be overwritten
+func (s *VerificationService) validateSandboxDatabase(
+	database *databases.Database,
+	sandboxDB *databases.Database,
+) error {
+	if sandboxDB.ID == database.ID {
+		return errors.New("sandbox database cannot be the verified database itself")
+	}
+
+	if sandboxDB.WorkspaceID == nil || database.WorkspaceID == nil ||
+		*sandboxDB.WorkspaceID != *database.WorkspaceID {
+		return errors.New("sandbox database must belong to the same workspace")
+	}
+
+	if sandboxDB.Type != database.Type {
+		return errors.New("sandbox database must be of the same type as the verified database")
+	}
+
+	sandboxBackupConfig, err := s.backupConfigService.GetBackupConfigByDbId(sandboxDB.ID)
+	if err != nil {
+		return err
+	}
+
+	if sandboxBackupConfig != nil && sandboxBackupConfig.IsBackupsEnabled {
+		return errors.New(
+			"sandbox database has enabled backups; its content is overwritten on each verification",
+		)
+	}
+
+	return nil
+}
+
+func (s *VerificationService) decryptSandboxPassword(sandboxDB *databases.Database) (string, error) {
+	password := ""
+
+	switch sandboxDB.Type {
+	case databases.DatabaseTypePostgres:
+		password = sandboxDB.Postgresql.Password
+	case databases.DatabaseTypeMysql:
+		password = sandboxDB.Mysql.Password
+	case databases.DatabaseTypeMariadb:
+		password = sandboxDB.Mariadb.Password
+	case databases.DatabaseTypeMongodb:
+		password = sandboxDB.Mongodb.Password
+	}
+
+	decryptedPassword, err := s.fieldEncryptor.Decrypt(sandboxDB.ID, password)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt sandbox database password: %w", err)
+	}
+
+	return decryptedPassword, nil
+}
+
+func (s *VerificationService) sendVerificationFailedNotification(
+	backup *backups.Backup,
+	verifyErr error,
+) {
+	backupConfig, err := s.backupConfigService.GetBackupConfigByDbId(backup.DatabaseID)
+	if err != nil || backupConfig == nil {
+		s.logger.Error("Failed to get backup config for notification", "error", err)
+		return
+	}
+
+	errMsg := fmt.Sprintf(
+		"Backup from %s could not be verified.\n%s",
+		backup.CreatedAt.UTC().Format(time.RFC3339),
+		verifyErr.Error(),
+	)
+
+	s.backupService.SendBackupNotification(
+		backupConfig,
+		backup,
+		backups_config.NotificationBackupVerificationFailed,
+		&errMsg,
+	)
+}
+
+// buildSandboxRestoreRequest copies connection of the sandbox database with
+// the decrypted password, so the copy is never saved
+func buildSandboxRestoreRequest(
+	sandboxDB *databases.Database,
+	password string,
+) restores.RestoreBackupRequest {
+	request := restores.RestoreBackupRequest{}
+
+	switch sandboxDB.Type {
+	case databases.DatabaseTypePostgres:
+		pg := *sandboxDB.Postgresql
+		pg.Password = password
+		request.PostgresqlDatabase = &pg
+	case databases.DatabaseTypeMysql:
+		mysql := *sandboxDB.Mysql
+		mysql.Password = password
+		request.MysqlDatabase = &mysql
+	case databases.DatabaseTypeMariadb:
+		mariadb := *sandboxDB.Mariadb
+		mariadb.Password = password
+		request.MariadbDatabase = &mariadb
+	case databases.DatabaseTypeMongodb:
+		mongodb := *sandboxDB.Mongodb
+		mongodb.Password = password
+		request.MongodbDatabase = &mongodb
+	}
+
+	return request
+}
@@ -0,0 +1,546 @@
+package backups_wal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"databasus-backend/internal/config"
+	"databasus-backend/internal/features/backups/backups"
+	common "databasus-backend/internal/features/backups/backups/common"
+	backups_config "databasus-backend/internal/features/backups/config"
+	"databasus-backend/internal/features/databases"
+	pgtypes "databasus-backend/internal/features/databases/databases/postgresql"
+	"databasus-backend/internal/features/storages"
+	"databasus-backend/internal/util/encryption"
+	"databasus-backend/internal/util/tools"
+
+	"github.com/google/uuid"
+)
+
+const (
+	walUploadInterval    = 10 * time.Second
+	receiverRestartDelay = 30 * time.Second
+	pgConnectTimeout     = 30
+)
+
+type walReceiver struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// WalArchivingBackgroundService keeps pg_receivewal running for each
+// database with enabled WAL archiving, uploads completed WAL files into
+// the storage and removes WAL which is older than the oldest base backup
+type WalArchivingBackgroundService struct {
+	walService          *WalService
+	backupService       *backups.BackupService
+	backupConfigService *backups_config.BackupConfigService
+	databaseService     *databases.DatabaseService
+	storageService      *storages.StorageService
+	fieldEncryptor      encryption.FieldEncryptor
+	logger              *slog.Logger
+
+	receivers map[uuid.UUID]*walReceiver
+	mu        sync.Mutex
+}
+
+func (s *WalArchivingBackgroundService) Run() {
+	s.logger.Info("Starting WAL archiving background service")
+
+	for {
+		if config.IsShouldShutdown() {
+			s.stopAllReceivers()
+			return
+		}
+
+		if err := s.syncReceivers(); err != nil {
+			s.logger.Error("Failed to sync WAL receivers", "error", err)
+		}
+
+		if err := s.cleanOldSegments(); err != nil {
+			s.logger.Error("Failed to clean old WAL segments", "error", err)
+		}
+
+		time.Sleep(1 * time.Minute)
+	}
+}
+
+// Stop stops all WAL receivers and waits for them to exit. It is called on
+// shutdown after config.Shutdown, so receivers are not started again
+func (s *WalArchivingBackgroundService) Stop() {
+	s.stopAllReceivers()
+}
+
+func (s *WalArchivingBackgroundService) OnBeforeDatabaseRemove(databaseID uuid.UUID) error {
+	if s.stopReceiver(databaseID) {
+		s.dropReplicationSlot(databaseID)
+	}
+
+	return s.walService.DeleteDatabaseSegments(databaseID)
+}
+
+func (s *WalArchivingBackgroundService) syncReceivers() error {
+	enabledBackupConfigs, err := s.backupConfigService.GetBackupConfigsWithEnabledBackups()
+	if err != nil {
+		return err
+	}
+
+	walDatabaseIDs := make([]uuid.UUID, 0)
+	for _, backupConfig := range enabledBackupConfigs {
+		if backupConfig.IsWalArchivingEnabled && backupConfig.StorageID != nil {
+			walDatabaseIDs = append(walDatabaseIDs, backupConfig.DatabaseID)
+		}
+	}
+
+	s.mu.Lock()
+	runningDatabaseIDs := make([]uuid.UUID, 0, len(s.receivers))
+	for databaseID := range s.receivers {
+		runningDatabaseIDs = append(runningDatabaseIDs, databaseID)
+	}
+
+	for _, databaseID := range walDatabaseIDs {
+		if _, isRunning := s.receivers[databaseID]; isRunning {
+			continue
+		}
+
+		if config.IsShouldShutdown() {
+			break
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		receiver := &walReceiver{cancel, make(chan struct{})}
+		s.receivers[databaseID] = receiver
+
+		go s.runReceiver(ctx, databaseID, receiver.done)
+		s.logger.Info("Started WAL receiver", "databaseId", databaseID)
+	}
+	s.mu.Unlock()
+
+	for _, databaseID := range runningDatabaseIDs {
+		if slices.Contains(walDatabaseIDs, databaseID) {
+			continue
+		}
+
+		// WAL archiving was disabled: release the slot, otherwise
+		// the server keeps WAL for it forever
+		if s.stopReceiver(databaseID) {
+			s.dropReplicationSlot(databaseID)
+		}
+	}
+
+	return nil
+}
+
+func (s *WalArchivingBackgroundService) cleanOldSegments() error {
+	enabledBackupConfigs, err := s.backupConfigService.GetBackupConfigsWithEnabledBackups()
+	if err != nil {
+		return err
+	}
+
+	for _, backupConfig := range enabledBackupConfigs {
+		oldestBaseBackup, err := s.backupService.GetOldestCompletedBackupByType(
+			backupConfig.DatabaseID,
+			common.BackupTypeBaseBackup,
+		)
+		if err != nil {
+			s.logger.Error(
+				"Failed to get oldest base backup",
+				"databaseId",
+				backupConfig.DatabaseID,
+				"error",
+				err,
+			)
+			continue
+		}
+
+		if oldestBaseBackup == nil {
+			if !backupConfig.IsWalArchivingEnabled {
+				// no base backups left to replay WAL on
+				if err := s.walService.DeleteDatabaseSegments(backupConfig.DatabaseID); err != nil {
+					s.logger.Error(
+						"Failed to delete WAL segments",
+						"databaseId",
+						backupConfig.DatabaseID,
+						"error",
+						err,
+					)
+				}
+			}
+
+			continue
+		}
+
+		if err := s.walService.DeleteSegmentsBeforeDate(
+			backupConfig.DatabaseID,
+			oldestBaseBackup.CreatedAt,
+		); err != nil {
+			s.logger.Error(
+				"Failed to delete old WAL segments",
+				"databaseId",
+				backupConfig.DatabaseID,
+				"error",
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+func (s *WalArchivingBackgroundService) runReceiver(
+	ctx context.Context,
+	databaseID uuid.UUID,
+	done chan struct{},
+) {
+	defer close(done)
+
+	for {
+		if ctx.Err() != nil || config.IsShouldShutdown() {
+			return
+		}
+
+		if err := s.receiveWal(ctx, databaseID); err != nil && ctx.Err() == nil {
+			s.logger.Error("WAL receiver failed", "databaseId", databaseID, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(receiverRestartDelay):
+		}
+	}
+}
+
+// receiveWal runs pg_receivewal until it exits and uploads
+// completed WAL files into the storage meanwhile
+func (s *WalArchivingBackgroundService) receiveWal(
+	ctx context.Context,
+	databaseID uuid.UUID,
+) error {
+	database, err := s.databaseService.GetDatabaseByID(databaseID)
+	if err != nil {
+		return err
+	}
+
+	if database.Type != databases.DatabaseTypePostgres || database.Postgresql == nil {
+		return fmt.Errorf("WAL archiving is supported only for PostgreSQL databases")
+	}
+
+	backupConfig, err := s.backupConfigService.GetBackupConfigByDbId(databaseID)
+	if err != nil {
+		return err
+	}
+
+	if backupConfig.StorageID == nil {
+		return fmt.Errorf("backup config storage ID is not defined")
+	}
+
+	storage, err := s.storageService.GetStorageByID(*backupConfig.StorageID)
+	if err != nil {
+		return err
+	}
+
+	walDir := getWalDirectory(databaseID)
+	if err := os.MkdirAll(walDir, 0700); err != nil {
+		return fmt.Errorf("failed to create WAL directory: %w", err)
+	}
+
+	slotName := getReplicationSlotName(databaseID)
+
+	if _, err := s.runPgReceivewal(
+		ctx,
+		database,
+		"--create-slot",
+		"--if-not-exists",
+		"-S", slotName,
+	); err != nil {
+		return fmt.Errorf("failed to create replication slot: %w", err)
+	}
+
+	receiverCtx, stopUploads := context.WithCancel(ctx)
+	uploadsDone := make(chan struct{})
+	go func() {
+		defer close(uploadsDone)
+
+		ticker := time.NewTicker(walUploadInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-receiverCtx.Done():
+				return
+			case <-ticker.C:
+				if config.IsShouldShutdown() {
+					return
+				}
+
+				if err := s.archiveCompletedFiles(receiverCtx, backupConfig, storage, walDir); err != nil {
+					s.logger.Error("Failed to archive WAL files", "databaseId", databaseID, "error", err)
+				}
+			}
+		}
+	}()
+
+	_, receiveErr := s.runPgReceivewal(
+		ctx,
+		database,
+		"-D", walDir,
+		"-S", slotName,
+		"--no-loop",
+	)
+
+	stopUploads()
+	<-uploadsDone
+
+	if ctx.Err() == nil && !config.IsShouldShutdown() {
+		if err := s.archiveCompletedFiles(ctx, backupConfig, storage, walDir); err != nil {
+			s.logger.Error("Failed to archive WAL files", "databaseId", databaseID, "error", err)
+		}
+	}
+
+	return receiveErr
+}
+
+// archiveCompletedFiles uploads completed WAL files. The newest segment is
+// kept locally after upload: pg_receivewal resumes streaming from the last
+// file in the directory after restart
+func (s *WalArchivingBackgroundService) archiveCompletedFiles(
+	ctx context.Context,
+	backupConfig *backups_config.BackupConfig,
+	storage *storages.Storage,
+	walDir string,
+) error {
+	entries, err := os.ReadDir(walDir)
+	if err != nil {
+		return err
+	}
+
+	fileNames := make([]string, 0, len(entries))
+	lastSegmentName := ""
+
+	for _, entry := range entries {
+		if entry.IsDir() || !IsArchivableWalFileName(entry.Name()) {
+			continue
+		}
+
+		fileNames = append(fileNames, entry.Name())
+
+		if walSegmentFileNameRegex.MatchString(entry.Name()) && entry.Name() > lastSegmentName {
+			lastSegmentName = entry.Name()
+		}
+	}
+
+	slices.Sort(fileNames)
+
+	for _, fileName := range fileNames {
+		filePath := filepath.Join(walDir, fileName)
+
+		if err := s.walService.ArchiveSegmentFile(ctx, backupConfig, storage, filePath); err != nil {
+			return err
+		}
+
+		if !walSegmentFileNameRegex.MatchString(fileName) || fileName == lastSegmentName {
+			continue
+		}
+
+		if err := os.Remove(filePath); err != nil {
+			s.logger.Error("Failed to remove archived WAL file", "filePath", filePath, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *WalArchivingBackgroundService) dropReplicationSlot(databaseID uuid.UUID) {
+	defer func() {
+		_ = os.RemoveAll(getWalDirectory(databaseID))
+	}()
+
+	database, err := s.databaseService.GetDatabaseByID(databaseID)
+	if err != nil {
+		s.logger.Error("Failed to get database to drop replication slot", "error", err)
+		return
+	}
+
+	if database.Postgresql == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	if _, err := s.runPgReceivewal(
+		ctx,
+		database,
+		"--drop-slot",
+		"-S", getReplicationSlotName(databaseID),
+	); err != nil {
+		s.logger.Error(
+			"Failed to drop replication slot, drop it manually to let the server recycle WAL",
+			"databaseId",
+			databaseID,
+			"slot",
+			getReplicationSlotName(databaseID),
+			"error",
+			err,
+		)
+		return
+	}
+
+	s.logger.Info("Dropped replication slot", "databaseId", databaseID)
+}
+
+// stopReceiver stops receiver of the database and waits for it to
+// exit. Returns false if there was no running receiver
+func (s *WalArchivingBackgroundService) stopReceiver(databaseID uuid.UUID) bool {
+	s.mu.Lock()
+	receiver, isRunning := s.receivers[databaseID]
+	delete(s.receivers, databaseID)
+	s.mu.Unlock()
+
+	if !isRunning {
+		return false
+	}
+
+	receiver.cancel()
+	<-receiver.done
+
+	s.logger.Info("Stopped WAL receiver", "databaseId", databaseID)
+	return true
+}
+
+func (s *WalArchivingBackgroundService) stopAllReceivers() {
+	s.mu.Lock()
+	databaseIDs := make([]uuid.UUID, 0, len(s.receivers))
+	for databaseID := range s.receivers {
+		databaseIDs = append(databaseIDs, databaseID)
+	}
+	s.mu.Unlock()
+
+	for _, databaseID := range databaseIDs {
+		s.stopReceiver(databaseID)
+	}
+}
+
+func (s *WalArchivingBackgroundService) runPgReceivewal(
+	ctx context.Context,
+	database *databases.Database,
+	args ...string,
+) ([]byte, error) {
+	pg := database.Postgresql
+
+	password, err := s.fieldEncryptor.Decrypt(database.ID, pg.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt database password: %w", err)
+	}
+
+	pgpassFile, err := createTempPgpassFile(pg, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary .pgpass file: %w", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(filepath.Dir(pgpassFile))
+	}()
+
+	pgBin := tools.GetPostgresqlExecutable(
+		pg.Version,
+		tools.PostgresqlExecutablePgReceivewal,
+		config.GetEnv().EnvMode,
+		config.GetEnv().PostgresesInstallDir,
+	)
+
+	args = append(args,
+		"--no-password",
+		"-h", pg.Host,
+		"-p", strconv.Itoa(pg.Port),
+		"-U", pg.Username,
+	)
+
+	cmd := exec.CommandContext(ctx, pgBin, args...)
+	cmd.Env = append(os.Environ(),
+		"PGPASSFILE="+pgpassFile,
+		"PGCONNECT_TIMEOUT="+strconv.Itoa(pgConnectTimeout),
+		"PGSSLCERT=",
+		"PGSSLKEY=",
+		"PGSSLROOTCERT=",
+		"PGSSLCRL=",
+	)
+
+	if pg.IsHttps {
+		cmd.Env = append(cmd.Env, "PGSSLMODE=require")
+	} else {
+		cmd.Env = append(cmd.Env, "PGSSLMODE=prefer")
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		stderrStr := strings.TrimSpace(stderr.String())
+
+		if containsIgnoreCase(stderrStr, "replication") &&
+			(containsIgnoreCase(stderrStr, "pg_hba.conf") ||
+				containsIgnoreCase(stderrStr, "permission denied")) {
+			return nil, fmt.Errorf(
+				"%s failed: the user must have REPLICATION privilege and pg_hba.conf must allow replication connections. stderr: %s",
+				filepath.Base(pgBin),
+				stderrStr,
+			)
+		}
+
+		return nil, fmt.Errorf("%s failed: %w – stderr: %s", filepath.Base(pgBin), err, stderrStr)
+	}
+
+	return output, nil
+}
+
+func getWalDirectory(databaseID uuid.UUID) string {
+	return filepath.Join(config.GetEnv().DataFolder, "wal", databaseID.String())
+}
+
+func getReplicationSlotName(databaseID uuid.UUID) string {
+	return "databasus_" + strings.ReplaceAll(databaseID.String(), "-", "")
+}
+
+func createTempPgpassFile(
+	pgConfig *pgtypes.PostgresqlDatabase,
+	password string,
+) (string, error) {
+	escapedHost := tools.EscapePgpassField(pgConfig.Host)
+	escapedUsername := tools.EscapePgpassField(pgConfig.Username)
+	escapedPassword := tools.EscapePgpassField(password)
+
+	pgpassContent := fmt.Sprintf("%s:%d:*:%s:%s",
+		escapedHost,
+		pgConfig.Port,
+		escapedUsername,
+		escapedPassword,
+	)
+
+	tempDir, err := os.MkdirTemp("", "pgpass")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+
+	pgpassFile := filepath.Join(tempDir, ".pgpass")
+	if err := os.WriteFile(pgpassFile, []byte(pgpassContent), 0600); err != nil {
+		return "", fmt.Errorf("failed to write temporary .pgpass file: %w", err)
+	}
+
+	return pgpassFile, nil
+}
+
+func containsIgnoreCase(str, substr string) bool {
+	return strings.Contains(strings.ToLower(str), strings.ToLower(substr))
+}
@@ -0,0 +1,50 @@
+package backups_wal
+
+import (
+	"net/http"
+
+	users_middleware "databasus-backend/internal/features/users/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WalController struct {
+	walService *WalService
+}
+
+func (c *WalController) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/wal/database/:id/recovery-window", c.GetRecoveryWindow)
+}
+
+// GetRecoveryWindow
+// @Summary Get point-in-time recovery window
+// @Description Get the time range the database can be recovered to using base backups and archived WAL
+// @Tags wal
+// @Produce json
+// @Param id path string true "Database ID"
+// @Success 200 {object} RecoveryWindowResponse
+// @Failure 400 {object} map[string]string "Invalid database ID"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Router /wal/database/{id}/recovery-window [get]
+func (c *WalController) GetRecoveryWindow(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid database ID"})
+		return
+	}
+
+	response, err := c.walService.GetRecoveryWindow(user, id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,60 @@
+package backups_wal
+
+import (
+	"sync"
+
+	"databasus-backend/internal/features/backups/backups"
+	backups_config "databasus-backend/internal/features/backups/config"
+	"databasus-backend/internal/features/databases"
+	encryption_secrets "databasus-backend/internal/features/encryption/secrets"
+	"databasus-backend/internal/features/storages"
+	"databasus-backend/internal/util/encryption"
+	"databasus-backend/internal/util/logger"
+
+	"github.com/google/uuid"
+)
+
+var walSegmentRepository = &WalSegmentRepository{}
+
+var walService = &WalService{
+	walSegmentRepository,
+	backups.GetBackupService(),
+	backups_config.GetBackupConfigService(),
+	databases.GetDatabaseService(),
+	storages.GetStorageService(),
+	encryption_secrets.GetSecretKeyService(),
+	encryption.GetFieldEncryptor(),
+	logger.GetLogger(),
+}
+
+var walArchivingBackgroundService = &WalArchivingBackgroundService{
+	walService,
+	backups.GetBackupService(),
+	backups_config.GetBackupConfigService(),
+	databases.GetDatabaseService(),
+	storages.GetStorageService(),
+	encryption.GetFieldEncryptor(),
+	logger.GetLogger(),
+	map[uuid.UUID]*walReceiver{},
+	sync.Mutex{},
+}
+
+var walController = &WalController{
+	walService,
+}
+
+func SetupDependencies() {
+	databases.GetDatabaseService().AddDbRemoveListener(walArchivingBackgroundService)
+}
+
+func GetWalService() *WalService {
+	return walService
+}
+
+func GetWalArchivingBackgroundService() *WalArchivingBackgroundService {
+	return walArchivingBackgroundService
+}
+
+func GetWalController() *WalController {
+	return walController
+}
@@ -0,0 +1,13 @@
+package backups_wal
+
+import "time"
+
+type RecoveryWindowResponse struct {
+	IsWalArchivingEnabled bool  `json:"isWalArchivingEnabled"`
+	SegmentsCount         int64 `json:"segmentsCount"`
+
+	// Recovery window bounds, nil until there is at least one
+	// completed base backup followed by archived WAL
+	EarliestRecoveryTime *time.Time `json:"earliestRecoveryTime"`
+	LatestRecoveryTime   *time.Time `json:"latestRecoveryTime"`
+}
@@ -0,0 +1,114 @@
+package backups_wal
+
+import (
+	backups_config "databasus-backend/internal/features/backups/config"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	walSegmentFileNameRegex = regexp.MustCompile(`^[0-9A-F]{24}$`)
+	walHistoryFileNameRegex = regexp.MustCompile(`^[0-9A-F]{8}\.history$`)
+)
+
+// defaultWalSegmentSizeBytes is used when the size of the segment is not
+// known, PostgreSQL clusters are initialized with 16 MB segments by default
+const defaultWalSegmentSizeBytes = 16 * 1024 * 1024
+
+// WalSegment is a single WAL file (segment or timeline history file)
+// received via pg_receivewal and uploaded into the storage
+type WalSegment struct {
+	ID uuid.UUID `json:"id" gorm:"column:id;type:uuid;primaryKey"`
+
+	DatabaseID uuid.UUID `json:"databaseId" gorm:"column:database_id;type:uuid;not null"`
+	StorageID  uuid.UUID `json:"storageId"  gorm:"column:storage_id;type:uuid;not null"`
+
+	FileName  string `json:"fileName"  gorm:"column:file_name;type:text;not null"`
+	SizeBytes int64  `json:"sizeBytes" gorm:"column:size_bytes;default:0"`
+
+	EncryptionSalt *string                         `json:"-"          gorm:"column:encryption_salt"`
+	EncryptionIV   *string                         `json:"-"          gorm:"column:encryption_iv"`
+	Encryption     backups_config.BackupEncryption `json:"encryption" gorm:"column:encryption;type:text;not null;default:'NONE'"`
+
+	// CompletedAt is when pg_receivewal finished writing the segment. It
+	// differs from CreatedAt (upload time) when uploads were delayed, e.g.
+	// after an outage of the storage
+	CompletedAt time.Time `json:"completedAt" gorm:"column:completed_at"`
+
+	CreatedAt time.Time `json:"createdAt" gorm:"column:created_at"`
+}
+
+func (s *WalSegment) TableName() string {
+	return "wal_segments"
+}
+
+func (s *WalSegment) IsHistoryFile() bool {
+	return walHistoryFileNameRegex.MatchString(s.FileName)
+}
+
+// GetLsnRange returns the WAL positions [start, end) covered by the segment,
+// parsed from its file name (timeline, log ID and segment number). The
+// segment size is taken from the archived file, ok is false for history
+// files
+func (s *WalSegment) GetLsnRange() (uint64, uint64, bool) {
+	if !walSegmentFileNameRegex.MatchString(s.FileName) {
+		return 0, 0, false
+	}
+
+	logID, err := strconv.ParseUint(s.FileName[8:16], 16, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	segmentNumber, err := strconv.ParseUint(s.FileName[16:24], 16, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	segmentSize := uint64(defaultWalSegmentSizeBytes)
+	if isValidWalSegmentSize(s.SizeBytes) {
+		segmentSize = uint64(s.SizeBytes)
+	}
+
+	startLsn := logID<<32 + segmentNumber*segmentSize
+
+	return startLsn, startLsn + segmentSize, true
+}
+
+// ParseLsn parses WAL position in PostgreSQL text form (e.g. "0/2000028")
+func ParseLsn(lsn string) (uint64, error) {
+	high, low, ok := strings.Cut(lsn, "/")
+	if !ok {
+		return 0, fmt.Errorf("invalid LSN: %s", lsn)
+	}
+
+	highValue, err := strconv.ParseUint(high, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid LSN: %s", lsn)
+	}
+
+	lowValue, err := strconv.ParseUint(low, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid LSN: %s", lsn)
+	}
+
+	return highValue<<32 | lowValue, nil
+}
+
+// isValidWalSegmentSize reports whether the size is a possible WAL segment
+// size: a power of two from 1 MB to 1 GB
+func isValidWalSegmentSize(sizeBytes int64) bool {
+	return sizeBytes >= 1024*1024 && sizeBytes <= 1024*1024*1024 && sizeBytes&(sizeBytes-1) == 0
+}
+
+// IsArchivableWalFileName reports whether the file in pg_receivewal
+// directory is completed and can be uploaded (.partial files are skipped)
+func IsArchivableWalFileName(fileName string) bool {
+	return walSegmentFileNameRegex.MatchString(fileName) ||
+		walHistoryFileNameRegex.MatchString(fileName)
+}
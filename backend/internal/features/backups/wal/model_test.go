@@ -0,0 +1,60 @@
+package backups_wal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetLsnRange_WithDefaultSegmentSize_RangeOfDefaultSizeReturned(t *testing.T) {
+	segment := &WalSegment{FileName: "000000010000000000000002"}
+
+	startLsn, endLsn, ok := segment.GetLsnRange()
+
+	assert.True(t, ok)
+	assert.Equal(t, uint64(0x2000000), startLsn)
+	assert.Equal(t, uint64(0x3000000), endLsn)
+}
+
+func Test_GetLsnRange_WithSegmentInSecondLogFile_RangeInSecondLogFileReturned(t *testing.T) {
+	segment := &WalSegment{FileName: "0000000200000001000000FF", SizeBytes: 16 * 1024 * 1024}
+
+	startLsn, endLsn, ok := segment.GetLsnRange()
+
+	assert.True(t, ok)
+	assert.Equal(t, uint64(0x1FF000000), startLsn)
+	assert.Equal(t, uint64(0x200000000), endLsn)
+}
+
+func Test_GetLsnRange_WithCustomSegmentSize_RangeOfCustomSizeReturned(t *testing.T) {
+	segment := &WalSegment{FileName: "000000010000000000000003", SizeBytes: 64 * 1024 * 1024}
+
+	startLsn, endLsn, ok := segment.GetLsnRange()
+
+	assert.True(t, ok)
+	assert.Equal(t, uint64(0xC000000), startLsn)
+	assert.Equal(t, uint64(0x10000000), endLsn)
+}
+
+func Test_GetLsnRange_WithHistoryFile_NoRangeReturned(t *testing.T) {
+	segment := &WalSegment{FileName: "00000002.history"}
+
+	_, _, ok := segment.GetLsnRange()
+
+	assert.False(t, ok)
+}
+
+func Test_ParseLsn_WithValidLsn_LsnParsed(t *testing.T) {
+	lsn, err := ParseLsn("1/2000028")
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0x102000028), lsn)
+}
+
+func Test_ParseLsn_WithInvalidLsn_ReturnsError(t *testing.T) {
+	_, err := ParseLsn("2000028")
+	assert.Error(t, err)
+
+	_, err = ParseLsn("0/XYZ")
+	assert.Error(t, err)
+}
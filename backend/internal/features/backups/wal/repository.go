@@ -0,0 +1,127 @@
+package backups_wal
+
+import (
+	"databasus-backend/internal/storage"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WalSegmentRepository struct{}
+
+// Create inserts the segment keeping its ID, because the ID is
+// used as the file ID in the storage and is known before the upload
+func (r *WalSegmentRepository) Create(segment *WalSegment) error {
+	if segment.DatabaseID == uuid.Nil || segment.StorageID == uuid.Nil {
+		return errors.New("database ID and storage ID are required")
+	}
+
+	if segment.ID == uuid.Nil {
+		segment.ID = uuid.New()
+	}
+
+	return storage.GetDb().Create(segment).Error
+}
+
+func (r *WalSegmentRepository) FindByDatabaseIDAndFileName(
+	databaseID uuid.UUID,
+	fileName string,
+) (*WalSegment, error) {
+	var segment WalSegment
+
+	if err := storage.
+		GetDb().
+		Where("database_id = ? AND file_name = ?", databaseID, fileName).
+		First(&segment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &segment, nil
+}
+
+func (r *WalSegmentRepository) FindByDatabaseID(databaseID uuid.UUID) ([]*WalSegment, error) {
+	var segments []*WalSegment
+
+	if err := storage.
+		GetDb().
+		Where("database_id = ?", databaseID).
+		Order("file_name ASC").
+		Find(&segments).Error; err != nil {
+		return nil, err
+	}
+
+	return segments, nil
+}
+
+func (r *WalSegmentRepository) FindSegmentsBeforeDate(
+	databaseID uuid.UUID,
+	date time.Time,
+) ([]*WalSegment, error) {
+	var segments []*WalSegment
+
+	if err := storage.
+		GetDb().
+		Where("database_id = ? AND created_at < ?", databaseID, date).
+		Order("created_at ASC").
+		Find(&segments).Error; err != nil {
+		return nil, err
+	}
+
+	return segments, nil
+}
+
+func (r *WalSegmentRepository) FindByStorageID(storageID uuid.UUID) ([]*WalSegment, error) {
+	var segments []*WalSegment
+
+	if err := storage.
+		GetDb().
+		Where("storage_id = ?", storageID).
+		Order("created_at ASC").
+		Find(&segments).Error; err != nil {
+		return nil, err
+	}
+
+	return segments, nil
+}
+
+// FindExistingIDs returns IDs of the given list which have a segment row
+func (r *WalSegmentRepository) FindExistingIDs(ids []uuid.UUID) ([]uuid.UUID, error) {
+	existingIDs := make([]uuid.UUID, 0)
+	if len(ids) == 0 {
+		return existingIDs, nil
+	}
+
+	if err := storage.
+		GetDb().
+		Model(&WalSegment{}).
+		Where("id IN ?", ids).
+		Pluck("id", &existingIDs).Error; err != nil {
+		return nil, err
+	}
+
+	return existingIDs, nil
+}
+
+func (r *WalSegmentRepository) CountByDatabaseID(databaseID uuid.UUID) (int64, error) {
+	var count int64
+
+	if err := storage.
+		GetDb().
+		Model(&WalSegment{}).
+		Where("database_id = ?", databaseID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *WalSegmentRepository) DeleteByID(id uuid.UUID) error {
+	return storage.GetDb().Delete(&WalSegment{}, "id = ?", id).Error
+}
@@ -0,0 +1,479 @@
+package backups_wal
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"databasus-backend/internal/features/backups/backups"
+	common "databasus-backend/internal/features/backups/backups/common"
+	backup_encryption "databasus-backend/internal/features/backups/backups/encryption"
+	backups_config "databasus-backend/internal/features/backups/config"
+	"databasus-backend/internal/features/databases"
+	encryption_secrets "databasus-backend/internal/features/encryption/secrets"
+	"databasus-backend/internal/features/storages"
+	users_models "databasus-backend/internal/features/users/models"
+	util_encryption "databasus-backend/internal/util/encryption"
+
+	"github.com/google/uuid"
+)
+
+type WalService struct {
+	walSegmentRepository *WalSegmentRepository
+	backupService        *backups.BackupService
+	backupConfigService  *backups_config.BackupConfigService
+	databaseService      *databases.DatabaseService
+	storageService       *storages.StorageService
+	secretKeyService     *encryption_secrets.SecretKeyService
+	fieldEncryptor       util_encryption.FieldEncryptor
+	logger               *slog.Logger
+}
+
+func (s *WalService) GetRecoveryWindow(
+	user *users_models.User,
+	databaseID uuid.UUID,
+) (*RecoveryWindowResponse, error) {
+	database, err := s.databaseService.GetDatabase(user, databaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	backupConfig, err := s.backupConfigService.GetBackupConfigByDbId(database.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	segmentsCount, err := s.walSegmentRepository.CountByDatabaseID(database.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &RecoveryWindowResponse{
+		IsWalArchivingEnabled: backupConfig.IsWalArchivingEnabled,
+		SegmentsCount:         segmentsCount,
+	}
+
+	oldestBaseBackup, err := s.backupService.GetOldestCompletedBackupByType(
+		database.ID,
+		common.BackupTypeBaseBackup,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if oldestBaseBackup == nil {
+		return response, nil
+	}
+
+	segments, err := s.walSegmentRepository.FindByDatabaseID(database.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	lastSegment, err := getLastContinuousSegment(segments, oldestBaseBackup.WalStartLsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastSegment == nil {
+		return response, nil
+	}
+
+	earliestRecoveryTime := oldestBaseBackup.CreatedAt.Add(
+		time.Duration(oldestBaseBackup.BackupDurationMs) * time.Millisecond,
+	)
+	if lastSegment.CompletedAt.Before(earliestRecoveryTime) {
+		return response, nil
+	}
+
+	response.EarliestRecoveryTime = &earliestRecoveryTime
+	response.LatestRecoveryTime = &lastSegment.CompletedAt
+
+	return response, nil
+}
+
+// ArchiveSegmentFile uploads completed WAL file into the storage (encrypted if
+// backups of the database are encrypted) and records it. Already archived
+// files are skipped, so the call is safe to repeat after restarts
+func (s *WalService) ArchiveSegmentFile(
+	ctx context.Context,
+	backupConfig *backups_config.BackupConfig,
+	storage *storages.Storage,
+	filePath string,
+) error {
+	fileName := filepath.Base(filePath)
+
+	existingSegment, err := s.walSegmentRepository.FindByDatabaseIDAndFileName(
+		backupConfig.DatabaseID,
+		fileName,
+	)
+	if err != nil {
+		return err
+	}
+
+	if existingSegment != nil {
+		return nil
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open WAL file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat WAL file: %w", err)
+	}
+
+	segment := &WalSegment{
+		ID:         uuid.New(),
+		DatabaseID: backupConfig.DatabaseID,
+		StorageID:  storage.ID,
+		FileName:   fileName,
+		SizeBytes:  fileInfo.Size(),
+		Encryption: backups_config.BackupEncryptionNone,
+		// pg_receivewal renames the completed file without writing it
+		// again, so its modification time is the completion time
+		CompletedAt: fileInfo.ModTime().UTC(),
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	var reader io.Reader = file
+	if backupConfig.Encryption == backups_config.BackupEncryptionEncrypted {
+		encryptedReader, err := s.encryptSegment(segment, file)
+		if err != nil {
+			return err
+		}
+
+		reader = encryptedReader
+	}
+
+	if err := storage.SaveFile(ctx, s.fieldEncryptor, s.logger, segment.ID, reader); err != nil {
+		return fmt.Errorf("failed to upload WAL file %s: %w", fileName, err)
+	}
+
+	if err := s.walSegmentRepository.Create(segment); err != nil {
+		_ = storage.DeleteFile(s.fieldEncryptor, segment.ID)
+		return err
+	}
+
+	s.logger.Info(
+		"WAL file archived",
+		"databaseId",
+		backupConfig.DatabaseID,
+		"fileName",
+		fileName,
+		"sizeBytes",
+		segment.SizeBytes,
+	)
+
+	return nil
+}
+
+// DownloadSegments downloads (and decrypts) WAL files of the database required
+// to replay from the base backup start position into targetDir. Segments are
+// selected by WAL position parsed from file names, not by upload time, because
+// segments uploaded late (e.g. after an outage) are still needed. All segments
+// after the start are downloaded and PostgreSQL stops the replay at the
+// recovery target. When startLsn is nil (base backups without recorded start
+// position) all archived WAL files are downloaded. Returns the number of
+// downloaded files
+func (s *WalService) DownloadSegments(
+	ctx context.Context,
+	databaseID uuid.UUID,
+	startLsn *string,
+	targetDir string,
+) (int, error) {
+	allSegments, err := s.walSegmentRepository.FindByDatabaseID(databaseID)
+	if err != nil {
+		return 0, err
+	}
+
+	segments, err := selectSegmentsToReplay(allSegments, startLsn)
+	if err != nil {
+		return 0, err
+	}
+
+	downloadedCount := 0
+
+	for _, segment := range segments {
+		if err := s.downloadSegment(ctx, segment, targetDir); err != nil {
+			return downloadedCount, err
+		}
+
+		downloadedCount++
+	}
+
+	return downloadedCount, nil
+}
+
+func (s *WalService) DeleteSegmentsBeforeDate(databaseID uuid.UUID, date time.Time) error {
+	segments, err := s.walSegmentRepository.FindSegmentsBeforeDate(databaseID, date)
+	if err != nil {
+		return err
+	}
+
+	for _, segment := range segments {
+		if segment.IsHistoryFile() {
+			// history files are tiny and needed to follow timeline switches
+			continue
+		}
+
+		if err := s.deleteSegment(segment); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *WalService) DeleteDatabaseSegments(databaseID uuid.UUID) error {
+	segments, err := s.walSegmentRepository.FindByDatabaseID(databaseID)
+	if err != nil {
+		return err
+	}
+
+	for _, segment := range segments {
+		if err := s.deleteSegment(segment); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *WalService) GetSegmentsByStorageID(storageID uuid.UUID) ([]*WalSegment, error) {
+	return s.walSegmentRepository.FindByStorageID(storageID)
+}
+
+// FilterExistingSegmentIDs returns IDs of the given list which belong to
+// WAL segments
+func (s *WalService) FilterExistingSegmentIDs(ids []uuid.UUID) ([]uuid.UUID, error) {
+	return s.walSegmentRepository.FindExistingIDs(ids)
+}
+
+func (s *WalService) deleteSegment(segment *WalSegment) error {
+	storage, err := s.storageService.GetStorageByID(segment.StorageID)
+	if err != nil {
+		return err
+	}
+
+	if err := storage.DeleteFile(s.fieldEncryptor, segment.ID); err != nil {
+		// proceed anyway, the same as for backups: storage
+		// may be unavailable or already cleaned up
+		s.logger.Error("Failed to delete WAL file", "fileName", segment.FileName, "error", err)
+	}
+
+	return s.walSegmentRepository.DeleteByID(segment.ID)
+}
+
+func (s *WalService) downloadSegment(
+	ctx context.Context,
+	segment *WalSegment,
+	targetDir string,
+) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("WAL download cancelled: %w", err)
+	}
+
+	storage, err := s.storageService.GetStorageByID(segment.StorageID)
+	if err != nil {
+		return err
+	}
+
+	rawReader, err := storage.GetFile(s.fieldEncryptor, segment.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get WAL file %s from storage: %w", segment.FileName, err)
+	}
+	defer func() {
+		_ = rawReader.Close()
+	}()
+
+	reader, err := s.decryptSegment(segment, rawReader)
+	if err != nil {
+		return err
+	}
+
+	targetFile, err := os.OpenFile(
+		filepath.Join(targetDir, segment.FileName),
+		os.O_CREATE|os.O_WRONLY|os.O_TRUNC,
+		0600,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create WAL file: %w", err)
+	}
+	defer func() {
+		_ = targetFile.Close()
+	}()
+
+	if _, err := io.Copy(targetFile, reader); err != nil {
+		return fmt.Errorf("failed to download WAL file %s: %w", segment.FileName, err)
+	}
+
+	return nil
+}
+
+func (s *WalService) encryptSegment(segment *WalSegment, file io.Reader) (io.Reader, error) {
+	salt, err := backup_encryption.GenerateSalt()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	nonce, err := backup_encryption.GenerateNonce()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	masterKey, err := s.secretKeyService.GetSecretKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get master key: %w", err)
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+
+	encWriter, err := backup_encryption.NewEncryptionWriter(
+		pipeWriter,
+		masterKey,
+		segment.ID,
+		salt,
+		nonce,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create encrypting writer: %w", err)
+	}
+
+	go func() {
+		_, copyErr := io.Copy(encWriter, file)
+		if copyErr == nil {
+			copyErr = encWriter.Close()
+		}
+
+		_ = pipeWriter.CloseWithError(copyErr)
+	}()
+
+	saltBase64 := base64.StdEncoding.EncodeToString(salt)
+	nonceBase64 := base64.StdEncoding.EncodeToString(nonce)
+	segment.EncryptionSalt = &saltBase64
+	segment.EncryptionIV = &nonceBase64
+	segment.Encryption = backups_config.BackupEncryptionEncrypted
+
+	return pipeReader, nil
+}
+
+func (s *WalService) decryptSegment(segment *WalSegment, reader io.Reader) (io.Reader, error) {
+	if segment.Encryption != backups_config.BackupEncryptionEncrypted {
+		return reader, nil
+	}
+
+	if segment.EncryptionSalt == nil || segment.EncryptionIV == nil {
+		return nil, errors.New("WAL file is encrypted but missing encryption metadata")
+	}
+
+	masterKey, err := s.secretKeyService.GetSecretKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get master key for decryption: %w", err)
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(*segment.EncryptionSalt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode encryption salt: %w", err)
+	}
+
+	iv, err := base64.StdEncoding.DecodeString(*segment.EncryptionIV)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode encryption IV: %w", err)
+	}
+
+	return backup_encryption.NewDecryptionReader(reader, masterKey, segment.ID, salt, iv)
+}
+
+// selectSegmentsToReplay returns segments which end after the start position
+// and all history files, they are tiny and needed to follow timeline switches
+func selectSegmentsToReplay(segments []*WalSegment, startLsn *string) ([]*WalSegment, error) {
+	if startLsn == nil {
+		return segments, nil
+	}
+
+	startPosition, err := ParseLsn(*startLsn)
+	if err != nil {
+		return nil, err
+	}
+
+	selectedSegments := make([]*WalSegment, 0, len(segments))
+
+	for _, segment := range segments {
+		_, endPosition, isSegment := segment.GetLsnRange()
+		if isSegment && endPosition <= startPosition {
+			continue
+		}
+
+		selectedSegments = append(selectedSegments, segment)
+	}
+
+	return selectedSegments, nil
+}
+
+// getLastContinuousSegment returns the last segment of the unbroken sequence
+// of segments starting at the segment which contains the start position, WAL
+// after a missing segment cannot be replayed. Returns nil when the segment
+// with the start position is not archived. When startLsn is nil the sequence
+// starts at the first archived segment
+func getLastContinuousSegment(segments []*WalSegment, startLsn *string) (*WalSegment, error) {
+	walSegments := make([]*WalSegment, 0, len(segments))
+	for _, segment := range segments {
+		if _, _, isSegment := segment.GetLsnRange(); isSegment {
+			walSegments = append(walSegments, segment)
+		}
+	}
+
+	if len(walSegments) == 0 {
+		return nil, nil
+	}
+
+	// the sort is stable, so segments of different timelines with the same
+	// position stay ordered by timeline as their file names
+	sort.SliceStable(walSegments, func(i, j int) bool {
+		iStart, _, _ := walSegments[i].GetLsnRange()
+		jStart, _, _ := walSegments[j].GetLsnRange()
+		return iStart < jStart
+	})
+
+	position, _, _ := walSegments[0].GetLsnRange()
+	if startLsn != nil {
+		startPosition, err := ParseLsn(*startLsn)
+		if err != nil {
+			return nil, err
+		}
+
+		position = startPosition
+	}
+
+	var lastSegment *WalSegment
+
+	for _, segment := range walSegments {
+		startPosition, endPosition, _ := segment.GetLsnRange()
+
+		if endPosition <= position {
+			continue
+		}
+
+		if startPosition > position {
+			break
+		}
+
+		lastSegment = segment
+		position = endPosition
+	}
+
+	return lastSegment, nil
+}
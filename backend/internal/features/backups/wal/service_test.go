@@ -0,0 +1,110 @@
+package backups_wal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SelectSegmentsToReplay_WithStartPosition_SegmentsBeforeItSkipped(t *testing.T) {
+	segments := createTestSegmentsWithTimelineSwitch()
+	startLsn := "0/2000028"
+
+	selectedSegments, err := selectSegmentsToReplay(segments, &startLsn)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"000000010000000000000002",
+		"000000010000000000000003",
+		"00000002.history",
+		"000000020000000000000003",
+	}, getFileNames(selectedSegments))
+}
+
+func Test_SelectSegmentsToReplay_WithoutStartPosition_AllSegmentsSelected(t *testing.T) {
+	segments := createTestSegmentsWithTimelineSwitch()
+
+	selectedSegments, err := selectSegmentsToReplay(segments, nil)
+
+	assert.NoError(t, err)
+	assert.Len(t, selectedSegments, len(segments))
+}
+
+func Test_GetLastContinuousSegment_WhenSegmentsUploadedLate_SegmentsPartOfSequence(t *testing.T) {
+	now := time.Now().UTC()
+
+	// segments 3 and 4 are uploaded together after an outage, the
+	// sequence is ordered by position rather than by upload time
+	segments := []*WalSegment{
+		{FileName: "000000010000000000000002", CompletedAt: now.Add(-3 * time.Hour)},
+		{FileName: "000000010000000000000003", CompletedAt: now.Add(-2 * time.Hour)},
+		{FileName: "000000010000000000000004", CompletedAt: now.Add(-1 * time.Hour)},
+	}
+	startLsn := "0/2000028"
+
+	lastSegment, err := getLastContinuousSegment(segments, &startLsn)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "000000010000000000000004", lastSegment.FileName)
+}
+
+func Test_GetLastContinuousSegment_WhenSegmentMissing_SequenceEndsBeforeIt(t *testing.T) {
+	segments := []*WalSegment{
+		{FileName: "000000010000000000000002"},
+		{FileName: "000000010000000000000003"},
+		{FileName: "000000010000000000000005"},
+	}
+	startLsn := "0/2000028"
+
+	lastSegment, err := getLastContinuousSegment(segments, &startLsn)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "000000010000000000000003", lastSegment.FileName)
+}
+
+func Test_GetLastContinuousSegment_WithTimelineSwitch_SequenceFollowsNewTimeline(t *testing.T) {
+	segments := []*WalSegment{
+		{FileName: "000000010000000000000002"},
+		{FileName: "000000010000000000000003"},
+		{FileName: "00000002.history"},
+		{FileName: "000000020000000000000003"},
+		{FileName: "000000020000000000000004"},
+	}
+	startLsn := "0/2000028"
+
+	lastSegment, err := getLastContinuousSegment(segments, &startLsn)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "000000020000000000000004", lastSegment.FileName)
+}
+
+func Test_GetLastContinuousSegment_WhenStartSegmentNotArchived_NoSegmentReturned(t *testing.T) {
+	segments := []*WalSegment{{FileName: "000000010000000000000003"}}
+	startLsn := "0/2000028"
+
+	lastSegment, err := getLastContinuousSegment(segments, &startLsn)
+
+	assert.NoError(t, err)
+	assert.Nil(t, lastSegment)
+}
+
+func createTestSegmentsWithTimelineSwitch() []*WalSegment {
+	return []*WalSegment{
+		{FileName: "000000010000000000000001"},
+		{FileName: "000000010000000000000002"},
+		{FileName: "000000010000000000000003"},
+		{FileName: "00000002.history"},
+		{FileName: "000000020000000000000003"},
+	}
+}
+
+func getFileNames(segments []*WalSegment) []string {
+	fileNames := make([]string, len(segments))
+
+	for i, segment := range segments {
+		fileNames[i] = segment.FileName
+	}
+
+	return fileNames
+}
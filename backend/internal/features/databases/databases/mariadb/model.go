@@ -15,6 +15,7 @@ import (
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type MariadbDatabase struct {
@@ -29,12 +30,32 @@ type MariadbDatabase struct {
 	Password string  `json:"password" gorm:"type:text;not null"`
 	Database *string `json:"database" gorm:"type:text"`
 	IsHttps  bool    `json:"isHttps"  gorm:"type:boolean;default:false"`
+
+	// ExcludeTables are tables of the database which are not dumped
+	ExcludeTables       []string `json:"excludeTables" gorm:"-"`
+	ExcludeTablesString string   `json:"-"             gorm:"column:exclude_tables;type:text;not null;default:''"`
 }
 
 func (m *MariadbDatabase) TableName() string {
 	return "mariadb_databases"
 }
 
+func (m *MariadbDatabase) BeforeSave(_ *gorm.DB) error {
+	m.ExcludeTablesString = strings.Join(m.ExcludeTables, ",")
+
+	return nil
+}
+
+func (m *MariadbDatabase) AfterFind(_ *gorm.DB) error {
+	if m.ExcludeTablesString != "" {
+		m.ExcludeTables = strings.Split(m.ExcludeTablesString, ",")
+	} else {
+		m.ExcludeTables = []string{}
+	}
+
+	return nil
+}
+
 func (m *MariadbDatabase) Validate() error {
 	if m.Host == "" {
 		return errors.New("host is required")
@@ -48,6 +69,66 @@ func (m *MariadbDatabase) Validate() error {
 	if m.Password == "" {
 		return errors.New("password is required")
 	}
+	for _, table := range m.ExcludeTables {
+		if table == "" || strings.Contains(table, ",") {
+			return errors.New("excluded table name cannot be empty or contain commas")
+		}
+	}
+	return nil
+}
+
+// ValidateExcludedTables checks that excluded tables exist in the live
+// database, so typos do not silently leave huge tables in the backup
+func (m *MariadbDatabase) ValidateExcludedTables(
+	logger *slog.Logger,
+	encryptor encryption.FieldEncryptor,
+	databaseID uuid.UUID,
+) error {
+	if len(m.ExcludeTables) == 0 {
+		return nil
+	}
+
+	if m.Database == nil || *m.Database == "" {
+		return errors.New("database name is required to validate excluded tables")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	password, err := decryptPasswordIfNeeded(m.Password, encryptor, databaseID)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt password: %w", err)
+	}
+
+	db, err := sql.Open("mysql", m.buildDSN(password, *m.Database))
+	if err != nil {
+		return fmt.Errorf("failed to connect to MariaDB database '%s': %w", *m.Database, err)
+	}
+	defer func() {
+		if closeErr := db.Close(); closeErr != nil {
+			logger.Error("Failed to close MariaDB connection", "error", closeErr)
+		}
+	}()
+
+	db.SetMaxOpenConns(1)
+
+	for _, table := range m.ExcludeTables {
+		var count int
+		err := db.QueryRowContext(
+			ctx,
+			"SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = ? AND table_name = ?",
+			*m.Database,
+			table,
+		).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("failed to check excluded table '%s': %w", table, err)
+		}
+
+		if count == 0 {
+			return fmt.Errorf("excluded table '%s' does not exist in the database", table)
+		}
+	}
+
 	return nil
 }
 
@@ -111,6 +192,7 @@ func (m *MariadbDatabase) Update(incoming *MariadbDatabase) {
 	m.Username = incoming.Username
 	m.Database = incoming.Database
 	m.IsHttps = incoming.IsHttps
+	m.ExcludeTables = incoming.ExcludeTables
 
 	if incoming.Password != "" {
 		m.Password = incoming.Password
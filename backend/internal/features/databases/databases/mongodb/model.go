@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"log/slog"
 	"regexp"
+	"slices"
+	"strings"
 	"time"
 
 	"databasus-backend/internal/util/encryption"
@@ -15,6 +17,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
 )
 
 type MongodbDatabase struct {
@@ -31,12 +34,32 @@ type MongodbDatabase struct {
 	AuthDatabase string `json:"authDatabase" gorm:"type:text;not null;default:'admin'"`
 	IsHttps      bool   `json:"isHttps"      gorm:"type:boolean;default:false"`
 	CpuCount     int    `json:"cpuCount"     gorm:"column:cpu_count;type:int;not null;default:1"`
+
+	// ExcludeCollections are collections of the database which are not dumped
+	ExcludeCollections       []string `json:"excludeCollections" gorm:"-"`
+	ExcludeCollectionsString string   `json:"-"                  gorm:"column:exclude_collections;type:text;not null;default:''"`
 }
 
 func (m *MongodbDatabase) TableName() string {
 	return "mongodb_databases"
 }
 
+func (m *MongodbDatabase) BeforeSave(_ *gorm.DB) error {
+	m.ExcludeCollectionsString = strings.Join(m.ExcludeCollections, ",")
+
+	return nil
+}
+
+func (m *MongodbDatabase) AfterFind(_ *gorm.DB) error {
+	if m.ExcludeCollectionsString != "" {
+		m.ExcludeCollections = strings.Split(m.ExcludeCollectionsString, ",")
+	} else {
+		m.ExcludeCollections = []string{}
+	}
+
+	return nil
+}
+
 func (m *MongodbDatabase) Validate() error {
 	if m.Host == "" {
 		return errors.New("host is required")
@@ -56,6 +79,57 @@ func (m *MongodbDatabase) Validate() error {
 	if m.CpuCount <= 0 {
 		return errors.New("cpu count must be greater than 0")
 	}
+	for _, collection := range m.ExcludeCollections {
+		if collection == "" || strings.Contains(collection, ",") {
+			return errors.New("excluded collection name cannot be empty or contain commas")
+		}
+	}
+	return nil
+}
+
+// ValidateExcludedCollections checks that excluded collections exist in the
+// live database, so typos do not silently leave huge collections in the backup
+func (m *MongodbDatabase) ValidateExcludedCollections(
+	logger *slog.Logger,
+	encryptor encryption.FieldEncryptor,
+	databaseID uuid.UUID,
+) error {
+	if len(m.ExcludeCollections) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	password, err := decryptPasswordIfNeeded(m.Password, encryptor, databaseID)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt password: %w", err)
+	}
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(m.buildConnectionURI(password)))
+	if err != nil {
+		return fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+	defer func() {
+		if disconnectErr := client.Disconnect(ctx); disconnectErr != nil {
+			logger.Error("Failed to disconnect from MongoDB", "error", disconnectErr)
+		}
+	}()
+
+	collections, err := client.Database(m.Database).ListCollectionNames(ctx, bson.D{})
+	if err != nil {
+		return fmt.Errorf("failed to list collections of database '%s': %w", m.Database, err)
+	}
+
+	for _, collection := range m.ExcludeCollections {
+		if !slices.Contains(collections, collection) {
+			return fmt.Errorf(
+				"excluded collection '%s' does not exist in the database",
+				collection,
+			)
+		}
+	}
+
 	return nil
 }
 
@@ -114,6 +188,7 @@ func (m *MongodbDatabase) Update(incoming *MongodbDatabase) {
 	m.AuthDatabase = incoming.AuthDatabase
 	m.IsHttps = incoming.IsHttps
 	m.CpuCount = incoming.CpuCount
+	m.ExcludeCollections = incoming.ExcludeCollections
 
 	if incoming.Password != "" {
 		m.Password = incoming.Password
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"log/slog"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -36,6 +37,13 @@ type PostgresqlDatabase struct {
 	IncludeSchemasString string   `json:"-"              gorm:"column:include_schemas;type:text;not null;default:''"`
 	CpuCount             int      `json:"cpuCount"       gorm:"column:cpu_count;type:int;not null;default:1"`
 
+	// ExcludeTables are not dumped at all, ExcludeTableData are dumped without
+	// data (DDL only), e.g. huge audit or log tables. Format is "schema.table"
+	ExcludeTables          []string `json:"excludeTables"    gorm:"-"`
+	ExcludeTablesString    string   `json:"-"                gorm:"column:exclude_tables;type:text;not null;default:''"`
+	ExcludeTableData       []string `json:"excludeTableData" gorm:"-"`
+	ExcludeTableDataString string   `json:"-"                gorm:"column:exclude_table_data;type:text;not null;default:''"`
+
 	// restore settings (not saved to DB)
 	IsExcludeExtensions bool `json:"isExcludeExtensions" gorm:"-"`
 }
@@ -51,6 +59,9 @@ func (p *PostgresqlDatabase) BeforeSave(_ *gorm.DB) error {
 		p.IncludeSchemasString = ""
 	}
 
+	p.ExcludeTablesString = strings.Join(p.ExcludeTables, ",")
+	p.ExcludeTableDataString = strings.Join(p.ExcludeTableData, ",")
+
 	return nil
 }
 
@@ -61,6 +72,9 @@ func (p *PostgresqlDatabase) AfterFind(_ *gorm.DB) error {
 		p.IncludeSchemas = []string{}
 	}
 
+	p.ExcludeTables = splitList(p.ExcludeTablesString)
+	p.ExcludeTableData = splitList(p.ExcludeTableDataString)
+
 	return nil
 }
 
@@ -85,9 +99,155 @@ func (p *PostgresqlDatabase) Validate() error {
 		return errors.New("cpu count must be greater than 0")
 	}
 
+	for _, table := range append(slices.Clone(p.ExcludeTables), p.ExcludeTableData...) {
+		if _, _, err := SplitQualifiedTableName(table); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateExcludedTables checks that excluded tables exist in the live
+// database, so typos do not silently leave huge tables in the backup
+func (p *PostgresqlDatabase) ValidateExcludedTables(
+	logger *slog.Logger,
+	encryptor encryption.FieldEncryptor,
+	databaseID uuid.UUID,
+) error {
+	tables := append(slices.Clone(p.ExcludeTables), p.ExcludeTableData...)
+	if len(tables) == 0 {
+		return nil
+	}
+
+	if p.Database == nil || *p.Database == "" {
+		return errors.New("database name is required to validate excluded tables")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	password, err := decryptPasswordIfNeeded(p.Password, encryptor, databaseID)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt password: %w", err)
+	}
+
+	conn, err := pgx.Connect(ctx, buildConnectionStringForDB(p, *p.Database, password))
+	if err != nil {
+		return fmt.Errorf("failed to connect to database '%s': %w", *p.Database, err)
+	}
+	defer func() {
+		if closeErr := conn.Close(ctx); closeErr != nil {
+			logger.Error("Failed to close connection", "error", closeErr)
+		}
+	}()
+
+	for _, table := range tables {
+		schemaName, tableName, err := SplitQualifiedTableName(table)
+		if err != nil {
+			return err
+		}
+
+		var isExists bool
+		err = conn.QueryRow(
+			ctx,
+			`SELECT EXISTS (
+				SELECT 1 FROM pg_catalog.pg_class c
+				JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+				WHERE n.nspname = $1 AND c.relname = $2
+				AND c.relkind IN ('r', 'p', 'm', 'f')
+			)`,
+			schemaName,
+			tableName,
+		).Scan(&isExists)
+		if err != nil {
+			return fmt.Errorf("failed to check excluded table '%s': %w", table, err)
+		}
+
+		if !isExists {
+			return fmt.Errorf("excluded table '%s' does not exist in the database", table)
+		}
+	}
+
 	return nil
 }
 
+// SplitQualifiedTableName splits "schema.table" into schema and table names
+func SplitQualifiedTableName(qualifiedName string) (string, string, error) {
+	schemaName, tableName, ok := strings.Cut(qualifiedName, ".")
+	if !ok || schemaName == "" || tableName == "" {
+		return "", "", fmt.Errorf(
+			"excluded table '%s' must be specified in format schema.table",
+			qualifiedName,
+		)
+	}
+
+	if strings.Contains(qualifiedName, ",") {
+		return "", "", fmt.Errorf("excluded table '%s' cannot contain commas", qualifiedName)
+	}
+
+	return schemaName, tableName, nil
+}
+
+// ListServerDatabases returns names of all databases on the server which
+// accept connections, template databases are skipped. The configured
+// database (or "postgres" if not set) is used as maintenance connection
+func (p *PostgresqlDatabase) ListServerDatabases(
+	logger *slog.Logger,
+	encryptor encryption.FieldEncryptor,
+	databaseID uuid.UUID,
+) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	password, err := decryptPasswordIfNeeded(p.Password, encryptor, databaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt password: %w", err)
+	}
+
+	maintenanceDB := "postgres"
+	if p.Database != nil && *p.Database != "" {
+		maintenanceDB = *p.Database
+	}
+
+	conn, err := pgx.Connect(ctx, buildConnectionStringForDB(p, maintenanceDB, password))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database '%s': %w", maintenanceDB, err)
+	}
+	defer func() {
+		if closeErr := conn.Close(ctx); closeErr != nil {
+			logger.Error("Failed to close connection", "error", closeErr)
+		}
+	}()
+
+	rows, err := conn.Query(
+		ctx,
+		`SELECT datname FROM pg_catalog.pg_database
+		WHERE NOT datistemplate AND datallowconn
+		ORDER BY datname`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list databases: %w", err)
+	}
+	defer rows.Close()
+
+	databaseNames := []string{}
+	for rows.Next() {
+		var databaseName string
+		if err := rows.Scan(&databaseName); err != nil {
+			return nil, fmt.Errorf("failed to scan database name: %w", err)
+		}
+
+		databaseNames = append(databaseNames, databaseName)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list databases: %w", err)
+	}
+
+	return databaseNames, nil
+}
+
 func (p *PostgresqlDatabase) TestConnection(
 	logger *slog.Logger,
 	encryptor encryption.FieldEncryptor,
@@ -116,6 +276,8 @@ func (p *PostgresqlDatabase) Update(incoming *PostgresqlDatabase) {
 	p.IsHttps = incoming.IsHttps
 	p.IncludeSchemas = incoming.IncludeSchemas
 	p.CpuCount = incoming.CpuCount
+	p.ExcludeTables = incoming.ExcludeTables
+	p.ExcludeTableData = incoming.ExcludeTableData
 
 	if incoming.Password != "" {
 		p.Password = incoming.Password
@@ -727,3 +889,11 @@ func extractSupabaseProjectID(username string) string {
 	}
 	return ""
 }
+
+func splitList(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+
+	return strings.Split(value, ",")
+}
@@ -0,0 +1,57 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Validate_WhenExcludedTableHasNoSchema_ReturnsError(t *testing.T) {
+	database := createValidPostgresqlDatabase()
+	database.ExcludeTableData = []string{"audit_log"}
+
+	err := database.Validate()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "schema.table")
+}
+
+func Test_Validate_WhenExcludedTablesQualified_ReturnsNoError(t *testing.T) {
+	database := createValidPostgresqlDatabase()
+	database.ExcludeTables = []string{"public.sessions"}
+	database.ExcludeTableData = []string{"audit.Log_Entries"}
+
+	assert.NoError(t, database.Validate())
+}
+
+func Test_BeforeSaveAndAfterFind_WhenExcludedTablesSet_ListsRoundTripped(t *testing.T) {
+	database := createValidPostgresqlDatabase()
+	database.ExcludeTables = []string{"public.sessions", "public.cache"}
+
+	require.NoError(t, database.BeforeSave(nil))
+	assert.Equal(t, "public.sessions,public.cache", database.ExcludeTablesString)
+	assert.Equal(t, "", database.ExcludeTableDataString)
+
+	loaded := &PostgresqlDatabase{
+		ExcludeTablesString:    database.ExcludeTablesString,
+		ExcludeTableDataString: database.ExcludeTableDataString,
+	}
+	require.NoError(t, loaded.AfterFind(nil))
+
+	assert.Equal(t, []string{"public.sessions", "public.cache"}, loaded.ExcludeTables)
+	assert.Equal(t, []string{}, loaded.ExcludeTableData)
+}
+
+func createValidPostgresqlDatabase() *PostgresqlDatabase {
+	databaseName := "app"
+
+	return &PostgresqlDatabase{
+		Host:     "localhost",
+		Port:     5432,
+		Username: "postgres",
+		Password: "postgres",
+		Database: &databaseName,
+		CpuCount: 1,
+	}
+}
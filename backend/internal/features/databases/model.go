@@ -9,6 +9,8 @@ import (
 	"databasus-backend/internal/util/encryption"
 	"errors"
 	"log/slog"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -84,6 +86,34 @@ func (d *Database) TestConnection(
 	return d.getSpecificDatabase().TestConnection(logger, encryptor, d.ID)
 }
 
+// ValidateExcludedObjects checks excluded tables and collections against the
+// live database. Nothing is checked when there are no exclusions
+func (d *Database) ValidateExcludedObjects(
+	logger *slog.Logger,
+	encryptor encryption.FieldEncryptor,
+) error {
+	switch d.Type {
+	case DatabaseTypePostgres:
+		if d.Postgresql != nil {
+			return d.Postgresql.ValidateExcludedTables(logger, encryptor, d.ID)
+		}
+	case DatabaseTypeMysql:
+		if d.Mysql != nil {
+			return d.Mysql.ValidateExcludedTables(logger, encryptor, d.ID)
+		}
+	case DatabaseTypeMariadb:
+		if d.Mariadb != nil {
+			return d.Mariadb.ValidateExcludedTables(logger, encryptor, d.ID)
+		}
+	case DatabaseTypeMongodb:
+		if d.Mongodb != nil {
+			return d.Mongodb.ValidateExcludedCollections(logger, encryptor, d.ID)
+		}
+	}
+
+	return nil
+}
+
 func (d *Database) HideSensitiveData() {
 	d.getSpecificDatabase().HideSensitiveData()
 }
@@ -148,6 +178,38 @@ func (d *Database) Update(incoming *Database) {
 	}
 }
 
+// GetHostKey returns "host:port" of the database server, so work hitting the
+// same server can be limited. Falls back to the database ID if not known
+func (d *Database) GetHostKey() string {
+	var host string
+	var port int
+
+	switch d.Type {
+	case DatabaseTypePostgres:
+		if d.Postgresql != nil {
+			host, port = d.Postgresql.Host, d.Postgresql.Port
+		}
+	case DatabaseTypeMysql:
+		if d.Mysql != nil {
+			host, port = d.Mysql.Host, d.Mysql.Port
+		}
+	case DatabaseTypeMariadb:
+		if d.Mariadb != nil {
+			host, port = d.Mariadb.Host, d.Mariadb.Port
+		}
+	case DatabaseTypeMongodb:
+		if d.Mongodb != nil {
+			host, port = d.Mongodb.Host, d.Mongodb.Port
+		}
+	}
+
+	if host == "" {
+		return d.ID.String()
+	}
+
+	return strings.ToLower(host) + ":" + strconv.Itoa(port)
+}
+
 func (d *Database) getSpecificDatabase() DatabaseConnector {
 	switch d.Type {
 	case DatabaseTypePostgres:
@@ -82,6 +82,10 @@ func (s *DatabaseService) CreateDatabase(
 		return nil, err
 	}
 
+	if err := database.ValidateExcludedObjects(s.logger, s.fieldEncryptor); err != nil {
+		return nil, err
+	}
+
 	if err := database.PopulateVersionIfEmpty(s.logger, s.fieldEncryptor); err != nil {
 		return nil, fmt.Errorf("failed to auto-detect database version: %w", err)
 	}
@@ -149,6 +153,10 @@ func (s *DatabaseService) UpdateDatabase(
 		return err
 	}
 
+	if err := existingDatabase.ValidateExcludedObjects(s.logger, s.fieldEncryptor); err != nil {
+		return err
+	}
+
 	if err := existingDatabase.PopulateVersionIfEmpty(s.logger, s.fieldEncryptor); err != nil {
 		return fmt.Errorf("failed to auto-detect database version: %w", err)
 	}
@@ -441,6 +449,9 @@ func (s *DatabaseService) CopyDatabase(
 				IsHttps:        existingDatabase.Postgresql.IsHttps,
 				IncludeSchemas: existingDatabase.Postgresql.IncludeSchemas,
 				CpuCount:       existingDatabase.Postgresql.CpuCount,
+
+				ExcludeTables:    existingDatabase.Postgresql.ExcludeTables,
+				ExcludeTableData: existingDatabase.Postgresql.ExcludeTableData,
 			}
 		}
 	case DatabaseTypeMysql:
@@ -455,6 +466,8 @@ func (s *DatabaseService) CopyDatabase(
 				Password:   existingDatabase.Mysql.Password,
 				Database:   existingDatabase.Mysql.Database,
 				IsHttps:    existingDatabase.Mysql.IsHttps,
+
+				ExcludeTables: existingDatabase.Mysql.ExcludeTables,
 			}
 		}
 	case DatabaseTypeMariadb:
@@ -469,6 +482,8 @@ func (s *DatabaseService) CopyDatabase(
 				Password:   existingDatabase.Mariadb.Password,
 				Database:   existingDatabase.Mariadb.Database,
 				IsHttps:    existingDatabase.Mariadb.IsHttps,
+
+				ExcludeTables: existingDatabase.Mariadb.ExcludeTables,
 			}
 		}
 	case DatabaseTypeMongodb:
@@ -485,6 +500,8 @@ func (s *DatabaseService) CopyDatabase(
 				AuthDatabase: existingDatabase.Mongodb.AuthDatabase,
 				IsHttps:      existingDatabase.Mongodb.IsHttps,
 				CpuCount:     existingDatabase.Mongodb.CpuCount,
+
+				ExcludeCollections: existingDatabase.Mongodb.ExcludeCollections,
 			}
 		}
 	}
@@ -3,6 +3,7 @@ package disk
 import (
 	"databasus-backend/internal/config"
 	"fmt"
+	"os"
 	"path/filepath"
 	"runtime"
 
@@ -24,6 +25,30 @@ func (s *DiskService) GetDiskUsage() (*DiskUsage, error) {
 		path = filepath.Dir(cfg.DataFolder) // Gets /databasus-data from /databasus-data/backups
 	}
 
+	return s.getDiskUsage(platform, path)
+}
+
+// GetDiskUsageForPath returns usage of the disk the path is located on. The
+// path may not exist yet, then the nearest existing parent directory is used
+func (s *DiskService) GetDiskUsageForPath(path string) (*DiskUsage, error) {
+	existingPath := filepath.Clean(path)
+	for {
+		if _, err := os.Stat(existingPath); err == nil {
+			break
+		}
+
+		parentPath := filepath.Dir(existingPath)
+		if parentPath == existingPath {
+			return nil, fmt.Errorf("no existing parent directory for path %s", path)
+		}
+
+		existingPath = parentPath
+	}
+
+	return s.getDiskUsage(s.detectPlatform(), existingPath)
+}
+
+func (s *DiskService) getDiskUsage(platform Platform, path string) (*DiskUsage, error) {
 	diskUsage, err := disk.Usage(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get disk usage for path %s: %w", path, err)
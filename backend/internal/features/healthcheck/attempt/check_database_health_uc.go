@@ -17,6 +17,7 @@ type CheckDatabaseHealthUseCase struct {
 	healthcheckAttemptRepository *HealthcheckAttemptRepository
 	healthcheckAttemptSender     HealthcheckAttemptSender
 	databaseService              DatabaseService
+	backupPauseService           BackupPauseService
 }
 
 func (uc *CheckDatabaseHealthUseCase) Execute(
@@ -233,6 +234,21 @@ func (uc *CheckDatabaseHealthUseCase) sendDbStatusNotification(
 		return
 	}
 
+	// databases of paused workspaces are expected to be unavailable (e.g.
+	// during a migration), so the status is updated silently
+	pause, err := uc.backupPauseService.GetActivePause(database.WorkspaceID)
+	if err != nil {
+		logger.GetLogger().Error(
+			"Failed to check backups pause",
+			slog.String("database_id", database.ID.String()),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	if pause != nil {
+		return
+	}
+
 	messageTitle := ""
 	messageBody := ""
 
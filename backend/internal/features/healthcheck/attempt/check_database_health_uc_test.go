@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	backups_pauses "databasus-backend/internal/features/backups/pauses"
 	"databasus-backend/internal/features/databases"
 	healthcheck_config "databasus-backend/internal/features/healthcheck/config"
 	"databasus-backend/internal/features/notifiers"
@@ -69,6 +70,7 @@ func Test_CheckDatabaseHealthUseCase(t *testing.T) {
 			healthcheckAttemptRepository: &HealthcheckAttemptRepository{},
 			healthcheckAttemptSender:     mockSender,
 			databaseService:              mockDatabaseService,
+			backupPauseService:           createMockBackupPauseService(nil),
 		}
 
 		// Execute healthcheck
@@ -102,6 +104,58 @@ func Test_CheckDatabaseHealthUseCase(t *testing.T) {
 		)
 	})
 
+	t.Run("Test_WorkspacePaused_DbMarkedAsUnavailableWithoutNotification", func(t *testing.T) {
+		database := databases.CreateTestDatabase(workspace.ID, storage, notifier)
+		defer databases.RemoveTestDatabase(database)
+
+		mockSender := &MockHealthcheckAttemptSender{}
+
+		mockDatabaseService := &MockDatabaseService{}
+		mockDatabaseService.On("TestDatabaseConnectionDirect", database).
+			Return(errors.New("test error"))
+		unavailableStatus := databases.HealthStatusUnavailable
+		mockDatabaseService.On("SetHealthStatus", database.ID, &unavailableStatus).
+			Return(nil)
+		mockDatabaseService.On("GetDatabaseByID", database.ID).
+			Return(database, nil)
+
+		healthcheckConfig := &healthcheck_config.HealthcheckConfig{
+			DatabaseID:                        database.ID,
+			IsHealthcheckEnabled:              true,
+			IsSentNotificationWhenUnavailable: true,
+			IntervalMinutes:                   1,
+			AttemptsBeforeConcideredAsDown:    1,
+			StoreAttemptsDays:                 7,
+		}
+
+		useCase := &CheckDatabaseHealthUseCase{
+			healthcheckAttemptRepository: &HealthcheckAttemptRepository{},
+			healthcheckAttemptSender:     mockSender,
+			databaseService:              mockDatabaseService,
+			backupPauseService: createMockBackupPauseService(&backups_pauses.BackupPause{
+				WorkspaceID: &workspace.ID,
+				Reason:      "Migration",
+			}),
+		}
+
+		err := useCase.Execute(time.Now().UTC(), healthcheckConfig)
+		assert.NoError(t, err)
+
+		mockDatabaseService.AssertCalled(
+			t,
+			"SetHealthStatus",
+			database.ID,
+			&unavailableStatus,
+		)
+		mockSender.AssertNotCalled(
+			t,
+			"SendNotification",
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+		)
+	})
+
 	t.Run(
 		"Test_DbShouldBeConsideredAsDownOnThirdFailedAttempt_DbNotMarkerdAsDownAfterFirstAttempt",
 		func(t *testing.T) {
@@ -133,6 +187,7 @@ func Test_CheckDatabaseHealthUseCase(t *testing.T) {
 				healthcheckAttemptRepository: &HealthcheckAttemptRepository{},
 				healthcheckAttemptSender:     mockSender,
 				databaseService:              mockDatabaseService,
+				backupPauseService:           createMockBackupPauseService(nil),
 			}
 
 			// Execute first healthcheck
@@ -210,6 +265,7 @@ func Test_CheckDatabaseHealthUseCase(t *testing.T) {
 				healthcheckAttemptRepository: &HealthcheckAttemptRepository{},
 				healthcheckAttemptSender:     mockSender,
 				databaseService:              mockDatabaseService,
+				backupPauseService:           createMockBackupPauseService(nil),
 			}
 
 			// Execute three failed healthchecks
@@ -286,6 +342,7 @@ func Test_CheckDatabaseHealthUseCase(t *testing.T) {
 			healthcheckAttemptRepository: &HealthcheckAttemptRepository{},
 			healthcheckAttemptSender:     mockSender,
 			databaseService:              mockDatabaseService,
+			backupPauseService:           createMockBackupPauseService(nil),
 		}
 
 		// Execute healthcheck (should succeed)
@@ -353,6 +410,7 @@ func Test_CheckDatabaseHealthUseCase(t *testing.T) {
 				healthcheckAttemptRepository: &HealthcheckAttemptRepository{},
 				healthcheckAttemptSender:     mockSender,
 				databaseService:              mockDatabaseService,
+				backupPauseService:           createMockBackupPauseService(nil),
 			}
 
 			// Execute first healthcheck
@@ -424,3 +482,10 @@ func Test_CheckDatabaseHealthUseCase(t *testing.T) {
 		},
 	)
 }
+
+func createMockBackupPauseService(pause *backups_pauses.BackupPause) *MockBackupPauseService {
+	mockBackupPauseService := &MockBackupPauseService{}
+	mockBackupPauseService.On("GetActivePause", mock.Anything).Return(pause, nil)
+
+	return mockBackupPauseService
+}
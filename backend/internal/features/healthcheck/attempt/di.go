@@ -1,6 +1,7 @@
 package healthcheck_attempt
 
 import (
+	backups_pauses "databasus-backend/internal/features/backups/pauses"
 	"databasus-backend/internal/features/databases"
 	healthcheck_config "databasus-backend/internal/features/healthcheck/config"
 	"databasus-backend/internal/features/notifiers"
@@ -19,6 +20,7 @@ var checkDatabaseHealthUseCase = &CheckDatabaseHealthUseCase{
 	healthcheckAttemptRepository,
 	notifiers.GetNotifierService(),
 	databases.GetDatabaseService(),
+	backups_pauses.GetBackupPauseService(),
 }
 
 var healthcheckAttemptBackgroundService = &HealthcheckAttemptBackgroundService{
@@ -1,6 +1,7 @@
 package healthcheck_attempt
 
 import (
+	backups_pauses "databasus-backend/internal/features/backups/pauses"
 	"databasus-backend/internal/features/databases"
 	"databasus-backend/internal/features/notifiers"
 
@@ -25,3 +26,7 @@ type DatabaseService interface {
 		healthStatus *databases.HealthStatus,
 	) error
 }
+
+type BackupPauseService interface {
+	GetActivePause(workspaceID *uuid.UUID) (*backups_pauses.BackupPause, error)
+}
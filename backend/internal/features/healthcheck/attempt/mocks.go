@@ -1,6 +1,7 @@
 package healthcheck_attempt
 
 import (
+	backups_pauses "databasus-backend/internal/features/backups/pauses"
 	"databasus-backend/internal/features/databases"
 	"databasus-backend/internal/features/notifiers"
 
@@ -53,3 +54,24 @@ func (m *MockDatabaseService) GetDatabaseByID(
 
 	return database, args.Error(1)
 }
+
+type MockBackupPauseService struct {
+	mock.Mock
+}
+
+func (m *MockBackupPauseService) GetActivePause(
+	workspaceID *uuid.UUID,
+) (*backups_pauses.BackupPause, error) {
+	args := m.Called(workspaceID)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	pause, ok := args.Get(0).(*backups_pauses.BackupPause)
+	if !ok {
+		return nil, args.Error(1)
+	}
+
+	return pause, args.Error(1)
+}
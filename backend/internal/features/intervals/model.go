@@ -3,6 +3,8 @@ package intervals
 import (
 	"errors"
 	"time"
+	// embeds the timezone database, so schedules work in images without it
+	_ "time/tzdata"
 
 	"github.com/google/uuid"
 	"github.com/robfig/cron/v3"
@@ -20,9 +22,17 @@ type Interval struct {
 	DayOfMonth *int `json:"dayOfMonth,omitempty"     gorm:"type:int"`
 	// only for CRON
 	CronExpression *string `json:"cronExpression,omitempty" gorm:"type:text"`
+
+	// IANA timezone TimeOfDay, Weekday, DayOfMonth and CronExpression are
+	// evaluated in, e.g. "Europe/Berlin". Empty means UTC
+	Timezone string `json:"timezone" gorm:"type:text;not null;default:'UTC'"`
 }
 
 func (i *Interval) BeforeSave(tx *gorm.DB) error {
+	if i.Timezone == "" {
+		i.Timezone = "UTC"
+	}
+
 	return i.Validate()
 }
 
@@ -43,6 +53,10 @@ func (i *Interval) Validate() error {
 		return errors.New("day of month is required for monthly intervals")
 	}
 
+	if _, err := i.GetLocation(); err != nil {
+		return err
+	}
+
 	// for cron interval cron expression is required and must be valid
 	if i.Interval == IntervalCron {
 		if i.CronExpression == nil || *i.CronExpression == "" {
@@ -63,6 +77,15 @@ func (i *Interval) ShouldTriggerBackup(now time.Time, lastBackupTime *time.Time)
 		return true
 	}
 
+	location, err := i.GetLocation()
+	if err != nil {
+		return false // malformed ⇒ play safe
+	}
+
+	now = now.In(location)
+	lastBackup := lastBackupTime.In(location)
+	lastBackupTime = &lastBackup
+
 	switch i.Interval {
 	case IntervalHourly:
 		return now.Sub(*lastBackupTime) >= time.Hour
@@ -79,6 +102,94 @@ func (i *Interval) ShouldTriggerBackup(now time.Time, lastBackupTime *time.Time)
 	}
 }
 
+// NextRunAfter returns the first scheduled run strictly after the given
+// time. For intervals without a fixed slot (hourly, weekly without weekday,
+// etc.) the run is counted from the given time, usually the last backup
+func (i *Interval) NextRunAfter(after time.Time) (time.Time, error) {
+	hour, minute, err := i.getTimeOfDay()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	location, err := i.GetLocation()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	after = after.In(location)
+
+	switch i.Interval {
+	case IntervalHourly:
+		return after.Add(time.Hour), nil
+	case IntervalDaily:
+		if i.TimeOfDay == nil {
+			return getWallClockTime(after.Year(), after.Month(), after.Day()+1, 0, 0, location), nil
+		}
+
+		next := getWallClockTime(after.Year(), after.Month(), after.Day(), hour, minute, location)
+		if !next.After(after) {
+			next = getWallClockTime(
+				after.Year(), after.Month(), after.Day()+1,
+				hour, minute, location,
+			)
+		}
+
+		return next, nil
+	case IntervalWeekly:
+		if i.Weekday == nil {
+			return after.Add(7 * 24 * time.Hour), nil
+		}
+
+		daysAhead := (*i.Weekday - int(after.Weekday()) + 7) % 7
+		next := getWallClockTime(
+			after.Year(), after.Month(), after.Day()+daysAhead,
+			hour, minute, location,
+		)
+		if !next.After(after) {
+			next = getWallClockTime(
+				after.Year(), after.Month(), after.Day()+daysAhead+7,
+				hour, minute, location,
+			)
+		}
+
+		return next, nil
+	case IntervalMonthly:
+		if i.DayOfMonth == nil {
+			return getWallClockTime(after.Year(), after.Month()+1, 1, 0, 0, location), nil
+		}
+
+		for monthsAhead := 0; monthsAhead <= 12; monthsAhead++ {
+			monthStart := getStartOfMonth(after).AddDate(0, monthsAhead, 0)
+
+			// short months run on their last day
+			day := min(*i.DayOfMonth, monthStart.AddDate(0, 1, -1).Day())
+			next := getWallClockTime(
+				monthStart.Year(), monthStart.Month(), day,
+				hour, minute, location,
+			)
+			if next.After(after) {
+				return next, nil
+			}
+		}
+
+		return time.Time{}, errors.New("cannot find next run of monthly interval")
+	case IntervalCron:
+		if i.CronExpression == nil || *i.CronExpression == "" {
+			return time.Time{}, errors.New("cron expression is required for cron intervals")
+		}
+
+		parser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+		schedule, err := parser.Parse(*i.CronExpression)
+		if err != nil {
+			return time.Time{}, errors.New("invalid cron expression: " + err.Error())
+		}
+
+		return schedule.Next(after), nil
+	default:
+		return time.Time{}, errors.New("unknown interval type")
+	}
+}
+
 func (i *Interval) Copy() *Interval {
 	return &Interval{
 		ID:             uuid.Nil,
@@ -87,6 +198,7 @@ func (i *Interval) Copy() *Interval {
 		Weekday:        i.Weekday,
 		DayOfMonth:     i.DayOfMonth,
 		CronExpression: i.CronExpression,
+		Timezone:       i.Timezone,
 	}
 }
 
@@ -102,15 +214,18 @@ func (i *Interval) shouldTriggerDaily(now, lastBackup time.Time) bool {
 	}
 
 	// Today's scheduled slot (todayTgt)
-	todayTgt := time.Date(
+	todayTgt := getWallClockTime(
 		now.Year(), now.Month(), now.Day(),
-		t.Hour(), t.Minute(), 0, 0, now.Location(),
+		t.Hour(), t.Minute(), now.Location(),
 	)
 
 	// The last scheduled slot that should already have happened
 	var lastScheduled time.Time
 	if now.Before(todayTgt) {
-		lastScheduled = todayTgt.AddDate(0, 0, -1)
+		lastScheduled = getWallClockTime(
+			now.Year(), now.Month(), now.Day()-1,
+			t.Hour(), t.Minute(), now.Location(),
+		)
 	} else {
 		lastScheduled = todayTgt
 	}
@@ -136,20 +251,21 @@ func (i *Interval) shouldTriggerWeekly(now, lastBackup time.Time) bool {
 			daysFromMonday = int(targetWd) - 1
 		}
 
-		targetThisWeek := startOfWeek.AddDate(0, 0, daysFromMonday)
+		targetThisWeek := getWallClockTime(
+			startOfWeek.Year(), startOfWeek.Month(), startOfWeek.Day()+daysFromMonday,
+			0, 0, now.Location(),
+		)
 
 		if i.TimeOfDay != nil {
 			t, err := time.Parse("15:04", *i.TimeOfDay)
 			if err == nil {
-				targetThisWeek = time.Date(
-					targetThisWeek.Year(),
-					targetThisWeek.Month(),
-					targetThisWeek.Day(),
+				targetThisWeek = getWallClockTime(
+					startOfWeek.Year(),
+					startOfWeek.Month(),
+					startOfWeek.Day()+daysFromMonday,
 					t.Hour(),
 					t.Minute(),
-					0,
-					0,
-					targetThisWeek.Location(),
+					now.Location(),
 				)
 			}
 		}
@@ -173,20 +289,18 @@ func (i *Interval) shouldTriggerMonthly(now, lastBackup time.Time) bool {
 		day := *i.DayOfMonth
 
 		// Calculate the target datetime for this month
-		targetThisMonth := time.Date(now.Year(), now.Month(), day, 0, 0, 0, 0, now.Location())
+		targetThisMonth := getWallClockTime(now.Year(), now.Month(), day, 0, 0, now.Location())
 
 		if i.TimeOfDay != nil {
 			t, err := time.Parse("15:04", *i.TimeOfDay)
 			if err == nil {
-				targetThisMonth = time.Date(
-					targetThisMonth.Year(),
-					targetThisMonth.Month(),
-					targetThisMonth.Day(),
+				targetThisMonth = getWallClockTime(
+					now.Year(),
+					now.Month(),
+					day,
 					t.Hour(),
 					t.Minute(),
-					0,
-					0,
-					targetThisMonth.Location(),
+					now.Location(),
 				)
 			}
 		}
@@ -203,6 +317,83 @@ func (i *Interval) shouldTriggerMonthly(now, lastBackup time.Time) bool {
 	return lastBackup.Before(getStartOfMonth(now))
 }
 
+// getTimeOfDay returns hour and minute of TimeOfDay, midnight if not set
+func (i *Interval) getTimeOfDay() (int, int, error) {
+	if i.TimeOfDay == nil {
+		return 0, 0, nil
+	}
+
+	t, err := time.Parse("15:04", *i.TimeOfDay)
+	if err != nil {
+		return 0, 0, errors.New("invalid time of day: " + *i.TimeOfDay)
+	}
+
+	return t.Hour(), t.Minute(), nil
+}
+
+// GetLocation returns the location of Timezone, UTC if it is empty
+func (i *Interval) GetLocation() (*time.Location, error) {
+	if i.Timezone == "" {
+		return time.UTC, nil
+	}
+
+	location, err := time.LoadLocation(i.Timezone)
+	if err != nil {
+		return nil, errors.New("invalid timezone: " + i.Timezone)
+	}
+
+	return location, nil
+}
+
+// getWallClockTime returns the moment the wall clock of the location shows
+// the given time, normalizing the date like time.Date. DST transitions are
+// resolved explicitly instead of relying on time.Date choice:
+//   - the time skipped by a gap (spring forward) runs at the end of the gap
+//   - the time repeated by an overlap (fall back) runs at its first occurrence
+func getWallClockTime(
+	year int,
+	month time.Month,
+	day, hour, minute int,
+	location *time.Location,
+) time.Time {
+	result := time.Date(year, month, day, hour, minute, 0, 0, location)
+	wallClock := time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	resultWallClock := time.Date(
+		result.Year(), result.Month(), result.Day(),
+		result.Hour(), result.Minute(), 0, 0, time.UTC,
+	)
+
+	// gap: the wall clock never shows the time, run at the transition
+	if resultWallClock.After(wallClock) {
+		zoneStart, _ := result.ZoneBounds()
+		return zoneStart
+	}
+
+	if resultWallClock.Before(wallClock) {
+		_, zoneEnd := result.ZoneBounds()
+		return zoneEnd
+	}
+
+	// overlap: the same wall clock is shown in the previous zone as well
+	zoneStart, _ := result.ZoneBounds()
+	if zoneStart.IsZero() {
+		return result
+	}
+
+	_, offset := result.Zone()
+	_, previousOffset := zoneStart.Add(-time.Nanosecond).Zone()
+	if previousOffset <= offset {
+		return result
+	}
+
+	earlier := result.Add(-time.Duration(previousOffset-offset) * time.Second)
+	if earlier.Before(zoneStart) && earlier.Hour() == hour && earlier.Minute() == minute {
+		return earlier
+	}
+
+	return result
+}
+
 func isSameDay(a, b time.Time) bool {
 	y1, m1, d1 := a.Date()
 	y2, m2, d2 := b.Date()
@@ -214,11 +405,11 @@ func getStartOfWeek(t time.Time) time.Time {
 	if wd == 0 {
 		wd = 7
 	}
-	return time.Date(t.Year(), t.Month(), t.Day()-wd+1, 0, 0, 0, 0, t.Location())
+	return getWallClockTime(t.Year(), t.Month(), t.Day()-wd+1, 0, 0, t.Location())
 }
 
 func getStartOfMonth(t time.Time) time.Time {
-	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return getWallClockTime(t.Year(), t.Month(), 1, 0, 0, t.Location())
 }
 
 // cron trigger: check if we've passed a scheduled cron time since last backup
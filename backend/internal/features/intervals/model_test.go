@@ -721,3 +721,264 @@ func TestInterval_Validate(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestInterval_NextRunAfter(t *testing.T) {
+	timeOfDay := "09:00"
+	// Monday, January 15, 2024
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	t.Run("Hourly: Next run one hour after", func(t *testing.T) {
+		interval := &Interval{Interval: IntervalHourly}
+
+		next, err := interval.NextRunAfter(baseTime)
+		assert.NoError(t, err)
+		assert.Equal(t, baseTime.Add(time.Hour), next)
+	})
+
+	t.Run("Daily before slot: Next run today", func(t *testing.T) {
+		interval := &Interval{Interval: IntervalDaily, TimeOfDay: &timeOfDay}
+
+		next, err := interval.NextRunAfter(time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC), next)
+	})
+
+	t.Run("Daily exactly at slot: Next run tomorrow", func(t *testing.T) {
+		interval := &Interval{Interval: IntervalDaily, TimeOfDay: &timeOfDay}
+
+		next, err := interval.NextRunAfter(time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 16, 9, 0, 0, 0, time.UTC), next)
+	})
+
+	t.Run("Weekly on Wednesday: Next run this week", func(t *testing.T) {
+		wednesday := int(time.Wednesday)
+		interval := &Interval{
+			Interval:  IntervalWeekly,
+			TimeOfDay: &timeOfDay,
+			Weekday:   &wednesday,
+		}
+
+		next, err := interval.NextRunAfter(baseTime)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 17, 9, 0, 0, 0, time.UTC), next)
+	})
+
+	t.Run("Weekly on Monday after slot: Next run next week", func(t *testing.T) {
+		monday := int(time.Monday)
+		interval := &Interval{
+			Interval:  IntervalWeekly,
+			TimeOfDay: &timeOfDay,
+			Weekday:   &monday,
+		}
+
+		next, err := interval.NextRunAfter(baseTime)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 22, 9, 0, 0, 0, time.UTC), next)
+	})
+
+	t.Run("Monthly on 31st in February: Next run on last day of month", func(t *testing.T) {
+		day := 31
+		interval := &Interval{
+			Interval:   IntervalMonthly,
+			TimeOfDay:  &timeOfDay,
+			DayOfMonth: &day,
+		}
+
+		next, err := interval.NextRunAfter(time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC), next)
+	})
+
+	t.Run("Cron: Next run from schedule", func(t *testing.T) {
+		cronExpression := "30 */6 * * *"
+		interval := &Interval{Interval: IntervalCron, CronExpression: &cronExpression}
+
+		next, err := interval.NextRunAfter(baseTime)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 15, 12, 30, 0, 0, time.UTC), next)
+	})
+
+	t.Run("Malformed time of day: Error returned", func(t *testing.T) {
+		malformed := "25:99"
+		interval := &Interval{Interval: IntervalDaily, TimeOfDay: &malformed}
+
+		_, err := interval.NextRunAfter(baseTime)
+		assert.Error(t, err)
+	})
+}
+
+func TestInterval_Timezone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+
+	t.Run("Daily in Berlin: Slot follows local time across DST", func(t *testing.T) {
+		timeOfDay := "09:00"
+		interval := &Interval{
+			Interval:  IntervalDaily,
+			TimeOfDay: &timeOfDay,
+			Timezone:  "Europe/Berlin",
+		}
+
+		// winter time, UTC+1
+		next, err := interval.NextRunAfter(time.Date(2024, 3, 29, 12, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 3, 30, 8, 0, 0, 0, time.UTC), next.UTC())
+
+		// summer time since March 31, UTC+2
+		next, err = interval.NextRunAfter(next)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 3, 31, 7, 0, 0, 0, time.UTC), next.UTC())
+	})
+
+	t.Run("Daily in Berlin: Time skipped by DST gap runs at end of gap", func(t *testing.T) {
+		timeOfDay := "02:30"
+		interval := &Interval{
+			Interval:  IntervalDaily,
+			TimeOfDay: &timeOfDay,
+			Timezone:  "Europe/Berlin",
+		}
+
+		next, err := interval.NextRunAfter(time.Date(2024, 3, 30, 12, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		// 03:00 CEST, when clocks jump from 02:00 to 03:00
+		assert.Equal(t, time.Date(2024, 3, 31, 1, 0, 0, 0, time.UTC), next.UTC())
+		assert.Equal(t, 3, next.In(berlin).Hour())
+	})
+
+	t.Run("Daily in Berlin: Time repeated by DST overlap runs once", func(t *testing.T) {
+		timeOfDay := "02:30"
+		interval := &Interval{
+			Interval:  IntervalDaily,
+			TimeOfDay: &timeOfDay,
+			Timezone:  "Europe/Berlin",
+		}
+
+		next, err := interval.NextRunAfter(time.Date(2024, 10, 26, 12, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		// first 02:30, still in summer time
+		assert.Equal(t, time.Date(2024, 10, 27, 0, 30, 0, 0, time.UTC), next.UTC())
+
+		next, err = interval.NextRunAfter(next)
+		assert.NoError(t, err)
+		// second 02:30 of October 27 is skipped
+		assert.Equal(t, time.Date(2024, 10, 28, 1, 30, 0, 0, time.UTC), next.UTC())
+	})
+
+	t.Run("Daily in Berlin: Trigger at local time of day", func(t *testing.T) {
+		timeOfDay := "09:00"
+		interval := &Interval{
+			Interval:  IntervalDaily,
+			TimeOfDay: &timeOfDay,
+			Timezone:  "Europe/Berlin",
+		}
+
+		lastBackup := time.Date(2024, 6, 30, 7, 30, 0, 0, time.UTC)
+
+		// 08:59 CEST
+		should := interval.ShouldTriggerBackup(
+			time.Date(2024, 7, 1, 6, 59, 0, 0, time.UTC),
+			&lastBackup,
+		)
+		assert.False(t, should)
+
+		// 09:00 CEST
+		should = interval.ShouldTriggerBackup(
+			time.Date(2024, 7, 1, 7, 0, 0, 0, time.UTC),
+			&lastBackup,
+		)
+		assert.True(t, should)
+	})
+
+	t.Run("Daily in Berlin: Gap slot triggers once after transition", func(t *testing.T) {
+		timeOfDay := "02:30"
+		interval := &Interval{
+			Interval:  IntervalDaily,
+			TimeOfDay: &timeOfDay,
+			Timezone:  "Europe/Berlin",
+		}
+
+		lastBackup := time.Date(2024, 3, 30, 1, 30, 0, 0, time.UTC)
+
+		// 01:59 CET, before the transition
+		should := interval.ShouldTriggerBackup(
+			time.Date(2024, 3, 31, 0, 59, 0, 0, time.UTC),
+			&lastBackup,
+		)
+		assert.False(t, should)
+
+		// 03:00 CEST, right after the transition
+		now := time.Date(2024, 3, 31, 1, 0, 0, 0, time.UTC)
+		should = interval.ShouldTriggerBackup(now, &lastBackup)
+		assert.True(t, should)
+
+		should = interval.ShouldTriggerBackup(now.Add(time.Hour), &now)
+		assert.False(t, should)
+	})
+
+	t.Run("Weekly in Berlin: Slot follows local time across DST", func(t *testing.T) {
+		timeOfDay := "09:00"
+		monday := int(time.Monday)
+		interval := &Interval{
+			Interval:  IntervalWeekly,
+			TimeOfDay: &timeOfDay,
+			Weekday:   &monday,
+			Timezone:  "Europe/Berlin",
+		}
+
+		next, err := interval.NextRunAfter(time.Date(2024, 3, 29, 12, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 4, 1, 7, 0, 0, 0, time.UTC), next.UTC())
+	})
+
+	t.Run("Monthly in Berlin: Slot follows local time across DST", func(t *testing.T) {
+		timeOfDay := "00:30"
+		day := 1
+		interval := &Interval{
+			Interval:   IntervalMonthly,
+			TimeOfDay:  &timeOfDay,
+			DayOfMonth: &day,
+			Timezone:   "Europe/Berlin",
+		}
+
+		next, err := interval.NextRunAfter(time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		// March 31 22:30 UTC is April 1 00:30 CEST
+		assert.Equal(t, time.Date(2024, 3, 31, 22, 30, 0, 0, time.UTC), next.UTC())
+	})
+
+	t.Run("Cron in New York: Schedule follows local time across DST", func(t *testing.T) {
+		cronExpression := "0 9 * * *"
+		interval := &Interval{
+			Interval:       IntervalCron,
+			CronExpression: &cronExpression,
+			Timezone:       "America/New_York",
+		}
+
+		// EST, UTC-5
+		next, err := interval.NextRunAfter(time.Date(2024, 3, 8, 15, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 3, 9, 14, 0, 0, 0, time.UTC), next.UTC())
+
+		// EDT since March 10, UTC-4
+		next, err = interval.NextRunAfter(next)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 3, 10, 13, 0, 0, 0, time.UTC), next.UTC())
+
+		lastBackup := time.Date(2024, 3, 9, 14, 0, 0, 0, time.UTC)
+		should := interval.ShouldTriggerBackup(
+			time.Date(2024, 3, 10, 13, 0, 0, 0, time.UTC),
+			&lastBackup,
+		)
+		assert.True(t, should)
+	})
+
+	t.Run("Unknown timezone: Validation fails", func(t *testing.T) {
+		interval := &Interval{
+			Interval: IntervalHourly,
+			Timezone: "Mars/Olympus_Mons",
+		}
+
+		assert.Error(t, interval.Validate())
+	})
+}
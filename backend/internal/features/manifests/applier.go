@@ -0,0 +1,614 @@
+package manifests
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"databasus-backend/internal/features/databases"
+	healthcheck_config "databasus-backend/internal/features/healthcheck/config"
+	"databasus-backend/internal/features/notifiers"
+	"databasus-backend/internal/features/storages"
+	users_models "databasus-backend/internal/features/users/models"
+
+	"github.com/google/uuid"
+)
+
+type manifestOperation struct {
+	change ManifestChange
+	// apply is nil for unchanged objects
+	apply func() error
+}
+
+// manifestApplier plans changes of a single manifest apply. Operations
+// are executed in the planned order, so databases may reference storages
+// and notifiers created by earlier operations
+type manifestApplier struct {
+	service     *ManifestService
+	user        *users_models.User
+	workspaceID uuid.UUID
+
+	existingStorages   *namedObjects[storages.Storage]
+	storageNames       map[uuid.UUID]string
+	existingNotifiers  *namedObjects[notifiers.Notifier]
+	existingDatabases  *namedObjects[databases.Database]
+	declaredStorages   map[string]bool
+	declaredNotifiers  map[string]bool
+	createdStorageIDs  map[string]uuid.UUID
+	createdNotifierIDs map[string]uuid.UUID
+	databaseIDs        map[string]uuid.UUID
+
+	operations []*manifestOperation
+}
+
+func (s *ManifestService) newManifestApplier(
+	user *users_models.User,
+	workspaceID uuid.UUID,
+) (*manifestApplier, error) {
+	existingStorages, err := s.storageService.GetStorages(user, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	existingNotifiers, err := s.notifierService.GetNotifiers(user, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	existingDatabases, err := s.databaseService.GetDatabasesByWorkspace(user, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &manifestApplier{
+		service:     s,
+		user:        user,
+		workspaceID: workspaceID,
+
+		existingStorages: newNamedObjects("storage", existingStorages, func(s *storages.Storage) string {
+			return s.Name
+		}),
+		storageNames: getStorageNames(existingStorages),
+		existingNotifiers: newNamedObjects("notifier", existingNotifiers, func(n *notifiers.Notifier) string {
+			return n.Name
+		}),
+		existingDatabases: newNamedObjects("database", existingDatabases, func(d *databases.Database) string {
+			return d.Name
+		}),
+		declaredStorages:   map[string]bool{},
+		declaredNotifiers:  map[string]bool{},
+		createdStorageIDs:  map[string]uuid.UUID{},
+		createdNotifierIDs: map[string]uuid.UUID{},
+		databaseIDs:        map[string]uuid.UUID{},
+	}, nil
+}
+
+func (a *manifestApplier) plan(manifest *WorkspaceManifest) error {
+	for _, storage := range manifest.Storages {
+		if err := a.planStorage(storage); err != nil {
+			return err
+		}
+	}
+
+	for _, notifier := range manifest.Notifiers {
+		if err := a.planNotifier(notifier); err != nil {
+			return err
+		}
+	}
+
+	for _, database := range manifest.Databases {
+		if err := a.planDatabase(database); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *manifestApplier) planStorage(incoming *storages.Storage) error {
+	a.declaredStorages[incoming.Name] = true
+
+	existing, err := a.existingStorages.find(incoming.Name)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		a.addOperation(ManifestObjectTypeStorage, incoming.Name, ManifestChangeActionCreate, func() error {
+			incoming.ID = uuid.Nil
+
+			if err := a.service.storageService.SaveStorage(a.user, a.workspaceID, incoming); err != nil {
+				return err
+			}
+
+			a.createdStorageIDs[incoming.Name] = incoming.ID
+			return nil
+		})
+
+		return nil
+	}
+
+	if existing.Type != incoming.Type {
+		return fmt.Errorf("type of storage '%s' cannot be changed", incoming.Name)
+	}
+
+	desired, err := cloneViaJSON(existing)
+	if err != nil {
+		return err
+	}
+	desired.Update(incoming)
+
+	isSame, err := isSameManifestValue(desired, existing)
+	if err != nil {
+		return err
+	}
+
+	if isSame {
+		a.addOperation(ManifestObjectTypeStorage, incoming.Name, ManifestChangeActionUnchanged, nil)
+		return nil
+	}
+
+	a.addOperation(ManifestObjectTypeStorage, incoming.Name, ManifestChangeActionUpdate, func() error {
+		incoming.ID = existing.ID
+		return a.service.storageService.SaveStorage(a.user, a.workspaceID, incoming)
+	})
+
+	return nil
+}
+
+func (a *manifestApplier) planNotifier(incoming *notifiers.Notifier) error {
+	a.declaredNotifiers[incoming.Name] = true
+
+	existing, err := a.existingNotifiers.find(incoming.Name)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		a.addOperation(ManifestObjectTypeNotifier, incoming.Name, ManifestChangeActionCreate, func() error {
+			incoming.ID = uuid.Nil
+
+			if err := a.service.notifierService.SaveNotifier(a.user, a.workspaceID, incoming); err != nil {
+				return err
+			}
+
+			a.createdNotifierIDs[incoming.Name] = incoming.ID
+			return nil
+		})
+
+		return nil
+	}
+
+	if existing.NotifierType != incoming.NotifierType {
+		return fmt.Errorf("type of notifier '%s' cannot be changed", incoming.Name)
+	}
+
+	desired, err := cloneViaJSON(existing)
+	if err != nil {
+		return err
+	}
+	desired.Update(incoming)
+
+	isSame, err := isSameManifestValue(desired, existing)
+	if err != nil {
+		return err
+	}
+
+	if isSame {
+		a.addOperation(ManifestObjectTypeNotifier, incoming.Name, ManifestChangeActionUnchanged, nil)
+		return nil
+	}
+
+	a.addOperation(ManifestObjectTypeNotifier, incoming.Name, ManifestChangeActionUpdate, func() error {
+		incoming.ID = existing.ID
+		return a.service.notifierService.SaveNotifier(a.user, a.workspaceID, incoming)
+	})
+
+	return nil
+}
+
+func (a *manifestApplier) planDatabase(databaseManifest *DatabaseManifest) error {
+	name := databaseManifest.Name
+
+	for _, notifierName := range databaseManifest.NotifierNames {
+		if !a.isNotifierKnown(notifierName) {
+			return fmt.Errorf("database '%s' references unknown notifier '%s'", name, notifierName)
+		}
+	}
+
+	if databaseManifest.BackupConfig != nil {
+		storageNames := slices.Clone(databaseManifest.BackupConfig.SecondaryStorageNames)
+		if databaseManifest.BackupConfig.StorageName != nil {
+			storageNames = append(storageNames, *databaseManifest.BackupConfig.StorageName)
+		}
+		if databaseManifest.BackupConfig.ArchiveStorageName != nil {
+			storageNames = append(storageNames, *databaseManifest.BackupConfig.ArchiveStorageName)
+		}
+
+		for _, storageName := range storageNames {
+			if !a.isStorageKnown(storageName) {
+				return fmt.Errorf("database '%s' references unknown storage '%s'", name, storageName)
+			}
+		}
+	}
+
+	incoming := databaseManifest.Database
+	incoming.ID = uuid.Nil
+	incoming.WorkspaceID = nil
+	incoming.Notifiers = nil
+	incoming.LastBackupTime = nil
+	incoming.LastBackupErrorMessage = nil
+	incoming.HealthStatus = nil
+
+	existing, err := a.existingDatabases.find(name)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		a.addOperation(ManifestObjectTypeDatabase, name, ManifestChangeActionCreate, func() error {
+			databaseNotifiers, err := a.resolveNotifiers(databaseManifest.NotifierNames)
+			if err != nil {
+				return err
+			}
+			incoming.Notifiers = databaseNotifiers
+
+			createdDatabase, err := a.service.databaseService.CreateDatabase(
+				a.user,
+				a.workspaceID,
+				&incoming,
+			)
+			if err != nil {
+				return err
+			}
+
+			a.databaseIDs[name] = createdDatabase.ID
+			return nil
+		})
+
+		// configs of new databases are created with defaults by
+		// creation listeners, so they are always overwritten
+		if databaseManifest.BackupConfig != nil {
+			a.addOperation(ManifestObjectTypeBackupConfig, name, ManifestChangeActionCreate, func() error {
+				return a.saveBackupConfig(a.databaseIDs[name], databaseManifest.BackupConfig)
+			})
+		}
+
+		if databaseManifest.HealthcheckConfig != nil {
+			a.addOperation(ManifestObjectTypeHealthcheckConfig, name, ManifestChangeActionCreate, func() error {
+				return a.saveHealthcheckConfig(a.databaseIDs[name], databaseManifest.HealthcheckConfig)
+			})
+		}
+
+		return nil
+	}
+
+	if existing.Type != incoming.Type {
+		return fmt.Errorf("type of database '%s' cannot be changed", name)
+	}
+
+	a.databaseIDs[name] = existing.ID
+
+	if err := a.planDatabaseUpdate(existing, &incoming, databaseManifest.NotifierNames); err != nil {
+		return err
+	}
+
+	if databaseManifest.BackupConfig != nil {
+		if err := a.planBackupConfigUpdate(existing, databaseManifest.BackupConfig); err != nil {
+			return err
+		}
+	}
+
+	if databaseManifest.HealthcheckConfig != nil {
+		if err := a.planHealthcheckConfigUpdate(existing, databaseManifest.HealthcheckConfig); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *manifestApplier) planDatabaseUpdate(
+	existing *databases.Database,
+	incoming *databases.Database,
+	notifierNames []string,
+) error {
+	currentManifest := toDatabaseManifest(existing)
+
+	desired, err := cloneViaJSON(existing)
+	if err != nil {
+		return err
+	}
+	desired.Update(incoming)
+
+	current, err := cloneViaJSON(existing)
+	if err != nil {
+		return err
+	}
+	current.Notifiers = nil
+
+	isSame, err := isSameManifestValue(desired, current)
+	if err != nil {
+		return err
+	}
+
+	sortedNotifierNames := slices.Clone(notifierNames)
+	slices.SortFunc(sortedNotifierNames, strings.Compare)
+
+	if isSame && slices.Equal(currentManifest.NotifierNames, sortedNotifierNames) {
+		a.addOperation(ManifestObjectTypeDatabase, existing.Name, ManifestChangeActionUnchanged, nil)
+		return nil
+	}
+
+	a.addOperation(ManifestObjectTypeDatabase, existing.Name, ManifestChangeActionUpdate, func() error {
+		databaseNotifiers, err := a.resolveNotifiers(notifierNames)
+		if err != nil {
+			return err
+		}
+
+		incoming.ID = existing.ID
+		incoming.Notifiers = databaseNotifiers
+
+		return a.service.databaseService.UpdateDatabase(a.user, incoming)
+	})
+
+	return nil
+}
+
+func (a *manifestApplier) planBackupConfigUpdate(
+	database *databases.Database,
+	incoming *BackupConfigManifest,
+) error {
+	existingConfig, err := a.service.backupConfigService.GetBackupConfigByDbId(database.ID)
+	if err != nil {
+		return err
+	}
+
+	currentManifest := toBackupConfigManifest(existingConfig, a.storageNames)
+
+	isSame, err := isSameManifestValue(currentManifest, incoming)
+	if err != nil {
+		return err
+	}
+
+	if isSame {
+		a.addOperation(ManifestObjectTypeBackupConfig, database.Name, ManifestChangeActionUnchanged, nil)
+		return nil
+	}
+
+	operation := a.addOperation(
+		ManifestObjectTypeBackupConfig,
+		database.Name,
+		ManifestChangeActionUpdate,
+		func() error {
+			return a.saveBackupConfig(database.ID, incoming)
+		},
+	)
+
+	if currentManifest.StorageName != nil &&
+		(incoming.StorageName == nil || *incoming.StorageName != *currentManifest.StorageName) {
+		warning := storageChangeWarning
+		operation.change.Warning = &warning
+	}
+
+	return nil
+}
+
+func (a *manifestApplier) planHealthcheckConfigUpdate(
+	database *databases.Database,
+	incoming *healthcheck_config.HealthcheckConfig,
+) error {
+	existingConfig, err := a.service.healthcheckConfigService.GetByDatabaseID(*a.user, database.ID)
+	if err != nil {
+		return err
+	}
+
+	isSame, err := isSameManifestValue(existingConfig, incoming)
+	if err != nil {
+		return err
+	}
+
+	if isSame {
+		a.addOperation(ManifestObjectTypeHealthcheckConfig, database.Name, ManifestChangeActionUnchanged, nil)
+		return nil
+	}
+
+	a.addOperation(ManifestObjectTypeHealthcheckConfig, database.Name, ManifestChangeActionUpdate, func() error {
+		return a.saveHealthcheckConfig(database.ID, incoming)
+	})
+
+	return nil
+}
+
+// saveBackupConfig builds the backup config from the manifest, resolving
+// storages by name and keeping the ID of the existing interval
+func (a *manifestApplier) saveBackupConfig(
+	databaseID uuid.UUID,
+	backupConfigManifest *BackupConfigManifest,
+) error {
+	existingConfig, err := a.service.backupConfigService.GetBackupConfigByDbId(databaseID)
+	if err != nil {
+		return err
+	}
+
+	backupConfig := backupConfigManifest.BackupConfig
+	backupConfig.DatabaseID = databaseID
+	backupConfig.Storage = nil
+	backupConfig.StorageID = nil
+	backupConfig.SecondaryStorages = make([]storages.Storage, 0)
+	backupConfig.ArchiveStorageID = nil
+	backupConfig.BackupIntervalID = existingConfig.BackupIntervalID
+
+	if backupConfig.BackupInterval != nil {
+		interval := *backupConfig.BackupInterval
+		interval.ID = existingConfig.BackupIntervalID
+		backupConfig.BackupInterval = &interval
+	}
+
+	if backupConfigManifest.StorageName != nil {
+		storage, err := a.resolveStorage(*backupConfigManifest.StorageName)
+		if err != nil {
+			return err
+		}
+
+		backupConfig.Storage = storage
+		backupConfig.StorageID = &storage.ID
+	}
+
+	for _, storageName := range backupConfigManifest.SecondaryStorageNames {
+		storage, err := a.resolveStorage(storageName)
+		if err != nil {
+			return err
+		}
+
+		backupConfig.SecondaryStorages = append(backupConfig.SecondaryStorages, *storage)
+	}
+
+	if backupConfigManifest.ArchiveStorageName != nil {
+		storage, err := a.resolveStorage(*backupConfigManifest.ArchiveStorageName)
+		if err != nil {
+			return err
+		}
+
+		backupConfig.ArchiveStorageID = &storage.ID
+	}
+
+	_, err = a.service.backupConfigService.SaveBackupConfigWithAuth(a.user, &backupConfig)
+	return err
+}
+
+func (a *manifestApplier) saveHealthcheckConfig(
+	databaseID uuid.UUID,
+	healthcheckConfig *healthcheck_config.HealthcheckConfig,
+) error {
+	return a.service.healthcheckConfigService.Save(*a.user, healthcheck_config.HealthcheckConfigDTO{
+		DatabaseID:                        databaseID,
+		IsHealthcheckEnabled:              healthcheckConfig.IsHealthcheckEnabled,
+		IsSentNotificationWhenUnavailable: healthcheckConfig.IsSentNotificationWhenUnavailable,
+		IntervalMinutes:                   healthcheckConfig.IntervalMinutes,
+		AttemptsBeforeConcideredAsDown:    healthcheckConfig.AttemptsBeforeConcideredAsDown,
+		StoreAttemptsDays:                 healthcheckConfig.StoreAttemptsDays,
+	})
+}
+
+func (a *manifestApplier) resolveStorage(name string) (*storages.Storage, error) {
+	storageID, isCreated := a.createdStorageIDs[name]
+	if !isCreated {
+		existing, err := a.existingStorages.find(name)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			return nil, fmt.Errorf("storage '%s' is not found", name)
+		}
+
+		storageID = existing.ID
+	}
+
+	return a.service.storageService.GetStorageByID(storageID)
+}
+
+func (a *manifestApplier) resolveNotifiers(names []string) ([]notifiers.Notifier, error) {
+	resolvedNotifiers := make([]notifiers.Notifier, 0, len(names))
+
+	for _, name := range names {
+		notifierID, isCreated := a.createdNotifierIDs[name]
+		if !isCreated {
+			existing, err := a.existingNotifiers.find(name)
+			if err != nil {
+				return nil, err
+			}
+			if existing == nil {
+				return nil, fmt.Errorf("notifier '%s' is not found", name)
+			}
+
+			notifierID = existing.ID
+		}
+
+		notifier, err := a.service.notifierService.GetNotifierByID(notifierID)
+		if err != nil {
+			return nil, err
+		}
+
+		resolvedNotifiers = append(resolvedNotifiers, *notifier)
+	}
+
+	return resolvedNotifiers, nil
+}
+
+func (a *manifestApplier) isStorageKnown(name string) bool {
+	return a.declaredStorages[name] || a.existingStorages.has(name)
+}
+
+func (a *manifestApplier) isNotifierKnown(name string) bool {
+	return a.declaredNotifiers[name] || a.existingNotifiers.has(name)
+}
+
+func (a *manifestApplier) addOperation(
+	objectType ManifestObjectType,
+	name string,
+	action ManifestChangeAction,
+	apply func() error,
+) *manifestOperation {
+	operation := &manifestOperation{
+		change: ManifestChange{
+			ObjectType: objectType,
+			Name:       name,
+			Action:     action,
+		},
+		apply: apply,
+	}
+
+	a.operations = append(a.operations, operation)
+
+	return operation
+}
+
+// namedObjects indexes existing objects of the workspace by name. Names
+// are not unique in the workspace, so ambiguous names cannot be matched
+type namedObjects[T any] struct {
+	objectName     string
+	byName         map[string]*T
+	ambiguousNames map[string]bool
+}
+
+func newNamedObjects[T any](
+	objectName string,
+	objects []*T,
+	getName func(*T) string,
+) *namedObjects[T] {
+	index := &namedObjects[T]{
+		objectName:     objectName,
+		byName:         make(map[string]*T, len(objects)),
+		ambiguousNames: map[string]bool{},
+	}
+
+	for _, object := range objects {
+		name := getName(object)
+
+		if _, isExists := index.byName[name]; isExists {
+			index.ambiguousNames[name] = true
+		}
+
+		index.byName[name] = object
+	}
+
+	return index
+}
+
+func (i *namedObjects[T]) find(name string) (*T, error) {
+	if i.ambiguousNames[name] {
+		return nil, fmt.Errorf(
+			"there are several %ss named '%s' in the workspace, rename them to apply the manifest",
+			i.objectName,
+			name,
+		)
+	}
+
+	return i.byName[name], nil
+}
+
+func (i *namedObjects[T]) has(name string) bool {
+	_, isExists := i.byName[name]
+	return isExists
+}
@@ -0,0 +1,124 @@
+package manifests
+
+import (
+	"net/http"
+
+	users_middleware "databasus-backend/internal/features/users/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ManifestController struct {
+	manifestService *ManifestService
+}
+
+func (c *ManifestController) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/workspaces/:id/manifest", c.ExportManifest)
+	router.POST("/workspaces/:id/manifest/apply", c.ApplyManifest)
+}
+
+// ExportManifest
+// @Summary Export workspace manifest
+// @Description Export storages, notifiers, databases and their backup and healthcheck configs as a declarative YAML or JSON document. Secrets are not exported
+// @Tags manifests
+// @Produce json
+// @Produce application/yaml
+// @Param id path string true "Workspace ID"
+// @Param format query string false "Document format: yaml or json" default(yaml)
+// @Success 200 {object} WorkspaceManifest
+// @Failure 400 {object} map[string]string "Invalid workspace ID"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Router /workspaces/{id}/manifest [get]
+func (c *ManifestController) ExportManifest(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid workspace ID"})
+		return
+	}
+
+	var request ExportManifestRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	manifest, err := c.manifestService.ExportManifestWithAuth(user, id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	document, err := encodeManifest(manifest, request.Format)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	contentType := "application/yaml"
+	if request.Format == ManifestFormatJson {
+		contentType = "application/json"
+	}
+
+	ctx.Data(http.StatusOK, contentType, document)
+}
+
+// ApplyManifest
+// @Summary Apply workspace manifest
+// @Description Create missing and update changed objects of the YAML or JSON manifest, matching them by name. Objects absent in the manifest are kept. Omitted secrets keep current values. With dryRun only planned changes are returned
+// @Tags manifests
+// @Accept application/yaml
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Param dryRun query bool false "Only plan changes"
+// @Param request body WorkspaceManifest true "Workspace manifest"
+// @Success 200 {object} ApplyManifestResponse
+// @Failure 400 {object} map[string]string "Invalid manifest"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Router /workspaces/{id}/manifest/apply [post]
+func (c *ManifestController) ApplyManifest(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid workspace ID"})
+		return
+	}
+
+	var request ApplyManifestRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	body, err := ctx.GetRawData()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	manifest, err := decodeManifest(body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := c.manifestService.ApplyManifestWithAuth(user, id, manifest, request.IsDryRun)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,263 @@
+package manifests
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	backups_config "databasus-backend/internal/features/backups/config"
+	"databasus-backend/internal/features/databases"
+	healthcheck_config "databasus-backend/internal/features/healthcheck/config"
+	"databasus-backend/internal/features/notifiers"
+	"databasus-backend/internal/features/storages"
+	local_storage "databasus-backend/internal/features/storages/models/local"
+	users_enums "databasus-backend/internal/features/users/enums"
+	users_testing "databasus-backend/internal/features/users/testing"
+	workspaces_controllers "databasus-backend/internal/features/workspaces/controllers"
+	workspaces_testing "databasus-backend/internal/features/workspaces/testing"
+	test_utils "databasus-backend/internal/util/testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestRouter() *gin.Engine {
+	return workspaces_testing.CreateTestRouter(
+		workspaces_controllers.GetWorkspaceController(),
+		workspaces_controllers.GetMembershipController(),
+		databases.GetDatabaseController(),
+		backups_config.GetBackupConfigController(),
+		healthcheck_config.GetHealthcheckConfigController(),
+		GetManifestController(),
+	)
+}
+
+func Test_ApplyManifest_WithExportedManifest_NothingChanged(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+	storage := storages.CreateTestStorage(workspace.ID)
+	notifier := notifiers.CreateTestNotifier(workspace.ID)
+	database := databases.CreateTestDatabase(workspace.ID, storage, notifier)
+
+	defer func() {
+		databases.RemoveTestDatabase(database)
+		time.Sleep(50 * time.Millisecond)
+		notifiers.RemoveTestNotifier(notifier)
+		storages.RemoveTestStorage(storage.ID)
+		workspaces_testing.RemoveTestWorkspace(workspace, router)
+	}()
+
+	exportResponse := test_utils.MakeGetRequest(
+		t,
+		router,
+		"/api/v1/workspaces/"+workspace.ID.String()+"/manifest?format=json",
+		"Bearer "+owner.Token,
+		http.StatusOK,
+	)
+
+	assert.NotContains(t, string(exportResponse.Body), storage.ID.String())
+	assert.NotContains(t, string(exportResponse.Body), "\"password\"")
+
+	// the scheduler moves the next run after the export
+	backupConfig, err := backups_config.GetBackupConfigService().GetBackupConfigByDbId(database.ID)
+	assert.NoError(t, err)
+	err = backups_config.GetBackupConfigService().SetNextRunAt(
+		backupConfig,
+		time.Now().UTC().Add(time.Hour),
+	)
+	assert.NoError(t, err)
+
+	var response ApplyManifestResponse
+	test_utils.MakePostRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/workspaces/"+workspace.ID.String()+"/manifest/apply",
+		"Bearer "+owner.Token,
+		json.RawMessage(exportResponse.Body),
+		http.StatusOK,
+		&response,
+	)
+
+	assert.NotEmpty(t, response.Changes)
+	for _, change := range response.Changes {
+		assert.Equal(t, ManifestChangeActionUnchanged, change.Action, change.Name)
+	}
+}
+
+func Test_ApplyManifest_WithNewStorage_StorageCreatedOnlyOnce(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	defer func() {
+		workspaces_testing.RemoveTestWorkspace(workspace, router)
+	}()
+
+	manifest := map[string]any{
+		"version": 1,
+		"storages": []map[string]any{
+			{"name": "Local backups", "type": storages.StorageTypeLocal, "localStorage": map[string]any{}},
+		},
+	}
+
+	applyURL := "/api/v1/workspaces/" + workspace.ID.String() + "/manifest/apply"
+
+	var dryRunResponse ApplyManifestResponse
+	test_utils.MakePostRequestAndUnmarshal(
+		t,
+		router,
+		applyURL+"?dryRun=true",
+		"Bearer "+owner.Token,
+		manifest,
+		http.StatusOK,
+		&dryRunResponse,
+	)
+
+	assert.True(t, dryRunResponse.IsDryRun)
+	assert.Len(t, dryRunResponse.Changes, 1)
+	assert.Equal(t, ManifestChangeActionCreate, dryRunResponse.Changes[0].Action)
+
+	var firstResponse ApplyManifestResponse
+	test_utils.MakePostRequestAndUnmarshal(
+		t,
+		router,
+		applyURL,
+		"Bearer "+owner.Token,
+		manifest,
+		http.StatusOK,
+		&firstResponse,
+	)
+
+	assert.Len(t, firstResponse.Changes, 1)
+	assert.Equal(t, ManifestChangeActionCreate, firstResponse.Changes[0].Action)
+
+	var secondResponse ApplyManifestResponse
+	test_utils.MakePostRequestAndUnmarshal(
+		t,
+		router,
+		applyURL,
+		"Bearer "+owner.Token,
+		manifest,
+		http.StatusOK,
+		&secondResponse,
+	)
+
+	assert.Len(t, secondResponse.Changes, 1)
+	assert.Equal(t, ManifestChangeActionUnchanged, secondResponse.Changes[0].Action)
+}
+
+func Test_ApplyManifest_WithUnknownStorageReference_ReturnsBadRequest(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	defer func() {
+		workspaces_testing.RemoveTestWorkspace(workspace, router)
+	}()
+
+	manifest := `
+version: 1
+databases:
+  - name: orders
+    type: POSTGRES
+    postgresql:
+      host: localhost
+      port: 5432
+    backupConfig:
+      storageName: missing storage
+`
+
+	response := test_utils.MakePostRequest(
+		t,
+		router,
+		"/api/v1/workspaces/"+workspace.ID.String()+"/manifest/apply",
+		"Bearer "+owner.Token,
+		json.RawMessage(mustYamlToJson(t, manifest)),
+		http.StatusBadRequest,
+	)
+
+	assert.Contains(t, string(response.Body), "unknown storage 'missing storage'")
+}
+
+func Test_EncodeManifest_WithIDsAndRuntimeState_OnlyConfigurationExported(t *testing.T) {
+	lastSaveError := "connection refused"
+	storageID := uuid.New()
+
+	manifest := &WorkspaceManifest{
+		Version: manifestVersion,
+		Storages: []*storages.Storage{
+			{
+				ID:            storageID,
+				WorkspaceID:   uuid.New(),
+				Type:          storages.StorageTypeLocal,
+				Name:          "Local backups",
+				LastSaveError: &lastSaveError,
+				LocalStorage:  &local_storage.LocalStorage{StorageID: storageID},
+			},
+		},
+	}
+
+	document, err := encodeManifest(manifest, ManifestFormatYaml)
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(document), "name: Local backups")
+	assert.NotContains(t, string(document), storageID.String())
+	assert.NotContains(t, string(document), lastSaveError)
+
+	decodedManifest, err := decodeManifest(document)
+	assert.NoError(t, err)
+	assert.NoError(t, decodedManifest.Validate())
+
+	isSame, err := isSameManifestValue(manifest, decodedManifest)
+	assert.NoError(t, err)
+	assert.True(t, isSame)
+}
+
+func Test_ToBackupConfigManifest_WithArchiveStorage_ArchiveStorageReferencedByName(t *testing.T) {
+	archiveStorageID := uuid.New()
+
+	backupConfigManifest := toBackupConfigManifest(
+		&backups_config.BackupConfig{
+			ArchiveStorageID: &archiveStorageID,
+			ArchiveAfterDays: 30,
+		},
+		map[uuid.UUID]string{archiveStorageID: "Cold archive"},
+	)
+
+	assert.NotNil(t, backupConfigManifest.ArchiveStorageName)
+	assert.Equal(t, "Cold archive", *backupConfigManifest.ArchiveStorageName)
+
+	manifest := &WorkspaceManifest{
+		Version: manifestVersion,
+		Databases: []*DatabaseManifest{
+			{BackupConfig: backupConfigManifest},
+		},
+	}
+
+	document, err := encodeManifest(manifest, ManifestFormatYaml)
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(document), "archiveStorageName: Cold archive")
+	assert.NotContains(t, string(document), archiveStorageID.String())
+}
+
+func Test_DecodeManifest_WithUnknownField_ReturnsError(t *testing.T) {
+	_, err := decodeManifest([]byte("version: 1\nstorages:\n  - name: s3\n    bucket: backups\n"))
+
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "bucket"))
+}
+
+func mustYamlToJson(t *testing.T, document string) []byte {
+	manifest, err := decodeManifest([]byte(document))
+	assert.NoError(t, err)
+
+	jsonManifest, err := json.Marshal(manifest)
+	assert.NoError(t, err)
+
+	return jsonManifest
+}
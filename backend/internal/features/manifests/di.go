@@ -0,0 +1,33 @@
+package manifests
+
+import (
+	audit_logs "databasus-backend/internal/features/audit_logs"
+	backups_config "databasus-backend/internal/features/backups/config"
+	"databasus-backend/internal/features/databases"
+	healthcheck_config "databasus-backend/internal/features/healthcheck/config"
+	"databasus-backend/internal/features/notifiers"
+	"databasus-backend/internal/features/storages"
+	workspaces_services "databasus-backend/internal/features/workspaces/services"
+)
+
+var manifestService = &ManifestService{
+	storages.GetStorageService(),
+	notifiers.GetNotifierService(),
+	databases.GetDatabaseService(),
+	backups_config.GetBackupConfigService(),
+	healthcheck_config.GetHealthcheckConfigService(),
+	workspaces_services.GetWorkspaceService(),
+	audit_logs.GetAuditLogService(),
+}
+
+var manifestController = &ManifestController{
+	manifestService,
+}
+
+func GetManifestService() *ManifestService {
+	return manifestService
+}
+
+func GetManifestController() *ManifestController {
+	return manifestController
+}
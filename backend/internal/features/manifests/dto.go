@@ -0,0 +1,21 @@
+package manifests
+
+type ExportManifestRequest struct {
+	Format ManifestFormat `form:"format"`
+}
+
+type ApplyManifestRequest struct {
+	IsDryRun bool `form:"dryRun"`
+}
+
+type ManifestChange struct {
+	ObjectType ManifestObjectType   `json:"objectType"`
+	Name       string               `json:"name"`
+	Action     ManifestChangeAction `json:"action"`
+	Warning    *string              `json:"warning,omitempty"`
+}
+
+type ApplyManifestResponse struct {
+	IsDryRun bool             `json:"isDryRun"`
+	Changes  []ManifestChange `json:"changes"`
+}
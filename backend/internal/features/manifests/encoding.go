@@ -0,0 +1,147 @@
+package manifests
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"sigs.k8s.io/yaml"
+)
+
+// excludedManifestKeys are IDs and runtime state of objects (including
+// state of the scheduler). Objects of the manifest reference each other by
+// name, so IDs are meaningless there
+var excludedManifestKeys = map[string]bool{
+	"id":                     true,
+	"workspaceId":            true,
+	"storageId":              true,
+	"archiveStorageId":       true,
+	"notifierId":             true,
+	"databaseId":             true,
+	"backupIntervalId":       true,
+	"lastSaveError":          true,
+	"lastSendError":          true,
+	"lastBackupTime":         true,
+	"lastBackupErrorMessage": true,
+	"healthStatus":           true,
+	"nextRunAt":              true,
+}
+
+func encodeManifest(manifest *WorkspaceManifest, format ManifestFormat) ([]byte, error) {
+	normalizedManifest, err := normalizeManifestValue(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonManifest, err := json.MarshalIndent(normalizedManifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case ManifestFormatJson:
+		return jsonManifest, nil
+	case "", ManifestFormatYaml:
+		return yaml.JSONToYAML(jsonManifest)
+	default:
+		return nil, fmt.Errorf("unsupported manifest format: %s", format)
+	}
+}
+
+// decodeManifest parses YAML or JSON manifest (JSON is valid YAML).
+// Unknown fields are rejected to catch typos
+func decodeManifest(data []byte) (*WorkspaceManifest, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, errors.New("manifest is empty")
+	}
+
+	var manifest WorkspaceManifest
+	if err := yaml.UnmarshalStrict(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	return &manifest, nil
+}
+
+// isSameManifestValue compares values as they would look in the manifest,
+// so IDs, runtime state and empty values do not matter
+func isSameManifestValue(a, b any) (bool, error) {
+	normalizedA, err := normalizeManifestValue(a)
+	if err != nil {
+		return false, err
+	}
+
+	normalizedB, err := normalizeManifestValue(b)
+	if err != nil {
+		return false, err
+	}
+
+	jsonA, err := json.Marshal(normalizedA)
+	if err != nil {
+		return false, err
+	}
+
+	jsonB, err := json.Marshal(normalizedB)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(jsonA, jsonB), nil
+}
+
+func normalizeManifestValue(value any) (any, error) {
+	jsonValue, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var genericValue any
+	if err := json.Unmarshal(jsonValue, &genericValue); err != nil {
+		return nil, err
+	}
+
+	return dropExcludedValues(genericValue), nil
+}
+
+// dropExcludedValues removes excluded keys, nulls and nil UUIDs
+func dropExcludedValues(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if excludedManifestKeys[key] || item == nil || item == uuid.Nil.String() {
+				delete(v, key)
+				continue
+			}
+
+			v[key] = dropExcludedValues(item)
+		}
+
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = dropExcludedValues(item)
+		}
+
+		return v
+	default:
+		return v
+	}
+}
+
+// cloneViaJSON deep copies the object via its JSON representation, fields
+// hidden from JSON are not copied
+func cloneViaJSON[T any](value *T) (*T, error) {
+	jsonValue, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var clone T
+	if err := json.Unmarshal(jsonValue, &clone); err != nil {
+		return nil, err
+	}
+
+	return &clone, nil
+}
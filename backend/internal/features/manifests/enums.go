@@ -0,0 +1,26 @@
+package manifests
+
+type ManifestFormat string
+
+const (
+	ManifestFormatYaml ManifestFormat = "yaml"
+	ManifestFormatJson ManifestFormat = "json"
+)
+
+type ManifestObjectType string
+
+const (
+	ManifestObjectTypeStorage           ManifestObjectType = "STORAGE"
+	ManifestObjectTypeNotifier          ManifestObjectType = "NOTIFIER"
+	ManifestObjectTypeDatabase          ManifestObjectType = "DATABASE"
+	ManifestObjectTypeBackupConfig      ManifestObjectType = "BACKUP_CONFIG"
+	ManifestObjectTypeHealthcheckConfig ManifestObjectType = "HEALTHCHECK_CONFIG"
+)
+
+type ManifestChangeAction string
+
+const (
+	ManifestChangeActionCreate    ManifestChangeAction = "CREATE"
+	ManifestChangeActionUpdate    ManifestChangeAction = "UPDATE"
+	ManifestChangeActionUnchanged ManifestChangeAction = "UNCHANGED"
+)
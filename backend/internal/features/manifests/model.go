@@ -0,0 +1,94 @@
+package manifests
+
+import (
+	"fmt"
+
+	backups_config "databasus-backend/internal/features/backups/config"
+	"databasus-backend/internal/features/databases"
+	healthcheck_config "databasus-backend/internal/features/healthcheck/config"
+	"databasus-backend/internal/features/notifiers"
+	"databasus-backend/internal/features/storages"
+)
+
+const manifestVersion = 1
+
+// WorkspaceManifest is a declarative description of storages, notifiers
+// and databases of the workspace. Objects are matched with existing ones
+// by name and reference each other by name, so the manifest does not
+// depend on IDs and can be applied to another workspace.
+//
+// Secrets are never exported. Omitted secrets keep current values on
+// apply, so they are only needed to create objects or to rotate secrets
+type WorkspaceManifest struct {
+	Version   int                   `json:"version"`
+	Storages  []*storages.Storage   `json:"storages"`
+	Notifiers []*notifiers.Notifier `json:"notifiers"`
+	Databases []*DatabaseManifest   `json:"databases"`
+}
+
+type DatabaseManifest struct {
+	databases.Database
+
+	NotifierNames []string `json:"notifierNames"`
+
+	// configs are kept as is when omitted
+	BackupConfig      *BackupConfigManifest                 `json:"backupConfig,omitempty"`
+	HealthcheckConfig *healthcheck_config.HealthcheckConfig `json:"healthcheckConfig,omitempty"`
+}
+
+type BackupConfigManifest struct {
+	backups_config.BackupConfig
+
+	StorageName           *string  `json:"storageName"`
+	SecondaryStorageNames []string `json:"secondaryStorageNames"`
+	ArchiveStorageName    *string  `json:"archiveStorageName"`
+}
+
+func (m *WorkspaceManifest) Validate() error {
+	if m.Version != manifestVersion {
+		return fmt.Errorf("unsupported manifest version %d, expected %d", m.Version, manifestVersion)
+	}
+
+	storageNames := make([]string, 0, len(m.Storages))
+	for _, storage := range m.Storages {
+		storageNames = append(storageNames, storage.Name)
+	}
+
+	notifierNames := make([]string, 0, len(m.Notifiers))
+	for _, notifier := range m.Notifiers {
+		notifierNames = append(notifierNames, notifier.Name)
+	}
+
+	databaseNames := make([]string, 0, len(m.Databases))
+	for _, database := range m.Databases {
+		databaseNames = append(databaseNames, database.Name)
+	}
+
+	if err := validateUniqueNames("storage", storageNames); err != nil {
+		return err
+	}
+
+	if err := validateUniqueNames("notifier", notifierNames); err != nil {
+		return err
+	}
+
+	return validateUniqueNames("database", databaseNames)
+}
+
+func validateUniqueNames(objectName string, names []string) error {
+	seenNames := make(map[string]bool, len(names))
+
+	for _, name := range names {
+		if name == "" {
+			return fmt.Errorf("%s name is required", objectName)
+		}
+
+		if seenNames[name] {
+			return fmt.Errorf("%s '%s' is declared more than once", objectName, name)
+		}
+
+		seenNames[name] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,244 @@
+package manifests
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	audit_logs "databasus-backend/internal/features/audit_logs"
+	backups_config "databasus-backend/internal/features/backups/config"
+	"databasus-backend/internal/features/databases"
+	healthcheck_config "databasus-backend/internal/features/healthcheck/config"
+	"databasus-backend/internal/features/notifiers"
+	"databasus-backend/internal/features/storages"
+	users_models "databasus-backend/internal/features/users/models"
+	workspaces_services "databasus-backend/internal/features/workspaces/services"
+
+	"github.com/google/uuid"
+)
+
+const storageChangeWarning = "changing the storage deletes existing backups of the database"
+
+type ManifestService struct {
+	storageService           *storages.StorageService
+	notifierService          *notifiers.NotifierService
+	databaseService          *databases.DatabaseService
+	backupConfigService      *backups_config.BackupConfigService
+	healthcheckConfigService *healthcheck_config.HealthcheckConfigService
+	workspaceService         *workspaces_services.WorkspaceService
+	auditLogService          *audit_logs.AuditLogService
+}
+
+// ExportManifestWithAuth describes current storages, notifiers and
+// databases of the workspace with secrets hidden
+func (s *ManifestService) ExportManifestWithAuth(
+	user *users_models.User,
+	workspaceID uuid.UUID,
+) (*WorkspaceManifest, error) {
+	canAccess, _, err := s.workspaceService.CanUserAccessWorkspace(workspaceID, user)
+	if err != nil {
+		return nil, err
+	}
+	if !canAccess {
+		return nil, errors.New("insufficient permissions to export this workspace")
+	}
+
+	workspaceStorages, err := s.storageService.GetStorages(user, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaceNotifiers, err := s.notifierService.GetNotifiers(user, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaceDatabases, err := s.databaseService.GetDatabasesByWorkspace(user, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &WorkspaceManifest{
+		Version:   manifestVersion,
+		Storages:  workspaceStorages,
+		Notifiers: workspaceNotifiers,
+		Databases: make([]*DatabaseManifest, 0, len(workspaceDatabases)),
+	}
+
+	storageNames := getStorageNames(workspaceStorages)
+
+	for _, database := range workspaceDatabases {
+		backupConfig, err := s.backupConfigService.GetBackupConfigByDbId(database.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		healthcheckConfig, err := s.healthcheckConfigService.GetByDatabaseID(*user, database.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		databaseManifest := toDatabaseManifest(database)
+		databaseManifest.BackupConfig = toBackupConfigManifest(backupConfig, storageNames)
+		databaseManifest.HealthcheckConfig = healthcheckConfig
+
+		manifest.Databases = append(manifest.Databases, databaseManifest)
+	}
+
+	slices.SortFunc(manifest.Databases, func(a, b *DatabaseManifest) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return manifest, nil
+}
+
+// ApplyManifestWithAuth creates objects of the manifest missing in the
+// workspace and updates the ones which differ. Objects which are not
+// mentioned in the manifest are kept. Applying the same manifest again
+// does not change anything (unless secrets are provided, they cannot be
+// compared and are always written)
+func (s *ManifestService) ApplyManifestWithAuth(
+	user *users_models.User,
+	workspaceID uuid.UUID,
+	manifest *WorkspaceManifest,
+	isDryRun bool,
+) (*ApplyManifestResponse, error) {
+	canManage, err := s.workspaceService.CanUserManageDBs(workspaceID, user)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage {
+		return nil, errors.New("insufficient permissions to apply manifest to this workspace")
+	}
+
+	if err := manifest.Validate(); err != nil {
+		return nil, err
+	}
+
+	applier, err := s.newManifestApplier(user, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the whole manifest is planned before any change, so invalid
+	// references and type changes do not leave it applied partially
+	if err := applier.plan(manifest); err != nil {
+		return nil, err
+	}
+
+	response := &ApplyManifestResponse{
+		IsDryRun: isDryRun,
+		Changes:  make([]ManifestChange, 0, len(applier.operations)),
+	}
+
+	for _, operation := range applier.operations {
+		response.Changes = append(response.Changes, operation.change)
+	}
+
+	if isDryRun {
+		return response, nil
+	}
+
+	createdCount, updatedCount := 0, 0
+
+	for _, operation := range applier.operations {
+		if operation.apply == nil {
+			continue
+		}
+
+		if err := operation.apply(); err != nil {
+			return nil, fmt.Errorf(
+				"failed to apply %s '%s': %w",
+				operation.change.ObjectType,
+				operation.change.Name,
+				err,
+			)
+		}
+
+		switch operation.change.Action {
+		case ManifestChangeActionCreate:
+			createdCount++
+		case ManifestChangeActionUpdate:
+			updatedCount++
+		}
+	}
+
+	if createdCount > 0 || updatedCount > 0 {
+		s.auditLogService.WriteAuditLog(
+			fmt.Sprintf(
+				"Workspace manifest applied: %d objects created, %d objects updated",
+				createdCount,
+				updatedCount,
+			),
+			&user.ID,
+			&workspaceID,
+		)
+	}
+
+	return response, nil
+}
+
+func toDatabaseManifest(database *databases.Database) *DatabaseManifest {
+	databaseManifest := &DatabaseManifest{
+		Database:      *database,
+		NotifierNames: make([]string, 0, len(database.Notifiers)),
+	}
+
+	for _, notifier := range database.Notifiers {
+		databaseManifest.NotifierNames = append(databaseManifest.NotifierNames, notifier.Name)
+	}
+
+	slices.SortFunc(databaseManifest.NotifierNames, strings.Compare)
+	databaseManifest.Notifiers = nil
+
+	return databaseManifest
+}
+
+// toBackupConfigManifest replaces storages of the backup config with their
+// names, storageNames maps IDs of workspace storages to names
+func toBackupConfigManifest(
+	backupConfig *backups_config.BackupConfig,
+	storageNames map[uuid.UUID]string,
+) *BackupConfigManifest {
+	backupConfigManifest := &BackupConfigManifest{
+		BackupConfig:          *backupConfig,
+		SecondaryStorageNames: make([]string, 0, len(backupConfig.SecondaryStorages)),
+	}
+
+	if backupConfig.Storage != nil {
+		storageName := backupConfig.Storage.Name
+		backupConfigManifest.StorageName = &storageName
+	}
+
+	for _, storage := range backupConfig.SecondaryStorages {
+		backupConfigManifest.SecondaryStorageNames = append(
+			backupConfigManifest.SecondaryStorageNames,
+			storage.Name,
+		)
+	}
+
+	slices.SortFunc(backupConfigManifest.SecondaryStorageNames, strings.Compare)
+
+	if backupConfig.ArchiveStorageID != nil {
+		if archiveStorageName, ok := storageNames[*backupConfig.ArchiveStorageID]; ok {
+			backupConfigManifest.ArchiveStorageName = &archiveStorageName
+		}
+	}
+
+	backupConfigManifest.Storage = nil
+	backupConfigManifest.StorageID = nil
+	backupConfigManifest.SecondaryStorages = nil
+	backupConfigManifest.ArchiveStorageID = nil
+
+	return backupConfigManifest
+}
+
+func getStorageNames(workspaceStorages []*storages.Storage) map[uuid.UUID]string {
+	storageNames := make(map[uuid.UUID]string, len(workspaceStorages))
+	for _, storage := range workspaceStorages {
+		storageNames[storage.ID] = storage.Name
+	}
+
+	return storageNames
+}
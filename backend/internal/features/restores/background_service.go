@@ -1,30 +1,87 @@
 package restores
 
 import (
+	"databasus-backend/internal/config"
 	"databasus-backend/internal/features/restores/enums"
+	"databasus-backend/internal/util/jobs"
+	"fmt"
 	"log/slog"
+	"time"
 )
 
 type RestoreBackgroundService struct {
+	restoreService    *RestoreService
 	restoreRepository *RestoreRepository
 	logger            *slog.Logger
 }
 
 func (s *RestoreBackgroundService) Run() {
-	if err := s.failRestoresInProgress(); err != nil {
-		s.logger.Error("Failed to fail restores in progress", "error", err)
+	if err := s.requeueInterruptedRestores(); err != nil {
+		s.logger.Error("Failed to requeue interrupted restores", "error", err)
 		panic(err)
 	}
+
+	for {
+		if config.IsDraining() {
+			return
+		}
+
+		s.restoreService.DispatchQueuedRestores()
+
+		time.Sleep(1 * time.Minute)
+
+		// leases of restores interrupted right before startup expire only
+		// after it, so they are checked on each run
+		if err := s.requeueInterruptedRestores(); err != nil {
+			s.logger.Error("Failed to requeue interrupted restores", "error", err)
+		}
+	}
 }
 
-func (s *RestoreBackgroundService) failRestoresInProgress() error {
-	restoresInProgress, err := s.restoreRepository.FindByStatus(enums.RestoreStatusInProgress)
+// requeueInterruptedRestores queues again restores whose lease expired
+// because the instance running them crashed or was restarted. Restores which
+// cannot be resumed or used all attempts are failed
+func (s *RestoreBackgroundService) requeueInterruptedRestores() error {
+	now := time.Now().UTC()
+
+	interruptedRestores, err := s.restoreRepository.FindWithExpiredLease(now)
 	if err != nil {
 		return err
 	}
 
-	for _, restore := range restoresInProgress {
+	for _, restore := range interruptedRestores {
+		// still running in this instance, only the heartbeat is late
+		if s.restoreService.IsRestoreRunning(restore.ID) {
+			continue
+		}
+
+		if restore.Request != nil && restore.Attempts < jobs.MaxAttempts {
+			isRequeued, err := s.restoreRepository.RequeueExpired(restore.ID, now)
+			if err != nil {
+				return err
+			}
+
+			if isRequeued {
+				s.logger.Info(
+					"Interrupted restore queued again",
+					"restoreId",
+					restore.ID,
+					"attempts",
+					restore.Attempts,
+				)
+			}
+
+			continue
+		}
+
 		failMessage := "Restore failed due to application restart"
+		if restore.Request != nil {
+			failMessage = fmt.Sprintf(
+				"Restore failed after %d attempts interrupted by application restart",
+				restore.Attempts,
+			)
+		}
+
 		restore.Status = enums.RestoreStatusFailed
 		restore.FailMessage = &failMessage
 
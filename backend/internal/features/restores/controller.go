@@ -14,6 +14,7 @@ type RestoreController struct {
 
 func (c *RestoreController) RegisterRoutes(router *gin.RouterGroup) {
 	router.GET("/restores/:backupId", c.GetRestores)
+	router.GET("/restores/:backupId/toc", c.GetBackupToc)
 	router.POST("/restores/:backupId/restore", c.RestoreBackup)
 }
 
@@ -49,9 +50,41 @@ func (c *RestoreController) GetRestores(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, restores)
 }
 
+// GetBackupToc
+// @Summary Get contents of a backup
+// @Description List schemas, tables and other objects stored in PostgreSQL backup
+// @Tags restores
+// @Produce json
+// @Param backupId path string true "Backup ID"
+// @Success 200 {object} models.BackupToc
+// @Failure 400
+// @Failure 401
+// @Router /restores/{backupId}/toc [get]
+func (c *RestoreController) GetBackupToc(ctx *gin.Context) {
+	user, ok := users_middleware.GetUserFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	backupID, err := uuid.Parse(ctx.Param("backupId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid backup ID"})
+		return
+	}
+
+	toc, err := c.restoreService.GetBackupToc(user, backupID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toc)
+}
+
 // RestoreBackup
 // @Summary Restore a backup
-// @Description Start a restore process for a specific backup
+// @Description Start a restore process for a specific backup. Physical restores and point-in-time recovery only prepare the target data directory: the user must start PostgreSQL on it, WAL is replayed and the recovery result is reported by PostgreSQL, not by Databasus
 // @Tags restores
 // @Param backupId path string true "Backup ID"
 // @Success 200 {object} map[string]string
@@ -11,6 +11,7 @@ import (
 	workspaces_services "databasus-backend/internal/features/workspaces/services"
 	"databasus-backend/internal/util/encryption"
 	"databasus-backend/internal/util/logger"
+	"sync"
 )
 
 var restoreRepository = &RestoreRepository{}
@@ -26,16 +27,22 @@ var restoreService = &RestoreService{
 	audit_logs.GetAuditLogService(),
 	encryption.GetFieldEncryptor(),
 	disk.GetDiskService(),
+	&sync.Map{},
 }
 var restoreController = &RestoreController{
 	restoreService,
 }
 
 var restoreBackgroundService = &RestoreBackgroundService{
+	restoreService,
 	restoreRepository,
 	logger.GetLogger(),
 }
 
+func GetRestoreService() *RestoreService {
+	return restoreService
+}
+
 func GetRestoreController() *RestoreController {
 	return restoreController
 }
@@ -5,6 +5,7 @@ import (
 	"databasus-backend/internal/features/databases/databases/mongodb"
 	"databasus-backend/internal/features/databases/databases/mysql"
 	"databasus-backend/internal/features/databases/databases/postgresql"
+	"databasus-backend/internal/features/restores/models"
 )
 
 type RestoreBackupRequest struct {
@@ -12,4 +13,40 @@ type RestoreBackupRequest struct {
 	MysqlDatabase      *mysql.MysqlDatabase           `json:"mysqlDatabase"`
 	MariadbDatabase    *mariadb.MariadbDatabase       `json:"mariadbDatabase"`
 	MongodbDatabase    *mongodb.MongodbDatabase       `json:"mongodbDatabase"`
+
+	// PointInTimeRecovery restores PostgreSQL base backup into a data
+	// directory instead of a running database; other targets are ignored
+	PointInTimeRecovery *models.PointInTimeRecovery `json:"pointInTimeRecovery"`
+
+	// PhysicalRestore lays out PostgreSQL physical backup into a data
+	// directory instead of a running database; other targets are ignored
+	PhysicalRestore *models.PhysicalRestore `json:"physicalRestore"`
+
+	// BinlogRecovery replays archived binary logs after the MySQL or
+	// MariaDB dump is restored into the target database
+	BinlogRecovery *models.BinlogRecovery `json:"binlogRecovery"`
+
+	// OplogRecovery restores MongoDB oplog mode dump into the target
+	// instance (all databases) and replays archived oplog on top of it
+	OplogRecovery *models.OplogRecovery `json:"oplogRecovery"`
+
+	// SelectiveRestore restores only selected schemas and tables of
+	// PostgreSQL backup instead of the whole database
+	SelectiveRestore *models.SelectiveRestore `json:"selectiveRestore"`
+
+	// ServerRestore restores globals, one database or all databases of
+	// PostgreSQL server backup
+	ServerRestore *models.ServerRestore `json:"serverRestore"`
+}
+
+// IsResumable reports whether the restore can be started again from scratch
+// after it is interrupted. Data directory restores leave a partial
+// extraction in the not empty target directory and data-only restores
+// would load again rows loaded before the interruption
+func (r *RestoreBackupRequest) IsResumable() bool {
+	if r.PhysicalRestore != nil || r.PointInTimeRecovery != nil {
+		return false
+	}
+
+	return r.SelectiveRestore == nil || !r.SelectiveRestore.IsDataOnly
 }
@@ -3,6 +3,8 @@ package enums
 type RestoreStatus string
 
 const (
+	// interrupted by application restart and waits to be started again
+	RestoreStatusQueued     RestoreStatus = "QUEUED"
 	RestoreStatusInProgress RestoreStatus = "IN_PROGRESS"
 	RestoreStatusCompleted  RestoreStatus = "COMPLETED"
 	RestoreStatusFailed     RestoreStatus = "FAILED"
@@ -0,0 +1,28 @@
+package models
+
+// BackupToc is the table of contents of a PostgreSQL custom format backup
+type BackupToc struct {
+	Schemas []string         `json:"schemas"`
+	Tables  []BackupTocTable `json:"tables"`
+	Entries []BackupTocEntry `json:"entries"`
+}
+
+// BackupTocTable is a relation stored in the backup. DataSizeBytes is the
+// compressed size of the table data inside the archive, nil when unknown
+type BackupTocTable struct {
+	Schema        string `json:"schema"`
+	Name          string `json:"name"`
+	ObjectType    string `json:"objectType"`
+	DataSizeBytes *int64 `json:"dataSizeBytes"`
+}
+
+// BackupTocEntry is a single pg_restore TOC entry, e.g. TABLE, INDEX or
+// TABLE DATA. Schema is empty for database level objects
+type BackupTocEntry struct {
+	DumpID     int    `json:"dumpId"`
+	ObjectType string `json:"objectType"`
+	Schema     string `json:"schema"`
+	Name       string `json:"name"`
+	Owner      string `json:"owner"`
+	SizeBytes  *int64 `json:"sizeBytes"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// BinlogRecovery replays archived binary logs on top of the restored
+// MySQL or MariaDB dump, starting from the dump binlog coordinates. Without
+// a target time binary logs are replayed up to the end of the archive
+type BinlogRecovery struct {
+	RecoveryTargetTime *time.Time `json:"recoveryTargetTime"`
+}
+
+func (b *BinlogRecovery) Validate() error {
+	if b.RecoveryTargetTime != nil && b.RecoveryTargetTime.After(time.Now().UTC()) {
+		return errors.New("recovery target time cannot be in the future")
+	}
+
+	return nil
+}
@@ -17,6 +17,15 @@ type Restore struct {
 
 	FailMessage *string `json:"failMessage" gorm:"column:fail_message"`
 
+	// how many times the restore has been started, a restore interrupted by
+	// application restart is started again until jobs.MaxAttempts is reached
+	Attempts int `json:"attempts" gorm:"column:attempts;not null;default:0"`
+	// extended by heartbeats of the running restore
+	LeaseExpiresAt *time.Time `json:"-" gorm:"column:lease_expires_at"`
+	// encrypted JSON of the restore request to start the restore again,
+	// nil if the restore cannot be resumed (e.g. restores of verifications)
+	Request *string `json:"-" gorm:"column:request"`
+
 	RestoreDurationMs int64     `json:"restoreDurationMs" gorm:"column:restore_duration_ms;default:0"`
 	CreatedAt         time.Time `json:"createdAt"         gorm:"column:created_at;default:now()"`
 }
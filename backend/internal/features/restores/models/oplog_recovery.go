@@ -0,0 +1,21 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// OplogRecovery restores MongoDB oplog mode dump of the whole instance and
+// replays archived oplog slices on top of it up to the recovery target.
+// Without a target time oplog is replayed up to the end of the archive
+type OplogRecovery struct {
+	RecoveryTargetTime *time.Time `json:"recoveryTargetTime"`
+}
+
+func (o *OplogRecovery) Validate() error {
+	if o.RecoveryTargetTime != nil && o.RecoveryTargetTime.After(time.Now().UTC()) {
+		return errors.New("recovery target time cannot be in the future")
+	}
+
+	return nil
+}
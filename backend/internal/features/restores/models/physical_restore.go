@@ -0,0 +1,31 @@
+package models
+
+import (
+	"errors"
+	"path/filepath"
+)
+
+// PhysicalRestore describes a restore of a PostgreSQL physical backup into a
+// data directory instead of a running database
+type PhysicalRestore struct {
+	// TargetDataDirectory is an absolute path on the Databasus host inside one
+	// of RESTORE_DATA_DIRECTORY_BASE_DIRS. It must be empty or not exist;
+	// PostgreSQL can be started on it after restore
+	TargetDataDirectory string `json:"targetDataDirectory"`
+}
+
+func (p *PhysicalRestore) Validate() error {
+	return validateTargetDataDirectory(p.TargetDataDirectory)
+}
+
+func validateTargetDataDirectory(targetDataDirectory string) error {
+	if targetDataDirectory == "" {
+		return errors.New("target data directory is required")
+	}
+
+	if !filepath.IsAbs(targetDataDirectory) {
+		return errors.New("target data directory must be an absolute path")
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"time"
+)
+
+var lsnRegex = regexp.MustCompile(`^[0-9A-Fa-f]{1,8}/[0-9A-Fa-f]{1,8}$`)
+
+// PointInTimeRecovery describes a physical restore of a PostgreSQL base
+// backup into a data directory with WAL replay up to the recovery target.
+// At most one target can be set; without a target WAL is replayed up to
+// the end of the archive.
+//
+// Databasus only prepares the data directory (base backup, WAL archive and
+// recovery settings), it does not run the recovery. The restore is completed
+// once the directory is prepared, WAL is replayed when the user starts
+// PostgreSQL on the directory, so the result of the recovery must be checked
+// in the PostgreSQL log
+type PointInTimeRecovery struct {
+	// TargetDataDirectory is an absolute path on the Databasus host inside one
+	// of RESTORE_DATA_DIRECTORY_BASE_DIRS. It must be empty or not exist;
+	// PostgreSQL can be started on it after restore
+	TargetDataDirectory string `json:"targetDataDirectory"`
+
+	RecoveryTargetTime *time.Time `json:"recoveryTargetTime"`
+	RecoveryTargetLsn  *string    `json:"recoveryTargetLsn"`
+}
+
+func (p *PointInTimeRecovery) Validate() error {
+	if err := validateTargetDataDirectory(p.TargetDataDirectory); err != nil {
+		return err
+	}
+
+	if p.RecoveryTargetTime != nil && p.RecoveryTargetLsn != nil {
+		return errors.New("only one of recovery target time or LSN can be specified")
+	}
+
+	if p.RecoveryTargetLsn != nil && !lsnRegex.MatchString(*p.RecoveryTargetLsn) {
+		return errors.New("recovery target LSN must be in format XXXXXXXX/XXXXXXXX")
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// SelectiveRestore limits PostgreSQL restore to a part of the backup. Tables
+// are specified as "schema.table"; indexes, constraints, triggers, defaults
+// and owned sequences follow their table. Without include lists everything
+// except excluded objects is restored; with include lists only the included
+// schemas and tables are restored, database level objects (extensions, etc.)
+// are skipped
+type SelectiveRestore struct {
+	IncludeSchemas []string `json:"includeSchemas"`
+	IncludeTables  []string `json:"includeTables"`
+	ExcludeSchemas []string `json:"excludeSchemas"`
+	ExcludeTables  []string `json:"excludeTables"`
+
+	// IsDataOnly restores only the table data into existing tables, nothing is
+	// dropped or recreated. Useful to refill accidentally truncated tables
+	IsDataOnly bool `json:"isDataOnly"`
+}
+
+func (s *SelectiveRestore) Validate() error {
+	if len(s.IncludeSchemas) == 0 && len(s.IncludeTables) == 0 &&
+		len(s.ExcludeSchemas) == 0 && len(s.ExcludeTables) == 0 {
+		return errors.New("selective restore requires at least one schema or table")
+	}
+
+	for _, schema := range slices.Concat(s.IncludeSchemas, s.ExcludeSchemas) {
+		if strings.TrimSpace(schema) == "" {
+			return errors.New("schema name cannot be empty")
+		}
+	}
+
+	for _, table := range slices.Concat(s.IncludeTables, s.ExcludeTables) {
+		schema, name, ok := strings.Cut(table, ".")
+		if !ok || strings.TrimSpace(schema) == "" || strings.TrimSpace(name) == "" {
+			return errors.New("tables must be specified in format schema.table")
+		}
+	}
+
+	return nil
+}
+
+func (s *SelectiveRestore) HasIncludes() bool {
+	return len(s.IncludeSchemas) > 0 || len(s.IncludeTables) > 0
+}
+
+func (s *SelectiveRestore) IsSchemaSelected(schema string) bool {
+	if slices.Contains(s.ExcludeSchemas, schema) {
+		return false
+	}
+
+	return !s.HasIncludes() || slices.Contains(s.IncludeSchemas, schema)
+}
+
+func (s *SelectiveRestore) IsTableSelected(schema string, table string) bool {
+	qualifiedName := schema + "." + table
+
+	if slices.Contains(s.ExcludeSchemas, schema) ||
+		slices.Contains(s.ExcludeTables, qualifiedName) {
+		return false
+	}
+
+	return !s.HasIncludes() || slices.Contains(s.IncludeSchemas, schema) ||
+		slices.Contains(s.IncludeTables, qualifiedName)
+}
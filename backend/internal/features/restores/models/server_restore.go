@@ -0,0 +1,46 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+type ServerRestoreScope string
+
+const (
+	// ServerRestoreScopeFull restores roles, tablespaces and recreates every
+	// database of the backup with original owners and privileges
+	ServerRestoreScopeFull ServerRestoreScope = "FULL"
+	// ServerRestoreScopeGlobals restores only roles and tablespaces
+	ServerRestoreScopeGlobals ServerRestoreScope = "GLOBALS"
+	// ServerRestoreScopeDatabase restores one database of the backup into
+	// the target database
+	ServerRestoreScopeDatabase ServerRestoreScope = "DATABASE"
+)
+
+// ServerRestore describes which part of PostgreSQL server backup is restored.
+// Roles which already exist on the target server are left as is
+type ServerRestore struct {
+	Scope ServerRestoreScope `json:"scope"`
+
+	// DatabaseName is the name of the database in the backup, required for
+	// DATABASE scope
+	DatabaseName *string `json:"databaseName"`
+}
+
+func (s *ServerRestore) Validate() error {
+	switch s.Scope {
+	case ServerRestoreScopeFull, ServerRestoreScopeGlobals:
+		if s.DatabaseName != nil {
+			return errors.New("database name can be set only for DATABASE scope")
+		}
+	case ServerRestoreScopeDatabase:
+		if s.DatabaseName == nil || strings.TrimSpace(*s.DatabaseName) == "" {
+			return errors.New("database name is required for DATABASE scope")
+		}
+	default:
+		return errors.New("server restore scope must be FULL, GLOBALS or DATABASE")
+	}
+
+	return nil
+}
@@ -4,25 +4,38 @@ import (
 	"databasus-backend/internal/features/restores/enums"
 	"databasus-backend/internal/features/restores/models"
 	"databasus-backend/internal/storage"
+	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type RestoreRepository struct{}
 
+// Create inserts the new restore, its ID is generated if not set
+func (r *RestoreRepository) Create(restore *models.Restore) error {
+	if restore.ID == uuid.Nil {
+		restore.ID = uuid.New()
+	}
+
+	return storage.
+		GetDb().
+		Omit("Backup").
+		Create(restore).
+		Error
+}
+
 func (r *RestoreRepository) Save(restore *models.Restore) error {
 	db := storage.GetDb()
 
-	isNew := restore.ID == uuid.Nil
-	if isNew {
-		restore.ID = uuid.New()
-		return db.Create(restore).
-			Omit("Backup").
-			Error
+	if restore.ID == uuid.Nil {
+		return r.Create(restore)
 	}
 
-	return db.Save(restore).
-		Omit("Backup").
+	// the lease is changed only by claims and heartbeats, so saving of the
+	// restore does not overwrite it with a stale value
+	return db.Omit("Backup", "LeaseExpiresAt").
+		Save(restore).
 		Error
 }
 
@@ -70,6 +83,83 @@ func (r *RestoreRepository) FindByStatus(status enums.RestoreStatus) ([]*models.
 	return restores, nil
 }
 
+// ClaimQueued moves the restore from QUEUED to IN_PROGRESS status, counts
+// the attempt and takes the lease. Returns false if the restore is not
+// queued anymore
+func (r *RestoreRepository) ClaimQueued(
+	restoreID uuid.UUID,
+	leaseExpiresAt time.Time,
+) (bool, error) {
+	result := storage.
+		GetDb().
+		Model(&models.Restore{}).
+		Where("id = ? AND status = ?", restoreID, enums.RestoreStatusQueued).
+		Updates(map[string]any{
+			"status":           enums.RestoreStatusInProgress,
+			"attempts":         gorm.Expr("attempts + 1"),
+			"lease_expires_at": leaseExpiresAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *RestoreRepository) ExtendLease(restoreID uuid.UUID, leaseExpiresAt time.Time) error {
+	return storage.
+		GetDb().
+		Model(&models.Restore{}).
+		Where("id = ? AND status = ?", restoreID, enums.RestoreStatusInProgress).
+		Update("lease_expires_at", leaseExpiresAt).
+		Error
+}
+
+// FindWithExpiredLease returns restores in progress which are not
+// heartbeated anymore. Restores started before leases were introduced have
+// no lease
+func (r *RestoreRepository) FindWithExpiredLease(now time.Time) ([]*models.Restore, error) {
+	var restores []*models.Restore
+
+	if err := storage.
+		GetDb().
+		Where(
+			"status = ? AND (lease_expires_at IS NULL OR lease_expires_at < ?)",
+			enums.RestoreStatusInProgress,
+			now,
+		).
+		Order("created_at ASC").
+		Find(&restores).Error; err != nil {
+		return nil, err
+	}
+
+	return restores, nil
+}
+
+// RequeueExpired moves the restore with expired lease back to QUEUED
+// status. Returns false if the restore has been finished or heartbeated
+// meanwhile
+func (r *RestoreRepository) RequeueExpired(restoreID uuid.UUID, now time.Time) (bool, error) {
+	result := storage.
+		GetDb().
+		Model(&models.Restore{}).
+		Where(
+			"id = ? AND status = ? AND (lease_expires_at IS NULL OR lease_expires_at < ?)",
+			restoreID,
+			enums.RestoreStatusInProgress,
+			now,
+		).
+		Updates(map[string]any{
+			"status":           enums.RestoreStatusQueued,
+			"lease_expires_at": nil,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
 func (r *RestoreRepository) DeleteByID(id uuid.UUID) error {
 	return storage.GetDb().Delete(&models.Restore{}, "id = ?", id).Error
 }
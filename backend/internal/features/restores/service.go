@@ -1,9 +1,12 @@
 package restores
 
 import (
+	"databasus-backend/internal/config"
 	audit_logs "databasus-backend/internal/features/audit_logs"
 	"databasus-backend/internal/features/backups/backups"
+	common "databasus-backend/internal/features/backups/backups/common"
 	backups_config "databasus-backend/internal/features/backups/config"
+	backups_oplog "databasus-backend/internal/features/backups/oplog"
 	"databasus-backend/internal/features/databases"
 	"databasus-backend/internal/features/disk"
 	"databasus-backend/internal/features/restores/enums"
@@ -13,10 +16,16 @@ import (
 	users_models "databasus-backend/internal/features/users/models"
 	workspaces_services "databasus-backend/internal/features/workspaces/services"
 	"databasus-backend/internal/util/encryption"
+	"databasus-backend/internal/util/jobs"
 	"databasus-backend/internal/util/tools"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -34,6 +43,8 @@ type RestoreService struct {
 	auditLogService      *audit_logs.AuditLogService
 	fieldEncryptor       encryption.FieldEncryptor
 	diskService          *disk.DiskService
+	// IDs of restores running in this instance
+	runningRestores *sync.Map
 }
 
 func (s *RestoreService) OnBeforeBackupRemove(backup *backups.Backup) error {
@@ -43,7 +54,8 @@ func (s *RestoreService) OnBeforeBackupRemove(backup *backups.Backup) error {
 	}
 
 	for _, restore := range restores {
-		if restore.Status == enums.RestoreStatusInProgress {
+		if restore.Status == enums.RestoreStatusInProgress ||
+			restore.Status == enums.RestoreStatusQueued {
 			return errors.New("restore is in progress, backup cannot be removed")
 		}
 	}
@@ -89,6 +101,53 @@ func (s *RestoreService) GetRestores(
 	return s.restoreRepository.FindByBackupID(backupID)
 }
 
+// GetBackupToc lists schemas, tables and other objects stored in PostgreSQL
+// backup, so a part of the backup can be selected for restore
+func (s *RestoreService) GetBackupToc(
+	user *users_models.User,
+	backupID uuid.UUID,
+) (*models.BackupToc, error) {
+	backup, err := s.backupService.GetBackup(backupID)
+	if err != nil {
+		return nil, err
+	}
+
+	database, err := s.databaseService.GetDatabaseByID(backup.DatabaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if database.WorkspaceID == nil {
+		return nil, errors.New("cannot get contents of backup for database without workspace")
+	}
+
+	canAccess, _, err := s.workspaceService.CanUserAccessWorkspace(
+		*database.WorkspaceID,
+		user,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !canAccess {
+		return nil, errors.New("insufficient permissions to access this backup")
+	}
+
+	if database.Type != databases.DatabaseTypePostgres {
+		return nil, errors.New("backup contents can be listed only for PostgreSQL")
+	}
+
+	if backup.Status != backups.BackupStatusCompleted {
+		return nil, errors.New("backup is not completed")
+	}
+
+	storage, err := s.backupService.GetReadableBackupStorage(backup)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.restoreBackupUsecase.ListBackupToc(database, backup, storage)
+}
+
 func (s *RestoreService) RestoreBackupWithAuth(
 	user *users_models.User,
 	backupID uuid.UUID,
@@ -119,22 +178,79 @@ func (s *RestoreService) RestoreBackupWithAuth(
 		return errors.New("insufficient permissions to restore this backup")
 	}
 
+	// restores into data directories write files on the Databasus host, so
+	// they are limited to users who manage the workspace
+	if requestDTO.PhysicalRestore != nil || requestDTO.PointInTimeRecovery != nil {
+		canManage, err := s.workspaceService.CanUserManageWorkspace(*database.WorkspaceID, user)
+		if err != nil {
+			return err
+		}
+		if !canManage {
+			return errors.New("insufficient permissions to restore this backup into a data directory")
+		}
+	}
+
 	backupDatabase, err := s.databaseService.GetDatabase(user, backup.DatabaseID)
 	if err != nil {
 		return err
 	}
 
-	if err := s.validateVersionCompatibility(backupDatabase, requestDTO); err != nil {
-		return err
+	if requestDTO.PointInTimeRecovery != nil {
+		if err := s.validatePointInTimeRecovery(backupDatabase, backup, requestDTO); err != nil {
+			return err
+		}
+	} else if requestDTO.PhysicalRestore != nil {
+		if err := s.validatePhysicalRestore(backupDatabase, backup, requestDTO); err != nil {
+			return err
+		}
+	} else {
+		if requestDTO.BinlogRecovery != nil {
+			if err := s.validateBinlogRecovery(backupDatabase, backup, requestDTO); err != nil {
+				return err
+			}
+		}
+
+		if requestDTO.OplogRecovery != nil {
+			if err := s.validateOplogRecovery(backupDatabase, backup, requestDTO); err != nil {
+				return err
+			}
+		}
+
+		if requestDTO.SelectiveRestore != nil {
+			if err := s.validateSelectiveRestore(backupDatabase, requestDTO); err != nil {
+				return err
+			}
+		}
+
+		if requestDTO.ServerRestore != nil {
+			if err := s.validateServerRestore(backupDatabase, backup, requestDTO); err != nil {
+				return err
+			}
+		} else if backup.Type == common.BackupTypeServerDump {
+			return errors.New("server backups can be restored only via server restore")
+		}
+
+		if err := s.validateVersionCompatibility(backupDatabase, requestDTO); err != nil {
+			return err
+		}
+
+		// Validate disk space before starting restore
+		if err := s.validateDiskSpace(backup, requestDTO); err != nil {
+			return err
+		}
+	}
+
+	if config.IsDraining() {
+		return errors.New("application is shutting down, try again after restart")
 	}
 
-	// Validate disk space before starting restore
-	if err := s.validateDiskSpace(backup, requestDTO); err != nil {
+	restore, err := s.createRestore(backup, requestDTO, requestDTO.IsResumable())
+	if err != nil {
 		return err
 	}
 
 	go func() {
-		if err := s.RestoreBackup(backup, requestDTO); err != nil {
+		if err := s.runRestore(restore, backup, requestDTO); err != nil {
 			s.logger.Error("Failed to restore backup", "error", err)
 		}
 	}()
@@ -152,62 +268,216 @@ func (s *RestoreService) RestoreBackupWithAuth(
 	return nil
 }
 
+// RestoreBackup restores the backup synchronously. Such restores are not
+// started again if interrupted by application restart
 func (s *RestoreService) RestoreBackup(
 	backup *backups.Backup,
 	requestDTO RestoreBackupRequest,
 ) error {
+	restore, err := s.createRestore(backup, requestDTO, false)
+	if err != nil {
+		return err
+	}
+
+	return s.runRestore(restore, backup, requestDTO)
+}
+
+// DispatchQueuedRestores starts again restores interrupted by application
+// restart
+func (s *RestoreService) DispatchQueuedRestores() {
+	queuedRestores, err := s.restoreRepository.FindByStatus(enums.RestoreStatusQueued)
+	if err != nil {
+		s.logger.Error("Failed to find queued restores", "error", err)
+		return
+	}
+
+	for _, restore := range queuedRestores {
+		if config.IsDraining() {
+			return
+		}
+
+		leaseExpiresAt := jobs.GetLeaseExpiresAt()
+
+		isClaimed, err := s.restoreRepository.ClaimQueued(restore.ID, leaseExpiresAt)
+		if err != nil {
+			s.logger.Error("Failed to start queued restore", "restoreId", restore.ID, "error", err)
+			continue
+		}
+
+		if !isClaimed {
+			continue
+		}
+
+		restore.Status = enums.RestoreStatusInProgress
+		restore.Attempts++
+		restore.LeaseExpiresAt = &leaseExpiresAt
+
+		requestDTO, err := s.decryptRestoreRequest(restore)
+		if err != nil {
+			if err := s.finishRestore(restore, time.Now().UTC(), err); err != nil {
+				s.logger.Error("Failed to fail queued restore", "error", err)
+			}
+
+			continue
+		}
+
+		s.logger.Info("Starting interrupted restore again", "restoreId", restore.ID)
+
+		go func() {
+			if err := s.runRestore(restore, restore.Backup, *requestDTO); err != nil {
+				s.logger.Error("Failed to restore backup", "error", err)
+			}
+		}()
+	}
+}
+
+// GetRunningRestoresCount returns the number of restores running in this
+// instance, used to drain them on shutdown
+func (s *RestoreService) GetRunningRestoresCount() int {
+	count := 0
+	s.runningRestores.Range(func(_, _ any) bool {
+		count++
+		return true
+	})
+
+	return count
+}
+
+func (s *RestoreService) IsRestoreRunning(restoreID uuid.UUID) bool {
+	_, isRunning := s.runningRestores.Load(restoreID)
+
+	return isRunning
+}
+
+// createRestore saves the restore in progress. The request of a resumable
+// restore is saved encrypted, so the restore can be started again after
+// application restart
+func (s *RestoreService) createRestore(
+	backup *backups.Backup,
+	requestDTO RestoreBackupRequest,
+	isResumable bool,
+) (*models.Restore, error) {
 	if backup.Status != backups.BackupStatusCompleted {
-		return errors.New("backup is not completed")
+		return nil, errors.New("backup is not completed")
 	}
 
 	database, err := s.databaseService.GetDatabaseByID(backup.DatabaseID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	switch database.Type {
 	case databases.DatabaseTypePostgres:
-		if requestDTO.PostgresqlDatabase == nil {
-			return errors.New("postgresql database is required")
+		if requestDTO.PostgresqlDatabase == nil && requestDTO.PointInTimeRecovery == nil &&
+			requestDTO.PhysicalRestore == nil {
+			return nil, errors.New("postgresql database is required")
 		}
 	case databases.DatabaseTypeMysql:
 		if requestDTO.MysqlDatabase == nil {
-			return errors.New("mysql database is required")
+			return nil, errors.New("mysql database is required")
 		}
 	case databases.DatabaseTypeMariadb:
 		if requestDTO.MariadbDatabase == nil {
-			return errors.New("mariadb database is required")
+			return nil, errors.New("mariadb database is required")
 		}
 	case databases.DatabaseTypeMongodb:
 		if requestDTO.MongodbDatabase == nil {
-			return errors.New("mongodb database is required")
+			return nil, errors.New("mongodb database is required")
 		}
 	}
 
-	restore := models.Restore{
+	leaseExpiresAt := jobs.GetLeaseExpiresAt()
+
+	restore := &models.Restore{
 		ID:     uuid.New(),
 		Status: enums.RestoreStatusInProgress,
 
 		BackupID: backup.ID,
 		Backup:   backup,
 
+		Attempts:       1,
+		LeaseExpiresAt: &leaseExpiresAt,
+
 		CreatedAt:         time.Now().UTC(),
 		RestoreDurationMs: 0,
 
 		FailMessage: nil,
 	}
 
-	// Save the restore first
-	if err := s.restoreRepository.Save(&restore); err != nil {
-		return err
+	if isResumable {
+		requestJSON, err := json.Marshal(requestDTO)
+		if err != nil {
+			return nil, err
+		}
+
+		encryptedRequest, err := s.fieldEncryptor.Encrypt(restore.ID, string(requestJSON))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt restore request: %w", err)
+		}
+
+		restore.Request = &encryptedRequest
+	}
+
+	if err := s.restoreRepository.Create(restore); err != nil {
+		return nil, err
 	}
 
-	// Save the restore again to include the postgresql database
-	if err := s.restoreRepository.Save(&restore); err != nil {
+	return restore, nil
+}
+
+func (s *RestoreService) decryptRestoreRequest(
+	restore *models.Restore,
+) (*RestoreBackupRequest, error) {
+	if restore.Request == nil {
+		return nil, errors.New("restore request is not saved")
+	}
+
+	requestJSON, err := s.fieldEncryptor.Decrypt(restore.ID, *restore.Request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt restore request: %w", err)
+	}
+
+	var requestDTO RestoreBackupRequest
+	if err := json.Unmarshal([]byte(requestJSON), &requestDTO); err != nil {
+		return nil, fmt.Errorf("failed to parse restore request: %w", err)
+	}
+
+	return &requestDTO, nil
+}
+
+// runRestore executes the restore while extending its lease
+func (s *RestoreService) runRestore(
+	restore *models.Restore,
+	backup *backups.Backup,
+	requestDTO RestoreBackupRequest,
+) error {
+	s.runningRestores.Store(restore.ID, struct{}{})
+	defer s.runningRestores.Delete(restore.ID)
+
+	stopHeartbeat := jobs.StartHeartbeat(s.logger, jobs.HeartbeatInterval, func() error {
+		return s.restoreRepository.ExtendLease(restore.ID, jobs.GetLeaseExpiresAt())
+	})
+	defer stopHeartbeat()
+
+	start := time.Now().UTC()
+
+	err := s.executeRestore(*restore, backup, requestDTO)
+
+	return s.finishRestore(restore, start, err)
+}
+
+func (s *RestoreService) executeRestore(
+	restore models.Restore,
+	backup *backups.Backup,
+	requestDTO RestoreBackupRequest,
+) error {
+	database, err := s.databaseService.GetDatabaseByID(backup.DatabaseID)
+	if err != nil {
 		return err
 	}
 
-	storage, err := s.storageService.GetStorageByID(backup.StorageID)
+	// falls back to a secondary copy when the primary file is not readable
+	storage, err := s.backupService.GetReadableBackupStorage(backup)
 	if err != nil {
 		return err
 	}
@@ -219,7 +489,29 @@ func (s *RestoreService) RestoreBackup(
 		return err
 	}
 
-	start := time.Now().UTC()
+	if requestDTO.PointInTimeRecovery != nil {
+		err = s.restoreBackupUsecase.ExecutePointInTimeRecovery(
+			database,
+			restore,
+			backup,
+			storage,
+			requestDTO.PointInTimeRecovery,
+		)
+
+		return err
+	}
+
+	if requestDTO.PhysicalRestore != nil {
+		err = s.restoreBackupUsecase.ExecutePhysicalRestore(
+			database,
+			restore,
+			backup,
+			storage,
+			requestDTO.PhysicalRestore,
+		)
+
+		return err
+	}
 
 	restoringToDB := &databases.Database{
 		Type:       database.Type,
@@ -233,6 +525,32 @@ func (s *RestoreService) RestoreBackup(
 		return fmt.Errorf("failed to auto-detect database version: %w", err)
 	}
 
+	if requestDTO.OplogRecovery != nil {
+		err = s.restoreBackupUsecase.ExecuteOplogRecovery(
+			database,
+			restoringToDB,
+			restore,
+			backup,
+			storage,
+			requestDTO.OplogRecovery,
+		)
+
+		return err
+	}
+
+	if requestDTO.ServerRestore != nil {
+		err = s.restoreBackupUsecase.ExecuteServerRestore(
+			database,
+			restoringToDB,
+			restore,
+			backup,
+			storage,
+			requestDTO.ServerRestore,
+		)
+
+		return err
+	}
+
 	isExcludeExtensions := false
 	if requestDTO.PostgresqlDatabase != nil {
 		isExcludeExtensions = requestDTO.PostgresqlDatabase.IsExcludeExtensions
@@ -246,27 +564,287 @@ func (s *RestoreService) RestoreBackup(
 		backup,
 		storage,
 		isExcludeExtensions,
+		requestDTO.SelectiveRestore,
 	)
-	if err != nil {
-		errMsg := err.Error()
+
+	if err == nil && requestDTO.BinlogRecovery != nil {
+		err = s.restoreBackupUsecase.ExecuteBinlogRecovery(
+			database,
+			restoringToDB,
+			restore,
+			backup,
+			requestDTO.BinlogRecovery,
+		)
+	}
+
+	return err
+}
+
+// finishRestore saves the restore final status depending on the restore error
+func (s *RestoreService) finishRestore(
+	restore *models.Restore,
+	start time.Time,
+	restoreErr error,
+) error {
+	restore.RestoreDurationMs = time.Since(start).Milliseconds()
+
+	// the resumable restore is started again after restart, a graceful
+	// shutdown is not counted as a failed attempt
+	isShutdown := restoreErr != nil &&
+		(strings.Contains(restoreErr.Error(), "shutdown") || config.IsShouldShutdown())
+	if isShutdown && restore.Request != nil {
+		restore.Status = enums.RestoreStatusQueued
+		restore.Attempts--
+		restore.RestoreDurationMs = 0
+
+		if err := s.restoreRepository.Save(restore); err != nil {
+			return err
+		}
+
+		return restoreErr
+	}
+
+	// the request holds credentials of the target database, so it is kept
+	// only while the restore may be started again
+	restore.Request = nil
+
+	if restoreErr != nil {
+		errMsg := restoreErr.Error()
 		restore.FailMessage = &errMsg
 		restore.Status = enums.RestoreStatusFailed
-		restore.RestoreDurationMs = time.Since(start).Milliseconds()
 
-		if err := s.restoreRepository.Save(&restore); err != nil {
+		if err := s.restoreRepository.Save(restore); err != nil {
 			return err
 		}
 
-		return err
+		return restoreErr
 	}
 
 	restore.Status = enums.RestoreStatusCompleted
-	restore.RestoreDurationMs = time.Since(start).Milliseconds()
 
-	if err := s.restoreRepository.Save(&restore); err != nil {
+	return s.restoreRepository.Save(restore)
+}
+
+func (s *RestoreService) validatePointInTimeRecovery(
+	backupDatabase *databases.Database,
+	backup *backups.Backup,
+	requestDTO RestoreBackupRequest,
+) error {
+	if backupDatabase.Type != databases.DatabaseTypePostgres {
+		return errors.New("point-in-time recovery is supported only for PostgreSQL")
+	}
+
+	if backup.Type != common.BackupTypeBaseBackup {
+		return errors.New("point-in-time recovery requires a base backup taken with WAL archiving")
+	}
+
+	if err := requestDTO.PointInTimeRecovery.Validate(); err != nil {
 		return err
 	}
 
+	return s.validateTargetDataDirectory(
+		backup,
+		requestDTO.PointInTimeRecovery.TargetDataDirectory,
+	)
+}
+
+func (s *RestoreService) validatePhysicalRestore(
+	backupDatabase *databases.Database,
+	backup *backups.Backup,
+	requestDTO RestoreBackupRequest,
+) error {
+	if backupDatabase.Type != databases.DatabaseTypePostgres {
+		return errors.New("physical restore is supported only for PostgreSQL")
+	}
+
+	if backup.Type != common.BackupTypePhysical {
+		return errors.New("physical restore requires a physical backup")
+	}
+
+	if err := requestDTO.PhysicalRestore.Validate(); err != nil {
+		return err
+	}
+
+	return s.validateTargetDataDirectory(backup, requestDTO.PhysicalRestore.TargetDataDirectory)
+}
+
+// validateTargetDataDirectory checks that the data directory is inside one of
+// the allowed base directories and that its disk fits the backup
+func (s *RestoreService) validateTargetDataDirectory(
+	backup *backups.Backup,
+	targetDataDirectory string,
+) error {
+	err := validateTargetDataDirectoryLocation(
+		targetDataDirectory,
+		config.GetEnv().RestoreDataDirectoryBaseDirs,
+	)
+	if err != nil {
+		return err
+	}
+
+	diskUsage, err := s.diskService.GetDiskUsageForPath(targetDataDirectory)
+	if err != nil {
+		return fmt.Errorf("failed to check disk space: %w", err)
+	}
+
+	return checkFreeDiskSpace(backup, diskUsage.FreeSpaceBytes)
+}
+
+// validateTargetDataDirectoryLocation allows data directories only strictly
+// inside the base directories. Symlinks of the existing part of the paths are
+// resolved first, so a link inside a base directory cannot point outside it
+func validateTargetDataDirectoryLocation(targetDataDirectory string, baseDirs []string) error {
+	if len(baseDirs) == 0 {
+		return errors.New(
+			"restores into data directories are disabled, set RESTORE_DATA_DIRECTORY_BASE_DIRS to enable them",
+		)
+	}
+
+	targetDir, err := resolveExistingPath(targetDataDirectory)
+	if err != nil {
+		return fmt.Errorf("failed to resolve target data directory: %w", err)
+	}
+
+	for _, baseDir := range baseDirs {
+		resolvedBaseDir, err := resolveExistingPath(baseDir)
+		if err != nil {
+			return fmt.Errorf("failed to resolve base directory %s: %w", baseDir, err)
+		}
+
+		relativePath, err := filepath.Rel(resolvedBaseDir, targetDir)
+		if err != nil {
+			continue
+		}
+
+		if relativePath != "." && relativePath != ".." &&
+			!strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf(
+		"target data directory must be inside one of the allowed base directories: %s",
+		strings.Join(baseDirs, ", "),
+	)
+}
+
+// resolveExistingPath resolves symlinks of the longest existing part of the
+// path and appends the part which does not exist yet
+func resolveExistingPath(path string) (string, error) {
+	existingPath := filepath.Clean(path)
+	missingPart := ""
+
+	for {
+		resolvedPath, err := filepath.EvalSymlinks(existingPath)
+		if err == nil {
+			return filepath.Join(resolvedPath, missingPart), nil
+		}
+
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		parentPath := filepath.Dir(existingPath)
+		if parentPath == existingPath {
+			return filepath.Clean(path), nil
+		}
+
+		missingPart = filepath.Join(filepath.Base(existingPath), missingPart)
+		existingPath = parentPath
+	}
+}
+
+func (s *RestoreService) validateBinlogRecovery(
+	backupDatabase *databases.Database,
+	backup *backups.Backup,
+	requestDTO RestoreBackupRequest,
+) error {
+	if backupDatabase.Type != databases.DatabaseTypeMysql &&
+		backupDatabase.Type != databases.DatabaseTypeMariadb {
+		return errors.New("binlog recovery is supported only for MySQL and MariaDB")
+	}
+
+	if backup.BinlogFile == nil || backup.BinlogPosition == nil {
+		return errors.New("binlog recovery requires a backup taken with binlog archiving")
+	}
+
+	targetTime := requestDTO.BinlogRecovery.RecoveryTargetTime
+	if targetTime != nil && targetTime.Before(backup.CreatedAt) {
+		return errors.New("recovery target time cannot be earlier than the backup")
+	}
+
+	return requestDTO.BinlogRecovery.Validate()
+}
+
+func (s *RestoreService) validateOplogRecovery(
+	backupDatabase *databases.Database,
+	backup *backups.Backup,
+	requestDTO RestoreBackupRequest,
+) error {
+	if backupDatabase.Type != databases.DatabaseTypeMongodb {
+		return errors.New("oplog recovery is supported only for MongoDB")
+	}
+
+	if backup.Type != common.BackupTypeOplogDump {
+		return errors.New("oplog recovery requires a dump taken with oplog archiving")
+	}
+
+	// the dump contains changes made until it finished
+	targetTime := requestDTO.OplogRecovery.RecoveryTargetTime
+	if targetTime != nil && targetTime.Before(backups_oplog.GetDumpConsistencyTime(backup)) {
+		return errors.New("recovery target time cannot be earlier than the end of the dump")
+	}
+
+	return requestDTO.OplogRecovery.Validate()
+}
+
+func (s *RestoreService) validateSelectiveRestore(
+	backupDatabase *databases.Database,
+	requestDTO RestoreBackupRequest,
+) error {
+	if backupDatabase.Type != databases.DatabaseTypePostgres {
+		return errors.New("selective restore is supported only for PostgreSQL")
+	}
+
+	return requestDTO.SelectiveRestore.Validate()
+}
+
+func (s *RestoreService) validateServerRestore(
+	backupDatabase *databases.Database,
+	backup *backups.Backup,
+	requestDTO RestoreBackupRequest,
+) error {
+	if backupDatabase.Type != databases.DatabaseTypePostgres {
+		return errors.New("server restore is supported only for PostgreSQL")
+	}
+
+	if backup.Type != common.BackupTypeServerDump {
+		return errors.New("server restore requires a server backup")
+	}
+
+	if requestDTO.SelectiveRestore != nil {
+		return errors.New("selective restore cannot be combined with server restore")
+	}
+
+	if requestDTO.PostgresqlDatabase == nil {
+		return errors.New("postgresql database is required")
+	}
+
+	if err := requestDTO.ServerRestore.Validate(); err != nil {
+		return err
+	}
+
+	// globals and full restores need only a database to connect to
+	pg := requestDTO.PostgresqlDatabase
+	if pg.Database == nil || *pg.Database == "" {
+		if requestDTO.ServerRestore.Scope == models.ServerRestoreScopeDatabase {
+			return errors.New("target database name is required to restore a single database")
+		}
+
+		maintenanceDatabase := "postgres"
+		pg.Database = &maintenanceDatabase
+	}
+
 	return nil
 }
 
@@ -392,6 +970,10 @@ func (s *RestoreService) validateDiskSpace(
 		return fmt.Errorf("failed to check disk space: %w", err)
 	}
 
+	return checkFreeDiskSpace(backup, diskUsage.FreeSpaceBytes)
+}
+
+func checkFreeDiskSpace(backup *backups.Backup, freeSpaceBytes int64) error {
 	// Convert backup size from MB to bytes
 	backupSizeBytes := int64(backup.BackupSizeMb * 1024 * 1024)
 
@@ -406,11 +988,11 @@ func (s *RestoreService) validateDiskSpace(
 	}
 
 	// Check if there's enough free space
-	if diskUsage.FreeSpaceBytes < requiredBytes {
+	if freeSpaceBytes < requiredBytes {
 		backupSizeGB := float64(backupSizeBytes) / (1024 * 1024 * 1024)
 		bufferSizeGB := float64(bufferBytes) / (1024 * 1024 * 1024)
 		requiredGB := float64(requiredBytes) / (1024 * 1024 * 1024)
-		availableGB := float64(diskUsage.FreeSpaceBytes) / (1024 * 1024 * 1024)
+		availableGB := float64(freeSpaceBytes) / (1024 * 1024 * 1024)
 
 		return fmt.Errorf(
 			"to restore this backup, %.1f GB (%.1f GB backup + %.1f GB buffer) is required, but only %.1f GB is available. Please free up disk space before restoring",
@@ -0,0 +1,153 @@
+package restores
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"databasus-backend/internal/features/databases/databases/postgresql"
+	"databasus-backend/internal/features/restores/enums"
+	"databasus-backend/internal/features/restores/models"
+	users_enums "databasus-backend/internal/features/users/enums"
+	users_testing "databasus-backend/internal/features/users/testing"
+	workspaces_testing "databasus-backend/internal/features/workspaces/testing"
+	"databasus-backend/internal/util/tools"
+)
+
+func Test_CreateRestore_WhenRestoreIsResumable_RequestSavedEncrypted(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	_, backup := createTestDatabaseWithBackupForRestore(workspace, owner, router)
+
+	request := RestoreBackupRequest{
+		PostgresqlDatabase: &postgresql.PostgresqlDatabase{
+			Version:  tools.PostgresqlVersion16,
+			Host:     "localhost",
+			Port:     5432,
+			Username: "postgres",
+			Password: "restore-secret",
+		},
+	}
+
+	restore, err := GetRestoreService().createRestore(backup, request, true)
+	assert.NoError(t, err)
+	defer func() {
+		_ = restoreRepository.DeleteByID(restore.ID)
+	}()
+
+	savedRestore, err := restoreRepository.FindByID(restore.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.RestoreStatusInProgress, savedRestore.Status)
+	assert.Equal(t, 1, savedRestore.Attempts)
+	assert.NotNil(t, savedRestore.LeaseExpiresAt)
+	assert.NotNil(t, savedRestore.Request)
+	assert.NotContains(t, *savedRestore.Request, "restore-secret")
+
+	savedRequest, err := GetRestoreService().decryptRestoreRequest(savedRestore)
+	assert.NoError(t, err)
+	assert.Equal(t, "restore-secret", savedRequest.PostgresqlDatabase.Password)
+}
+
+func Test_CreateRestore_WhenRestoreIsNotResumable_RequestNotSaved(t *testing.T) {
+	router := createTestRouter()
+	owner := users_testing.CreateTestUser(users_enums.UserRoleMember)
+	workspace := workspaces_testing.CreateTestWorkspace("Test Workspace", owner, router)
+
+	_, backup := createTestDatabaseWithBackupForRestore(workspace, owner, router)
+
+	request := RestoreBackupRequest{
+		PostgresqlDatabase: &postgresql.PostgresqlDatabase{
+			Version:  tools.PostgresqlVersion16,
+			Host:     "localhost",
+			Port:     5432,
+			Username: "postgres",
+			Password: "restore-secret",
+		},
+	}
+
+	restore, err := GetRestoreService().createRestore(backup, request, false)
+	assert.NoError(t, err)
+	defer func() {
+		_ = restoreRepository.DeleteByID(restore.ID)
+	}()
+
+	savedRestore, err := restoreRepository.FindByID(restore.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, savedRestore.Request)
+}
+
+func Test_IsResumable_WhenRestoreCannotBeRepeated_NotResumable(t *testing.T) {
+	postgresqlRequest := RestoreBackupRequest{
+		PostgresqlDatabase: &postgresql.PostgresqlDatabase{},
+	}
+	assert.True(t, postgresqlRequest.IsResumable())
+
+	selectiveRequest := RestoreBackupRequest{
+		PostgresqlDatabase: &postgresql.PostgresqlDatabase{},
+		SelectiveRestore:   &models.SelectiveRestore{IncludeTables: []string{"public.users"}},
+	}
+	assert.True(t, selectiveRequest.IsResumable())
+
+	selectiveRequest.SelectiveRestore.IsDataOnly = true
+	assert.False(t, selectiveRequest.IsResumable())
+
+	physicalRequest := RestoreBackupRequest{PhysicalRestore: &models.PhysicalRestore{}}
+	assert.False(t, physicalRequest.IsResumable())
+
+	pitrRequest := RestoreBackupRequest{PointInTimeRecovery: &models.PointInTimeRecovery{}}
+	assert.False(t, pitrRequest.IsResumable())
+}
+
+func Test_ValidateTargetDataDirectoryLocation_OnlyDirectoriesInsideBaseDirsAllowed(t *testing.T) {
+	baseDir := t.TempDir()
+	outsideDir := t.TempDir()
+
+	linkPath := filepath.Join(baseDir, "link")
+	assert.NoError(t, os.Symlink(outsideDir, linkPath))
+
+	baseDirs := []string{baseDir}
+
+	t.Run("directory inside base dir is allowed", func(t *testing.T) {
+		err := validateTargetDataDirectoryLocation(filepath.Join(baseDir, "pgdata"), baseDirs)
+		assert.NoError(t, err)
+	})
+
+	t.Run("nested missing directory inside base dir is allowed", func(t *testing.T) {
+		err := validateTargetDataDirectoryLocation(
+			filepath.Join(baseDir, "restores", "pgdata"),
+			baseDirs,
+		)
+		assert.NoError(t, err)
+	})
+
+	t.Run("base dir itself is rejected", func(t *testing.T) {
+		assert.Error(t, validateTargetDataDirectoryLocation(baseDir, baseDirs))
+	})
+
+	t.Run("directory outside base dir is rejected", func(t *testing.T) {
+		err := validateTargetDataDirectoryLocation(filepath.Join(outsideDir, "pgdata"), baseDirs)
+		assert.Error(t, err)
+	})
+
+	t.Run("directory escaping base dir via dots is rejected", func(t *testing.T) {
+		err := validateTargetDataDirectoryLocation(
+			baseDir+string(filepath.Separator)+".."+string(filepath.Separator)+"pgdata",
+			baseDirs,
+		)
+		assert.Error(t, err)
+	})
+
+	t.Run("directory behind symlink pointing outside base dir is rejected", func(t *testing.T) {
+		err := validateTargetDataDirectoryLocation(filepath.Join(linkPath, "pgdata"), baseDirs)
+		assert.Error(t, err)
+	})
+
+	t.Run("no base dirs configured rejects all directories", func(t *testing.T) {
+		err := validateTargetDataDirectoryLocation(filepath.Join(baseDir, "pgdata"), nil)
+		assert.Error(t, err)
+	})
+}
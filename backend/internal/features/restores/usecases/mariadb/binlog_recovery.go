@@ -0,0 +1,186 @@
+package usecases_mariadb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"databasus-backend/internal/config"
+	"databasus-backend/internal/features/backups/backups"
+	"databasus-backend/internal/features/databases"
+	"databasus-backend/internal/features/restores/models"
+	util_encryption "databasus-backend/internal/util/encryption"
+	"databasus-backend/internal/util/tools"
+)
+
+// ApplyBinlogs replays archived binary logs on the restored database from
+// the backup binlog coordinates up to the recovery target time. Only
+// changes of the backed up database are replayed
+func (uc *RestoreMariadbBackupUsecase) ApplyBinlogs(
+	originalDB *databases.Database,
+	restoringToDB *databases.Database,
+	restore models.Restore,
+	backup *backups.Backup,
+	binlogRecovery *models.BinlogRecovery,
+) error {
+	if originalDB.Mariadb == nil || originalDB.Mariadb.Database == nil {
+		return errors.New("original database name is required for binlog recovery")
+	}
+
+	if backup.BinlogFile == nil || backup.BinlogPosition == nil {
+		return errors.New("backup has no binlog coordinates")
+	}
+
+	mdb := restoringToDB.Mariadb
+	if mdb == nil || mdb.Database == nil || *mdb.Database == "" {
+		return errors.New("target database name is required for binlog recovery")
+	}
+
+	uc.logger.Info(
+		"Replaying MariaDB binary logs",
+		"restoreId", restore.ID,
+		"backupId", backup.ID,
+		"startFile", *backup.BinlogFile,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Minute)
+	defer cancel()
+
+	binlogDir, err := os.MkdirTemp(config.GetEnv().TempFolder, "binlog_"+restore.ID.String())
+	if err != nil {
+		return fmt.Errorf("failed to create binlog directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(binlogDir) }()
+
+	binlogFilePaths, err := uc.binlogService.DownloadBinlogFiles(
+		ctx,
+		backup,
+		binlogRecovery.RecoveryTargetTime,
+		binlogDir,
+	)
+	if err != nil {
+		return err
+	}
+
+	decryptedPassword, err := util_encryption.GetFieldEncryptor().Decrypt(
+		originalDB.ID,
+		mdb.Password,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt password: %w", err)
+	}
+
+	myCnfFile, err := uc.createTempMyCnfFile(mdb, decryptedPassword)
+	if err != nil {
+		return fmt.Errorf("failed to create .mdb.cnf: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(filepath.Dir(myCnfFile)) }()
+
+	binlogArgs := []string{
+		"--start-position=" + strconv.FormatInt(*backup.BinlogPosition, 10),
+	}
+
+	originalDbName := *originalDB.Mariadb.Database
+	if originalDbName != *mdb.Database {
+		// rewrite rules are applied before --database filter
+		binlogArgs = append(binlogArgs, "--rewrite-db="+originalDbName+"->"+*mdb.Database)
+	}
+	binlogArgs = append(binlogArgs, "--database="+*mdb.Database)
+
+	if binlogRecovery.RecoveryTargetTime != nil {
+		binlogArgs = append(
+			binlogArgs,
+			"--stop-datetime="+binlogRecovery.RecoveryTargetTime.UTC().Format(time.DateTime),
+		)
+	}
+
+	binlogArgs = append(binlogArgs, binlogFilePaths...)
+
+	return replayBinlogs(
+		ctx,
+		tools.GetMariadbExecutable(
+			tools.MariadbExecutableMariadbBinlog,
+			mdb.Version,
+			config.GetEnv().EnvMode,
+			config.GetEnv().MariadbInstallDir,
+		),
+		binlogArgs,
+		tools.GetMariadbExecutable(
+			tools.MariadbExecutableMariadb,
+			mdb.Version,
+			config.GetEnv().EnvMode,
+			config.GetEnv().MariadbInstallDir,
+		),
+		myCnfFile,
+	)
+}
+
+// replayBinlogs pipes mariadb-binlog output into the mariadb client
+func replayBinlogs(
+	ctx context.Context,
+	binlogBin string,
+	binlogArgs []string,
+	mariadbBin string,
+	myCnfFile string,
+) error {
+	binlogCmd := exec.CommandContext(ctx, binlogBin, binlogArgs...)
+	// --stop-datetime is interpreted in the local time zone of mariadb-binlog
+	binlogCmd.Env = append(os.Environ(), "TZ=UTC")
+
+	mariadbCmd := exec.CommandContext(ctx, mariadbBin, "--defaults-file="+myCnfFile)
+	mariadbCmd.Env = append(os.Environ(), "MYSQL_PWD=", "LC_ALL=C.UTF-8", "LANG=C.UTF-8")
+
+	binlogOutput, err := binlogCmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("stdout pipe: %w", err)
+	}
+	mariadbCmd.Stdin = binlogOutput
+
+	var binlogStderr, mariadbStderr bytes.Buffer
+	binlogCmd.Stderr = &binlogStderr
+	mariadbCmd.Stderr = &mariadbStderr
+
+	if err := mariadbCmd.Start(); err != nil {
+		return fmt.Errorf("start mariadb: %w", err)
+	}
+
+	if err := binlogCmd.Start(); err != nil {
+		_ = mariadbCmd.Process.Kill()
+		_ = mariadbCmd.Wait()
+		return fmt.Errorf("start mariadb-binlog: %w", err)
+	}
+
+	binlogErr := binlogCmd.Wait()
+	mariadbErr := mariadbCmd.Wait()
+
+	if config.IsShouldShutdown() {
+		return errors.New("binlog recovery cancelled due to shutdown")
+	}
+
+	if binlogErr != nil {
+		return fmt.Errorf(
+			"%s failed: %v – stderr: %s",
+			filepath.Base(binlogBin),
+			binlogErr,
+			strings.TrimSpace(binlogStderr.String()),
+		)
+	}
+
+	if mariadbErr != nil {
+		return fmt.Errorf(
+			"%s failed to apply binary logs: %v – stderr: %s",
+			filepath.Base(mariadbBin),
+			mariadbErr,
+			strings.TrimSpace(mariadbStderr.String()),
+		)
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package usecases_mariadb
 
 import (
+	backups_binlog "databasus-backend/internal/features/backups/binlog"
 	"databasus-backend/internal/features/encryption/secrets"
 	"databasus-backend/internal/util/logger"
 )
@@ -8,6 +9,7 @@ import (
 var restoreMariadbBackupUsecase = &RestoreMariadbBackupUsecase{
 	logger.GetLogger(),
 	secrets.GetSecretKeyService(),
+	backups_binlog.GetBinlogService(),
 }
 
 func GetRestoreMariadbBackupUsecase() *RestoreMariadbBackupUsecase {
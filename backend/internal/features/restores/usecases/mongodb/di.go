@@ -1,6 +1,7 @@
 package usecases_mongodb
 
 import (
+	backups_oplog "databasus-backend/internal/features/backups/oplog"
 	encryption_secrets "databasus-backend/internal/features/encryption/secrets"
 	"databasus-backend/internal/util/logger"
 )
@@ -8,6 +9,7 @@ import (
 var restoreMongodbBackupUsecase = &RestoreMongodbBackupUsecase{
 	logger.GetLogger(),
 	encryption_secrets.GetSecretKeyService(),
+	backups_oplog.GetOplogService(),
 }
 
 func GetRestoreMongodbBackupUsecase() *RestoreMongodbBackupUsecase {
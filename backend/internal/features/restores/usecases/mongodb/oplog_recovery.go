@@ -0,0 +1,164 @@
+package usecases_mongodb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"databasus-backend/internal/config"
+	"databasus-backend/internal/features/backups/backups"
+	common "databasus-backend/internal/features/backups/backups/common"
+	"databasus-backend/internal/features/databases"
+	mongodbtypes "databasus-backend/internal/features/databases/databases/mongodb"
+	"databasus-backend/internal/features/restores/models"
+	"databasus-backend/internal/features/storages"
+	util_encryption "databasus-backend/internal/util/encryption"
+	"databasus-backend/internal/util/tools"
+)
+
+// ExecuteOplogRecovery restores oplog mode dump of the whole instance into
+// the target (replaying the oplog captured during the dump to make it
+// consistent) and then replays archived oplog slices up to the recovery
+// target time
+func (uc *RestoreMongodbBackupUsecase) ExecuteOplogRecovery(
+	originalDB *databases.Database,
+	restoringToDB *databases.Database,
+	restore models.Restore,
+	backup *backups.Backup,
+	storage *storages.Storage,
+	oplogRecovery *models.OplogRecovery,
+) error {
+	if originalDB.Type != databases.DatabaseTypeMongodb {
+		return errors.New("database type not supported")
+	}
+
+	if backup.Type != common.BackupTypeOplogDump {
+		return errors.New("oplog recovery requires a dump taken with oplog archiving")
+	}
+
+	mdb := restoringToDB.Mongodb
+	if mdb == nil {
+		return fmt.Errorf("mongodb configuration is required for restore")
+	}
+
+	uc.logger.Info(
+		"Restoring MongoDB oplog mode dump with oplog replay",
+		"restoreId", restore.ID,
+		"backupId", backup.ID,
+	)
+
+	decryptedPassword, err := util_encryption.GetFieldEncryptor().Decrypt(
+		restoringToDB.ID,
+		mdb.Password,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt password: %w", err)
+	}
+
+	mongorestoreBin := tools.GetMongodbExecutable(
+		tools.MongodbExecutableMongorestore,
+		config.GetEnv().EnvMode,
+		config.GetEnv().MongodbInstallDir,
+	)
+
+	if err := uc.restoreFromStorage(
+		mongorestoreBin,
+		uc.buildOplogDumpRestoreArgs(mdb, decryptedPassword),
+		backup,
+		storage,
+	); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), restoreTimeout)
+	defer cancel()
+
+	// mongorestore replays oplog.bson placed in the root of the dump directory
+	oplogDir, err := os.MkdirTemp(config.GetEnv().TempFolder, "oplog_"+restore.ID.String())
+	if err != nil {
+		return fmt.Errorf("failed to create oplog directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(oplogDir) }()
+
+	if err := uc.oplogService.DownloadSlices(
+		ctx,
+		backup,
+		oplogRecovery.RecoveryTargetTime,
+		filepath.Join(oplogDir, "oplog.bson"),
+	); err != nil {
+		return err
+	}
+
+	args := []string{
+		"--uri=" + mdb.BuildMongodumpURI(decryptedPassword),
+		"--oplogReplay",
+	}
+
+	if oplogRecovery.RecoveryTargetTime != nil {
+		// entries with timestamp >= limit are not replayed, so the whole
+		// second of the target time is included
+		oplogLimit := oplogRecovery.RecoveryTargetTime.UTC().Unix() + 1
+		args = append(args, "--oplogLimit="+strconv.FormatInt(oplogLimit, 10)+":0")
+	}
+
+	args = append(args, "--dir="+oplogDir)
+
+	return uc.replayOplog(ctx, mongorestoreBin, args)
+}
+
+func (uc *RestoreMongodbBackupUsecase) buildOplogDumpRestoreArgs(
+	mdb *mongodbtypes.MongodbDatabase,
+	password string,
+) []string {
+	args := []string{
+		"--uri=" + mdb.BuildMongodumpURI(password),
+		"--archive",
+		"--gzip",
+		"--drop",
+		"--oplogReplay",
+	}
+
+	parallelWorkers := max(1, min(mdb.CpuCount, 16))
+	if parallelWorkers > 1 {
+		args = append(
+			args,
+			"--numInsertionWorkersPerCollection="+fmt.Sprintf("%d", parallelWorkers),
+		)
+	}
+
+	return args
+}
+
+func (uc *RestoreMongodbBackupUsecase) replayOplog(
+	ctx context.Context,
+	mongorestoreBin string,
+	args []string,
+) error {
+	cmd := exec.CommandContext(ctx, mongorestoreBin, args...)
+	cmd.Env = append(os.Environ(), "LC_ALL=C.UTF-8", "LANG=C.UTF-8")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	uc.logger.Info("Replaying archived MongoDB oplog", "command", mongorestoreBin)
+
+	startedAt := time.Now().UTC()
+	waitErr := cmd.Run()
+
+	if config.IsShouldShutdown() {
+		return fmt.Errorf("restore cancelled due to shutdown")
+	}
+
+	if waitErr != nil {
+		return uc.handleMongoRestoreError(waitErr, stderr.Bytes(), mongorestoreBin)
+	}
+
+	uc.logger.Info("Archived MongoDB oplog replayed", "duration", time.Since(startedAt))
+	return nil
+}
@@ -17,6 +17,7 @@ import (
 	"databasus-backend/internal/features/backups/backups"
 	"databasus-backend/internal/features/backups/backups/encryption"
 	backups_config "databasus-backend/internal/features/backups/config"
+	backups_oplog "databasus-backend/internal/features/backups/oplog"
 	"databasus-backend/internal/features/databases"
 	mongodbtypes "databasus-backend/internal/features/databases/databases/mongodb"
 	encryption_secrets "databasus-backend/internal/features/encryption/secrets"
@@ -33,6 +34,7 @@ const (
 type RestoreMongodbBackupUsecase struct {
 	logger           *slog.Logger
 	secretKeyService *encryption_secrets.SecretKeyService
+	oplogService     *backups_oplog.OplogService
 }
 
 func (uc *RestoreMongodbBackupUsecase) Execute(
@@ -0,0 +1,189 @@
+package usecases_mysql
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"databasus-backend/internal/config"
+	"databasus-backend/internal/features/backups/backups"
+	"databasus-backend/internal/features/databases"
+	"databasus-backend/internal/features/restores/models"
+	util_encryption "databasus-backend/internal/util/encryption"
+	"databasus-backend/internal/util/tools"
+)
+
+// ApplyBinlogs replays archived binary logs on the restored database from
+// the backup binlog coordinates up to the recovery target time. Only
+// changes of the backed up database are replayed
+func (uc *RestoreMysqlBackupUsecase) ApplyBinlogs(
+	originalDB *databases.Database,
+	restoringToDB *databases.Database,
+	restore models.Restore,
+	backup *backups.Backup,
+	binlogRecovery *models.BinlogRecovery,
+) error {
+	if originalDB.Mysql == nil || originalDB.Mysql.Database == nil {
+		return errors.New("original database name is required for binlog recovery")
+	}
+
+	if backup.BinlogFile == nil || backup.BinlogPosition == nil {
+		return errors.New("backup has no binlog coordinates")
+	}
+
+	my := restoringToDB.Mysql
+	if my == nil || my.Database == nil || *my.Database == "" {
+		return errors.New("target database name is required for binlog recovery")
+	}
+
+	uc.logger.Info(
+		"Replaying MySQL binary logs",
+		"restoreId", restore.ID,
+		"backupId", backup.ID,
+		"startFile", *backup.BinlogFile,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Minute)
+	defer cancel()
+
+	binlogDir, err := os.MkdirTemp(config.GetEnv().TempFolder, "binlog_"+restore.ID.String())
+	if err != nil {
+		return fmt.Errorf("failed to create binlog directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(binlogDir) }()
+
+	binlogFilePaths, err := uc.binlogService.DownloadBinlogFiles(
+		ctx,
+		backup,
+		binlogRecovery.RecoveryTargetTime,
+		binlogDir,
+	)
+	if err != nil {
+		return err
+	}
+
+	decryptedPassword, err := util_encryption.GetFieldEncryptor().Decrypt(
+		originalDB.ID,
+		my.Password,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt password: %w", err)
+	}
+
+	myCnfFile, err := uc.createTempMyCnfFile(my, decryptedPassword)
+	if err != nil {
+		return fmt.Errorf("failed to create .my.cnf: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(filepath.Dir(myCnfFile)) }()
+
+	binlogArgs := []string{
+		"--start-position=" + strconv.FormatInt(*backup.BinlogPosition, 10),
+		// replayed transactions get new GTIDs, otherwise the server skips
+		// them as already executed when restoring into the source server
+		"--skip-gtids",
+	}
+
+	originalDbName := *originalDB.Mysql.Database
+	if originalDbName != *my.Database {
+		// rewrite rules are applied before --database filter
+		binlogArgs = append(binlogArgs, "--rewrite-db="+originalDbName+"->"+*my.Database)
+	}
+	binlogArgs = append(binlogArgs, "--database="+*my.Database)
+
+	if binlogRecovery.RecoveryTargetTime != nil {
+		binlogArgs = append(
+			binlogArgs,
+			"--stop-datetime="+binlogRecovery.RecoveryTargetTime.UTC().Format(time.DateTime),
+		)
+	}
+
+	binlogArgs = append(binlogArgs, binlogFilePaths...)
+
+	return replayBinlogs(
+		ctx,
+		tools.GetMysqlExecutable(
+			my.Version,
+			tools.MysqlExecutableMysqlbinlog,
+			config.GetEnv().EnvMode,
+			config.GetEnv().MysqlInstallDir,
+		),
+		binlogArgs,
+		tools.GetMysqlExecutable(
+			my.Version,
+			tools.MysqlExecutableMysql,
+			config.GetEnv().EnvMode,
+			config.GetEnv().MysqlInstallDir,
+		),
+		myCnfFile,
+	)
+}
+
+// replayBinlogs pipes mysqlbinlog output into the mysql client
+func replayBinlogs(
+	ctx context.Context,
+	binlogBin string,
+	binlogArgs []string,
+	mysqlBin string,
+	myCnfFile string,
+) error {
+	binlogCmd := exec.CommandContext(ctx, binlogBin, binlogArgs...)
+	// --stop-datetime is interpreted in the local time zone of mysqlbinlog
+	binlogCmd.Env = append(os.Environ(), "TZ=UTC")
+
+	mysqlCmd := exec.CommandContext(ctx, mysqlBin, "--defaults-file="+myCnfFile)
+	mysqlCmd.Env = append(os.Environ(), "MYSQL_PWD=", "LC_ALL=C.UTF-8", "LANG=C.UTF-8")
+
+	binlogOutput, err := binlogCmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("stdout pipe: %w", err)
+	}
+	mysqlCmd.Stdin = binlogOutput
+
+	var binlogStderr, mysqlStderr bytes.Buffer
+	binlogCmd.Stderr = &binlogStderr
+	mysqlCmd.Stderr = &mysqlStderr
+
+	if err := mysqlCmd.Start(); err != nil {
+		return fmt.Errorf("start mysql: %w", err)
+	}
+
+	if err := binlogCmd.Start(); err != nil {
+		_ = mysqlCmd.Process.Kill()
+		_ = mysqlCmd.Wait()
+		return fmt.Errorf("start mysqlbinlog: %w", err)
+	}
+
+	binlogErr := binlogCmd.Wait()
+	mysqlErr := mysqlCmd.Wait()
+
+	if config.IsShouldShutdown() {
+		return errors.New("binlog recovery cancelled due to shutdown")
+	}
+
+	if binlogErr != nil {
+		return fmt.Errorf(
+			"%s failed: %v – stderr: %s",
+			filepath.Base(binlogBin),
+			binlogErr,
+			strings.TrimSpace(binlogStderr.String()),
+		)
+	}
+
+	if mysqlErr != nil {
+		return fmt.Errorf(
+			"%s failed to apply binary logs: %v – stderr: %s",
+			filepath.Base(mysqlBin),
+			mysqlErr,
+			strings.TrimSpace(mysqlStderr.String()),
+		)
+	}
+
+	return nil
+}
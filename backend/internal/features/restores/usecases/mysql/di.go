@@ -1,6 +1,7 @@
 package usecases_mysql
 
 import (
+	backups_binlog "databasus-backend/internal/features/backups/binlog"
 	"databasus-backend/internal/features/encryption/secrets"
 	"databasus-backend/internal/util/logger"
 )
@@ -8,6 +9,7 @@ import (
 var restoreMysqlBackupUsecase = &RestoreMysqlBackupUsecase{
 	logger.GetLogger(),
 	secrets.GetSecretKeyService(),
+	backups_binlog.GetBinlogService(),
 }
 
 func GetRestoreMysqlBackupUsecase() *RestoreMysqlBackupUsecase {
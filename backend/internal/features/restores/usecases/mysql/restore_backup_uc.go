@@ -20,6 +20,7 @@ import (
 	"databasus-backend/internal/config"
 	"databasus-backend/internal/features/backups/backups"
 	"databasus-backend/internal/features/backups/backups/encryption"
+	backups_binlog "databasus-backend/internal/features/backups/binlog"
 	backups_config "databasus-backend/internal/features/backups/config"
 	"databasus-backend/internal/features/databases"
 	mysqltypes "databasus-backend/internal/features/databases/databases/mysql"
@@ -33,6 +34,7 @@ import (
 type RestoreMysqlBackupUsecase struct {
 	logger           *slog.Logger
 	secretKeyService *encryption_secrets.SecretKeyService
+	binlogService    *backups_binlog.BinlogService
 }
 
 func (uc *RestoreMysqlBackupUsecase) Execute(
@@ -0,0 +1,308 @@
+package usecases_postgresql
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	archiveFormatCustom = 1
+
+	archiveBlockData  = 1
+	archiveBlockBlobs = 3
+)
+
+// archiveSizesReader reads pg_dump custom format archive (-Fc) to find the
+// size of each data block. Backups are written by pg_dump to stdout, so data
+// offsets are not stored in the TOC and the data blocks have to be scanned.
+// The layout follows pg_backup_archiver.c and pg_backup_custom.c
+type archiveSizesReader struct {
+	reader  *bufio.Reader
+	version int
+	intSize int
+	offSize int
+}
+
+func archiveVersion(major, minor, revision int) int {
+	return major<<16 | minor<<8 | revision
+}
+
+// readArchiveDataSizes returns compressed sizes of table data and large
+// objects blocks by dump ID
+func readArchiveDataSizes(reader io.Reader) (map[int]int64, error) {
+	r := &archiveSizesReader{reader: bufio.NewReaderSize(reader, 1024*1024)}
+
+	if err := r.readHeader(); err != nil {
+		return nil, err
+	}
+
+	if err := r.skipToc(); err != nil {
+		return nil, err
+	}
+
+	sizes := map[int]int64{}
+	for {
+		blockType, err := r.reader.ReadByte()
+		if errors.Is(err, io.EOF) {
+			return sizes, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		dumpID, err := r.readInt()
+		if err != nil {
+			return nil, err
+		}
+
+		var size int64
+		switch blockType {
+		case archiveBlockData:
+			size, err = r.skipChunks()
+		case archiveBlockBlobs:
+			size, err = r.skipBlobs()
+		default:
+			return nil, fmt.Errorf("unexpected archive block type %d", blockType)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		sizes[dumpID] += size
+	}
+}
+
+func (r *archiveSizesReader) readHeader() error {
+	magic := make([]byte, 5)
+	if _, err := io.ReadFull(r.reader, magic); err != nil {
+		return err
+	}
+
+	if string(magic) != "PGDMP" {
+		return errors.New("backup is not a pg_dump custom format archive")
+	}
+
+	versionBytes := make([]byte, 3)
+	if _, err := io.ReadFull(r.reader, versionBytes); err != nil {
+		return err
+	}
+
+	r.version = archiveVersion(
+		int(versionBytes[0]),
+		int(versionBytes[1]),
+		int(versionBytes[2]),
+	)
+	if r.version < archiveVersion(1, 10, 0) || r.version > archiveVersion(1, 16, 0) {
+		return fmt.Errorf(
+			"unsupported archive version %d.%d",
+			versionBytes[0],
+			versionBytes[1],
+		)
+	}
+
+	intSize, err := r.reader.ReadByte()
+	if err != nil {
+		return err
+	}
+	offSize, err := r.reader.ReadByte()
+	if err != nil {
+		return err
+	}
+	r.intSize = int(intSize)
+	r.offSize = int(offSize)
+
+	format, err := r.reader.ReadByte()
+	if err != nil {
+		return err
+	}
+	if format != archiveFormatCustom {
+		return errors.New("backup is not a pg_dump custom format archive")
+	}
+
+	// compression algorithm byte or compression level
+	if r.version >= archiveVersion(1, 15, 0) {
+		_, err = r.reader.ReadByte()
+	} else {
+		_, err = r.readInt()
+	}
+	if err != nil {
+		return err
+	}
+
+	// creation time: sec, min, hour, mday, mon, year, isdst
+	for range 7 {
+		if _, err := r.readInt(); err != nil {
+			return err
+		}
+	}
+
+	// database name, server version and pg_dump version
+	for range 3 {
+		if err := r.skipStr(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *archiveSizesReader) skipToc() error {
+	tocCount, err := r.readInt()
+	if err != nil {
+		return err
+	}
+
+	for range tocCount {
+		// dump ID, had dumper
+		for range 2 {
+			if _, err := r.readInt(); err != nil {
+				return err
+			}
+		}
+
+		// table OID, OID, tag, description
+		for range 4 {
+			if err := r.skipStr(); err != nil {
+				return err
+			}
+		}
+
+		if r.version >= archiveVersion(1, 11, 0) {
+			if _, err := r.readInt(); err != nil { // section
+				return err
+			}
+		}
+
+		// definition, drop statement, copy statement, namespace, tablespace
+		for range 5 {
+			if err := r.skipStr(); err != nil {
+				return err
+			}
+		}
+
+		if r.version >= archiveVersion(1, 14, 0) {
+			if err := r.skipStr(); err != nil { // table access method
+				return err
+			}
+		}
+
+		if r.version >= archiveVersion(1, 16, 0) {
+			if _, err := r.readInt(); err != nil { // relkind
+				return err
+			}
+		}
+
+		// owner, "with OIDs" flag
+		for range 2 {
+			if err := r.skipStr(); err != nil {
+				return err
+			}
+		}
+
+		// dependencies list is terminated by NULL string
+		for {
+			length, err := r.readInt()
+			if err != nil {
+				return err
+			}
+			if length < 0 {
+				break
+			}
+			if _, err := r.reader.Discard(length); err != nil {
+				return err
+			}
+		}
+
+		// data offset: flag byte and offset bytes
+		if _, err := r.reader.Discard(1 + r.offSize); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *archiveSizesReader) skipChunks() (int64, error) {
+	var size int64
+
+	for {
+		length, err := r.readInt()
+		if err != nil {
+			return 0, err
+		}
+		if length == 0 {
+			return size, nil
+		}
+		if length < 0 {
+			return 0, errors.New("invalid archive chunk length")
+		}
+
+		if _, err := r.reader.Discard(length); err != nil {
+			return 0, err
+		}
+
+		size += int64(length)
+	}
+}
+
+func (r *archiveSizesReader) skipBlobs() (int64, error) {
+	var size int64
+
+	for {
+		oid, err := r.readInt()
+		if err != nil {
+			return 0, err
+		}
+		if oid == 0 {
+			return size, nil
+		}
+
+		blobSize, err := r.skipChunks()
+		if err != nil {
+			return 0, err
+		}
+
+		size += blobSize
+	}
+}
+
+// readInt reads sign byte followed by intSize bytes in little-endian order
+func (r *archiveSizesReader) readInt() (int, error) {
+	sign, err := r.reader.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	value := 0
+	for i := range r.intSize {
+		b, err := r.reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+
+		value |= int(b) << (8 * i)
+	}
+
+	if sign != 0 {
+		value = -value
+	}
+
+	return value, nil
+}
+
+// skipStr skips string written as length followed by bytes, negative length
+// means NULL
+func (r *archiveSizesReader) skipStr() error {
+	length, err := r.readInt()
+	if err != nil {
+		return err
+	}
+
+	if length > 0 {
+		_, err = r.reader.Discard(length)
+	}
+
+	return err
+}
@@ -0,0 +1,93 @@
+package usecases_postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"databasus-backend/internal/config"
+	"databasus-backend/internal/features/backups/backups"
+	common "databasus-backend/internal/features/backups/backups/common"
+	"databasus-backend/internal/features/databases"
+	"databasus-backend/internal/features/restores/models"
+	"databasus-backend/internal/features/storages"
+	"databasus-backend/internal/util/tools"
+)
+
+// ListBackupToc downloads the backup and lists its table of contents via
+// pg_restore -l. Data sizes are read from the archive itself; if the archive
+// cannot be scanned, sizes are omitted
+func (uc *RestorePostgresqlBackupUsecase) ListBackupToc(
+	originalDB *databases.Database,
+	backup *backups.Backup,
+	storage *storages.Storage,
+) (*models.BackupToc, error) {
+	if originalDB.Type != databases.DatabaseTypePostgres || originalDB.Postgresql == nil {
+		return nil, errors.New("backup contents can be listed only for PostgreSQL")
+	}
+
+	if backup.Type.IsDataDirectoryArchive() || backup.Type == common.BackupTypeServerDump {
+		return nil, errors.New("backup contents can be listed only for single database backups")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Minute)
+	defer cancel()
+
+	tempBackupFile, cleanupFunc, err := uc.downloadBackupToTempFile(ctx, backup, storage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download backup to temporary file: %w", err)
+	}
+	defer cleanupFunc()
+
+	pgBin := tools.GetPostgresqlExecutable(
+		originalDB.Postgresql.Version,
+		"pg_restore",
+		config.GetEnv().EnvMode,
+		config.GetEnv().PostgresesInstallDir,
+	)
+
+	listCmd := exec.CommandContext(ctx, pgBin, "-l", "-v", tempBackupFile)
+	listCmd.Env = append(os.Environ(), "LC_ALL=C.UTF-8", "LANG=C.UTF-8")
+
+	tocOutput, err := listCmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("failed to list backup contents: %s", string(exitErr.Stderr))
+		}
+
+		return nil, fmt.Errorf("failed to list backup contents: %w", err)
+	}
+
+	sizes, err := uc.readBackupDataSizes(tempBackupFile)
+	if err != nil {
+		uc.logger.Warn(
+			"Failed to read data sizes from backup archive",
+			"backupId",
+			backup.ID,
+			"error",
+			err,
+		)
+	}
+
+	return buildBackupToc(parseTocList(string(tocOutput)), sizes), nil
+}
+
+func (uc *RestorePostgresqlBackupUsecase) readBackupDataSizes(
+	backupFile string,
+) (map[int]int64, error) {
+	file, err := os.Open(backupFile)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			uc.logger.Error("Failed to close backup file", "error", err)
+		}
+	}()
+
+	return readArchiveDataSizes(file)
+}
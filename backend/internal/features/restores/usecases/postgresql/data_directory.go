@@ -0,0 +1,267 @@
+package usecases_postgresql
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"databasus-backend/internal/config"
+	"databasus-backend/internal/features/backups/backups"
+	"databasus-backend/internal/features/backups/backups/encryption"
+	backups_config "databasus-backend/internal/features/backups/config"
+	"databasus-backend/internal/features/storages"
+	util_encryption "databasus-backend/internal/util/encryption"
+)
+
+const dataDirectoryRestoreTimeout = 23 * time.Hour
+
+// createDataDirectoryRestoreContext creates restore context, cancelled on
+// timeout or application shutdown
+func createDataDirectoryRestoreContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(context.Background(), dataDirectoryRestoreTimeout)
+
+	go func() {
+		ticker := time.NewTicker(1 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if config.IsShouldShutdown() {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	return ctx, cancel
+}
+
+// extractDataDirectoryArchive extracts pg_basebackup tar archive of the
+// backup into the data directory
+func (uc *RestorePostgresqlBackupUsecase) extractDataDirectoryArchive(
+	ctx context.Context,
+	backup *backups.Backup,
+	storage *storages.Storage,
+	dataDir string,
+) error {
+	backupReader, err := uc.openBackupReader(backup, storage)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := backupReader.Close(); err != nil {
+			uc.logger.Error("Failed to close backup reader", "error", err)
+		}
+	}()
+
+	tarReader := tar.NewReader(backupReader)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("restore cancelled: %w", err)
+		}
+
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar archive: %w", err)
+		}
+
+		targetPath := filepath.Join(dataDir, header.Name)
+		if !isPathInDirectory(targetPath, dataDir) {
+			return fmt.Errorf("invalid path in backup archive: %s", header.Name)
+		}
+
+		// a symlink created by an earlier entry must not redirect writes
+		// outside of the data directory
+		if err := checkNoSymlinksInPath(dataDir, targetPath); err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(targetPath, 0700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeTarFile(tarReader, targetPath, header); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := validateSymlinkTarget(dataDir, targetPath, header.Linkname); err != nil {
+				return err
+			}
+
+			if err := os.Symlink(header.Linkname, targetPath); err != nil {
+				return err
+			}
+		default:
+			uc.logger.Warn(
+				"Skipping unsupported entry in backup archive",
+				"name",
+				header.Name,
+				"type",
+				header.Typeflag,
+			)
+		}
+	}
+}
+
+// openBackupReader returns backup file reader, decrypting it if needed
+func (uc *RestorePostgresqlBackupUsecase) openBackupReader(
+	backup *backups.Backup,
+	storage *storages.Storage,
+) (io.ReadCloser, error) {
+	rawReader, err := storage.GetFile(util_encryption.GetFieldEncryptor(), backup.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backup file from storage: %w", err)
+	}
+
+	if backup.Encryption != backups_config.BackupEncryptionEncrypted {
+		return rawReader, nil
+	}
+
+	closeOnError := func(err error) (io.ReadCloser, error) {
+		_ = rawReader.Close()
+		return nil, err
+	}
+
+	if backup.EncryptionSalt == nil || backup.EncryptionIV == nil {
+		return closeOnError(fmt.Errorf("backup is encrypted but missing encryption metadata"))
+	}
+
+	masterKey, err := uc.secretKeyService.GetSecretKey()
+	if err != nil {
+		return closeOnError(fmt.Errorf("failed to get master key for decryption: %w", err))
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(*backup.EncryptionSalt)
+	if err != nil {
+		return closeOnError(fmt.Errorf("failed to decode encryption salt: %w", err))
+	}
+
+	iv, err := base64.StdEncoding.DecodeString(*backup.EncryptionIV)
+	if err != nil {
+		return closeOnError(fmt.Errorf("failed to decode encryption IV: %w", err))
+	}
+
+	decryptReader, err := encryption.NewDecryptionReader(rawReader, masterKey, backup.ID, salt, iv)
+	if err != nil {
+		return closeOnError(fmt.Errorf("failed to create decryption reader: %w", err))
+	}
+
+	return &readCloser{decryptReader, rawReader}, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func prepareEmptyDataDirectory(dataDir string) error {
+	entries, err := os.ReadDir(dataDir)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read target data directory: %w", err)
+	}
+
+	if len(entries) > 0 {
+		return errors.New("target data directory is not empty")
+	}
+
+	if err := os.MkdirAll(dataDir, 0700); err != nil {
+		return fmt.Errorf("failed to create target data directory: %w", err)
+	}
+
+	// PostgreSQL refuses to start if data directory is accessible by others
+	return os.Chmod(dataDir, 0700)
+}
+
+// validateSymlinkTarget allows only relative symlinks resolving inside the
+// data directory. Tablespaces symlinks (pg_tblspc) are absolute, they are
+// not supported by physical restores
+func validateSymlinkTarget(dataDir string, linkPath string, linkTarget string) error {
+	if filepath.IsAbs(linkTarget) {
+		return fmt.Errorf("absolute symlink in backup archive is not allowed: %s", linkTarget)
+	}
+
+	resolvedPath := filepath.Join(filepath.Dir(linkPath), linkTarget)
+	if !isPathInDirectory(resolvedPath, dataDir) {
+		return fmt.Errorf(
+			"symlink in backup archive points outside of the data directory: %s",
+			linkTarget,
+		)
+	}
+
+	return nil
+}
+
+// checkNoSymlinksInPath returns an error if the path or any of its parent
+// directories inside the data directory is a symlink
+func checkNoSymlinksInPath(dataDir string, targetPath string) error {
+	relativePath, err := filepath.Rel(dataDir, targetPath)
+	if err != nil {
+		return err
+	}
+
+	if relativePath == "." {
+		return nil
+	}
+
+	currentPath := dataDir
+	for _, part := range strings.Split(relativePath, string(os.PathSeparator)) {
+		currentPath = filepath.Join(currentPath, part)
+
+		fileInfo, err := os.Lstat(currentPath)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if fileInfo.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("backup archive writes through symlink: %s", currentPath)
+		}
+	}
+
+	return nil
+}
+
+func isPathInDirectory(path string, dir string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(os.PathSeparator))
+}
+
+func writeTarFile(reader io.Reader, targetPath string, header *tar.Header) error {
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0700); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(
+		targetPath,
+		os.O_CREATE|os.O_WRONLY|os.O_TRUNC,
+		os.FileMode(header.Mode).Perm(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(file, reader); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
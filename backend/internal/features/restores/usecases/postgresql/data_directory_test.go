@@ -0,0 +1,40 @@
+package usecases_postgresql
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ValidateSymlinkTarget_OnlyRelativeLinksInsideDataDirAllowed(t *testing.T) {
+	dataDir := t.TempDir()
+	linkPath := filepath.Join(dataDir, "pg_wal_link")
+
+	assert.NoError(t, validateSymlinkTarget(dataDir, linkPath, "pg_wal"))
+	assert.NoError(t, validateSymlinkTarget(dataDir, linkPath, "base/../pg_wal"))
+
+	assert.Error(t, validateSymlinkTarget(dataDir, linkPath, "/etc"))
+	assert.Error(t, validateSymlinkTarget(dataDir, linkPath, "../outside"))
+	assert.Error(t, validateSymlinkTarget(
+		dataDir,
+		filepath.Join(dataDir, "base", "link"),
+		"../../outside",
+	))
+}
+
+func Test_CheckNoSymlinksInPath_WritesThroughSymlinkRejected(t *testing.T) {
+	dataDir := t.TempDir()
+	outsideDir := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dataDir, "base"), 0700))
+	require.NoError(t, os.Symlink(outsideDir, filepath.Join(dataDir, "link")))
+
+	assert.NoError(t, checkNoSymlinksInPath(dataDir, filepath.Join(dataDir, "base", "1")))
+	assert.NoError(t, checkNoSymlinksInPath(dataDir, filepath.Join(dataDir, "new", "file")))
+
+	assert.Error(t, checkNoSymlinksInPath(dataDir, filepath.Join(dataDir, "link")))
+	assert.Error(t, checkNoSymlinksInPath(dataDir, filepath.Join(dataDir, "link", "file")))
+}
@@ -1,6 +1,7 @@
 package usecases_postgresql
 
 import (
+	backups_wal "databasus-backend/internal/features/backups/wal"
 	"databasus-backend/internal/features/encryption/secrets"
 	"databasus-backend/internal/util/logger"
 )
@@ -8,6 +9,7 @@ import (
 var restorePostgresqlBackupUsecase = &RestorePostgresqlBackupUsecase{
 	logger.GetLogger(),
 	secrets.GetSecretKeyService(),
+	backups_wal.GetWalService(),
 }
 
 func GetRestorePostgresqlBackupUsecase() *RestorePostgresqlBackupUsecase {
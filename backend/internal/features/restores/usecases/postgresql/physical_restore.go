@@ -0,0 +1,73 @@
+package usecases_postgresql
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"databasus-backend/internal/features/backups/backups"
+	common "databasus-backend/internal/features/backups/backups/common"
+	"databasus-backend/internal/features/databases"
+	"databasus-backend/internal/features/restores/models"
+	"databasus-backend/internal/features/storages"
+)
+
+// ExecutePhysicalRestore lays out the physical backup into the target data
+// directory. The archive already contains WAL required for consistency, so
+// PostgreSQL of the same major version can be started on the directory as is
+func (uc *RestorePostgresqlBackupUsecase) ExecutePhysicalRestore(
+	originalDB *databases.Database,
+	restore models.Restore,
+	backup *backups.Backup,
+	storage *storages.Storage,
+	physicalRestore *models.PhysicalRestore,
+) error {
+	if originalDB.Type != databases.DatabaseTypePostgres {
+		return errors.New("physical restore is supported only for PostgreSQL")
+	}
+
+	if backup.Type != common.BackupTypePhysical {
+		return errors.New("physical restore requires a physical backup")
+	}
+
+	if err := physicalRestore.Validate(); err != nil {
+		return err
+	}
+
+	uc.logger.Info(
+		"Restoring PostgreSQL physical backup into data directory",
+		"restoreId",
+		restore.ID,
+		"backupId",
+		backup.ID,
+		"targetDataDirectory",
+		physicalRestore.TargetDataDirectory,
+	)
+
+	ctx, cancel := createDataDirectoryRestoreContext()
+	defer cancel()
+
+	dataDir := filepath.Clean(physicalRestore.TargetDataDirectory)
+	if err := prepareEmptyDataDirectory(dataDir); err != nil {
+		return err
+	}
+
+	if err := uc.extractDataDirectoryArchive(ctx, backup, storage, dataDir); err != nil {
+		return fmt.Errorf("failed to extract physical backup: %w", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dataDir, "PG_VERSION")); err != nil {
+		return errors.New("backup archive does not contain a PostgreSQL data directory")
+	}
+
+	uc.logger.Info(
+		"Physical restore completed, start PostgreSQL on the data directory",
+		"restoreId",
+		restore.ID,
+		"targetDataDirectory",
+		dataDir,
+	)
+
+	return nil
+}
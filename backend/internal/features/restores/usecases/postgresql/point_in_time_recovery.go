@@ -0,0 +1,152 @@
+package usecases_postgresql
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"databasus-backend/internal/features/backups/backups"
+	common "databasus-backend/internal/features/backups/backups/common"
+	"databasus-backend/internal/features/databases"
+	"databasus-backend/internal/features/restores/models"
+	"databasus-backend/internal/features/storages"
+)
+
+const walArchiveDirName = "databasus_wal_archive"
+
+// ExecutePointInTimeRecovery lays out the base backup into the target data
+// directory, downloads archived WAL and configures recovery, so PostgreSQL
+// replays WAL up to the recovery target on the first start
+func (uc *RestorePostgresqlBackupUsecase) ExecutePointInTimeRecovery(
+	originalDB *databases.Database,
+	restore models.Restore,
+	backup *backups.Backup,
+	storage *storages.Storage,
+	pitr *models.PointInTimeRecovery,
+) error {
+	if originalDB.Type != databases.DatabaseTypePostgres {
+		return errors.New("point-in-time recovery is supported only for PostgreSQL")
+	}
+
+	if backup.Type != common.BackupTypeBaseBackup {
+		return errors.New("point-in-time recovery requires a base backup taken with WAL archiving")
+	}
+
+	if err := pitr.Validate(); err != nil {
+		return err
+	}
+
+	if pitr.RecoveryTargetTime != nil && pitr.RecoveryTargetTime.Before(backup.CreatedAt) {
+		return errors.New("recovery target time is earlier than the base backup")
+	}
+
+	uc.logger.Info(
+		"Restoring PostgreSQL base backup with point-in-time recovery",
+		"restoreId",
+		restore.ID,
+		"backupId",
+		backup.ID,
+		"targetDataDirectory",
+		pitr.TargetDataDirectory,
+	)
+
+	ctx, cancel := createDataDirectoryRestoreContext()
+	defer cancel()
+
+	dataDir := filepath.Clean(pitr.TargetDataDirectory)
+	if err := prepareEmptyDataDirectory(dataDir); err != nil {
+		return err
+	}
+
+	if err := uc.extractDataDirectoryArchive(ctx, backup, storage, dataDir); err != nil {
+		return fmt.Errorf("failed to extract base backup: %w", err)
+	}
+
+	// WAL is not included into base backups, but PostgreSQL expects the directory
+	if err := os.MkdirAll(filepath.Join(dataDir, "pg_wal"), 0700); err != nil {
+		return fmt.Errorf("failed to create pg_wal directory: %w", err)
+	}
+
+	walArchiveDir := filepath.Join(dataDir, walArchiveDirName)
+	if err := os.MkdirAll(walArchiveDir, 0700); err != nil {
+		return fmt.Errorf("failed to create WAL archive directory: %w", err)
+	}
+
+	downloadedCount, err := uc.walService.DownloadSegments(
+		ctx,
+		originalDB.ID,
+		backup.WalStartLsn,
+		walArchiveDir,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to download WAL segments: %w", err)
+	}
+
+	if downloadedCount == 0 {
+		return errors.New("no archived WAL segments found after the base backup")
+	}
+
+	if err := writeRecoveryConfig(dataDir, pitr); err != nil {
+		return err
+	}
+
+	uc.logger.Info(
+		"Point-in-time recovery prepared, start PostgreSQL on the data directory to replay WAL",
+		"restoreId",
+		restore.ID,
+		"targetDataDirectory",
+		dataDir,
+		"walSegmentsCount",
+		downloadedCount,
+	)
+
+	return nil
+}
+
+// writeRecoveryConfig creates recovery.signal and appends recovery settings into
+// postgresql.auto.conf. restore_command uses relative path, because PostgreSQL
+// runs it in the data directory, so the directory can be moved after restore
+func writeRecoveryConfig(dataDir string, pitr *models.PointInTimeRecovery) error {
+	settings := []string{
+		"",
+		"# Added by Databasus point-in-time recovery",
+		fmt.Sprintf("restore_command = 'cp \"%s/%%f\" \"%%p\"'", walArchiveDirName),
+	}
+
+	if pitr.RecoveryTargetTime != nil {
+		settings = append(settings, fmt.Sprintf(
+			"recovery_target_time = '%s'",
+			pitr.RecoveryTargetTime.UTC().Format("2006-01-02 15:04:05.999999-07:00"),
+		))
+	}
+
+	if pitr.RecoveryTargetLsn != nil {
+		settings = append(settings, fmt.Sprintf("recovery_target_lsn = '%s'", *pitr.RecoveryTargetLsn))
+	}
+
+	if pitr.RecoveryTargetTime != nil || pitr.RecoveryTargetLsn != nil {
+		settings = append(settings, "recovery_target_action = 'promote'")
+	}
+
+	autoConfFile, err := os.OpenFile(
+		filepath.Join(dataDir, "postgresql.auto.conf"),
+		os.O_CREATE|os.O_WRONLY|os.O_APPEND,
+		0600,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to open postgresql.auto.conf: %w", err)
+	}
+
+	if _, err := autoConfFile.WriteString(strings.Join(settings, "\n") + "\n"); err != nil {
+		_ = autoConfFile.Close()
+		return fmt.Errorf("failed to write recovery settings: %w", err)
+	}
+
+	if err := autoConfFile.Close(); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dataDir, "recovery.signal"), []byte{}, 0600)
+}
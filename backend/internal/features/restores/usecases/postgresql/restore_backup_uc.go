@@ -16,8 +16,10 @@ import (
 
 	"databasus-backend/internal/config"
 	"databasus-backend/internal/features/backups/backups"
+	common "databasus-backend/internal/features/backups/backups/common"
 	"databasus-backend/internal/features/backups/backups/encryption"
 	backups_config "databasus-backend/internal/features/backups/config"
+	backups_wal "databasus-backend/internal/features/backups/wal"
 	"databasus-backend/internal/features/databases"
 	pgtypes "databasus-backend/internal/features/databases/databases/postgresql"
 	encryption_secrets "databasus-backend/internal/features/encryption/secrets"
@@ -32,6 +34,7 @@ import (
 type RestorePostgresqlBackupUsecase struct {
 	logger           *slog.Logger
 	secretKeyService *encryption_secrets.SecretKeyService
+	walService       *backups_wal.WalService
 }
 
 func (uc *RestorePostgresqlBackupUsecase) Execute(
@@ -42,11 +45,24 @@ func (uc *RestorePostgresqlBackupUsecase) Execute(
 	backup *backups.Backup,
 	storage *storages.Storage,
 	isExcludeExtensions bool,
+	selectiveRestore *models.SelectiveRestore,
 ) error {
 	if originalDB.Type != databases.DatabaseTypePostgres {
 		return errors.New("database type not supported")
 	}
 
+	if backup.Type == common.BackupTypeBaseBackup {
+		return errors.New("base backups can be restored only via point-in-time recovery")
+	}
+
+	if backup.Type == common.BackupTypePhysical {
+		return errors.New("physical backups can be restored only into a data directory")
+	}
+
+	if backup.Type == common.BackupTypeServerDump {
+		return errors.New("server backups can be restored only via server restore")
+	}
+
 	uc.logger.Info(
 		"Restoring PostgreSQL backup via pg_restore",
 		"restoreId",
@@ -79,6 +95,7 @@ func (uc *RestorePostgresqlBackupUsecase) Execute(
 		storage,
 		pg,
 		isExcludeExtensions,
+		selectiveRestore,
 	)
 }
 
@@ -90,6 +107,7 @@ func (uc *RestorePostgresqlBackupUsecase) restoreCustomType(
 	storage *storages.Storage,
 	pg *pgtypes.PostgresqlDatabase,
 	isExcludeExtensions bool,
+	selectiveRestore *models.SelectiveRestore,
 ) error {
 	uc.logger.Info(
 		"Restoring backup in custom type (-Fc)",
@@ -99,10 +117,19 @@ func (uc *RestorePostgresqlBackupUsecase) restoreCustomType(
 		pg.CpuCount,
 	)
 
-	// If excluding extensions, we must use file-based restore (requires TOC file generation)
+	// If excluding extensions or restoring selected objects, we must use file-based
+	// restore (requires TOC file generation)
 	// Also use file-based restore for parallel jobs (multiple CPUs)
-	if isExcludeExtensions || pg.CpuCount > 1 {
-		return uc.restoreViaFile(originalDB, pgBin, backup, storage, pg, isExcludeExtensions)
+	if isExcludeExtensions || selectiveRestore != nil || pg.CpuCount > 1 {
+		return uc.restoreViaFile(
+			originalDB,
+			pgBin,
+			backup,
+			storage,
+			pg,
+			isExcludeExtensions,
+			selectiveRestore,
+		)
 	}
 
 	// Single CPU without extension exclusion: stream directly via stdin
@@ -325,6 +352,7 @@ func (uc *RestorePostgresqlBackupUsecase) restoreViaFile(
 	storage *storages.Storage,
 	pg *pgtypes.PostgresqlDatabase,
 	isExcludeExtensions bool,
+	selectiveRestore *models.SelectiveRestore,
 ) error {
 	uc.logger.Info(
 		"Restoring via file with parallel jobs",
@@ -347,12 +375,17 @@ func (uc *RestorePostgresqlBackupUsecase) restoreViaFile(
 		"-U", pg.Username,
 		"-d", *pg.Database,
 		"--verbose",
-		"--clean",
-		"--if-exists",
 		"--no-owner",
 		"--no-acl",
 	}
 
+	// Data only restore fills existing tables, nothing is dropped
+	if selectiveRestore != nil && selectiveRestore.IsDataOnly {
+		args = append(args, "--data-only")
+	} else {
+		args = append(args, "--clean", "--if-exists")
+	}
+
 	return uc.restoreFromStorage(
 		originalDB,
 		pgBin,
@@ -362,6 +395,7 @@ func (uc *RestorePostgresqlBackupUsecase) restoreViaFile(
 		storage,
 		pg,
 		isExcludeExtensions,
+		selectiveRestore,
 	)
 }
 
@@ -375,6 +409,7 @@ func (uc *RestorePostgresqlBackupUsecase) restoreFromStorage(
 	storage *storages.Storage,
 	pgConfig *pgtypes.PostgresqlDatabase,
 	isExcludeExtensions bool,
+	selectiveRestore *models.SelectiveRestore,
 ) error {
 	uc.logger.Info(
 		"Restoring PostgreSQL backup from storage via temporary file",
@@ -384,6 +419,8 @@ func (uc *RestorePostgresqlBackupUsecase) restoreFromStorage(
 		args,
 		"isExcludeExtensions",
 		isExcludeExtensions,
+		"isSelectiveRestore",
+		selectiveRestore != nil,
 	)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Minute)
@@ -440,14 +477,17 @@ func (uc *RestorePostgresqlBackupUsecase) restoreFromStorage(
 	}
 	defer cleanupFunc()
 
-	// If excluding extensions, generate filtered TOC list and use it
-	if isExcludeExtensions {
+	// If excluding extensions or restoring selected objects, generate filtered TOC
+	// list and use it
+	if isExcludeExtensions || selectiveRestore != nil {
 		tocListFile, err := uc.generateFilteredTocList(
 			ctx,
 			pgBin,
 			tempBackupFile,
 			pgpassFile,
 			pgConfig,
+			isExcludeExtensions,
+			selectiveRestore,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to generate filtered TOC list: %w", err)
@@ -836,19 +876,30 @@ func containsIgnoreCase(str, substr string) bool {
 	return strings.Contains(strings.ToLower(str), strings.ToLower(substr))
 }
 
-// generateFilteredTocList generates a pg_restore TOC list file with extensions filtered out.
-// This is used when isExcludeExtensions is true to skip CREATE EXTENSION statements.
+// generateFilteredTocList generates a pg_restore TOC list file with extensions and
+// not selected objects filtered out. This is used when isExcludeExtensions is true to
+// skip CREATE EXTENSION statements and when only a part of the backup is restored.
 func (uc *RestorePostgresqlBackupUsecase) generateFilteredTocList(
 	ctx context.Context,
 	pgBin string,
 	backupFile string,
 	pgpassFile string,
 	pgConfig *pgtypes.PostgresqlDatabase,
+	isExcludeExtensions bool,
+	selectiveRestore *models.SelectiveRestore,
 ) (string, error) {
-	uc.logger.Info("Generating filtered TOC list to exclude extensions", "backupFile", backupFile)
+	uc.logger.Info(
+		"Generating filtered TOC list",
+		"backupFile",
+		backupFile,
+		"isExcludeExtensions",
+		isExcludeExtensions,
+		"isSelectiveRestore",
+		selectiveRestore != nil,
+	)
 
-	// Run pg_restore -l to get the TOC list
-	listCmd := exec.CommandContext(ctx, pgBin, "-l", backupFile)
+	// Run pg_restore -l to get the TOC list, verbose mode adds dependencies
+	listCmd := exec.CommandContext(ctx, pgBin, "-l", "-v", backupFile)
 	uc.setupPgRestoreEnvironment(listCmd, pgpassFile, pgConfig)
 
 	tocOutput, err := listCmd.Output()
@@ -856,8 +907,12 @@ func (uc *RestorePostgresqlBackupUsecase) generateFilteredTocList(
 		return "", fmt.Errorf("failed to generate TOC list: %w", err)
 	}
 
+	tocList := parseTocList(string(tocOutput))
+
 	// Filter out EXTENSION-related lines (both CREATE EXTENSION and COMMENT ON EXTENSION)
+	// and entries which do not belong to selected schemas and tables
 	var filteredLines []string
+	selectedEntriesCount := 0
 	for line := range strings.SplitSeq(string(tocOutput), "\n") {
 		trimmedLine := strings.TrimSpace(line)
 		if trimmedLine == "" {
@@ -869,14 +924,28 @@ func (uc *RestorePostgresqlBackupUsecase) generateFilteredTocList(
 		// Skip lines that contain " EXTENSION " - this catches both:
 		// - CREATE EXTENSION entries: "3420; 0 0 EXTENSION - uuid-ossp"
 		// - COMMENT ON EXTENSION entries: "3462; 0 0 COMMENT - EXTENSION "uuid-ossp""
-		if strings.Contains(upperLine, " EXTENSION ") {
+		if isExcludeExtensions && strings.Contains(upperLine, " EXTENSION ") {
 			uc.logger.Info("Excluding extension-related entry from restore", "tocLine", trimmedLine)
 			continue
 		}
 
+		entry := parseTocEntryLine(line)
+		if selectiveRestore != nil && entry != nil &&
+			!tocList.isSelected(tocList.byID[entry.DumpID], selectiveRestore) {
+			continue
+		}
+
+		if entry != nil {
+			selectedEntriesCount++
+		}
+
 		filteredLines = append(filteredLines, line)
 	}
 
+	if selectiveRestore != nil && selectedEntriesCount == 0 {
+		return "", errors.New("no objects in the backup match selected schemas and tables")
+	}
+
 	// Write filtered TOC to temporary file
 	tocFile, err := os.CreateTemp(config.GetEnv().TempFolder, "pg_restore_toc_*.list")
 	if err != nil {
@@ -0,0 +1,314 @@
+package usecases_postgresql
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"databasus-backend/internal/config"
+	"databasus-backend/internal/features/backups/backups"
+	common "databasus-backend/internal/features/backups/backups/common"
+	"databasus-backend/internal/features/databases"
+	pgtypes "databasus-backend/internal/features/databases/databases/postgresql"
+	"databasus-backend/internal/features/restores/models"
+	"databasus-backend/internal/features/storages"
+	"databasus-backend/internal/util/tools"
+)
+
+const defaultMaintenanceDatabase = "postgres"
+
+// ExecuteServerRestore restores the server backup archive into the target
+// server. Globals are applied via psql and errors for already existing roles
+// are ignored. Databases are restored one by one as they are read from the
+// archive, so only one database dump is kept on disk at a time
+func (uc *RestorePostgresqlBackupUsecase) ExecuteServerRestore(
+	originalDB *databases.Database,
+	restoringToDB *databases.Database,
+	restore models.Restore,
+	backup *backups.Backup,
+	storage *storages.Storage,
+	serverRestore *models.ServerRestore,
+) error {
+	if originalDB.Type != databases.DatabaseTypePostgres {
+		return errors.New("server restore is supported only for PostgreSQL")
+	}
+
+	if backup.Type != common.BackupTypeServerDump {
+		return errors.New("server restore requires a server backup")
+	}
+
+	if err := serverRestore.Validate(); err != nil {
+		return err
+	}
+
+	pg := restoringToDB.Postgresql
+	if pg == nil {
+		return fmt.Errorf("postgresql configuration is required for restore")
+	}
+
+	if serverRestore.Scope == models.ServerRestoreScopeDatabase &&
+		(pg.Database == nil || *pg.Database == "") {
+		return fmt.Errorf("target database name is required to restore a single database")
+	}
+
+	uc.logger.Info(
+		"Restoring PostgreSQL server backup",
+		"restoreId",
+		restore.ID,
+		"backupId",
+		backup.ID,
+		"scope",
+		serverRestore.Scope,
+	)
+
+	ctx, cancel := createDataDirectoryRestoreContext()
+	defer cancel()
+
+	pgpassFile, err := uc.createTempPgpassFile(pg, pg.Password)
+	if err != nil {
+		return fmt.Errorf("failed to create temporary .pgpass file: %w", err)
+	}
+	if pgpassFile == "" {
+		return fmt.Errorf("temporary .pgpass file was not created")
+	}
+	defer func() {
+		_ = os.RemoveAll(filepath.Dir(pgpassFile))
+	}()
+
+	backupReader, err := uc.openBackupReader(backup, storage)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := backupReader.Close(); err != nil {
+			uc.logger.Error("Failed to close backup reader", "error", err)
+		}
+	}()
+
+	isDatabaseFound := false
+	tarReader := tar.NewReader(backupReader)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("restore cancelled: %w", err)
+		}
+
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read server backup archive: %w", err)
+		}
+
+		if header.Name == common.ServerDumpGlobalsEntry {
+			if serverRestore.Scope == models.ServerRestoreScopeDatabase {
+				continue
+			}
+
+			err = uc.restoreServerGlobals(ctx, originalDB, pg, pgpassFile, tarReader)
+			if err != nil {
+				return err
+			}
+
+			if serverRestore.Scope == models.ServerRestoreScopeGlobals {
+				return nil
+			}
+
+			continue
+		}
+
+		databaseName, ok := common.ParseServerDumpDatabaseEntry(header.Name)
+		if !ok {
+			uc.logger.Warn("Skipping unknown entry in server backup archive", "name", header.Name)
+			continue
+		}
+
+		switch serverRestore.Scope {
+		case models.ServerRestoreScopeFull:
+			err = uc.restoreServerDatabase(
+				ctx,
+				originalDB,
+				pg,
+				pgpassFile,
+				tarReader,
+				databaseName,
+				true,
+			)
+		case models.ServerRestoreScopeDatabase:
+			if databaseName != *serverRestore.DatabaseName {
+				continue
+			}
+
+			isDatabaseFound = true
+			err = uc.restoreServerDatabase(
+				ctx,
+				originalDB,
+				pg,
+				pgpassFile,
+				tarReader,
+				databaseName,
+				false,
+			)
+		}
+		if err != nil {
+			return err
+		}
+
+		if isDatabaseFound {
+			return nil
+		}
+	}
+
+	if serverRestore.Scope == models.ServerRestoreScopeDatabase {
+		return fmt.Errorf("database '%s' not found in the backup", *serverRestore.DatabaseName)
+	}
+
+	if serverRestore.Scope == models.ServerRestoreScopeGlobals {
+		return errors.New("globals not found in the backup")
+	}
+
+	return nil
+}
+
+// restoreServerGlobals applies roles and tablespaces via psql without
+// ON_ERROR_STOP, so already existing roles do not abort the restore
+func (uc *RestorePostgresqlBackupUsecase) restoreServerGlobals(
+	ctx context.Context,
+	originalDB *databases.Database,
+	pg *pgtypes.PostgresqlDatabase,
+	pgpassFile string,
+	globalsReader io.Reader,
+) error {
+	globalsFile, err := uc.extractServerArchivePart(ctx, globalsReader)
+	if err != nil {
+		return fmt.Errorf("failed to extract globals: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(globalsFile)
+	}()
+
+	psqlBin := tools.GetPostgresqlExecutable(
+		pg.Version,
+		tools.PostgresqlExecutablePsql,
+		config.GetEnv().EnvMode,
+		config.GetEnv().PostgresesInstallDir,
+	)
+
+	args := []string{
+		"-X", // do not read psqlrc
+		"--no-password",
+		"-h", pg.Host,
+		"-p", strconv.Itoa(pg.Port),
+		"-U", pg.Username,
+		"-d", getMaintenanceDatabase(pg),
+		"-f", globalsFile,
+	}
+
+	uc.logger.Info("Restoring server globals (roles and tablespaces)")
+
+	if err := uc.executePgRestore(ctx, originalDB, psqlBin, args, pgpassFile, pg); err != nil {
+		return fmt.Errorf("failed to restore globals: %w", err)
+	}
+
+	return nil
+}
+
+// restoreServerDatabase restores the database dump from the archive. When
+// isRecreate is set the database is dropped and created with its original
+// name, owner and privileges, otherwise the dump is restored into the target
+// database without owners and privileges like a regular restore
+func (uc *RestorePostgresqlBackupUsecase) restoreServerDatabase(
+	ctx context.Context,
+	originalDB *databases.Database,
+	pg *pgtypes.PostgresqlDatabase,
+	pgpassFile string,
+	dumpReader io.Reader,
+	databaseName string,
+	isRecreate bool,
+) error {
+	dumpFile, err := uc.extractServerArchivePart(ctx, dumpReader)
+	if err != nil {
+		return fmt.Errorf("failed to extract dump of database '%s': %w", databaseName, err)
+	}
+	defer func() {
+		_ = os.Remove(dumpFile)
+	}()
+
+	pgBin := tools.GetPostgresqlExecutable(
+		pg.Version,
+		"pg_restore",
+		config.GetEnv().EnvMode,
+		config.GetEnv().PostgresesInstallDir,
+	)
+
+	args := []string{
+		"-Fc",
+		"-j", strconv.Itoa(max(1, min(pg.CpuCount, 8))),
+		"--no-password",
+		"-h", pg.Host,
+		"-p", strconv.Itoa(pg.Port),
+		"-U", pg.Username,
+		"--verbose",
+		"--clean",
+		"--if-exists",
+	}
+
+	if isRecreate {
+		// with --create pg_restore connects to the given database only to
+		// issue DROP and CREATE DATABASE, so it cannot be the restored one
+		connectDatabase := getMaintenanceDatabase(pg)
+		if connectDatabase == databaseName {
+			connectDatabase = "template1"
+		}
+
+		args = append(args, "--create", "-d", connectDatabase)
+	} else {
+		args = append(args, "--no-owner", "--no-acl", "-d", *pg.Database)
+	}
+
+	args = append(args, dumpFile)
+
+	uc.logger.Info("Restoring server database", "database", databaseName, "isRecreate", isRecreate)
+
+	if err := uc.executePgRestore(ctx, originalDB, pgBin, args, pgpassFile, pg); err != nil {
+		return fmt.Errorf("failed to restore database '%s': %w", databaseName, err)
+	}
+
+	return nil
+}
+
+// extractServerArchivePart writes the current archive entry into a temporary
+// file, pg_restore requires a seekable file for parallel restore
+func (uc *RestorePostgresqlBackupUsecase) extractServerArchivePart(
+	ctx context.Context,
+	reader io.Reader,
+) (string, error) {
+	partFile, err := os.CreateTemp(config.GetEnv().TempFolder, "server_restore_*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+
+	_, copyErr := uc.copyWithShutdownCheck(ctx, partFile, reader)
+	closeErr := partFile.Close()
+
+	if copyErr != nil || closeErr != nil {
+		_ = os.Remove(partFile.Name())
+		return "", errors.Join(copyErr, closeErr)
+	}
+
+	return partFile.Name(), nil
+}
+
+func getMaintenanceDatabase(pg *pgtypes.PostgresqlDatabase) string {
+	if pg.Database != nil && *pg.Database != "" {
+		return *pg.Database
+	}
+
+	return defaultMaintenanceDatabase
+}
@@ -0,0 +1,378 @@
+package usecases_postgresql
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"databasus-backend/internal/features/restores/models"
+)
+
+// tocEntryRegex matches pg_restore -l entry line: "<dumpId>; <tableoid> <oid> <rest>"
+var tocEntryRegex = regexp.MustCompile(`^(\d+); \d+ \d+ (.+)$`)
+
+// multiWordObjectTypes are TOC object types containing spaces. They must be
+// checked before splitting the line by spaces, longest first
+var multiWordObjectTypes = []string{
+	"PUBLICATION TABLES IN SCHEMA",
+	"TEXT SEARCH CONFIGURATION",
+	"TEXT SEARCH DICTIONARY",
+	"MATERIALIZED VIEW DATA",
+	"TEXT SEARCH TEMPLATE",
+	"FOREIGN DATA WRAPPER",
+	"DATABASE PROPERTIES",
+	"PROCEDURAL LANGUAGE",
+	"TEXT SEARCH PARSER",
+	"PUBLICATION TABLE",
+	"SEQUENCE OWNED BY",
+	"MATERIALIZED VIEW",
+	"SUBSCRIPTION TABLE",
+	"CHECK CONSTRAINT",
+	"STATISTICS DATA",
+	"OPERATOR FAMILY",
+	"OPERATOR CLASS",
+	"SECURITY LABEL",
+	"BLOB METADATA",
+	"EVENT TRIGGER",
+	"FOREIGN TABLE",
+	"ACCESS METHOD",
+	"FK CONSTRAINT",
+	"LARGE OBJECTS",
+	"LARGE OBJECT",
+	"INDEX ATTACH",
+	"SEQUENCE SET",
+	"TABLE ATTACH",
+	"USER MAPPING",
+	"ROW SECURITY",
+	"DEFAULT ACL",
+	"SHELL TYPE",
+	"TABLE DATA",
+}
+
+// relationObjectTypes are selected by "schema.table" names
+var relationObjectTypes = []string{
+	"TABLE",
+	"VIEW",
+	"MATERIALIZED VIEW",
+	"FOREIGN TABLE",
+	"SEQUENCE",
+}
+
+// attachedObjectTypes have no meaning without the object they depend on and
+// are restored together with it (e.g. indexes and data of a table)
+var attachedObjectTypes = []string{
+	"TABLE DATA",
+	"SEQUENCE SET",
+	"SEQUENCE OWNED BY",
+	"MATERIALIZED VIEW DATA",
+	"INDEX",
+	"INDEX ATTACH",
+	"TABLE ATTACH",
+	"CONSTRAINT",
+	"CHECK CONSTRAINT",
+	"FK CONSTRAINT",
+	"TRIGGER",
+	"DEFAULT",
+	"POLICY",
+	"ROW SECURITY",
+	"RULE",
+	"STATISTICS",
+	"STATISTICS DATA",
+	"PUBLICATION TABLE",
+	"COMMENT",
+	"SECURITY LABEL",
+	"ACL",
+}
+
+// tocEntry is a parsed entry of pg_restore -l -v output
+type tocEntry struct {
+	DumpID       int
+	ObjectType   string
+	Schema       string
+	Name         string
+	Owner        string
+	Dependencies []int
+	Line         string
+}
+
+type tocList struct {
+	entries []*tocEntry
+	byID    map[int]*tocEntry
+
+	// sequenceTables maps sequences used by table defaults or identity
+	// columns to the tables, so they are restored together
+	sequenceTables map[int]int
+}
+
+// parseTocList parses pg_restore -l -v output. Dependencies are printed by
+// verbose mode as "; depends on: 1 2" comment lines after the entry
+func parseTocList(output string) *tocList {
+	list := &tocList{
+		byID:           map[int]*tocEntry{},
+		sequenceTables: map[int]int{},
+	}
+
+	var lastEntry *tocEntry
+	for line := range strings.SplitSeq(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		if strings.HasPrefix(line, ";") {
+			comment := strings.TrimSpace(strings.TrimPrefix(line, ";"))
+			dependencies, ok := strings.CutPrefix(comment, "depends on:")
+			if ok && lastEntry != nil {
+				for field := range strings.FieldsSeq(dependencies) {
+					if dumpID, err := strconv.Atoi(field); err == nil {
+						lastEntry.Dependencies = append(lastEntry.Dependencies, dumpID)
+					}
+				}
+			}
+
+			continue
+		}
+
+		entry := parseTocEntryLine(line)
+		if entry == nil {
+			continue
+		}
+
+		list.entries = append(list.entries, entry)
+		list.byID[entry.DumpID] = entry
+		lastEntry = entry
+	}
+
+	list.linkSequencesToTables()
+
+	return list
+}
+
+func parseTocEntryLine(line string) *tocEntry {
+	matches := tocEntryRegex.FindStringSubmatch(strings.TrimSpace(line))
+	if matches == nil {
+		return nil
+	}
+
+	dumpID, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return nil
+	}
+
+	rest := matches[2]
+
+	objectType, _, _ := strings.Cut(rest, " ")
+	for _, multiWordType := range multiWordObjectTypes {
+		if strings.HasPrefix(rest, multiWordType+" ") {
+			objectType = multiWordType
+			break
+		}
+	}
+
+	rest = strings.TrimPrefix(rest, objectType)
+	rest = strings.TrimPrefix(rest, " ")
+
+	// schema has no spaces and is "-" when absent; owner is the last field
+	// and may be empty; tag is everything between and may contain spaces
+	schema, tagAndOwner, _ := strings.Cut(rest, " ")
+	if schema == "-" {
+		schema = ""
+	}
+
+	name := tagAndOwner
+	owner := ""
+	if idx := strings.LastIndex(tagAndOwner, " "); idx >= 0 {
+		name = tagAndOwner[:idx]
+		owner = tagAndOwner[idx+1:]
+	}
+
+	return &tocEntry{
+		DumpID:     dumpID,
+		ObjectType: objectType,
+		Schema:     schema,
+		Name:       name,
+		Owner:      owner,
+		Line:       line,
+	}
+}
+
+// isSelected reports whether the entry belongs to the selected part of the
+// backup. Attached objects are resolved to the relation or schema they
+// belong to
+func (l *tocList) isSelected(entry *tocEntry, selectiveRestore *models.SelectiveRestore) bool {
+	owner := l.findOwner(entry)
+
+	switch {
+	case slices.Contains(relationObjectTypes, owner.ObjectType):
+		return selectiveRestore.IsTableSelected(owner.Schema, owner.Name)
+	case owner.ObjectType == "SCHEMA":
+		return selectiveRestore.IsSchemaSelected(owner.Name)
+	case owner.Schema != "":
+		return selectiveRestore.IsSchemaSelected(owner.Schema)
+	default:
+		// database level objects are restored only when nothing is
+		// included explicitly
+		return !selectiveRestore.HasIncludes()
+	}
+}
+
+// findOwner follows dependencies of attached objects up to the relation or
+// the standalone object they belong to
+func (l *tocList) findOwner(entry *tocEntry) *tocEntry {
+	// depth is limited to protect from dependency cycles
+	for range 10 {
+		if slices.Contains(relationObjectTypes, entry.ObjectType) {
+			if tableID, ok := l.sequenceTables[entry.DumpID]; ok {
+				return l.byID[tableID]
+			}
+
+			return entry
+		}
+
+		if !slices.Contains(attachedObjectTypes, entry.ObjectType) {
+			return entry
+		}
+
+		attachedTo := l.findAttachedTo(entry)
+		if attachedTo == nil {
+			return entry
+		}
+
+		entry = attachedTo
+	}
+
+	return entry
+}
+
+// findAttachedTo picks the dependency the entry is attached to. Tags of
+// constraints, triggers and defaults start with the table name, so relation
+// with matching name is preferred (e.g. FK constraint also depends on the
+// referenced table)
+func (l *tocList) findAttachedTo(entry *tocEntry) *tocEntry {
+	var firstRelation, firstNonSchema, first *tocEntry
+
+	for _, dumpID := range entry.Dependencies {
+		dependency, ok := l.byID[dumpID]
+		if !ok {
+			continue
+		}
+
+		if first == nil {
+			first = dependency
+		}
+
+		if dependency.ObjectType != "SCHEMA" && firstNonSchema == nil {
+			firstNonSchema = dependency
+		}
+
+		if slices.Contains(relationObjectTypes, dependency.ObjectType) {
+			if strings.HasPrefix(entry.Name, dependency.Name+" ") {
+				return dependency
+			}
+
+			if firstRelation == nil {
+				firstRelation = dependency
+			}
+		}
+	}
+
+	switch {
+	case firstRelation != nil:
+		return firstRelation
+	case firstNonSchema != nil:
+		return firstNonSchema
+	default:
+		return first
+	}
+}
+
+// linkSequencesToTables finds sequences used by serial column defaults and
+// identity columns. Such sequences are dropped together with the table, so
+// they must be restored together with it
+func (l *tocList) linkSequencesToTables() {
+	for _, entry := range l.entries {
+		switch entry.ObjectType {
+		case "SEQUENCE":
+			// identity sequences depend on their table
+			for _, dumpID := range entry.Dependencies {
+				if dependency, ok := l.byID[dumpID]; ok && dependency.ObjectType == "TABLE" {
+					l.sequenceTables[entry.DumpID] = dependency.DumpID
+					break
+				}
+			}
+		case "DEFAULT":
+			// serial column defaults depend on their table and sequence
+			table := l.findAttachedTo(entry)
+			if table == nil || table.ObjectType != "TABLE" {
+				continue
+			}
+
+			for _, dumpID := range entry.Dependencies {
+				if dependency, ok := l.byID[dumpID]; ok && dependency.ObjectType == "SEQUENCE" {
+					l.sequenceTables[dependency.DumpID] = table.DumpID
+				}
+			}
+		}
+	}
+}
+
+// buildBackupToc converts parsed entries into the API representation. Sizes
+// are data block sizes by dump ID, nil when they could not be read
+func buildBackupToc(list *tocList, sizes map[int]int64) *models.BackupToc {
+	toc := &models.BackupToc{
+		Schemas: []string{},
+		Tables:  []models.BackupTocTable{},
+		Entries: []models.BackupTocEntry{},
+	}
+
+	dataSizes := map[string]int64{}
+	for _, entry := range list.entries {
+		size, ok := sizes[entry.DumpID]
+		if entry.ObjectType == "TABLE DATA" && ok {
+			dataSizes[entry.Schema+"."+entry.Name] += size
+		}
+	}
+
+	for _, entry := range list.entries {
+		tocEntry := models.BackupTocEntry{
+			DumpID:     entry.DumpID,
+			ObjectType: entry.ObjectType,
+			Schema:     entry.Schema,
+			Name:       entry.Name,
+			Owner:      entry.Owner,
+		}
+
+		if size, ok := sizes[entry.DumpID]; ok {
+			tocEntry.SizeBytes = &size
+		}
+
+		toc.Entries = append(toc.Entries, tocEntry)
+
+		if entry.ObjectType == "SCHEMA" && !slices.Contains(toc.Schemas, entry.Name) {
+			toc.Schemas = append(toc.Schemas, entry.Name)
+		}
+
+		if !slices.Contains(relationObjectTypes, entry.ObjectType) {
+			continue
+		}
+
+		if entry.Schema != "" && !slices.Contains(toc.Schemas, entry.Schema) {
+			toc.Schemas = append(toc.Schemas, entry.Schema)
+		}
+
+		table := models.BackupTocTable{
+			Schema:     entry.Schema,
+			Name:       entry.Name,
+			ObjectType: entry.ObjectType,
+		}
+
+		if sizes != nil && entry.ObjectType == "TABLE" {
+			dataSize := dataSizes[entry.Schema+"."+entry.Name]
+			table.DataSizeBytes = &dataSize
+		}
+
+		toc.Tables = append(toc.Tables, table)
+	}
+
+	slices.Sort(toc.Schemas)
+
+	return toc
+}
@@ -0,0 +1,270 @@
+package usecases_postgresql
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"databasus-backend/internal/features/restores/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTocOutput = `;
+; Archive created at 2026-10-18 10:00:00 UTC
+;     dbname: shop
+;
+; Selected TOC Entries:
+;
+5; 2615 2200 SCHEMA - public pg_database_owner
+6; 2615 16385 SCHEMA - sales postgres
+3400; 0 0 COMMENT - SCHEMA public pg_database_owner
+;	depends on: 5
+2; 3079 16386 EXTENSION - pg_trgm
+3401; 0 0 COMMENT - EXTENSION pg_trgm
+;	depends on: 2
+215; 1255 16390 FUNCTION public touch_updated_at() postgres
+;	depends on: 5
+216; 1259 16400 TABLE public users postgres
+;	depends on: 5
+217; 1259 16399 SEQUENCE public users_id_seq postgres
+;	depends on: 5
+218; 1259 16410 TABLE public orders postgres
+;	depends on: 5
+219; 1259 16420 TABLE sales invoices postgres
+;	depends on: 6
+3300; 2604 16403 DEFAULT public users id postgres
+;	depends on: 217 216
+3350; 0 16400 TABLE DATA public users postgres
+;	depends on: 216
+3351; 0 16410 TABLE DATA public orders postgres
+;	depends on: 218
+3352; 0 16420 TABLE DATA sales invoices postgres
+;	depends on: 219
+3360; 0 0 SEQUENCE SET public users_id_seq postgres
+;	depends on: 217
+3370; 2606 16405 CONSTRAINT public users users_pkey postgres
+;	depends on: 216
+3371; 1259 16406 INDEX public users_email_idx postgres
+;	depends on: 216
+3380; 2606 16415 FK CONSTRAINT public orders orders_user_id_fkey postgres
+;	depends on: 218 3370 216
+`
+
+func Test_ParseTocList_WhenVerboseOutputGiven_EntriesAndDependenciesParsed(t *testing.T) {
+	list := parseTocList(testTocOutput)
+
+	require.Len(t, list.entries, 18)
+
+	users := list.byID[216]
+	assert.Equal(t, "TABLE", users.ObjectType)
+	assert.Equal(t, "public", users.Schema)
+	assert.Equal(t, "users", users.Name)
+	assert.Equal(t, "postgres", users.Owner)
+	assert.Equal(t, []int{5}, users.Dependencies)
+
+	fkConstraint := list.byID[3380]
+	assert.Equal(t, "FK CONSTRAINT", fkConstraint.ObjectType)
+	assert.Equal(t, "orders orders_user_id_fkey", fkConstraint.Name)
+	assert.Equal(t, []int{218, 3370, 216}, fkConstraint.Dependencies)
+
+	extension := list.byID[2]
+	assert.Equal(t, "EXTENSION", extension.ObjectType)
+	assert.Equal(t, "", extension.Schema)
+	assert.Equal(t, "pg_trgm", extension.Name)
+	assert.Equal(t, "", extension.Owner)
+
+	assert.Equal(t, "TABLE DATA", list.byID[3350].ObjectType)
+	assert.Equal(t, "SEQUENCE SET", list.byID[3360].ObjectType)
+}
+
+func Test_IsSelected_WhenSingleTableIncluded_OnlyTableAndAttachedObjectsSelected(t *testing.T) {
+	list := parseTocList(testTocOutput)
+	selectiveRestore := &models.SelectiveRestore{IncludeTables: []string{"public.users"}}
+
+	selectedIDs := getSelectedDumpIDs(list, selectiveRestore)
+
+	assert.Equal(t, []int{216, 217, 3300, 3350, 3360, 3370, 3371}, selectedIDs)
+}
+
+func Test_IsSelected_WhenSchemaIncluded_SchemaObjectsSelected(t *testing.T) {
+	list := parseTocList(testTocOutput)
+	selectiveRestore := &models.SelectiveRestore{IncludeSchemas: []string{"sales"}}
+
+	selectedIDs := getSelectedDumpIDs(list, selectiveRestore)
+
+	assert.Equal(t, []int{6, 219, 3352}, selectedIDs)
+}
+
+func Test_IsSelected_WhenTableExcluded_EverythingElseSelected(t *testing.T) {
+	list := parseTocList(testTocOutput)
+	selectiveRestore := &models.SelectiveRestore{ExcludeTables: []string{"public.orders"}}
+
+	selectedIDs := getSelectedDumpIDs(list, selectiveRestore)
+
+	assert.NotContains(t, selectedIDs, 218)
+	assert.NotContains(t, selectedIDs, 3351)
+	assert.NotContains(t, selectedIDs, 3380)
+	assert.Contains(t, selectedIDs, 2)
+	assert.Contains(t, selectedIDs, 216)
+	assert.Contains(t, selectedIDs, 3352)
+	assert.Len(t, selectedIDs, len(list.entries)-3)
+}
+
+func Test_BuildBackupToc_WhenSizesGiven_TablesHaveDataSizes(t *testing.T) {
+	list := parseTocList(testTocOutput)
+
+	toc := buildBackupToc(list, map[int]int64{3350: 1024, 3352: 2048})
+
+	assert.Equal(t, []string{"public", "sales"}, toc.Schemas)
+	assert.Len(t, toc.Entries, 18)
+	require.Len(t, toc.Tables, 4)
+
+	assert.Equal(t, "users", toc.Tables[0].Name)
+	require.NotNil(t, toc.Tables[0].DataSizeBytes)
+	assert.Equal(t, int64(1024), *toc.Tables[0].DataSizeBytes)
+
+	assert.Equal(t, "SEQUENCE", toc.Tables[1].ObjectType)
+	assert.Nil(t, toc.Tables[1].DataSizeBytes)
+
+	assert.Equal(t, "orders", toc.Tables[2].Name)
+	require.NotNil(t, toc.Tables[2].DataSizeBytes)
+	assert.Equal(t, int64(0), *toc.Tables[2].DataSizeBytes)
+}
+
+func Test_BuildBackupToc_WhenSizesUnknown_DataSizesOmitted(t *testing.T) {
+	list := parseTocList(testTocOutput)
+
+	toc := buildBackupToc(list, nil)
+
+	for _, table := range toc.Tables {
+		assert.Nil(t, table.DataSizeBytes)
+	}
+}
+
+func Test_ReadArchiveDataSizes_WhenCustomArchiveGiven_BlockSizesReturned(t *testing.T) {
+	archive := buildTestCustomArchive(t)
+
+	sizes, err := readArchiveDataSizes(bytes.NewReader(archive))
+
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int64{3350: 150, 3351: 7, 3400: 12}, sizes)
+}
+
+func Test_ReadArchiveDataSizes_WhenNotCustomArchive_ReturnsError(t *testing.T) {
+	_, err := readArchiveDataSizes(bytes.NewReader([]byte("-- PostgreSQL database dump")))
+
+	assert.Error(t, err)
+}
+
+func getSelectedDumpIDs(list *tocList, selectiveRestore *models.SelectiveRestore) []int {
+	selectedIDs := []int{}
+	for _, entry := range list.entries {
+		if list.isSelected(entry, selectiveRestore) {
+			selectedIDs = append(selectedIDs, entry.DumpID)
+		}
+	}
+
+	return selectedIDs
+}
+
+// buildTestCustomArchive writes a minimal pg_dump 1.16 custom format archive
+// with two table data blocks and one large objects block
+func buildTestCustomArchive(t *testing.T) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+
+	writeInt := func(value int) {
+		sign := byte(0)
+		if value < 0 {
+			sign = 1
+			value = -value
+		}
+
+		buf.WriteByte(sign)
+		intBytes := make([]byte, 4)
+		binary.LittleEndian.PutUint32(intBytes, uint32(value))
+		buf.Write(intBytes)
+	}
+
+	writeStr := func(value *string) {
+		if value == nil {
+			writeInt(-1)
+			return
+		}
+
+		writeInt(len(*value))
+		buf.WriteString(*value)
+	}
+
+	str := func(value string) *string { return &value }
+
+	writeChunks := func(chunkSizes ...int) {
+		for _, chunkSize := range chunkSizes {
+			writeInt(chunkSize)
+			buf.Write(make([]byte, chunkSize))
+		}
+		writeInt(0)
+	}
+
+	buf.WriteString("PGDMP")
+	buf.Write([]byte{1, 16, 0}) // version
+	buf.WriteByte(4)            // int size
+	buf.WriteByte(8)            // offset size
+	buf.WriteByte(1)            // custom format
+	buf.WriteByte(0)            // no compression
+
+	for range 7 {
+		writeInt(1)
+	}
+
+	writeStr(str("shop"))
+	writeStr(str("16.4"))
+	writeStr(str("16.4"))
+
+	dumpIDs := []int{216, 3350, 3351, 3400}
+	writeInt(len(dumpIDs))
+
+	for _, dumpID := range dumpIDs {
+		writeInt(dumpID)
+		writeInt(1)
+		writeStr(str("1259"))
+		writeStr(str("16400"))
+		writeStr(str("users"))
+		writeStr(str("TABLE DATA"))
+		writeInt(2) // section
+		writeStr(str(""))
+		writeStr(str(""))
+		writeStr(str("COPY public.users (id) FROM stdin;\n"))
+		writeStr(str("public"))
+		writeStr(str(""))
+		writeStr(str("heap"))
+		writeInt(int('r'))
+		writeStr(str("postgres"))
+		writeStr(str("false"))
+		writeStr(str("216"))
+		writeStr(nil)
+		buf.WriteByte(1) // offset not set
+		buf.Write(make([]byte, 8))
+	}
+
+	buf.WriteByte(archiveBlockData)
+	writeInt(3350)
+	writeChunks(100, 50)
+
+	buf.WriteByte(archiveBlockData)
+	writeInt(3351)
+	writeChunks(7)
+
+	buf.WriteByte(archiveBlockBlobs)
+	writeInt(3400)
+	writeInt(16500)
+	writeChunks(5)
+	writeInt(16501)
+	writeChunks(7)
+	writeInt(0)
+
+	return buf.Bytes()
+}
@@ -29,6 +29,7 @@ func (uc *RestoreBackupUsecase) Execute(
 	backup *backups.Backup,
 	storage *storages.Storage,
 	isExcludeExtensions bool,
+	selectiveRestore *models.SelectiveRestore,
 ) error {
 	switch originalDB.Type {
 	case databases.DatabaseTypePostgres:
@@ -40,6 +41,7 @@ func (uc *RestoreBackupUsecase) Execute(
 			backup,
 			storage,
 			isExcludeExtensions,
+			selectiveRestore,
 		)
 	case databases.DatabaseTypeMysql:
 		return uc.restoreMysqlBackupUsecase.Execute(
@@ -72,3 +74,128 @@ func (uc *RestoreBackupUsecase) Execute(
 		return errors.New("database type not supported")
 	}
 }
+
+func (uc *RestoreBackupUsecase) ListBackupToc(
+	originalDB *databases.Database,
+	backup *backups.Backup,
+	storage *storages.Storage,
+) (*models.BackupToc, error) {
+	if originalDB.Type != databases.DatabaseTypePostgres {
+		return nil, errors.New("backup contents can be listed only for PostgreSQL")
+	}
+
+	return uc.restorePostgresqlBackupUsecase.ListBackupToc(originalDB, backup, storage)
+}
+
+func (uc *RestoreBackupUsecase) ExecutePointInTimeRecovery(
+	originalDB *databases.Database,
+	restore models.Restore,
+	backup *backups.Backup,
+	storage *storages.Storage,
+	pitr *models.PointInTimeRecovery,
+) error {
+	if originalDB.Type != databases.DatabaseTypePostgres {
+		return errors.New("point-in-time recovery is supported only for PostgreSQL")
+	}
+
+	return uc.restorePostgresqlBackupUsecase.ExecutePointInTimeRecovery(
+		originalDB,
+		restore,
+		backup,
+		storage,
+		pitr,
+	)
+}
+
+func (uc *RestoreBackupUsecase) ExecutePhysicalRestore(
+	originalDB *databases.Database,
+	restore models.Restore,
+	backup *backups.Backup,
+	storage *storages.Storage,
+	physicalRestore *models.PhysicalRestore,
+) error {
+	if originalDB.Type != databases.DatabaseTypePostgres {
+		return errors.New("physical restore is supported only for PostgreSQL")
+	}
+
+	return uc.restorePostgresqlBackupUsecase.ExecutePhysicalRestore(
+		originalDB,
+		restore,
+		backup,
+		storage,
+		physicalRestore,
+	)
+}
+
+func (uc *RestoreBackupUsecase) ExecuteServerRestore(
+	originalDB *databases.Database,
+	restoringToDB *databases.Database,
+	restore models.Restore,
+	backup *backups.Backup,
+	storage *storages.Storage,
+	serverRestore *models.ServerRestore,
+) error {
+	if originalDB.Type != databases.DatabaseTypePostgres {
+		return errors.New("server restore is supported only for PostgreSQL")
+	}
+
+	return uc.restorePostgresqlBackupUsecase.ExecuteServerRestore(
+		originalDB,
+		restoringToDB,
+		restore,
+		backup,
+		storage,
+		serverRestore,
+	)
+}
+
+func (uc *RestoreBackupUsecase) ExecuteBinlogRecovery(
+	originalDB *databases.Database,
+	restoringToDB *databases.Database,
+	restore models.Restore,
+	backup *backups.Backup,
+	binlogRecovery *models.BinlogRecovery,
+) error {
+	switch originalDB.Type {
+	case databases.DatabaseTypeMysql:
+		return uc.restoreMysqlBackupUsecase.ApplyBinlogs(
+			originalDB,
+			restoringToDB,
+			restore,
+			backup,
+			binlogRecovery,
+		)
+	case databases.DatabaseTypeMariadb:
+		return uc.restoreMariadbBackupUsecase.ApplyBinlogs(
+			originalDB,
+			restoringToDB,
+			restore,
+			backup,
+			binlogRecovery,
+		)
+	default:
+		return errors.New("binlog recovery is supported only for MySQL and MariaDB")
+	}
+}
+
+func (uc *RestoreBackupUsecase) ExecuteOplogRecovery(
+	originalDB *databases.Database,
+	restoringToDB *databases.Database,
+	restore models.Restore,
+	backup *backups.Backup,
+	storage *storages.Storage,
+	oplogRecovery *models.OplogRecovery,
+) error {
+	if originalDB.Type != databases.DatabaseTypeMongodb {
+		return errors.New("oplog recovery is supported only for MongoDB")
+	}
+
+	return uc.restoreMongodbBackupUsecase.ExecuteOplogRecovery(
+		originalDB,
+		restoringToDB,
+		restore,
+		backup,
+		storage,
+		oplogRecovery,
+	)
+}
@@ -0,0 +1,29 @@
+package storages_common
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StorageFile is a file saved into the storage under its file ID
+type StorageFile struct {
+	FileID     uuid.UUID `json:"fileId"`
+	SizeBytes  int64     `json:"sizeBytes"`
+	ModifiedAt time.Time `json:"modifiedAt"`
+}
+
+// ParseFileID returns the file ID of the file name. Files not named by
+// a UUID were not saved by Databasus and are skipped while listing
+func ParseFileID(fileName string) (uuid.UUID, bool) {
+	if len(fileName) != 36 {
+		return uuid.Nil, false
+	}
+
+	fileID, err := uuid.Parse(fileName)
+	if err != nil {
+		return uuid.Nil, false
+	}
+
+	return fileID, true
+}
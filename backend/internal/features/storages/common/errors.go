@@ -0,0 +1,6 @@
+package storages_common
+
+import "errors"
+
+// ErrFileNotFound is returned when the file does not exist in the storage
+var ErrFileNotFound = errors.New("file not found in the storage")
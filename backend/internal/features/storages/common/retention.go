@@ -0,0 +1,25 @@
+package storages_common
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrFileLocked is returned when the file cannot be deleted because the
+// storage keeps it immutable (e.g. S3 Object Lock) until the lock expires
+var ErrFileLocked = errors.New("file is locked by the storage")
+
+type retainUntilKey struct{}
+
+// WithRetainUntil sets the time until which files saved with the context
+// must be kept immutable by storages which support it
+func WithRetainUntil(ctx context.Context, retainUntil time.Time) context.Context {
+	return context.WithValue(ctx, retainUntilKey{}, retainUntil)
+}
+
+// GetRetainUntil returns the time set by WithRetainUntil
+func GetRetainUntil(ctx context.Context) (time.Time, bool) {
+	retainUntil, ok := ctx.Value(retainUntilKey{}).(time.Time)
+	return retainUntil, ok
+}
@@ -2,9 +2,11 @@ package storages
 
 import (
 	"context"
+	storages_common "databasus-backend/internal/features/storages/common"
 	"databasus-backend/internal/util/encryption"
 	"io"
 	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -20,8 +22,16 @@ type StorageFileSaver interface {
 
 	GetFile(encryptor encryption.FieldEncryptor, fileID uuid.UUID) (io.ReadCloser, error)
 
+	// GetFileSize returns the size of the file without reading it.
+	// ErrFileNotFound is returned when the file does not exist
+	GetFileSize(encryptor encryption.FieldEncryptor, fileID uuid.UUID) (int64, error)
+
 	DeleteFile(encryptor encryption.FieldEncryptor, fileID uuid.UUID) error
 
+	// ListFiles returns backup files placed in the storage. Files which names
+	// are not file IDs are skipped
+	ListFiles(encryptor encryption.FieldEncryptor) ([]storages_common.StorageFile, error)
+
 	Validate(encryptor encryption.FieldEncryptor) error
 
 	TestConnection(encryptor encryption.FieldEncryptor) error
@@ -31,6 +41,18 @@ type StorageFileSaver interface {
 	EncryptSensitiveData(encryptor encryption.FieldEncryptor) error
 }
 
+// StorageFileLocker is implemented by storages which can keep files
+// immutable (e.g. S3 Object Lock). Locked files cannot be deleted until the
+// lock expires
+type StorageFileLocker interface {
+	// IsFileLockEnabled reports whether saved files are locked
+	IsFileLockEnabled() bool
+
+	// GetFileLockedUntil returns the time until which the file cannot be
+	// deleted, nil if it is not locked
+	GetFileLockedUntil(encryptor encryption.FieldEncryptor, fileID uuid.UUID) (*time.Time, error)
+}
+
 type StorageDatabaseCounter interface {
 	GetStorageAttachedDatabasesIDs(storageID uuid.UUID) ([]uuid.UUID, error)
 }
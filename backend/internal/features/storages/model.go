@@ -2,6 +2,7 @@ package storages
 
 import (
 	"context"
+	storages_common "databasus-backend/internal/features/storages/common"
 	azure_blob_storage "databasus-backend/internal/features/storages/models/azure_blob"
 	ftp_storage "databasus-backend/internal/features/storages/models/ftp"
 	google_drive_storage "databasus-backend/internal/features/storages/models/google_drive"
@@ -14,6 +15,7 @@ import (
 	"errors"
 	"io"
 	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -62,10 +64,48 @@ func (s *Storage) GetFile(
 	return s.getSpecificStorage().GetFile(encryptor, fileID)
 }
 
+// GetFileSize returns the size of the file in the storage without reading it,
+// storages_common.ErrFileNotFound if the file does not exist
+func (s *Storage) GetFileSize(
+	encryptor encryption.FieldEncryptor,
+	fileID uuid.UUID,
+) (int64, error) {
+	return s.getSpecificStorage().GetFileSize(encryptor, fileID)
+}
+
 func (s *Storage) DeleteFile(encryptor encryption.FieldEncryptor, fileID uuid.UUID) error {
 	return s.getSpecificStorage().DeleteFile(encryptor, fileID)
 }
 
+// IsFileLockEnabled reports whether the storage keeps saved files immutable
+// (e.g. S3 Object Lock) until the retain-until time set on saving
+func (s *Storage) IsFileLockEnabled() bool {
+	fileLocker, ok := s.getSpecificStorage().(StorageFileLocker)
+
+	return ok && fileLocker.IsFileLockEnabled()
+}
+
+// GetFileLockedUntil returns the time until which the file cannot be deleted
+// because of the storage lock, nil if the file is not locked or the storage
+// does not support locking
+func (s *Storage) GetFileLockedUntil(
+	encryptor encryption.FieldEncryptor,
+	fileID uuid.UUID,
+) (*time.Time, error) {
+	fileLocker, ok := s.getSpecificStorage().(StorageFileLocker)
+	if !ok {
+		return nil, nil
+	}
+
+	return fileLocker.GetFileLockedUntil(encryptor, fileID)
+}
+
+func (s *Storage) ListFiles(
+	encryptor encryption.FieldEncryptor,
+) ([]storages_common.StorageFile, error) {
+	return s.getSpecificStorage().ListFiles(encryptor)
+}
+
 func (s *Storage) Validate(encryptor encryption.FieldEncryptor) error {
 	if s.Type == "" {
 		return errors.New("storage type is required")
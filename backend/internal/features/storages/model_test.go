@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"databasus-backend/internal/config"
+	storages_common "databasus-backend/internal/features/storages/common"
 	azure_blob_storage "databasus-backend/internal/features/storages/models/azure_blob"
 	ftp_storage "databasus-backend/internal/features/storages/models/ftp"
 	google_drive_storage "databasus-backend/internal/features/storages/models/google_drive"
@@ -265,6 +266,36 @@ acl = private`, s3Container.accessKey, s3Container.secretKey, s3Container.endpoi
 				}
 			})
 
+			t.Run("Test_TestGetFileSize_ReturnsSavedFileSize", func(t *testing.T) {
+				fileData, err := os.ReadFile(testFilePath)
+				require.NoError(t, err, "Should be able to read test file")
+
+				fileID := uuid.New()
+				err = tc.storage.SaveFile(
+					context.Background(),
+					encryptor,
+					logger.GetLogger(),
+					fileID,
+					bytes.NewReader(fileData),
+				)
+				require.NoError(t, err, "SaveFile should succeed")
+				defer func() {
+					_ = tc.storage.DeleteFile(encryptor, fileID)
+				}()
+
+				size, err := tc.storage.GetFileSize(encryptor, fileID)
+				assert.NoError(t, err, "GetFileSize should succeed")
+				assert.Equal(t, int64(len(fileData)), size, "File size should match")
+
+				_, err = tc.storage.GetFileSize(encryptor, uuid.New())
+				assert.ErrorIs(
+					t,
+					err,
+					storages_common.ErrFileNotFound,
+					"GetFileSize should return ErrFileNotFound for non-existent file",
+				)
+			})
+
 			t.Run("Test_TestDeleteNonExistentFile_DoesNotError", func(t *testing.T) {
 				// Try to delete a non-existent file
 				nonExistentID := uuid.New()
@@ -275,6 +306,96 @@ acl = private`, s3Container.accessKey, s3Container.secretKey, s3Container.endpoi
 	}
 }
 
+func Test_S3Storage_ObjectLock(t *testing.T) {
+	ctx := context.Background()
+
+	validateEnvVariables(t)
+
+	s3Container, err := setupS3Container(ctx)
+	require.NoError(t, err, "Failed to setup S3 container")
+
+	lockBucketName, err := setupS3ObjectLockBucket(ctx, s3Container)
+	require.NoError(t, err, "Failed to setup S3 object lock bucket")
+
+	encryptor := encryption.GetFieldEncryptor()
+
+	createS3Storage := func(bucketName string) *s3_storage.S3Storage {
+		return &s3_storage.S3Storage{
+			StorageID:      uuid.New(),
+			S3Bucket:       bucketName,
+			S3Region:       s3Container.region,
+			S3AccessKey:    s3Container.accessKey,
+			S3SecretKey:    s3Container.secretKey,
+			S3Endpoint:     "http://" + s3Container.endpoint,
+			ObjectLockMode: s3_storage.ObjectLockModeGovernance,
+		}
+	}
+
+	t.Run("Test_TestConnection_WhenBucketHasNoObjectLock_ReturnsError", func(t *testing.T) {
+		err := createS3Storage(s3Container.bucketName).TestConnection(encryptor)
+		assert.ErrorContains(t, err, "is not enabled on bucket")
+	})
+
+	t.Run("Test_TestConnection_WhenBucketHasObjectLock_ConnectionSucceeds", func(t *testing.T) {
+		err := createS3Storage(lockBucketName).TestConnection(encryptor)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Test_SaveFileWithRetainUntil_FileLockedAndNotDeleted", func(t *testing.T) {
+		storage := createS3Storage(lockBucketName)
+		fileID := uuid.New()
+		retainUntil := time.Now().UTC().Add(time.Minute)
+
+		err := storage.SaveFile(
+			storages_common.WithRetainUntil(context.Background(), retainUntil),
+			encryptor,
+			logger.GetLogger(),
+			fileID,
+			bytes.NewReader([]byte("locked backup")),
+		)
+		require.NoError(t, err)
+
+		lockedUntil, err := storage.GetFileLockedUntil(encryptor, fileID)
+		require.NoError(t, err)
+		require.NotNil(t, lockedUntil)
+		assert.WithinDuration(t, retainUntil, *lockedUntil, time.Second)
+
+		err = storage.DeleteFile(encryptor, fileID)
+		assert.ErrorIs(t, err, storages_common.ErrFileLocked)
+
+		file, err := storage.GetFile(encryptor, fileID)
+		require.NoError(t, err, "Locked file should be kept")
+		file.Close()
+	})
+
+	t.Run("Test_SaveFileWithoutRetainUntil_FileNotLockedAndDeleted", func(t *testing.T) {
+		storage := createS3Storage(lockBucketName)
+		fileID := uuid.New()
+
+		err := storage.SaveFile(
+			context.Background(),
+			encryptor,
+			logger.GetLogger(),
+			fileID,
+			bytes.NewReader([]byte("not locked backup")),
+		)
+		require.NoError(t, err)
+
+		lockedUntil, err := storage.GetFileLockedUntil(encryptor, fileID)
+		require.NoError(t, err)
+		assert.Nil(t, lockedUntil)
+
+		err = storage.DeleteFile(encryptor, fileID)
+		assert.NoError(t, err)
+
+		file, err := storage.GetFile(encryptor, fileID)
+		assert.Error(t, err, "GetFile should fail for deleted file")
+		if file != nil {
+			file.Close()
+		}
+	})
+}
+
 func setupTestFile() (string, error) {
 	tempDir := os.TempDir()
 	testFilePath := filepath.Join(tempDir, "test_file.txt")
@@ -337,6 +458,41 @@ func setupS3Container(ctx context.Context) (*S3Container, error) {
 	}, nil
 }
 
+// setupS3ObjectLockBucket creates a MinIO bucket with object lock (and so
+// versioning) enabled
+func setupS3ObjectLockBucket(ctx context.Context, s3Container *S3Container) (string, error) {
+	bucketName := "test-lock-bucket"
+
+	minioClient, err := minio.New(s3Container.endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(s3Container.accessKey, s3Container.secretKey, ""),
+		Secure: false,
+		Region: s3Container.region,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create minio client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	exists, err := minioClient.BucketExists(ctx, bucketName)
+	if err != nil {
+		return "", fmt.Errorf("failed to check if bucket exists: %w", err)
+	}
+
+	if !exists {
+		if err := minioClient.MakeBucket(
+			ctx,
+			bucketName,
+			minio.MakeBucketOptions{Region: s3Container.region, ObjectLocking: true},
+		); err != nil {
+			return "", fmt.Errorf("failed to create bucket: %w", err)
+		}
+	}
+
+	return bucketName, nil
+}
+
 func setupAzuriteContainer(ctx context.Context) (*AzuriteContainer, error) {
 	env := config.GetEnv()
 
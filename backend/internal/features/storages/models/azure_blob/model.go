@@ -3,6 +3,7 @@ package azure_blob_storage
 import (
 	"bytes"
 	"context"
+	storages_common "databasus-backend/internal/features/storages/common"
 	"databasus-backend/internal/util/encryption"
 	"encoding/base64"
 	"errors"
@@ -17,6 +18,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
 	"github.com/google/uuid"
 )
@@ -178,6 +180,36 @@ func (s *AzureBlobStorage) GetFile(
 	return response.Body, nil
 }
 
+func (s *AzureBlobStorage) GetFileSize(
+	encryptor encryption.FieldEncryptor,
+	fileID uuid.UUID,
+) (int64, error) {
+	client, err := s.getClient(encryptor)
+	if err != nil {
+		return 0, err
+	}
+
+	blobName := s.buildBlobName(fileID.String())
+
+	properties, err := client.ServiceClient().
+		NewContainerClient(s.ContainerName).
+		NewBlobClient(blobName).
+		GetProperties(context.TODO(), nil)
+	if err != nil {
+		if bloberror.HasCode(err, bloberror.BlobNotFound) {
+			return 0, fmt.Errorf("%w: %s", storages_common.ErrFileNotFound, fileID.String())
+		}
+
+		return 0, fmt.Errorf("failed to get blob properties from Azure: %w", err)
+	}
+
+	if properties.ContentLength == nil {
+		return 0, errors.New("azure did not return blob size")
+	}
+
+	return *properties.ContentLength, nil
+}
+
 func (s *AzureBlobStorage) DeleteFile(encryptor encryption.FieldEncryptor, fileID uuid.UUID) error {
 	client, err := s.getClient(encryptor)
 	if err != nil {
@@ -203,6 +235,54 @@ func (s *AzureBlobStorage) DeleteFile(encryptor encryption.FieldEncryptor, fileI
 	return nil
 }
 
+func (s *AzureBlobStorage) ListFiles(
+	encryptor encryption.FieldEncryptor,
+) ([]storages_common.StorageFile, error) {
+	client, err := s.getClient(encryptor)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := s.buildBlobName("")
+
+	pager := client.NewListBlobsFlatPager(s.ContainerName, &azblob.ListBlobsFlatOptions{
+		Prefix: &prefix,
+	})
+
+	files := make([]storages_common.StorageFile, 0)
+	for pager.More() {
+		page, err := pager.NextPage(context.TODO())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list blobs in Azure: %w", err)
+		}
+
+		for _, blob := range page.Segment.BlobItems {
+			if blob.Name == nil {
+				continue
+			}
+
+			fileID, ok := storages_common.ParseFileID(strings.TrimPrefix(*blob.Name, prefix))
+			if !ok {
+				continue
+			}
+
+			file := storages_common.StorageFile{FileID: fileID}
+			if blob.Properties != nil {
+				if blob.Properties.ContentLength != nil {
+					file.SizeBytes = *blob.Properties.ContentLength
+				}
+				if blob.Properties.LastModified != nil {
+					file.ModifiedAt = blob.Properties.LastModified.UTC()
+				}
+			}
+
+			files = append(files, file)
+		}
+	}
+
+	return files, nil
+}
+
 func (s *AzureBlobStorage) Validate(encryptor encryption.FieldEncryptor) error {
 	if s.ContainerName == "" {
 		return errors.New("container name is required")
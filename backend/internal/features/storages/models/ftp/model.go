@@ -3,11 +3,13 @@ package ftp_storage
 import (
 	"context"
 	"crypto/tls"
+	storages_common "databasus-backend/internal/features/storages/common"
 	"databasus-backend/internal/util/encryption"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"net/textproto"
 	"strings"
 	"time"
 
@@ -133,6 +135,31 @@ func (f *FTPStorage) GetFile(
 	}, nil
 }
 
+func (f *FTPStorage) GetFileSize(
+	encryptor encryption.FieldEncryptor,
+	fileID uuid.UUID,
+) (int64, error) {
+	conn, err := f.connect(encryptor, ftpConnectTimeout)
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect to FTP: %w", err)
+	}
+	defer func() {
+		_ = conn.Quit()
+	}()
+
+	size, err := conn.FileSize(f.getFilePath(fileID.String()))
+	if err != nil {
+		var protocolErr *textproto.Error
+		if errors.As(err, &protocolErr) && protocolErr.Code == ftp.StatusFileUnavailable {
+			return 0, fmt.Errorf("%w: %s", storages_common.ErrFileNotFound, fileID.String())
+		}
+
+		return 0, fmt.Errorf("failed to get file size from FTP: %w", err)
+	}
+
+	return size, nil
+}
+
 func (f *FTPStorage) DeleteFile(encryptor encryption.FieldEncryptor, fileID uuid.UUID) error {
 	conn, err := f.connect(encryptor, ftpConnectTimeout)
 	if err != nil {
@@ -157,6 +184,45 @@ func (f *FTPStorage) DeleteFile(encryptor encryption.FieldEncryptor, fileID uuid
 	return nil
 }
 
+func (f *FTPStorage) ListFiles(
+	encryptor encryption.FieldEncryptor,
+) ([]storages_common.StorageFile, error) {
+	conn, err := f.connect(encryptor, ftpConnectTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to FTP: %w", err)
+	}
+	defer func() {
+		_ = conn.Quit()
+	}()
+
+	dir := strings.Trim(f.Path, "/")
+
+	entries, err := conn.List(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files in FTP: %w", err)
+	}
+
+	files := make([]storages_common.StorageFile, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Type != ftp.EntryTypeFile {
+			continue
+		}
+
+		fileID, ok := storages_common.ParseFileID(entry.Name)
+		if !ok {
+			continue
+		}
+
+		files = append(files, storages_common.StorageFile{
+			FileID:     fileID,
+			SizeBytes:  int64(entry.Size),
+			ModifiedAt: entry.Time.UTC(),
+		})
+	}
+
+	return files, nil
+}
+
 func (f *FTPStorage) Validate(encryptor encryption.FieldEncryptor) error {
 	if f.Host == "" {
 		return errors.New("FTP host is required")
@@ -2,6 +2,7 @@ package google_drive_storage
 
 import (
 	"context"
+	storages_common "databasus-backend/internal/features/storages/common"
 	"databasus-backend/internal/util/encryption"
 	"encoding/json"
 	"errors"
@@ -181,6 +182,38 @@ func (s *GoogleDriveStorage) GetFile(
 	return result, err
 }
 
+func (s *GoogleDriveStorage) GetFileSize(
+	encryptor encryption.FieldEncryptor,
+	fileID uuid.UUID,
+) (int64, error) {
+	var size int64
+	err := s.withRetryOnAuth(
+		context.Background(),
+		encryptor,
+		func(driveService *drive.Service) error {
+			folderID, err := s.findBackupsFolder(driveService)
+			if err != nil {
+				return fmt.Errorf("failed to find backups folder: %w", err)
+			}
+
+			fileIDGoogle, err := s.lookupFileID(driveService, fileID.String(), folderID)
+			if err != nil {
+				return err
+			}
+
+			file, err := driveService.Files.Get(fileIDGoogle).Fields("size").Do()
+			if err != nil {
+				return fmt.Errorf("failed to get file from Google Drive: %w", err)
+			}
+
+			size = file.Size
+			return nil
+		},
+	)
+
+	return size, err
+}
+
 func (s *GoogleDriveStorage) DeleteFile(
 	encryptor encryption.FieldEncryptor,
 	fileID uuid.UUID,
@@ -196,6 +229,66 @@ func (s *GoogleDriveStorage) DeleteFile(
 	})
 }
 
+func (s *GoogleDriveStorage) ListFiles(
+	encryptor encryption.FieldEncryptor,
+) ([]storages_common.StorageFile, error) {
+	var files []storages_common.StorageFile
+
+	err := s.withRetryOnAuth(
+		context.Background(),
+		encryptor,
+		func(driveService *drive.Service) error {
+			files = make([]storages_common.StorageFile, 0)
+
+			folderID, err := s.findBackupsFolder(driveService)
+			if err != nil {
+				return fmt.Errorf("failed to find backups folder: %w", err)
+			}
+
+			query := fmt.Sprintf("trashed = false and '%s' in parents", folderID)
+			pageToken := ""
+
+			for {
+				call := driveService.Files.List().
+					Q(query).
+					Fields("nextPageToken, files(name, size, modifiedTime)").
+					PageSize(1000)
+				if pageToken != "" {
+					call = call.PageToken(pageToken)
+				}
+
+				results, err := call.Do()
+				if err != nil {
+					return fmt.Errorf("failed to list files in Google Drive: %w", err)
+				}
+
+				for _, file := range results.Files {
+					fileID, ok := storages_common.ParseFileID(file.Name)
+					if !ok {
+						continue
+					}
+
+					modifiedAt, _ := time.Parse(time.RFC3339, file.ModifiedTime)
+
+					files = append(files, storages_common.StorageFile{
+						FileID:     fileID,
+						SizeBytes:  file.Size,
+						ModifiedAt: modifiedAt.UTC(),
+					})
+				}
+
+				if results.NextPageToken == "" {
+					return nil
+				}
+
+				pageToken = results.NextPageToken
+			}
+		},
+	)
+
+	return files, err
+}
+
 func (s *GoogleDriveStorage) Validate(encryptor encryption.FieldEncryptor) error {
 	switch {
 	case s.ClientID == "":
@@ -600,7 +693,11 @@ func (s *GoogleDriveStorage) lookupFileID(
 	}
 
 	if len(results.Files) == 0 {
-		return "", fmt.Errorf("file %q not found in Google Drive backups folder", name)
+		return "", fmt.Errorf(
+			"file %q not found in Google Drive backups folder: %w",
+			name,
+			storages_common.ErrFileNotFound,
+		)
 	}
 
 	return results.Files[0].Id, nil
@@ -3,6 +3,7 @@ package local_storage
 import (
 	"context"
 	"databasus-backend/internal/config"
+	storages_common "databasus-backend/internal/features/storages/common"
 	"databasus-backend/internal/util/encryption"
 	files_utils "databasus-backend/internal/util/files"
 	"fmt"
@@ -146,6 +147,24 @@ func (l *LocalStorage) GetFile(
 	return file, nil
 }
 
+func (l *LocalStorage) GetFileSize(
+	encryptor encryption.FieldEncryptor,
+	fileID uuid.UUID,
+) (int64, error) {
+	filePath := filepath.Join(config.GetEnv().DataFolder, fileID.String())
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, fmt.Errorf("%w: %s", storages_common.ErrFileNotFound, fileID.String())
+		}
+
+		return 0, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	return info.Size(), nil
+}
+
 func (l *LocalStorage) DeleteFile(encryptor encryption.FieldEncryptor, fileID uuid.UUID) error {
 	filePath := filepath.Join(config.GetEnv().DataFolder, fileID.String())
 
@@ -160,6 +179,41 @@ func (l *LocalStorage) DeleteFile(encryptor encryption.FieldEncryptor, fileID uu
 	return nil
 }
 
+func (l *LocalStorage) ListFiles(
+	encryptor encryption.FieldEncryptor,
+) ([]storages_common.StorageFile, error) {
+	entries, err := os.ReadDir(config.GetEnv().DataFolder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backups directory: %w", err)
+	}
+
+	files := make([]storages_common.StorageFile, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		fileID, ok := storages_common.ParseFileID(entry.Name())
+		if !ok {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			// the file has been removed while listing
+			continue
+		}
+
+		files = append(files, storages_common.StorageFile{
+			FileID:     fileID,
+			SizeBytes:  info.Size(),
+			ModifiedAt: info.ModTime().UTC(),
+		})
+	}
+
+	return files, nil
+}
+
 func (l *LocalStorage) Validate(encryptor encryption.FieldEncryptor) error {
 	return nil
 }
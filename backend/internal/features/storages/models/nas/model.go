@@ -3,12 +3,14 @@ package nas_storage
 import (
 	"context"
 	"crypto/tls"
+	storages_common "databasus-backend/internal/features/storages/common"
 	"databasus-backend/internal/util/encryption"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -192,6 +194,38 @@ func (n *NASStorage) GetFile(
 	}, nil
 }
 
+func (n *NASStorage) GetFileSize(
+	encryptor encryption.FieldEncryptor,
+	fileID uuid.UUID,
+) (int64, error) {
+	session, err := n.createSession(encryptor)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create NAS session: %w", err)
+	}
+	defer func() {
+		_ = session.Logoff()
+	}()
+
+	fs, err := session.Mount(n.Share)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mount share '%s': %w", n.Share, err)
+	}
+	defer func() {
+		_ = fs.Umount()
+	}()
+
+	info, err := fs.Stat(n.getFilePath(fileID.String()))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, fmt.Errorf("%w: %s", storages_common.ErrFileNotFound, fileID.String())
+		}
+
+		return 0, fmt.Errorf("failed to stat file in NAS: %w", err)
+	}
+
+	return info.Size(), nil
+}
+
 func (n *NASStorage) DeleteFile(encryptor encryption.FieldEncryptor, fileID uuid.UUID) error {
 	session, err := n.createSession(encryptor)
 	if err != nil {
@@ -226,6 +260,56 @@ func (n *NASStorage) DeleteFile(encryptor encryption.FieldEncryptor, fileID uuid
 	return nil
 }
 
+func (n *NASStorage) ListFiles(
+	encryptor encryption.FieldEncryptor,
+) ([]storages_common.StorageFile, error) {
+	session, err := n.createSession(encryptor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create NAS session: %w", err)
+	}
+	defer func() {
+		_ = session.Logoff()
+	}()
+
+	fs, err := session.Mount(n.Share)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mount share '%s': %w", n.Share, err)
+	}
+	defer func() {
+		_ = fs.Umount()
+	}()
+
+	dir := strings.TrimSuffix(n.getFilePath(""), "/")
+	if dir == "" {
+		dir = "."
+	}
+
+	entries, err := fs.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files in NAS: %w", err)
+	}
+
+	files := make([]storages_common.StorageFile, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		fileID, ok := storages_common.ParseFileID(entry.Name())
+		if !ok {
+			continue
+		}
+
+		files = append(files, storages_common.StorageFile{
+			FileID:     fileID,
+			SizeBytes:  entry.Size(),
+			ModifiedAt: entry.ModTime().UTC(),
+		})
+	}
+
+	return files, nil
+}
+
 func (n *NASStorage) Validate(encryptor encryption.FieldEncryptor) error {
 	if n.Host == "" {
 		return errors.New("NAS host is required")
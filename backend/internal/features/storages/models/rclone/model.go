@@ -3,6 +3,7 @@ package rclone_storage
 import (
 	"bufio"
 	"context"
+	storages_common "databasus-backend/internal/features/storages/common"
 	"databasus-backend/internal/util/encryption"
 	"errors"
 	"fmt"
@@ -114,6 +115,29 @@ func (r *RcloneStorage) GetFile(
 	return reader, nil
 }
 
+func (r *RcloneStorage) GetFileSize(
+	encryptor encryption.FieldEncryptor,
+	fileID uuid.UUID,
+) (int64, error) {
+	ctx := context.Background()
+
+	remoteFs, err := r.getFs(ctx, encryptor)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create rclone filesystem: %w", err)
+	}
+
+	obj, err := remoteFs.NewObject(ctx, r.getFilePath(fileID.String()))
+	if err != nil {
+		if errors.Is(err, fs.ErrorObjectNotFound) {
+			return 0, fmt.Errorf("%w: %s", storages_common.ErrFileNotFound, fileID.String())
+		}
+
+		return 0, fmt.Errorf("failed to get object from rclone: %w", err)
+	}
+
+	return obj.Size(), nil
+}
+
 func (r *RcloneStorage) DeleteFile(encryptor encryption.FieldEncryptor, fileID uuid.UUID) error {
 	ctx := context.Background()
 
@@ -137,6 +161,43 @@ func (r *RcloneStorage) DeleteFile(encryptor encryption.FieldEncryptor, fileID u
 	return nil
 }
 
+func (r *RcloneStorage) ListFiles(
+	encryptor encryption.FieldEncryptor,
+) ([]storages_common.StorageFile, error) {
+	ctx := context.Background()
+
+	remoteFs, err := r.getFs(ctx, encryptor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rclone filesystem: %w", err)
+	}
+
+	entries, err := remoteFs.List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files in rclone: %w", err)
+	}
+
+	files := make([]storages_common.StorageFile, 0, len(entries))
+	for _, entry := range entries {
+		obj, isObject := entry.(fs.Object)
+		if !isObject {
+			continue
+		}
+
+		fileID, ok := storages_common.ParseFileID(obj.Remote())
+		if !ok {
+			continue
+		}
+
+		files = append(files, storages_common.StorageFile{
+			FileID:     fileID,
+			SizeBytes:  obj.Size(),
+			ModifiedAt: obj.ModTime(ctx).UTC(),
+		})
+	}
+
+	return files, nil
+}
+
 func (r *RcloneStorage) Validate(encryptor encryption.FieldEncryptor) error {
 	if r.ConfigContent == "" {
 		return errors.New("rclone config content is required")
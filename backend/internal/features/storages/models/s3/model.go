@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/md5"
 	"crypto/tls"
+	storages_common "databasus-backend/internal/features/storages/common"
 	"databasus-backend/internal/util/encryption"
 	"encoding/base64"
 	"errors"
@@ -33,6 +34,17 @@ const (
 	multipartChunkSize = 16 * 1024 * 1024
 )
 
+// ObjectLockMode is the S3 Object Lock retention mode of saved files. With
+// GOVERNANCE users with the special permission can still remove locked
+// files, with COMPLIANCE nobody can until the lock expires
+type ObjectLockMode string
+
+const (
+	ObjectLockModeNone       ObjectLockMode = "NONE"
+	ObjectLockModeGovernance ObjectLockMode = "GOVERNANCE"
+	ObjectLockModeCompliance ObjectLockMode = "COMPLIANCE"
+)
+
 type S3Storage struct {
 	StorageID   uuid.UUID `json:"storageId"   gorm:"primaryKey;type:uuid;column:storage_id"`
 	S3Bucket    string    `json:"s3Bucket"    gorm:"not null;type:text;column:s3_bucket"`
@@ -44,6 +56,11 @@ type S3Storage struct {
 	S3Prefix                string `json:"s3Prefix"                gorm:"type:text;column:s3_prefix"`
 	S3UseVirtualHostedStyle bool   `json:"s3UseVirtualHostedStyle" gorm:"default:false;column:s3_use_virtual_hosted_style"`
 	SkipTLSVerify           bool   `json:"skipTLSVerify"           gorm:"default:false;column:skip_tls_verify"`
+
+	// files are locked until the time set by storages_common.WithRetainUntil
+	// (derived from retention of the backup). Files saved without it are
+	// protected only by the default retention of the bucket, if any
+	ObjectLockMode ObjectLockMode `json:"objectLockMode" gorm:"not null;type:text;default:'NONE';column:object_lock_mode"`
 }
 
 func (s *S3Storage) TableName() string {
@@ -74,7 +91,7 @@ func (s *S3Storage) SaveFile(
 		ctx,
 		s.S3Bucket,
 		objectKey,
-		minio.PutObjectOptions{},
+		s.getObjectLockOptions(ctx, minio.PutObjectOptions{}),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to initiate multipart upload: %w", err)
@@ -155,9 +172,9 @@ func (s *S3Storage) SaveFile(
 			objectKey,
 			bytes.NewReader([]byte{}),
 			0,
-			minio.PutObjectOptions{
+			s.getObjectLockOptions(ctx, minio.PutObjectOptions{
 				SendContentMd5: true,
-			},
+			}),
 		)
 		if err != nil {
 			return fmt.Errorf("failed to upload empty file: %w", err)
@@ -228,6 +245,12 @@ func (s *S3Storage) DeleteFile(encryptor encryption.FieldEncryptor, fileID uuid.
 
 	objectKey := s.buildObjectKey(fileID.String())
 
+	// buckets with Object Lock are versioned, removing the object only adds
+	// a delete marker, so all versions are removed to delete the file
+	if s.isObjectLockEnabled() {
+		return s.deleteObjectVersions(client, objectKey)
+	}
+
 	// Delete the object using MinIO client
 	err = client.RemoveObject(
 		context.TODO(),
@@ -242,6 +265,125 @@ func (s *S3Storage) DeleteFile(encryptor encryption.FieldEncryptor, fileID uuid.
 	return nil
 }
 
+func (s *S3Storage) GetFileSize(
+	encryptor encryption.FieldEncryptor,
+	fileID uuid.UUID,
+) (int64, error) {
+	client, err := s.getClient(encryptor)
+	if err != nil {
+		return 0, err
+	}
+
+	objectInfo, err := client.StatObject(
+		context.TODO(),
+		s.S3Bucket,
+		s.buildObjectKey(fileID.String()),
+		minio.StatObjectOptions{},
+	)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return 0, fmt.Errorf("%w: %s", storages_common.ErrFileNotFound, fileID.String())
+		}
+
+		return 0, fmt.Errorf("failed to stat file in S3: %w", err)
+	}
+
+	return objectInfo.Size, nil
+}
+
+func (s *S3Storage) IsFileLockEnabled() bool {
+	return s.isObjectLockEnabled()
+}
+
+func (s *S3Storage) GetFileLockedUntil(
+	encryptor encryption.FieldEncryptor,
+	fileID uuid.UUID,
+) (*time.Time, error) {
+	if !s.isObjectLockEnabled() {
+		return nil, nil
+	}
+
+	client, err := s.getClient(encryptor)
+	if err != nil {
+		return nil, err
+	}
+
+	objectKey := s.buildObjectKey(fileID.String())
+	now := time.Now().UTC()
+
+	var lockedUntil *time.Time
+	for object := range client.ListObjects(
+		context.TODO(),
+		s.S3Bucket,
+		minio.ListObjectsOptions{Prefix: objectKey, WithVersions: true},
+	) {
+		if object.Err != nil {
+			return nil, fmt.Errorf("failed to list file versions in S3: %w", object.Err)
+		}
+
+		if object.Key != objectKey || object.IsDeleteMarker {
+			continue
+		}
+
+		_, retainUntil, err := client.GetObjectRetention(
+			context.TODO(),
+			s.S3Bucket,
+			objectKey,
+			object.VersionID,
+		)
+		if err != nil {
+			if minio.ToErrorResponse(err).Code == "NoSuchObjectLockConfiguration" {
+				continue
+			}
+
+			return nil, fmt.Errorf("failed to get file retention from S3: %w", err)
+		}
+
+		if retainUntil != nil && retainUntil.After(now) &&
+			(lockedUntil == nil || retainUntil.After(*lockedUntil)) {
+			utcRetainUntil := retainUntil.UTC()
+			lockedUntil = &utcRetainUntil
+		}
+	}
+
+	return lockedUntil, nil
+}
+
+func (s *S3Storage) ListFiles(
+	encryptor encryption.FieldEncryptor,
+) ([]storages_common.StorageFile, error) {
+	client, err := s.getClient(encryptor)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := s.buildObjectKey("")
+
+	files := make([]storages_common.StorageFile, 0)
+	for object := range client.ListObjects(
+		context.TODO(),
+		s.S3Bucket,
+		minio.ListObjectsOptions{Prefix: prefix},
+	) {
+		if object.Err != nil {
+			return nil, fmt.Errorf("failed to list files in S3: %w", object.Err)
+		}
+
+		fileID, ok := storages_common.ParseFileID(strings.TrimPrefix(object.Key, prefix))
+		if !ok {
+			continue
+		}
+
+		files = append(files, storages_common.StorageFile{
+			FileID:     fileID,
+			SizeBytes:  object.Size,
+			ModifiedAt: object.LastModified.UTC(),
+		})
+	}
+
+	return files, nil
+}
+
 func (s *S3Storage) Validate(encryptor encryption.FieldEncryptor) error {
 	if s.S3Bucket == "" {
 		return errors.New("S3 bucket is required")
@@ -253,6 +395,12 @@ func (s *S3Storage) Validate(encryptor encryption.FieldEncryptor) error {
 		return errors.New("S3 secret key is required")
 	}
 
+	switch s.ObjectLockMode {
+	case "", ObjectLockModeNone, ObjectLockModeGovernance, ObjectLockModeCompliance:
+	default:
+		return fmt.Errorf("invalid S3 object lock mode: %s", s.ObjectLockMode)
+	}
+
 	return nil
 }
 
@@ -280,6 +428,12 @@ func (s *S3Storage) TestConnection(encryptor encryption.FieldEncryptor) error {
 		return fmt.Errorf("bucket '%s' does not exist", s.S3Bucket)
 	}
 
+	if s.isObjectLockEnabled() {
+		if err := s.checkBucketObjectLock(ctx, client); err != nil {
+			return err
+		}
+	}
+
 	// Test write and delete permissions by uploading and removing a small test file
 	testFileID := uuid.New().String() + "-test"
 	testObjectKey := s.buildObjectKey(testFileID)
@@ -301,13 +455,21 @@ func (s *S3Storage) TestConnection(encryptor encryption.FieldEncryptor) error {
 		return fmt.Errorf("failed to upload test file to S3: %w", err)
 	}
 
-	// Delete test file
-	err = client.RemoveObject(
-		ctx,
-		s.S3Bucket,
-		testObjectKey,
-		minio.RemoveObjectOptions{},
-	)
+	// Delete test file. With object lock it stays in the bucket if the
+	// bucket locks new files by default
+	if s.isObjectLockEnabled() {
+		err = s.deleteObjectVersions(client, testObjectKey)
+		if errors.Is(err, storages_common.ErrFileLocked) {
+			err = nil
+		}
+	} else {
+		err = client.RemoveObject(
+			ctx,
+			s.S3Bucket,
+			testObjectKey,
+			minio.RemoveObjectOptions{},
+		)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to delete test file from S3: %w", err)
 	}
@@ -346,6 +508,7 @@ func (s *S3Storage) Update(incoming *S3Storage) {
 	s.S3Endpoint = incoming.S3Endpoint
 	s.S3UseVirtualHostedStyle = incoming.S3UseVirtualHostedStyle
 	s.SkipTLSVerify = incoming.SkipTLSVerify
+	s.ObjectLockMode = incoming.ObjectLockMode
 
 	if incoming.S3AccessKey != "" {
 		s.S3AccessKey = incoming.S3AccessKey
@@ -359,6 +522,154 @@ func (s *S3Storage) Update(incoming *S3Storage) {
 	// otherwise we will have to transfer all the data to the new prefix
 }
 
+func (s *S3Storage) isObjectLockEnabled() bool {
+	return s.ObjectLockMode == ObjectLockModeGovernance ||
+		s.ObjectLockMode == ObjectLockModeCompliance
+}
+
+// getObjectLockOptions adds the lock of the file to the upload options if
+// Object Lock is enabled and the retain-until time is set in the context
+func (s *S3Storage) getObjectLockOptions(
+	ctx context.Context,
+	options minio.PutObjectOptions,
+) minio.PutObjectOptions {
+	if !s.isObjectLockEnabled() {
+		return options
+	}
+
+	retainUntil, ok := storages_common.GetRetainUntil(ctx)
+	if !ok || !retainUntil.After(time.Now().UTC()) {
+		return options
+	}
+
+	options.Mode = minio.RetentionMode(s.ObjectLockMode)
+	options.RetainUntilDate = retainUntil.UTC()
+
+	return options
+}
+
+func (s *S3Storage) checkBucketObjectLock(ctx context.Context, client *minio.Client) error {
+	versioning, err := client.GetBucketVersioning(ctx, s.S3Bucket)
+	if err != nil {
+		return fmt.Errorf("failed to get bucket versioning: %w", err)
+	}
+
+	if !versioning.Enabled() {
+		return fmt.Errorf(
+			"versioning is not enabled on bucket '%s', it is required for object lock",
+			s.S3Bucket,
+		)
+	}
+
+	objectLock, _, _, _, err := client.GetObjectLockConfig(ctx, s.S3Bucket)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "ObjectLockConfigurationNotFoundError" {
+			return fmt.Errorf("object lock is not enabled on bucket '%s'", s.S3Bucket)
+		}
+
+		return fmt.Errorf("failed to get bucket object lock configuration: %w", err)
+	}
+
+	if objectLock != "Enabled" {
+		return fmt.Errorf("object lock is not enabled on bucket '%s'", s.S3Bucket)
+	}
+
+	return nil
+}
+
+// isObjectVersionLocked reports whether the version of the object has an
+// unexpired retention or a legal hold
+func (s *S3Storage) isObjectVersionLocked(
+	client *minio.Client,
+	objectKey string,
+	versionID string,
+) (bool, error) {
+	_, retainUntil, err := client.GetObjectRetention(
+		context.TODO(),
+		s.S3Bucket,
+		objectKey,
+		versionID,
+	)
+	if err != nil && minio.ToErrorResponse(err).Code != "NoSuchObjectLockConfiguration" {
+		return false, err
+	}
+
+	if err == nil && retainUntil != nil && retainUntil.After(time.Now().UTC()) {
+		return true, nil
+	}
+
+	legalHold, err := client.GetObjectLegalHold(
+		context.TODO(),
+		s.S3Bucket,
+		objectKey,
+		minio.GetObjectLegalHoldOptions{VersionID: versionID},
+	)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchObjectLockConfiguration" {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return legalHold != nil && *legalHold == minio.LegalHoldEnabled, nil
+}
+
+// deleteObjectVersions removes all versions of the object. Locked versions
+// are not removed (governance mode is not bypassed) and ErrFileLocked is
+// returned
+func (s *S3Storage) deleteObjectVersions(client *minio.Client, objectKey string) error {
+	isLocked := false
+
+	for object := range client.ListObjects(
+		context.TODO(),
+		s.S3Bucket,
+		minio.ListObjectsOptions{Prefix: objectKey, WithVersions: true},
+	) {
+		if object.Err != nil {
+			return fmt.Errorf("failed to list file versions in S3: %w", object.Err)
+		}
+
+		if object.Key != objectKey {
+			continue
+		}
+
+		err := client.RemoveObject(
+			context.TODO(),
+			s.S3Bucket,
+			objectKey,
+			minio.RemoveObjectOptions{VersionID: object.VersionID},
+		)
+		if err != nil {
+			// access is denied for locked versions as well as for missing
+			// permissions, the latter must be reported
+			if minio.ToErrorResponse(err).Code == "AccessDenied" {
+				isVersionLocked, lockErr := s.isObjectVersionLocked(
+					client,
+					objectKey,
+					object.VersionID,
+				)
+				if lockErr != nil {
+					return fmt.Errorf("failed to check file version lock in S3: %w", lockErr)
+				}
+
+				if isVersionLocked {
+					isLocked = true
+					continue
+				}
+			}
+
+			return fmt.Errorf("failed to delete file version from S3: %w", err)
+		}
+	}
+
+	if isLocked {
+		return storages_common.ErrFileLocked
+	}
+
+	return nil
+}
+
 func (s *S3Storage) buildObjectKey(fileName string) string {
 	if s.S3Prefix == "" {
 		return fileName
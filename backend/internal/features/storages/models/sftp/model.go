@@ -2,12 +2,14 @@ package sftp_storage
 
 import (
 	"context"
+	storages_common "databasus-backend/internal/features/storages/common"
 	"databasus-backend/internal/util/encryption"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net"
+	"os"
 	"strings"
 	"time"
 
@@ -153,6 +155,31 @@ func (s *SFTPStorage) GetFile(
 	}, nil
 }
 
+func (s *SFTPStorage) GetFileSize(
+	encryptor encryption.FieldEncryptor,
+	fileID uuid.UUID,
+) (int64, error) {
+	client, sshConn, err := s.connect(encryptor, sftpConnectTimeout)
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect to SFTP: %w", err)
+	}
+	defer func() {
+		_ = client.Close()
+		_ = sshConn.Close()
+	}()
+
+	info, err := client.Stat(s.getFilePath(fileID.String()))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, fmt.Errorf("%w: %s", storages_common.ErrFileNotFound, fileID.String())
+		}
+
+		return 0, fmt.Errorf("failed to stat file in SFTP: %w", err)
+	}
+
+	return info.Size(), nil
+}
+
 func (s *SFTPStorage) DeleteFile(encryptor encryption.FieldEncryptor, fileID uuid.UUID) error {
 	client, sshConn, err := s.connect(encryptor, sftpConnectTimeout)
 	if err != nil {
@@ -178,6 +205,49 @@ func (s *SFTPStorage) DeleteFile(encryptor encryption.FieldEncryptor, fileID uui
 	return nil
 }
 
+func (s *SFTPStorage) ListFiles(
+	encryptor encryption.FieldEncryptor,
+) ([]storages_common.StorageFile, error) {
+	client, sshConn, err := s.connect(encryptor, sftpConnectTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to SFTP: %w", err)
+	}
+	defer func() {
+		_ = client.Close()
+		_ = sshConn.Close()
+	}()
+
+	dir := strings.TrimSuffix(s.getFilePath(""), "/")
+	if dir == "" {
+		dir = "."
+	}
+
+	entries, err := client.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files in SFTP: %w", err)
+	}
+
+	files := make([]storages_common.StorageFile, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		fileID, ok := storages_common.ParseFileID(entry.Name())
+		if !ok {
+			continue
+		}
+
+		files = append(files, storages_common.StorageFile{
+			FileID:     fileID,
+			SizeBytes:  entry.Size(),
+			ModifiedAt: entry.ModTime().UTC(),
+		})
+	}
+
+	return files, nil
+}
+
 func (s *SFTPStorage) Validate(encryptor encryption.FieldEncryptor) error {
 	if s.Host == "" {
 		return errors.New("SFTP host is required")
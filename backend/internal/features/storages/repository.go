@@ -174,6 +174,27 @@ func (r *StorageRepository) FindByWorkspaceID(workspaceID uuid.UUID) ([]*Storage
 	return storages, nil
 }
 
+func (r *StorageRepository) FindAll() ([]*Storage, error) {
+	var storages []*Storage
+
+	if err := db.
+		GetDb().
+		Preload("LocalStorage").
+		Preload("S3Storage").
+		Preload("GoogleDriveStorage").
+		Preload("NASStorage").
+		Preload("AzureBlobStorage").
+		Preload("FTPStorage").
+		Preload("SFTPStorage").
+		Preload("RcloneStorage").
+		Order("name ASC").
+		Find(&storages).Error; err != nil {
+		return nil, err
+	}
+
+	return storages, nil
+}
+
 func (r *StorageRepository) Delete(s *Storage) error {
 	return db.GetDb().Transaction(func(tx *gorm.DB) error {
 		// Delete specific storage based on type
@@ -247,6 +247,12 @@ func (s *StorageService) GetStorageByID(
 	return s.storageRepository.FindByID(id)
 }
 
+// GetAllStorages returns storages of all workspaces for background jobs,
+// sensitive data is kept
+func (s *StorageService) GetAllStorages() ([]*Storage, error) {
+	return s.storageRepository.FindAll()
+}
+
 func (s *StorageService) TransferStorageToWorkspace(
 	user *users_models.User,
 	storageID uuid.UUID,
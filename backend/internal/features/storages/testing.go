@@ -2,6 +2,7 @@ package storages
 
 import (
 	local_storage "databasus-backend/internal/features/storages/models/local"
+	s3_storage "databasus-backend/internal/features/storages/models/s3"
 
 	"github.com/google/uuid"
 )
@@ -22,6 +23,30 @@ func CreateTestStorage(workspaceID uuid.UUID) *Storage {
 	return storage
 }
 
+// CreateTestS3Storage creates an S3 storage which is never connected to,
+// for tests which need a storage of another type than local
+func CreateTestS3Storage(workspaceID uuid.UUID) *Storage {
+	storage := &Storage{
+		WorkspaceID: workspaceID,
+		Type:        StorageTypeS3,
+		Name:        "Test S3 Storage " + uuid.New().String(),
+		S3Storage: &s3_storage.S3Storage{
+			S3Bucket:       "test-bucket",
+			S3Region:       "us-east-1",
+			S3AccessKey:    "test-access-key",
+			S3SecretKey:    "test-secret-key",
+			ObjectLockMode: s3_storage.ObjectLockModeNone,
+		},
+	}
+
+	storage, err := storageRepository.Save(storage)
+	if err != nil {
+		panic(err)
+	}
+
+	return storage
+}
+
 func RemoveTestStorage(id uuid.UUID) {
 	storage, err := storageRepository.FindByID(id)
 	if err != nil {
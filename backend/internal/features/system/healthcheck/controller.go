@@ -16,7 +16,8 @@ func (c *HealthcheckController) RegisterRoutes(router *gin.RouterGroup) {
 
 // CheckHealth
 // @Summary Check system health
-// @Description Check if the system is healthy by testing database connection
+// @Description Check if the system is healthy by testing database connection. While the
+// @Description application is shutting down it reports running backups and restores it waits for
 // @Tags system/health
 // @Produce json
 // @Success 200 {object} HealthcheckResponse
@@ -35,5 +36,11 @@ func (c *HealthcheckController) CheckHealth(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusServiceUnavailable, HealthcheckResponse{Status: err.Error()})
+	ctx.JSON(
+		http.StatusServiceUnavailable,
+		HealthcheckResponse{
+			Status: err.Error(),
+			Drain:  c.healthcheckService.GetDrainState(),
+		},
+	)
 }
@@ -0,0 +1,48 @@
+package system_healthcheck
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"databasus-backend/internal/config"
+	test_utils "databasus-backend/internal/util/testing"
+)
+
+func createTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	GetHealthcheckController().RegisterRoutes(router.Group("/api/v1"))
+
+	return router
+}
+
+func Test_CheckHealth_WhenDraining_UnhealthyWithDrainState(t *testing.T) {
+	defer config.ResetShutdownForTesting()
+
+	router := createTestRouter()
+
+	config.StartDraining()
+
+	var response HealthcheckResponse
+	test_utils.MakeGetRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/system/health",
+		"",
+		http.StatusServiceUnavailable,
+		&response,
+	)
+
+	assert.Contains(t, response.Status, "application is shutting down")
+	assert.NotNil(t, response.Drain)
+	assert.Equal(t, 0, response.Drain.RunningBackupsCount)
+	assert.Equal(t, 0, response.Drain.RunningRestoresCount)
+}
+
+func Test_GetDrainState_WhenNotDraining_NoDrainState(t *testing.T) {
+	assert.Nil(t, healthcheckService.GetDrainState())
+}
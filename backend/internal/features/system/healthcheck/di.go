@@ -3,11 +3,14 @@ package system_healthcheck
 import (
 	"databasus-backend/internal/features/backups/backups"
 	"databasus-backend/internal/features/disk"
+	"databasus-backend/internal/features/restores"
 )
 
 var healthcheckService = &HealthcheckService{
 	disk.GetDiskService(),
 	backups.GetBackupBackgroundService(),
+	backups.GetBackupService(),
+	restores.GetRestoreService(),
 }
 var healthcheckController = &HealthcheckController{
 	healthcheckService,
@@ -2,4 +2,12 @@ package system_healthcheck
 
 type HealthcheckResponse struct {
 	Status string `json:"status"`
+	// filled while the application waits for running backups and restores
+	// to finish on shutdown
+	Drain *DrainStateResponse `json:"drain,omitempty"`
+}
+
+type DrainStateResponse struct {
+	RunningBackupsCount  int `json:"runningBackupsCount"`
+	RunningRestoresCount int `json:"runningRestoresCount"`
 }
@@ -1,18 +1,32 @@
 package system_healthcheck
 
 import (
+	"databasus-backend/internal/config"
 	"databasus-backend/internal/features/backups/backups"
 	"databasus-backend/internal/features/disk"
+	"databasus-backend/internal/features/restores"
 	"databasus-backend/internal/storage"
 	"errors"
+	"fmt"
 )
 
 type HealthcheckService struct {
 	diskService             *disk.DiskService
 	backupBackgroundService *backups.BackupBackgroundService
+	backupService           *backups.BackupService
+	restoreService          *restores.RestoreService
 }
 
 func (s *HealthcheckService) IsHealthy() error {
+	// the application is reported as unhealthy, so no new work is routed to it
+	if drainState := s.GetDrainState(); drainState != nil {
+		return fmt.Errorf(
+			"application is shutting down, waiting for %d backups and %d restores to finish",
+			drainState.RunningBackupsCount,
+			drainState.RunningRestoresCount,
+		)
+	}
+
 	diskUsage, err := s.diskService.GetDiskUsage()
 	if err != nil {
 		return errors.New("cannot get disk usage")
@@ -35,3 +49,16 @@ func (s *HealthcheckService) IsHealthy() error {
 
 	return nil
 }
+
+// GetDrainState returns running backups and restores the application waits
+// for on shutdown, nil if the application is not shutting down
+func (s *HealthcheckService) GetDrainState() *DrainStateResponse {
+	if !config.IsDraining() {
+		return nil
+	}
+
+	return &DrainStateResponse{
+		RunningBackupsCount:  s.backupService.GetRunningBackupsCount(),
+		RunningRestoresCount: s.restoreService.GetRunningRestoresCount(),
+	}
+}
@@ -34,9 +34,9 @@ func (c *UserController) RegisterRoutes(router *gin.RouterGroup) {
 
 func (c *UserController) RegisterProtectedRoutes(router *gin.RouterGroup) {
 	router.GET("/users/me", c.GetCurrentUser)
-	router.PUT("/users/me", c.UpdateUserInfo)
-	router.PUT("/users/change-password", c.ChangePassword)
-	router.POST("/users/invite", c.InviteUser)
+	router.PUT("/users/me", user_middleware.RequireUserSession(), c.UpdateUserInfo)
+	router.PUT("/users/change-password", user_middleware.RequireUserSession(), c.ChangePassword)
+	router.POST("/users/invite", user_middleware.RequireUserSession(), c.InviteUser)
 }
 
 func (c *UserController) SetSignInLimiter(limiter *rate.Limiter) {
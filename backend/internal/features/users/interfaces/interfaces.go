@@ -1,9 +1,16 @@
 package users_interfaces
 
 import (
+	users_models "databasus-backend/internal/features/users/models"
+
 	"github.com/google/uuid"
 )
 
 type AuditLogWriter interface {
 	WriteAuditLog(message string, userID *uuid.UUID, workspaceID *uuid.UUID)
 }
+
+type APIKeyAuthenticator interface {
+	GetUserFromAPIKey(key string) (*users_models.User, error)
+	WriteAPIKeyRequestAuditLog(user *users_models.User, method string, path string, status int)
+}
@@ -5,11 +5,13 @@ import (
 	users_models "databasus-backend/internal/features/users/models"
 	users_services "databasus-backend/internal/features/users/services"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware validates JWT token and adds user to context
+// AuthMiddleware validates JWT token or workspace API key and adds user to
+// context. Requests made with API keys are written to audit log
 func AuthMiddleware(userService *users_services.UserService) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		token := ctx.GetHeader("Authorization")
@@ -24,6 +26,26 @@ func AuthMiddleware(userService *users_services.UserService) gin.HandlerFunc {
 			token = token[7:]
 		}
 
+		if strings.HasPrefix(token, users_models.APIKeyPrefix) {
+			user, err := userService.GetUserFromAPIKey(token)
+			if err != nil {
+				ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+				ctx.Abort()
+				return
+			}
+
+			ctx.Set("user", user)
+			ctx.Next()
+
+			userService.WriteAPIKeyRequestAuditLog(
+				user,
+				ctx.Request.Method,
+				ctx.FullPath(),
+				ctx.Writer.Status(),
+			)
+			return
+		}
+
 		user, err := userService.GetUserFromToken(token)
 		if err != nil {
 			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
@@ -36,6 +58,27 @@ func AuthMiddleware(userService *users_services.UserService) gin.HandlerFunc {
 	}
 }
 
+// RequireUserSession rejects requests authenticated with API keys. It
+// protects account-level endpoints, which API keys must not reach
+func RequireUserSession() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user, ok := GetUserFromContext(ctx)
+		if !ok {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			ctx.Abort()
+			return
+		}
+
+		if user.IsAPIKey() {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "API keys cannot access this endpoint"})
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
 func RequireRole(requiredRole users_enums.UserRole) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		userInterface, exists := ctx.Get("user")
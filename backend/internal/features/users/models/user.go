@@ -18,6 +18,21 @@ type User struct {
 	GitHubOAuthID        *string                `json:"-"         gorm:"column:github_oauth_id"`
 	GoogleOAuthID        *string                `json:"-"         gorm:"column:google_oauth_id"`
 	CreatedAt            time.Time              `json:"createdAt"`
+
+	// APIKey is set when the request is authenticated with a workspace API
+	// key instead of a user session. Such user is limited to the key's
+	// workspace and role
+	APIKey *APIKeyScope `json:"-" gorm:"-"`
+}
+
+// APIKeyPrefix distinguishes API keys from JWT tokens in Authorization header
+const APIKeyPrefix = "dbs_"
+
+type APIKeyScope struct {
+	ID          uuid.UUID
+	Name        string
+	WorkspaceID uuid.UUID
+	Role        users_enums.WorkspaceRole
 }
 
 func (User) TableName() string {
@@ -26,6 +41,10 @@ func (User) TableName() string {
 
 // Permission methods
 func (u *User) CanInviteUsers(settings *UsersSettings) bool {
+	if u.IsAPIKey() {
+		return false
+	}
+
 	if u.Role == users_enums.UserRoleAdmin {
 		return true
 	}
@@ -42,6 +61,10 @@ func (u *User) CanUpdateSettings() bool {
 }
 
 func (u *User) CanCreateWorkspaces(settings *UsersSettings) bool {
+	if u.IsAPIKey() {
+		return false
+	}
+
 	if u.Role == users_enums.UserRoleAdmin {
 		return true
 	}
@@ -52,6 +75,10 @@ func (u *User) IsActiveUser() bool {
 	return u.Status == users_enums.UserStatusActive
 }
 
+func (u *User) IsAPIKey() bool {
+	return u.APIKey != nil
+}
+
 func (u *User) HasPassword() bool {
 	return u.HashedPassword != nil && *u.HashedPassword != ""
 }
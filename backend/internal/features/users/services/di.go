@@ -10,6 +10,7 @@ var userService = &UserService{
 	secrets.GetSecretKeyService(),
 	settingsService,
 	nil,
+	nil,
 }
 var settingsService = &SettingsService{
 	users_repositories.GetUsersSettingsRepository(),
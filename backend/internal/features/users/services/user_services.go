@@ -31,12 +31,38 @@ type UserService struct {
 	secretKeyService *secrets.SecretKeyService
 	settingsService  *SettingsService
 	auditLogWriter   users_interfaces.AuditLogWriter
+	apiKeyAuth       users_interfaces.APIKeyAuthenticator
 }
 
 func (s *UserService) SetAuditLogWriter(writer users_interfaces.AuditLogWriter) {
 	s.auditLogWriter = writer
 }
 
+func (s *UserService) SetAPIKeyAuthenticator(authenticator users_interfaces.APIKeyAuthenticator) {
+	s.apiKeyAuth = authenticator
+}
+
+func (s *UserService) GetUserFromAPIKey(key string) (*users_models.User, error) {
+	if s.apiKeyAuth == nil {
+		return nil, errors.New("API keys are not supported")
+	}
+
+	return s.apiKeyAuth.GetUserFromAPIKey(key)
+}
+
+func (s *UserService) WriteAPIKeyRequestAuditLog(
+	user *users_models.User,
+	method string,
+	path string,
+	status int,
+) {
+	if s.apiKeyAuth == nil {
+		return
+	}
+
+	s.apiKeyAuth.WriteAPIKeyRequestAuditLog(user, method, path, status)
+}
+
 func (s *UserService) SignUp(request *users_dto.SignUpRequestDTO) error {
 	existingUser, err := s.userRepository.GetUserByEmail(request.Email)
 	if err != nil {
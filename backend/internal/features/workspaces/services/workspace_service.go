@@ -100,6 +100,25 @@ func (s *WorkspaceService) GetWorkspace(
 func (s *WorkspaceService) GetUserWorkspaces(
 	user *users_models.User,
 ) (*workspaces_dto.ListWorkspacesResponseDTO, error) {
+	if user.IsAPIKey() {
+		workspace, err := s.workspaceRepository.GetWorkspaceByID(user.APIKey.WorkspaceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user workspaces: %w", err)
+		}
+
+		role := user.APIKey.Role
+		return &workspaces_dto.ListWorkspacesResponseDTO{
+			Workspaces: []workspaces_dto.WorkspaceResponseDTO{
+				{
+					ID:        workspace.ID,
+					Name:      workspace.Name,
+					CreatedAt: workspace.CreatedAt,
+					UserRole:  &role,
+				},
+			},
+		}, nil
+	}
+
 	workspaces, err := s.membershipRepository.GetWorkspacesWithRolesByUserID(user.Role, user.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user workspaces: %w", err)
@@ -148,15 +167,13 @@ func (s *WorkspaceService) UpdateWorkspace(
 }
 
 func (s *WorkspaceService) DeleteWorkspace(workspaceID uuid.UUID, user *users_models.User) error {
-	if user.Role != users_enums.UserRoleAdmin {
-		userWorkspaceRole, err := s.GetUserWorkspaceRole(workspaceID, user.ID)
-		if err != nil {
-			return fmt.Errorf("failed to get user role: %w", err)
-		}
+	userWorkspaceRole, err := s.getWorkspaceRole(workspaceID, user)
+	if err != nil {
+		return fmt.Errorf("failed to get user role: %w", err)
+	}
 
-		if userWorkspaceRole == nil || *userWorkspaceRole != users_enums.WorkspaceRoleOwner {
-			return workspaces_errors.ErrOnlyOwnerOrAdminCanDeleteWorkspace
-		}
+	if userWorkspaceRole == nil || *userWorkspaceRole != users_enums.WorkspaceRoleOwner {
+		return workspaces_errors.ErrOnlyOwnerOrAdminCanDeleteWorkspace
 	}
 
 	workspace, err := s.workspaceRepository.GetWorkspaceByID(workspaceID)
@@ -194,12 +211,7 @@ func (s *WorkspaceService) CanUserAccessWorkspace(
 	workspaceID uuid.UUID,
 	user *users_models.User,
 ) (bool, *users_enums.WorkspaceRole, error) {
-	if user.Role == users_enums.UserRoleAdmin {
-		adminRole := users_enums.WorkspaceRoleOwner
-		return true, &adminRole, nil
-	}
-
-	role, err := s.membershipRepository.GetUserWorkspaceRole(workspaceID, user.ID)
+	role, err := s.getWorkspaceRole(workspaceID, user)
 	if err != nil {
 		return false, nil, nil
 	}
@@ -211,11 +223,7 @@ func (s *WorkspaceService) CanUserManageWorkspace(
 	workspaceID uuid.UUID,
 	user *users_models.User,
 ) (bool, error) {
-	if user.Role == users_enums.UserRoleAdmin {
-		return true, nil
-	}
-
-	role, err := s.membershipRepository.GetUserWorkspaceRole(workspaceID, user.ID)
+	role, err := s.getWorkspaceRole(workspaceID, user)
 	if err != nil {
 		return false, err
 	}
@@ -232,11 +240,7 @@ func (s *WorkspaceService) CanUserManageDBs(
 	workspaceID uuid.UUID,
 	user *users_models.User,
 ) (bool, error) {
-	if user.Role == users_enums.UserRoleAdmin {
-		return true, nil
-	}
-
-	role, err := s.membershipRepository.GetUserWorkspaceRole(workspaceID, user.ID)
+	role, err := s.getWorkspaceRole(workspaceID, user)
 	if err != nil {
 		return false, err
 	}
@@ -253,11 +257,7 @@ func (s *WorkspaceService) CanUserManageMembership(
 	workspaceID uuid.UUID,
 	user *users_models.User,
 ) (bool, error) {
-	if user.Role == users_enums.UserRoleAdmin {
-		return true, nil
-	}
-
-	role, err := s.membershipRepository.GetUserWorkspaceRole(workspaceID, user.ID)
+	role, err := s.getWorkspaceRole(workspaceID, user)
 	if err != nil {
 		return false, err
 	}
@@ -273,11 +273,7 @@ func (s *WorkspaceService) CanUserManageAdmins(
 	workspaceID uuid.UUID,
 	user *users_models.User,
 ) (bool, error) {
-	if user.Role == users_enums.UserRoleAdmin {
-		return true, nil
-	}
-
-	role, err := s.membershipRepository.GetUserWorkspaceRole(workspaceID, user.ID)
+	role, err := s.getWorkspaceRole(workspaceID, user)
 	if err != nil {
 		return false, err
 	}
@@ -289,6 +285,30 @@ func (s *WorkspaceService) CanUserManageAdmins(
 	return *role == users_enums.WorkspaceRoleOwner, nil
 }
 
+// getWorkspaceRole returns the role the user acts with in the workspace.
+// Admins act as owners of all workspaces, API keys act with the key's role
+// in the key's workspace only
+func (s *WorkspaceService) getWorkspaceRole(
+	workspaceID uuid.UUID,
+	user *users_models.User,
+) (*users_enums.WorkspaceRole, error) {
+	if user.IsAPIKey() {
+		if user.APIKey.WorkspaceID != workspaceID {
+			return nil, nil
+		}
+
+		role := user.APIKey.Role
+		return &role, nil
+	}
+
+	if user.Role == users_enums.UserRoleAdmin {
+		adminRole := users_enums.WorkspaceRoleOwner
+		return &adminRole, nil
+	}
+
+	return s.membershipRepository.GetUserWorkspaceRole(workspaceID, user.ID)
+}
+
 func (s *WorkspaceService) GetWorkspaceAuditLogs(
 	workspaceID uuid.UUID,
 	user *users_models.User,
@@ -0,0 +1,56 @@
+package jobs
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Backups and restores hold a lease while they are running. The lease is
+// extended by heartbeats, so a job with expired lease has been interrupted
+// by a crash or a restart of the instance running it and can be started again
+const (
+	LeaseDuration     = 2 * time.Minute
+	HeartbeatInterval = 30 * time.Second
+
+	// how many times an interrupted job is started before it is failed
+	MaxAttempts = 3
+)
+
+func GetLeaseExpiresAt() time.Time {
+	return time.Now().UTC().Add(LeaseDuration)
+}
+
+// StartHeartbeat calls extendLease every interval until the returned stop
+// function is called
+func StartHeartbeat(
+	logger *slog.Logger,
+	interval time.Duration,
+	extendLease func() error,
+) func() {
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := extendLease(); err != nil {
+					logger.Error("Failed to extend job lease", "error", err)
+				}
+			}
+		}
+	}()
+
+	var stopOnce sync.Once
+
+	return func() {
+		stopOnce.Do(func() {
+			close(done)
+		})
+	}
+}
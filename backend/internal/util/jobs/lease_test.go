@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StartHeartbeat_WhenRunning_LeaseExtendedUntilStopped(t *testing.T) {
+	var extendCount atomic.Int32
+
+	stop := StartHeartbeat(slog.Default(), 10*time.Millisecond, func() error {
+		extendCount.Add(1)
+		return nil
+	})
+
+	assert.Eventually(t, func() bool {
+		return extendCount.Load() >= 2
+	}, time.Second, 5*time.Millisecond)
+
+	stop()
+	stop()
+
+	countAfterStop := extendCount.Load()
+	time.Sleep(50 * time.Millisecond)
+
+	assert.LessOrEqual(t, extendCount.Load(), countAfterStop+1)
+}
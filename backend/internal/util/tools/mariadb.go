@@ -41,8 +41,9 @@ const (
 type MariadbExecutable string
 
 const (
-	MariadbExecutableMariadbDump MariadbExecutable = "mariadb-dump"
-	MariadbExecutableMariadb     MariadbExecutable = "mariadb"
+	MariadbExecutableMariadbDump   MariadbExecutable = "mariadb-dump"
+	MariadbExecutableMariadb       MariadbExecutable = "mariadb"
+	MariadbExecutableMariadbBinlog MariadbExecutable = "mariadb-binlog"
 )
 
 // GetMariadbClientVersionForServer returns the appropriate client version to use
@@ -0,0 +1,62 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE backup_configs
+    ADD COLUMN is_wal_archiving_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE backups
+    ADD COLUMN type          TEXT NOT NULL DEFAULT 'DEFAULT',
+    ADD COLUMN wal_start_lsn TEXT,
+    ADD COLUMN wal_stop_lsn  TEXT;
+
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+
+CREATE TABLE wal_segments (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    database_id     UUID NOT NULL,
+    storage_id      UUID NOT NULL,
+    file_name       TEXT NOT NULL,
+    size_bytes      BIGINT NOT NULL DEFAULT 0,
+    encryption_salt TEXT,
+    encryption_iv   TEXT,
+    encryption      TEXT NOT NULL DEFAULT 'NONE',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE wal_segments
+    ADD CONSTRAINT fk_wal_segments_database_id
+    FOREIGN KEY (database_id)
+    REFERENCES databases (id)
+    ON DELETE CASCADE;
+
+ALTER TABLE wal_segments
+    ADD CONSTRAINT fk_wal_segments_storage_id
+    FOREIGN KEY (storage_id)
+    REFERENCES storages (id)
+    ON DELETE CASCADE;
+
+ALTER TABLE wal_segments
+    ADD CONSTRAINT uq_wal_segments_database_id_file_name
+    UNIQUE (database_id, file_name);
+
+CREATE INDEX idx_wal_segments_database_id_created_at ON wal_segments (database_id, created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_wal_segments_database_id_created_at;
+DROP TABLE IF EXISTS wal_segments;
+
+ALTER TABLE backups
+    DROP COLUMN IF EXISTS wal_stop_lsn,
+    DROP COLUMN IF EXISTS wal_start_lsn,
+    DROP COLUMN IF EXISTS type;
+
+ALTER TABLE backup_configs
+    DROP COLUMN IF EXISTS is_wal_archiving_enabled;
+
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE backup_configs
+    ADD COLUMN backup_method TEXT NOT NULL DEFAULT 'LOGICAL';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE backup_configs
+    DROP COLUMN IF EXISTS backup_method;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE backup_configs
+    ADD COLUMN retention_policy_type TEXT NOT NULL DEFAULT 'TIME_PERIOD',
+    ADD COLUMN gfs_hourly_count      INT  NOT NULL DEFAULT 0,
+    ADD COLUMN gfs_daily_count       INT  NOT NULL DEFAULT 0,
+    ADD COLUMN gfs_weekly_count      INT  NOT NULL DEFAULT 0,
+    ADD COLUMN gfs_monthly_count     INT  NOT NULL DEFAULT 0,
+    ADD COLUMN gfs_yearly_count      INT  NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE backup_configs
+    DROP COLUMN IF EXISTS gfs_yearly_count,
+    DROP COLUMN IF EXISTS gfs_monthly_count,
+    DROP COLUMN IF EXISTS gfs_weekly_count,
+    DROP COLUMN IF EXISTS gfs_daily_count,
+    DROP COLUMN IF EXISTS gfs_hourly_count,
+    DROP COLUMN IF EXISTS retention_policy_type;
+-- +goose StatementEnd
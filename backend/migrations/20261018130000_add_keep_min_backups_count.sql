@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- existing configs get the same default as new ones, the last completed
+-- backup is always kept
+ALTER TABLE backup_configs
+    ADD COLUMN keep_min_backups_count INT NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE backup_configs
+    DROP COLUMN IF EXISTS keep_min_backups_count;
+-- +goose StatementEnd
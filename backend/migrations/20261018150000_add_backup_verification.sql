@@ -0,0 +1,111 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE verification_configs (
+    database_id         UUID PRIMARY KEY,
+    is_enabled          BOOLEAN NOT NULL DEFAULT FALSE,
+    sandbox_database_id UUID,
+    interval_hours      INT NOT NULL DEFAULT 24
+);
+
+ALTER TABLE verification_configs
+    ADD CONSTRAINT fk_verification_configs_database_id
+    FOREIGN KEY (database_id)
+    REFERENCES databases (id)
+    ON DELETE CASCADE;
+
+ALTER TABLE verification_configs
+    ADD CONSTRAINT fk_verification_configs_sandbox_database_id
+    FOREIGN KEY (sandbox_database_id)
+    REFERENCES databases (id)
+    ON DELETE SET NULL;
+
+CREATE INDEX idx_verification_configs_sandbox_database_id
+    ON verification_configs (sandbox_database_id);
+
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+
+CREATE TABLE verification_checks (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    database_id   UUID NOT NULL,
+    type          TEXT NOT NULL,
+    table_name    TEXT NOT NULL DEFAULT '',
+    min_row_count BIGINT NOT NULL DEFAULT 0,
+    query         TEXT NOT NULL DEFAULT '',
+    position      INT NOT NULL DEFAULT 0
+);
+
+ALTER TABLE verification_checks
+    ADD CONSTRAINT fk_verification_checks_database_id
+    FOREIGN KEY (database_id)
+    REFERENCES verification_configs (database_id)
+    ON DELETE CASCADE;
+
+CREATE INDEX idx_verification_checks_database_id ON verification_checks (database_id);
+
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+
+CREATE TABLE verification_results (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    database_id         UUID NOT NULL,
+    backup_id           UUID NOT NULL,
+    sandbox_database_id UUID NOT NULL,
+    status              TEXT NOT NULL,
+    fail_message        TEXT,
+    passed_checks_count INT NOT NULL DEFAULT 0,
+    total_checks_count  INT NOT NULL DEFAULT 0,
+    restore_duration_ms BIGINT NOT NULL DEFAULT 0,
+    duration_ms         BIGINT NOT NULL DEFAULT 0,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at        TIMESTAMPTZ
+);
+
+ALTER TABLE verification_results
+    ADD CONSTRAINT fk_verification_results_database_id
+    FOREIGN KEY (database_id)
+    REFERENCES databases (id)
+    ON DELETE CASCADE;
+
+ALTER TABLE verification_results
+    ADD CONSTRAINT fk_verification_results_backup_id
+    FOREIGN KEY (backup_id)
+    REFERENCES backups (id)
+    ON DELETE CASCADE;
+
+CREATE INDEX idx_verification_results_database_id_created_at
+    ON verification_results (database_id, created_at DESC);
+
+CREATE INDEX idx_verification_results_status ON verification_results (status);
+
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+
+-- existing configs that notify on failed backups notify on failed verification too
+UPDATE backup_configs
+    SET send_notifications_on = send_notifications_on || ',BACKUP_VERIFICATION_FAILED'
+    WHERE send_notifications_on LIKE '%BACKUP_FAILED%';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+UPDATE backup_configs
+    SET send_notifications_on = REPLACE(send_notifications_on, ',BACKUP_VERIFICATION_FAILED', '');
+
+DROP INDEX IF EXISTS idx_verification_results_status;
+DROP INDEX IF EXISTS idx_verification_results_database_id_created_at;
+DROP TABLE IF EXISTS verification_results;
+
+DROP INDEX IF EXISTS idx_verification_checks_database_id;
+DROP TABLE IF EXISTS verification_checks;
+
+DROP INDEX IF EXISTS idx_verification_configs_sandbox_database_id;
+DROP TABLE IF EXISTS verification_configs;
+
+-- +goose StatementEnd
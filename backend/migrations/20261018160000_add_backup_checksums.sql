@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE backups
+    ADD COLUMN checksum             TEXT,
+    ADD COLUMN integrity_status     TEXT,
+    ADD COLUMN integrity_message    TEXT,
+    ADD COLUMN integrity_checked_at TIMESTAMPTZ;
+
+CREATE INDEX idx_backups_integrity_status ON backups (integrity_status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_backups_integrity_status;
+
+ALTER TABLE backups
+    DROP COLUMN IF EXISTS integrity_checked_at,
+    DROP COLUMN IF EXISTS integrity_message,
+    DROP COLUMN IF EXISTS integrity_status,
+    DROP COLUMN IF EXISTS checksum;
+-- +goose StatementEnd
@@ -0,0 +1,62 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE storage_reconciliation_reports (
+    storage_id           UUID PRIMARY KEY,
+    status               TEXT NOT NULL,
+    fail_message         TEXT,
+    listed_files_count   INT NOT NULL DEFAULT 0,
+    orphaned_files_count INT NOT NULL DEFAULT 0,
+    orphaned_size_bytes  BIGINT NOT NULL DEFAULT 0,
+    missing_files_count  INT NOT NULL DEFAULT 0,
+    started_at           TIMESTAMPTZ NOT NULL,
+    completed_at         TIMESTAMPTZ
+);
+
+ALTER TABLE storage_reconciliation_reports
+    ADD CONSTRAINT fk_storage_reconciliation_reports_storage_id
+    FOREIGN KEY (storage_id)
+    REFERENCES storages (id)
+    ON DELETE CASCADE;
+
+CREATE INDEX idx_storage_reconciliation_reports_status
+    ON storage_reconciliation_reports (status);
+
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+
+CREATE TABLE storage_reconciliation_files (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    storage_id  UUID NOT NULL,
+    file_id     UUID NOT NULL,
+    kind        TEXT NOT NULL,
+    source      TEXT,
+    database_id UUID,
+    size_bytes  BIGINT NOT NULL DEFAULT 0,
+    modified_at TIMESTAMPTZ
+);
+
+ALTER TABLE storage_reconciliation_files
+    ADD CONSTRAINT fk_storage_reconciliation_files_storage_id
+    FOREIGN KEY (storage_id)
+    REFERENCES storage_reconciliation_reports (storage_id)
+    ON DELETE CASCADE;
+
+CREATE INDEX idx_storage_reconciliation_files_storage_id
+    ON storage_reconciliation_files (storage_id);
+
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_wal_segments_storage_id ON wal_segments (storage_id);
+CREATE INDEX IF NOT EXISTS idx_backup_copies_storage_id ON backup_copies (storage_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_backup_copies_storage_id;
+DROP INDEX IF EXISTS idx_wal_segments_storage_id;
+DROP TABLE IF EXISTS storage_reconciliation_files;
+DROP TABLE IF EXISTS storage_reconciliation_reports;
+-- +goose StatementEnd
@@ -0,0 +1,59 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE api_keys (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id       UUID NOT NULL,
+    name               TEXT NOT NULL,
+    key_prefix         TEXT NOT NULL,
+    hashed_key         TEXT NOT NULL,
+    role               TEXT NOT NULL,
+    created_by_user_id UUID NOT NULL,
+    expires_at         TIMESTAMPTZ,
+    last_used_at       TIMESTAMPTZ,
+    revoked_at         TIMESTAMPTZ,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE api_keys
+    ADD CONSTRAINT fk_api_keys_workspace_id
+    FOREIGN KEY (workspace_id)
+    REFERENCES workspaces (id)
+    ON DELETE CASCADE;
+
+ALTER TABLE api_keys
+    ADD CONSTRAINT fk_api_keys_created_by_user_id
+    FOREIGN KEY (created_by_user_id)
+    REFERENCES users (id)
+    ON DELETE CASCADE;
+
+ALTER TABLE api_keys
+    ADD CONSTRAINT uk_api_keys_hashed_key
+    UNIQUE (hashed_key);
+
+CREATE INDEX idx_api_keys_workspace_id ON api_keys (workspace_id);
+
+ALTER TABLE audit_logs
+    ADD COLUMN api_key_id UUID;
+
+ALTER TABLE audit_logs
+    ADD CONSTRAINT fk_audit_logs_api_key_id
+    FOREIGN KEY (api_key_id)
+    REFERENCES api_keys (id)
+    ON DELETE SET NULL;
+
+CREATE INDEX idx_audit_logs_api_key_id ON audit_logs (api_key_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_audit_logs_api_key_id;
+ALTER TABLE audit_logs DROP CONSTRAINT IF EXISTS fk_audit_logs_api_key_id;
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS api_key_id;
+
+DROP INDEX IF EXISTS idx_api_keys_workspace_id;
+DROP TABLE IF EXISTS api_keys;
+
+-- +goose StatementEnd
@@ -0,0 +1,62 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE backup_configs
+    ADD COLUMN is_binlog_archiving_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE backups
+    ADD COLUMN binlog_file     TEXT,
+    ADD COLUMN binlog_position BIGINT,
+    ADD COLUMN gtid_set        TEXT;
+
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+
+CREATE TABLE binlog_files (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    database_id     UUID NOT NULL,
+    storage_id      UUID NOT NULL,
+    file_name       TEXT NOT NULL,
+    size_bytes      BIGINT NOT NULL DEFAULT 0,
+    encryption_salt TEXT,
+    encryption_iv   TEXT,
+    encryption      TEXT NOT NULL DEFAULT 'NONE',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE binlog_files
+    ADD CONSTRAINT fk_binlog_files_database_id
+    FOREIGN KEY (database_id)
+    REFERENCES databases (id)
+    ON DELETE CASCADE;
+
+ALTER TABLE binlog_files
+    ADD CONSTRAINT fk_binlog_files_storage_id
+    FOREIGN KEY (storage_id)
+    REFERENCES storages (id)
+    ON DELETE CASCADE;
+
+ALTER TABLE binlog_files
+    ADD CONSTRAINT uq_binlog_files_database_id_file_name
+    UNIQUE (database_id, file_name);
+
+CREATE INDEX idx_binlog_files_database_id_created_at ON binlog_files (database_id, created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_binlog_files_database_id_created_at;
+DROP TABLE IF EXISTS binlog_files;
+
+ALTER TABLE backups
+    DROP COLUMN IF EXISTS gtid_set,
+    DROP COLUMN IF EXISTS binlog_position,
+    DROP COLUMN IF EXISTS binlog_file;
+
+ALTER TABLE backup_configs
+    DROP COLUMN IF EXISTS is_binlog_archiving_enabled;
+
+-- +goose StatementEnd
@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE backup_configs
+    ADD COLUMN is_oplog_archiving_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+
+CREATE TABLE oplog_slices (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    database_id     UUID NOT NULL,
+    storage_id      UUID NOT NULL,
+    start_ts        BIGINT NOT NULL,
+    end_ts          BIGINT NOT NULL,
+    end_time        TIMESTAMPTZ NOT NULL,
+    entries_count   BIGINT NOT NULL DEFAULT 0,
+    size_bytes      BIGINT NOT NULL DEFAULT 0,
+    encryption_salt TEXT,
+    encryption_iv   TEXT,
+    encryption      TEXT NOT NULL DEFAULT 'NONE',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE oplog_slices
+    ADD CONSTRAINT fk_oplog_slices_database_id
+    FOREIGN KEY (database_id)
+    REFERENCES databases (id)
+    ON DELETE CASCADE;
+
+ALTER TABLE oplog_slices
+    ADD CONSTRAINT fk_oplog_slices_storage_id
+    FOREIGN KEY (storage_id)
+    REFERENCES storages (id)
+    ON DELETE CASCADE;
+
+CREATE INDEX idx_oplog_slices_database_id_end_time ON oplog_slices (database_id, end_time);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_oplog_slices_database_id_end_time;
+DROP TABLE IF EXISTS oplog_slices;
+
+ALTER TABLE backup_configs
+    DROP COLUMN IF EXISTS is_oplog_archiving_enabled;
+
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE postgresql_databases
+    ADD COLUMN exclude_tables TEXT NOT NULL DEFAULT '',
+    ADD COLUMN exclude_table_data TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE mysql_databases ADD COLUMN exclude_tables TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE mariadb_databases ADD COLUMN exclude_tables TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE mongodb_databases ADD COLUMN exclude_collections TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE mongodb_databases DROP COLUMN exclude_collections;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE mariadb_databases DROP COLUMN exclude_tables;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE mysql_databases DROP COLUMN exclude_tables;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE postgresql_databases
+    DROP COLUMN exclude_tables,
+    DROP COLUMN exclude_table_data;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE backups ADD COLUMN is_manual BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_backups_queued ON backups (is_manual DESC, created_at ASC) WHERE status = 'QUEUED';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_backups_queued;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE backups DROP COLUMN is_manual;
+-- +goose StatementEnd
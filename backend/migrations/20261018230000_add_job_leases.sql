@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE backups
+    ADD COLUMN attempts         INT NOT NULL DEFAULT 0,
+    ADD COLUMN lease_expires_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE restores
+    ADD COLUMN attempts         INT NOT NULL DEFAULT 0,
+    ADD COLUMN lease_expires_at TIMESTAMPTZ,
+    ADD COLUMN request          TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE restores
+    DROP COLUMN request,
+    DROP COLUMN lease_expires_at,
+    DROP COLUMN attempts;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE backups
+    DROP COLUMN lease_expires_at,
+    DROP COLUMN attempts;
+-- +goose StatementEnd
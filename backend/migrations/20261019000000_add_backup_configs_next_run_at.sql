@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE backup_configs ADD COLUMN next_run_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_backup_configs_next_run_at ON backup_configs (next_run_at) WHERE is_backups_enabled = TRUE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_backup_configs_next_run_at;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE backup_configs DROP COLUMN next_run_at;
+-- +goose StatementEnd
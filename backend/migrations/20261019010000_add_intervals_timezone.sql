@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE intervals ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE intervals DROP COLUMN timezone;
+-- +goose StatementEnd
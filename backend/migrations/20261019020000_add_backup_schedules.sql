@@ -0,0 +1,76 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE backup_schedules (
+    id                     UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    database_id            UUID NOT NULL,
+    name                   TEXT NOT NULL,
+    is_enabled             BOOLEAN NOT NULL DEFAULT FALSE,
+    backup_mode            TEXT NOT NULL DEFAULT 'FULL',
+    backup_interval_id     UUID NOT NULL,
+    store_period           TEXT NOT NULL,
+    keep_min_backups_count INT NOT NULL DEFAULT 0,
+    storage_id             UUID NOT NULL,
+    send_notifications_on  TEXT NOT NULL,
+    next_run_at            TIMESTAMPTZ,
+    created_at             TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE backup_schedules
+    ADD CONSTRAINT fk_backup_schedules_database_id
+    FOREIGN KEY (database_id)
+    REFERENCES backup_configs (database_id)
+    ON DELETE CASCADE;
+
+ALTER TABLE backup_schedules
+    ADD CONSTRAINT fk_backup_schedules_backup_interval_id
+    FOREIGN KEY (backup_interval_id)
+    REFERENCES intervals (id);
+
+ALTER TABLE backup_schedules
+    ADD CONSTRAINT fk_backup_schedules_storage_id
+    FOREIGN KEY (storage_id)
+    REFERENCES storages (id);
+
+CREATE UNIQUE INDEX idx_backup_schedules_database_id_name
+    ON backup_schedules (database_id, name);
+
+CREATE INDEX idx_backup_schedules_storage_id ON backup_schedules (storage_id);
+
+CREATE INDEX idx_backup_schedules_next_run_at
+    ON backup_schedules (next_run_at) WHERE is_enabled = TRUE;
+
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE backup_configs ADD COLUMN backup_mode TEXT NOT NULL DEFAULT 'FULL';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- no foreign key: backups of a removed schedule are kept until removed manually
+ALTER TABLE backups ADD COLUMN schedule_id UUID;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_backups_schedule_id ON backups (schedule_id) WHERE schedule_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_backups_schedule_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE backups DROP COLUMN schedule_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE backup_configs DROP COLUMN backup_mode;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_backup_schedules_next_run_at;
+DROP INDEX IF EXISTS idx_backup_schedules_storage_id;
+DROP INDEX IF EXISTS idx_backup_schedules_database_id_name;
+DROP TABLE IF EXISTS backup_schedules;
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE backup_configs ADD COLUMN blackout_windows TEXT NOT NULL DEFAULT '[]';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+
+CREATE TABLE backup_pauses (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id       UUID,
+    reason             TEXT NOT NULL,
+    resume_at          TIMESTAMPTZ,
+    created_by_user_id UUID,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE backup_pauses
+    ADD CONSTRAINT fk_backup_pauses_workspace_id
+    FOREIGN KEY (workspace_id)
+    REFERENCES workspaces (id)
+    ON DELETE CASCADE;
+
+ALTER TABLE backup_pauses
+    ADD CONSTRAINT fk_backup_pauses_created_by_user_id
+    FOREIGN KEY (created_by_user_id)
+    REFERENCES users (id)
+    ON DELETE SET NULL;
+
+-- one pause per workspace and one global pause (without workspace)
+CREATE UNIQUE INDEX idx_backup_pauses_workspace_id
+    ON backup_pauses ((COALESCE(workspace_id, '00000000-0000-0000-0000-000000000000'::uuid)));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_backup_pauses_workspace_id;
+DROP TABLE IF EXISTS backup_pauses;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE backup_configs DROP COLUMN blackout_windows;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE backups
+    ADD COLUMN pinned_at         TIMESTAMPTZ,
+    ADD COLUMN pin_reason        TEXT,
+    ADD COLUMN pinned_until      TIMESTAMPTZ,
+    ADD COLUMN pinned_by_user_id UUID;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_backups_pinned_until ON backups (pinned_until) WHERE pinned_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_backups_pinned_until;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE backups
+    DROP COLUMN pinned_at,
+    DROP COLUMN pin_reason,
+    DROP COLUMN pinned_until,
+    DROP COLUMN pinned_by_user_id;
+-- +goose StatementEnd
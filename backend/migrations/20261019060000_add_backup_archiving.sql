@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE backup_configs
+    ADD COLUMN archive_storage_id UUID,
+    ADD COLUMN archive_after_days INT NOT NULL DEFAULT 0;
+
+ALTER TABLE backup_configs
+    ADD CONSTRAINT fk_backup_configs_archive_storage_id
+    FOREIGN KEY (archive_storage_id)
+    REFERENCES storages (id);
+
+CREATE INDEX idx_backup_configs_archive_storage_id
+    ON backup_configs (archive_storage_id) WHERE archive_storage_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE backups ADD COLUMN archived_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE backups DROP COLUMN archived_at;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_backup_configs_archive_storage_id;
+
+ALTER TABLE backup_configs
+    DROP CONSTRAINT IF EXISTS fk_backup_configs_archive_storage_id;
+
+ALTER TABLE backup_configs
+    DROP COLUMN archive_after_days,
+    DROP COLUMN archive_storage_id;
+-- +goose StatementEnd
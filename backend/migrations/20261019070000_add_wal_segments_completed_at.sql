@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE wal_segments ADD COLUMN completed_at TIMESTAMPTZ;
+
+UPDATE wal_segments SET completed_at = created_at;
+
+ALTER TABLE wal_segments ALTER COLUMN completed_at SET NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE wal_segments DROP COLUMN completed_at;
+-- +goose StatementEnd
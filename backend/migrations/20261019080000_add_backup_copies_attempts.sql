@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE backup_copies
+    ADD COLUMN attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN next_attempt_at TIMESTAMPTZ,
+    ADD COLUMN lease_expires_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+
+-- existing configs and schedules that notify on failed backups notify on
+-- failed replication too
+UPDATE backup_configs
+    SET send_notifications_on = send_notifications_on || ',BACKUP_REPLICATION_FAILED'
+    WHERE send_notifications_on LIKE '%BACKUP_FAILED%';
+
+UPDATE backup_schedules
+    SET send_notifications_on = send_notifications_on || ',BACKUP_REPLICATION_FAILED'
+    WHERE send_notifications_on LIKE '%BACKUP_FAILED%';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+UPDATE backup_schedules
+    SET send_notifications_on = REPLACE(send_notifications_on, ',BACKUP_REPLICATION_FAILED', '');
+
+UPDATE backup_configs
+    SET send_notifications_on = REPLACE(send_notifications_on, ',BACKUP_REPLICATION_FAILED', '');
+
+ALTER TABLE backup_copies
+    DROP COLUMN lease_expires_at,
+    DROP COLUMN next_attempt_at,
+    DROP COLUMN attempts;
+-- +goose StatementEnd